@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"shelllab/backend/database"
+	"shelllab/backend/media"
 	"shelllab/backend/services"
 
 	"github.com/joho/godotenv"
@@ -30,17 +33,31 @@ type App struct {
 	categoryRepo  *database.CategoryRepository
 	atlasLootRepo *database.AtlasLootRepository
 	favoriteRepo  *database.FavoriteRepository
-
-	// Cache for category lookups
-	categoryCache      map[int]*database.Category
-	rootCategoryByName map[string]int
+	wishlistRepo  *database.WishlistRepository
+	searchRepo    *database.SavedSearchRepository
+	changeLogRepo *database.ChangeLogRepository
 
 	// Services
 	npcService  *services.NpcService
 	syncService *services.SyncService
+	patchNotes  *services.PatchNotesService
+	userData    *services.UserDataService
+	jobManager  *services.JobManager
 	scraper     *services.ScraperService
+	httpCache   *services.HTTPCache
 	mysqlDB     *database.MySQLConnection
 
+	// Downloaded icons and NPC images, served to the UI by assets
+	icons  *media.Cache
+	images *media.Cache
+	atlas  *media.Atlas
+	assets *services.AssetHandler
+
+	// Cancel funcs of direct (non-job) sync calls, keyed by operation ID
+	opsMu  sync.Mutex
+	ops    map[int64]*operation
+	nextOp int64
+
 	// Mode
 	isDevMode bool
 }
@@ -48,10 +65,9 @@ type App struct {
 // NewApp creates a new App application struct
 func NewApp(dataDir string, isDevMode bool) *App {
 	return &App{
-		DataDir:            dataDir,
-		isDevMode:          isDevMode,
-		categoryCache:      make(map[int]*database.Category),
-		rootCategoryByName: make(map[string]int),
+		DataDir:   dataDir,
+		isDevMode: isDevMode,
+		ops:       make(map[int64]*operation),
 	}
 }
 
@@ -66,10 +82,11 @@ func (a *App) startup(ctx context.Context) {
 		fmt.Println("Warning: .env file not found, MySQL features disabled")
 	}
 
-	// Initialize SQLite database
+	// Initialize SQLite database, with user data (favorites...) in a separate user.db
 	dbPath := filepath.Join(a.DataDir, "shelllab.db")
+	userDBPath := filepath.Join(a.DataDir, "user.db")
 
-	db, err := database.NewSQLiteDB(dbPath)
+	db, err := database.NewSQLiteDBWithUserData(dbPath, userDBPath)
 	if err != nil {
 		fmt.Printf("ERROR: Failed to open database: %v\n", err)
 		return
@@ -94,22 +111,42 @@ func (a *App) startup(ctx context.Context) {
 	a.categoryRepo = database.NewCategoryRepository(db)
 	a.atlasLootRepo = database.NewAtlasLootRepository(db)
 	a.favoriteRepo = database.NewFavoriteRepository(db)
+	a.wishlistRepo = database.NewWishlistRepository(db)
+	a.searchRepo = database.NewSavedSearchRepository(db)
+	a.changeLogRepo = database.NewChangeLogRepository(db)
+
+	// Initialize wishlists schema
+	if err := a.wishlistRepo.InitSchema(); err != nil {
+		fmt.Printf("ERROR: Failed to initialize wishlists schema: %v\n", err)
+	}
+
+	// Initialize saved searches schema
+	if err := a.searchRepo.InitSchema(); err != nil {
+		fmt.Printf("ERROR: Failed to initialize saved searches schema: %v\n", err)
+	}
+
+	// One-time move of favorites kept by older versions onto wishlists
+	a.migrateFavorites()
+
+	// Periodic backups of user.db to data/backups
+	a.initUserData()
+
+	// Initialize sync change log schema
+	if err := a.changeLogRepo.InitSchema(); err != nil {
+		fmt.Printf("ERROR: Failed to initialize change log schema: %v\n", err)
+	}
+
+	// Apply signed game data patches shipped in data/patches
+	a.applyDataPatches()
 
-	// Initialize favorites schema
-	if err := a.favoriteRepo.InitSchema(); err != nil {
-		fmt.Printf("ERROR: Failed to initialize favorites schema: %v\n", err)
+	// Downloaded icons and images are indexed in media_files
+	if err := media.InitSchema(db.DB()); err != nil {
+		fmt.Printf("ERROR: Failed to initialize media schema: %v\n", err)
 	}
+	a.initAssets()
 
 	// Initialize MySQL (Optional)
-	mysqlUser := os.Getenv("MYSQL_USER")
-	if mysqlUser != "" {
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-			os.Getenv("MYSQL_USER"),
-			os.Getenv("MYSQL_PASSWORD"),
-			os.Getenv("MYSQL_HOST"),
-			os.Getenv("MYSQL_PORT"),
-			os.Getenv("MYSQL_DATABASE"),
-		)
+	if dsn := database.MySQLDSNFromEnv(); dsn != "" {
 		mysqlConn, err := database.NewMySQLConnection(dsn)
 		if err != nil {
 			fmt.Printf("MySQL Connection Failed: %v\n", err)
@@ -128,9 +165,6 @@ func (a *App) startup(ctx context.Context) {
 	fmt.Printf("  - Items: %d\n", itemCount)
 	fmt.Printf("  - Categories: %d\n", catCount)
 
-	// Build category cache
-	a.buildCategoryCache()
-
 	// Data import using importers
 	// dataDir is already set in a.DataDir
 
@@ -186,9 +220,21 @@ func (a *App) startup(ctx context.Context) {
 	// No need to auto-download on startup
 
 	// Initialize NPC Service
+	// Raw pages are cached on disk so re-syncs can be replayed after parser fixes
+	a.httpCache = services.NewHTTPCache(services.SharedHTTPClient(), filepath.Join(a.DataDir, "http_cache"), 7*24*time.Hour)
+	if os.Getenv("SHELLLAB_HTTP_REPLAY") == "1" {
+		a.httpCache.SetMode(services.CacheModeReplay)
+	}
+
 	a.scraper = services.NewScraperService()
+	a.scraper.Client = a.httpCache
 	a.npcService = services.NewNpcService(a.db.DB(), a.mysqlDB, a.scraper, a.itemRepo, a.creatureRepo, a.DataDir)
 	a.syncService = services.NewSyncService(a.db.DB())
+	a.syncService.SetCache(a.httpCache)
+	a.patchNotes = services.NewPatchNotesService(a.db.DB())
+
+	// Full syncs run as persisted jobs; interrupted ones resume here
+	a.initJobManager()
 
 	// Async sync creature spawns for dev convenience
 	if a.isDevMode && a.mysqlDB != nil {
@@ -199,7 +245,7 @@ func (a *App) startup(ctx context.Context) {
 			fmt.Println("⚡ Starting async creature spawn sync (First Run)...")
 			go func() {
 				// No progress callback necessary for background startup task
-				err := a.npcService.SyncAllCreatureSpawns(nil)
+				err := a.npcService.SyncAllCreatureSpawns(a.ctx, nil)
 				if err != nil {
 					fmt.Printf("Startup spawn sync warning: %v\n", err)
 				} else {
@@ -224,26 +270,13 @@ func (a *App) importFullTables(dataDir string) {
 
 	fmt.Println("⚡ Checking database tables and importing from MySQL if needed...")
 	importer := database.NewMySQLImporter(a.db.DB(), a.mysqlDB.DB())
-	if err := importer.ImportAllFromMySQL(); err != nil {
+	if err := importer.ImportAllFromMySQL(a.ctx); err != nil {
 		fmt.Printf("❌ MySQL Import Failed: %v\n", err)
 	} else {
 		fmt.Println("✓ MySQL Import Check Complete")
 	}
 }
 
-// buildCategoryCache builds a cache of categories for faster lookups
-func (a *App) buildCategoryCache() {
-	roots, err := a.categoryRepo.GetRootCategories()
-	if err != nil {
-		return
-	}
-
-	for _, cat := range roots {
-		a.categoryCache[cat.ID] = cat
-		a.rootCategoryByName[cat.Name] = cat.ID
-	}
-}
-
 // shutdown is called when the app is closing
 func (a *App) shutdown(ctx context.Context) {
 	if a.db != nil {
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"shelllab/backend/database"
 	"shelllab/backend/services"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // GetCreatureTypes returns all creature types with counts
@@ -20,44 +18,16 @@ func (a *App) GetCreatureTypes() []*database.CreatureType {
 	return types
 }
 
-// BrowseCreaturesByType returns creatures filtered by type
-func (a *App) BrowseCreaturesByType(creatureType int, nameFilter string) []*database.Creature {
-	fmt.Printf("[API] BrowseCreaturesByType called type=%d filter='%s'\n", creatureType, nameFilter)
-	creatures, _, err := a.creatureRepo.GetCreaturesByType(creatureType, nameFilter, 9999, 0)
-	if err != nil {
-		fmt.Printf("[API] Error browsing creatures: %v\n", err)
-		return []*database.Creature{}
-	}
-	fmt.Printf("[API] BrowseCreaturesByType returning %d creatures\n", len(creatures))
-	return creatures
-}
-
-// CreaturePageResult is the result of paginated creature query
-type CreaturePageResult struct {
-	Creatures []*database.Creature `json:"creatures"`
-	Total     int                  `json:"total"`
-	HasMore   bool                 `json:"hasMore"`
-}
-
-// BrowseCreaturesByTypePaged returns creatures with pagination support
-func (a *App) BrowseCreaturesByTypePaged(creatureType int, nameFilter string, limit, offset int) *CreaturePageResult {
-	fmt.Printf("[API] BrowseCreaturesByTypePaged: type=%d filter='%s' limit=%d offset=%d\n", creatureType, nameFilter, limit, offset)
-
-	creatures, total, err := a.creatureRepo.GetCreaturesByType(creatureType, nameFilter, limit, offset)
+// BrowseCreaturesByType returns a page of creatures filtered by type
+func (a *App) BrowseCreaturesByType(creatureType int, nameFilter string, page database.PageQuery) *database.Page[*database.Creature] {
+	fmt.Printf("[API] BrowseCreaturesByType called type=%d filter='%s' page=%+v\n", creatureType, nameFilter, page)
+	result, err := a.creatureRepo.GetCreaturesByType(creatureType, nameFilter, page)
 	if err != nil {
 		fmt.Printf("[API] Error browsing creatures: %v\n", err)
-		return &CreaturePageResult{
-			Creatures: []*database.Creature{},
-			Total:     0,
-			HasMore:   false,
-		}
-	}
-
-	return &CreaturePageResult{
-		Creatures: creatures,
-		Total:     total,
-		HasMore:   (offset + len(creatures)) < total,
+		return &database.Page[*database.Creature]{Items: []*database.Creature{}}
 	}
+	fmt.Printf("[API] BrowseCreaturesByType returning %d of %d creatures\n", len(result.Items), result.Total)
+	return result
 }
 
 // SearchCreatures searches for creatures by name
@@ -90,6 +60,16 @@ func (a *App) GetCreatureLoot(entry int) []*database.LootItem {
 	return loot
 }
 
+// GetLootTree returns a creature loot table with its reference tables nested
+func (a *App) GetLootTree(lootID int) *database.LootTree {
+	tree, err := a.lootRepo.GetLootTree(lootID)
+	if err != nil {
+		fmt.Printf("Error getting loot tree [%d]: %v\n", lootID, err)
+		return &database.LootTree{LootID: lootID, Nodes: []*database.LootNode{}}
+	}
+	return tree
+}
+
 // GetNpcDetails returns full details for an NPC (Scraped + DB)
 func (a *App) GetNpcDetails(entry int) *services.NpcFullDetails {
 	fmt.Printf("[API] GetNpcDetails called for %d\n", entry)
@@ -104,7 +84,7 @@ func (a *App) GetNpcDetails(entry int) *services.NpcFullDetails {
 // SyncNpcData forces a re-sync of NPC data
 func (a *App) SyncNpcData(entry int) *services.NpcFullDetails {
 	fmt.Printf("[API] SyncNpcData called for %d\n", entry)
-	err := a.npcService.SyncNpcData(entry)
+	err := a.npcService.SyncNpcData(a.ctx, entry)
 	if err != nil {
 		fmt.Printf("Error syncing NPC data: %v\n", err)
 	}
@@ -114,6 +94,7 @@ func (a *App) SyncNpcData(entry int) *services.NpcFullDetails {
 }
 
 // FullSyncNpcs re-syncs all NPC data (Web + MySQL) starting from a specific ID
+// Runs as a persisted job, see ListJobs/PauseJob/ResumeJob
 func (a *App) FullSyncNpcs(startFrom int, delayMs int) string {
 	fmt.Printf("[API] FullSyncNpcs called with startFrom=%d, delayMs=%d\n", startFrom, delayMs)
 
@@ -121,25 +102,8 @@ func (a *App) FullSyncNpcs(startFrom int, delayMs int) string {
 		delayMs = 200 // Default delay
 	}
 
-	a.npcService.ResetStop()
-
-	go func() {
-		progressCb := func(current, total int, id int) {
-			runtime.EventsEmit(a.ctx, "sync:npc_full:progress", map[string]interface{}{
-				"current": current,
-				"total":   total,
-				"id":      id,
-			})
-		}
-
-		err := a.npcService.FullSyncNpcs(startFrom, delayMs, progressCb)
-		if err != nil {
-			fmt.Printf("Error syncing all NPCs: %v\n", err)
-			runtime.EventsEmit(a.ctx, "sync:npc_full:error", err.Error())
-		} else {
-			runtime.EventsEmit(a.ctx, "sync:npc_full:complete", "Full NPC sync complete")
-		}
-	}()
-
-	return "Started"
+	return a.submitSyncJob(services.JobTypeNpcs, services.JobParams{
+		DelayMs:   delayMs,
+		StartFrom: startFrom,
+	})
 }
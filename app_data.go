@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"shelllab/backend/datapatch"
+)
+
+// dataPatchPublicKey is the base64 ed25519 key data patches must be signed with.
+// Release builds set it with -ldflags "-X main.dataPatchPublicKey=<key>".
+var dataPatchPublicKey = ""
+
+// trustedPatchKeys returns the build key. The environment is deliberately not
+// consulted, so nothing outside the release build can add a trusted key.
+func trustedPatchKeys() []ed25519.PublicKey {
+	s := strings.TrimSpace(dataPatchPublicKey)
+	if s == "" {
+		return nil
+	}
+	key, err := datapatch.ParsePublicKey(s)
+	if err != nil {
+		fmt.Printf("⚠ Ignoring data patch key: %v\n", err)
+		return nil
+	}
+	return []ed25519.PublicKey{key}
+}
+
+// applyDataPatches brings the game data up to date from data/patches/*.slpatch
+func (a *App) applyDataPatches() {
+	if err := datapatch.InitSchema(a.db.DB()); err != nil {
+		fmt.Printf("ERROR: Failed to initialize data version schema: %v\n", err)
+		return
+	}
+
+	dir := filepath.Join(a.DataDir, "patches")
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+datapatch.FileExt))
+	if len(files) == 0 {
+		return
+	}
+
+	keys := trustedPatchKeys()
+	if len(keys) == 0 {
+		fmt.Println("⚠ Data patches found but no patch signing key is configured, skipping")
+		return
+	}
+
+	results, err := datapatch.NewUpdater(a.db.DB(), keys).ApplyDir(dir)
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("⚠ Skipped data patch %s: %s\n", r.File, r.Error)
+		} else if !r.Skipped {
+			fmt.Printf("✓ Applied data patch %s (v%d -> v%d): %d rows written, %d deleted\n",
+				r.File, r.FromVersion, r.ToVersion, r.Upserted, r.Deleted)
+		}
+	}
+	if err != nil {
+		fmt.Printf("ERROR: Failed to apply data patches: %v\n", err)
+	}
+}
+
+// ============================================================================
+// Data Patch APIs
+// ============================================================================
+
+// GetDataVersion returns the game data version of the local database
+func (a *App) GetDataVersion() int {
+	version, err := datapatch.CurrentVersion(a.db.DB())
+	if err != nil {
+		fmt.Printf("[API] GetDataVersion error: %v\n", err)
+		return 0
+	}
+	return version
+}
+
+// ApplyDataPatch verifies and applies a local .slpatch file
+func (a *App) ApplyDataPatch(path string) string {
+	fmt.Printf("[API] ApplyDataPatch called: %s\n", path)
+
+	keys := trustedPatchKeys()
+	if len(keys) == 0 {
+		return "No patch signing key is configured"
+	}
+
+	result, err := datapatch.NewUpdater(a.db.DB(), keys).ApplyFile(path)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	if result.Skipped {
+		return fmt.Sprintf("Patch already applied (data version %d)", result.ToVersion)
+	}
+
+	return fmt.Sprintf("Updated data to version %d: %d rows written, %d deleted",
+		result.ToVersion, result.Upserted, result.Deleted)
+}
@@ -17,16 +17,16 @@ func (a *App) GetObjectTypes() []*database.ObjectType {
 	return types
 }
 
-// GetObjectsByType returns objects filtered by type
-func (a *App) GetObjectsByType(typeID int, nameFilter string) []*database.GameObject {
-	fmt.Printf("[API] GetObjectsByType called: type=%d, filter='%s'\n", typeID, nameFilter)
-	objects, err := a.objectRepo.GetObjectsByType(typeID, nameFilter)
+// GetObjectsByType returns a page of objects filtered by type
+func (a *App) GetObjectsByType(typeID int, nameFilter string, page database.PageQuery) *database.Page[*database.GameObject] {
+	fmt.Printf("[API] GetObjectsByType called: type=%d, filter='%s' page=%+v\n", typeID, nameFilter, page)
+	result, err := a.objectRepo.GetObjectsByType(typeID, nameFilter, page)
 	if err != nil {
 		fmt.Printf("[API] Error browsing objects: %v\n", err)
-		return []*database.GameObject{}
+		return &database.Page[*database.GameObject]{Items: []*database.GameObject{}}
 	}
-	fmt.Printf("[API] GetObjectsByType returning %d objects\n", len(objects))
-	return objects
+	fmt.Printf("[API] GetObjectsByType returning %d of %d objects\n", len(result.Items), result.Total)
+	return result
 }
 
 // SearchObjects searches for objects by name
@@ -3,13 +3,38 @@ package main
 import (
 	"encoding/base64"
 	"fmt"
-	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+
+	"shelllab/backend/media"
+	"shelllab/backend/services"
+	"shelllab/backend/tooltip"
 )
 
+// initAssets sets up the icon and NPC image caches and the handler that
+// serves them to the UI
+func (a *App) initAssets() {
+	a.icons = media.New(a.db.DB(), media.KindIcon, filepath.Join(a.DataDir, "icons"), services.SharedHTTPClient())
+	a.images = media.New(a.db.DB(), media.KindImage, filepath.Join(a.DataDir, "npc_images"), services.SharedHTTPClient())
+	a.atlas = media.NewAtlas(a.icons.Dir())
+
+	iconsFS, _ := fs.Sub(embeddedIcons, "data/icons")
+	npcFS, _ := fs.Sub(embeddedNpcImages, "data/npc_images")
+	a.assets = services.NewAssetHandler(a.icons, a.images, iconsFS, npcFS)
+}
+
+// serveAsset is the Wails asset server fallback for /icons, /local-icons and
+// /npc. Requests before startup has opened the database get a 404.
+func (a *App) serveAsset(w http.ResponseWriter, r *http.Request) {
+	if a.assets == nil {
+		http.NotFound(w, r)
+		return
+	}
+	a.assets.ServeHTTP(w, r)
+}
+
 // ImageResult represents the result of an image fetch
 type ImageResult struct {
 	Data     string `json:"data"`     // Base64 encoded image data
@@ -21,6 +46,8 @@ type ImageResult struct {
 // GetLocalImage reads a local image file and returns it as base64
 // imageType: "icon", "npc_model", "npc_map"
 // name: file name (e.g., "inv_sword_01" for icons, "model_15114" for npc)
+// The frontend now loads images from the asset handler (/icons, /npc); this
+// stays for callers that need the bytes.
 func (a *App) GetLocalImage(imageType string, name string) *ImageResult {
 	var basePath string
 	var extensions []string
@@ -55,66 +82,82 @@ func (a *App) GetLocalImage(imageType string, name string) *ImageResult {
 	return &ImageResult{Error: "file not found: " + name}
 }
 
+// loadItemIcon reads an item's icon from data/icons, or nil when it is not downloaded
+func (a *App) loadItemIcon(itemID int) *tooltip.Icon {
+	item, err := a.itemRepo.GetItemByID(itemID)
+	if err != nil {
+		return nil
+	}
+	return tooltip.LoadIcon(filepath.Join(a.DataDir, "icons"), item.IconPath)
+}
+
+// GetIconSprites returns where the given iconPath names are drawn in the icon
+// sprite atlases at size (18 or 36), keyed by the names as passed. Icons not in
+// the atlases are left out and should be loaded from /icons/<name>.
+func (a *App) GetIconSprites(iconPaths []string, size int) map[string]*media.Sprite {
+	fmt.Printf("[API] GetIconSprites called: %d names, size=%d\n", len(iconPaths), size)
+	if a.atlas == nil || !media.ValidAtlasSize(size) {
+		return map[string]*media.Sprite{}
+	}
+	return a.atlas.Sprites(iconPaths, size, "/icons/atlas/")
+}
+
 // FetchRemoteImage fetches an image from a remote URL and returns it as base64
-// Also optionally saves it locally for caching
+// Also saves it to the icon or NPC image cache when imageType and name are set
+// Superseded in the frontend by the asset handler's /npc/<name>?src=<url>.
 func (a *App) FetchRemoteImage(url string, imageType string, name string) *ImageResult {
 	if url == "" {
 		return &ImageResult{Error: "empty URL"}
 	}
 
-	// Create HTTP request with User-Agent
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return &ImageResult{Error: "failed to create request: " + err.Error()}
+	// Save locally through the media caches when the image type is known
+	var cache *media.Cache
+	key := url
+	switch imageType {
+	case "icon":
+		cache, key = a.icons, name
+	case "npc_model", "npc_map":
+		cache = a.images
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var data []byte
+	var err error
+	if cache != nil && name != "" {
+		var path string
+		if path, err = cache.Fetch(a.ctx, key, url); err == nil {
+			data, err = os.ReadFile(path)
+		}
+	} else {
+		data, err = media.Download(a.ctx, services.SharedHTTPClient(), url)
+	}
 	if err != nil {
 		return &ImageResult{Error: "failed to fetch: " + err.Error()}
 	}
-	defer resp.Body.Close()
+	_, mimeType, _ := media.Sniff(data)
 
-	if resp.StatusCode != 200 {
-		return &ImageResult{Error: fmt.Sprintf("HTTP %d", resp.StatusCode)}
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return &ImageResult{Error: "failed to read response: " + err.Error()}
+	return &ImageResult{
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+		Source:   "remote",
 	}
+}
 
-	// Determine MIME type
-	mimeType := resp.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = "image/jpeg"
+// CleanupImages removes downloaded icons and NPC images no database row
+// references. With dryRun nothing is deleted; the results list what would be.
+func (a *App) CleanupImages(dryRun bool) []*media.GCResult {
+	fmt.Printf("[API] CleanupImages called (dryRun=%v)\n", dryRun)
+	results := []*media.GCResult{}
+	if a.icons == nil {
+		return results
 	}
 
-	// Optionally save locally
-	if imageType != "" && name != "" {
-		var saveDir string
-		switch imageType {
-		case "icon":
-			saveDir = filepath.Join(a.DataDir, "icons")
-		case "npc_model", "npc_map":
-			saveDir = filepath.Join(a.DataDir, "npc_images")
-		}
-		if saveDir != "" {
-			os.MkdirAll(saveDir, 0755)
-			ext := ".jpg"
-			if strings.Contains(mimeType, "png") {
-				ext = ".png"
-			}
-			savePath := filepath.Join(saveDir, name+ext)
-			os.WriteFile(savePath, data, 0644)
-			fmt.Printf("[Image] Saved to local: %s\n", savePath)
+	for _, cache := range []*media.Cache{a.icons, a.images} {
+		result, err := cache.GC(dryRun)
+		if err != nil {
+			fmt.Printf("[API] Error: %v\n", err)
+			return results
 		}
+		results = append(results, result)
 	}
-
-	return &ImageResult{
-		Data:     base64.StdEncoding.EncodeToString(data),
-		MimeType: mimeType,
-		Source:   "remote",
-	}
+	return results
 }
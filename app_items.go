@@ -1,12 +1,14 @@
 package main
 
 import (
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"shelllab/backend/database"
+	"shelllab/backend/tooltip"
 )
 
 // GetRootCategories returns top-level categories (e.g., "Mage Sets", "Molten Core")
@@ -62,28 +64,28 @@ func (a *App) GetItemClasses() []*database.ItemClass {
 	return classes
 }
 
-// BrowseItemsByClass returns items for a specific class/subclass
-func (a *App) BrowseItemsByClass(class, subClass int, nameFilter string) []*database.Item {
-	fmt.Printf("[API] BrowseItemsByClass called: class=%d, subClass=%d, filter='%s'\n", class, subClass, nameFilter)
-	// No limit - return all matching items
-	items, _, err := a.itemRepo.GetItemsByClass(class, subClass, nameFilter, 999999, 0)
+// BrowseItemsByClass returns a page of items for a specific class/subclass
+func (a *App) BrowseItemsByClass(class, subClass int, nameFilter string, page database.PageQuery) *database.Page[*database.Item] {
+	fmt.Printf("[API] BrowseItemsByClass called: class=%d, subClass=%d, filter='%s' page=%+v\n", class, subClass, nameFilter, page)
+	result, err := a.itemRepo.GetItemsByClass(class, subClass, nameFilter, page)
 	if err != nil {
 		fmt.Printf("[API] Error browsing items: %v\n", err)
-		return []*database.Item{}
+		return &database.Page[*database.Item]{Items: []*database.Item{}}
 	}
-	fmt.Printf("[API] BrowseItemsByClass returning %d items\n", len(items))
-	return a.enrichItemsWithIcons(items)
+	fmt.Printf("[API] BrowseItemsByClass returning %d of %d items\n", len(result.Items), result.Total)
+	result.Items = a.enrichItemsWithIcons(result.Items)
+	return result
 }
 
-// BrowseItemsByClassAndSlot returns items for a specific class/subclass/inventoryType
-func (a *App) BrowseItemsByClassAndSlot(class, subClass, inventoryType int, nameFilter string) []*database.Item {
-	// No limit - return all matching items
-	items, _, err := a.itemRepo.GetItemsByClassAndSlot(class, subClass, inventoryType, nameFilter, 999999, 0)
+// BrowseItemsByClassAndSlot returns a page of items for a specific class/subclass/inventoryType
+func (a *App) BrowseItemsByClassAndSlot(class, subClass, inventoryType int, nameFilter string, page database.PageQuery) *database.Page[*database.Item] {
+	result, err := a.itemRepo.GetItemsByClassAndSlot(class, subClass, inventoryType, nameFilter, page)
 	if err != nil {
 		fmt.Printf("Error browsing items by slot: %v\n", err)
-		return []*database.Item{}
+		return &database.Page[*database.Item]{Items: []*database.Item{}}
 	}
-	return a.enrichItemsWithIcons(items)
+	result.Items = a.enrichItemsWithIcons(result.Items)
+	return result
 }
 
 // AdvancedSearch performs a detailed search
@@ -147,6 +149,48 @@ func (a *App) GetTooltipData(itemID int) *database.TooltipData {
 	return data
 }
 
+// RenderTooltip renders an item tooltip for sharing.
+// format: "discord", "bbcode", "text", "html" (standalone, icon embedded) or "link" (in-game chat link)
+func (a *App) RenderTooltip(itemID int, format string) string {
+	fmt.Printf("[API] RenderTooltip called: %d (%s)\n", itemID, format)
+	data, err := a.itemRepo.GetTooltipData(itemID)
+	if err != nil {
+		fmt.Printf("[API] Error getting tooltip data: %v\n", err)
+		return ""
+	}
+
+	var icon *tooltip.Icon
+	if format == tooltip.FormatHTML {
+		icon = a.loadItemIcon(itemID)
+	}
+
+	out, err := tooltip.Render(data, format, icon)
+	if err != nil {
+		fmt.Printf("[API] Error rendering tooltip: %v\n", err)
+		return ""
+	}
+	return out
+}
+
+// RenderTooltipImage draws an item tooltip as a PNG for sharing where markup doesn't render
+func (a *App) RenderTooltipImage(itemID int) *ImageResult {
+	fmt.Printf("[API] RenderTooltipImage called: %d\n", itemID)
+	data, err := a.itemRepo.GetTooltipData(itemID)
+	if err != nil {
+		return &ImageResult{Error: fmt.Sprintf("item %d not found", itemID)}
+	}
+
+	img, err := tooltip.PNG(data, a.loadItemIcon(itemID))
+	if err != nil {
+		return &ImageResult{Error: err.Error()}
+	}
+	return &ImageResult{
+		Data:     base64.StdEncoding.EncodeToString(img),
+		MimeType: "image/png",
+		Source:   "local",
+	}
+}
+
 // GetItemSets returns all item sets for browsing
 func (a *App) GetItemSets() []*database.ItemSetBrowse {
 	fmt.Println("[API] GetItemSets called")
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"shelllab/backend/services"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ============================================================================
+// Sync Job APIs
+// ============================================================================
+
+// initJobManager registers the full sync runners and resumes interrupted jobs
+func (a *App) initJobManager() {
+	a.jobManager = services.NewJobManager(a.db.DB())
+	if err := a.jobManager.InitSchema(); err != nil {
+		fmt.Printf("Warning: Failed to init sync_jobs table: %v\n", err)
+	}
+
+	a.jobManager.OnUpdate = func(job *services.SyncJob) {
+		runtime.EventsEmit(a.ctx, "jobs:update", job)
+	}
+
+	a.jobManager.Register(services.JobTypeItems, a.runItemSyncJob)
+	a.jobManager.Register(services.JobTypeSpells, a.runSpellSyncJob)
+	a.jobManager.Register(services.JobTypeQuests, a.runQuestSyncJob)
+	a.jobManager.Register(services.JobTypeNpcs, a.runNpcSyncJob)
+
+	a.jobManager.Start(a.ctx)
+}
+
+// submitSyncJob queues a full sync job and returns a status message for the frontend
+func (a *App) submitSyncJob(jobType string, params services.JobParams) string {
+	job, err := a.jobManager.Submit(jobType, params)
+	if err != nil {
+		fmt.Printf("[API] Failed to queue %s sync: %v\n", jobType, err)
+		return err.Error()
+	}
+	fmt.Printf("[API] Queued %s sync as job %d\n", jobType, job.ID)
+	return "Started"
+}
+
+// ListJobs returns recent sync jobs, newest first
+func (a *App) ListJobs(limit int) []*services.SyncJob {
+	fmt.Printf("[API] ListJobs called with limit=%d\n", limit)
+	jobs, err := a.jobManager.List(limit)
+	if err != nil {
+		fmt.Printf("[API] Error listing jobs: %v\n", err)
+		return []*services.SyncJob{}
+	}
+	if jobs == nil {
+		return []*services.SyncJob{}
+	}
+	return jobs
+}
+
+// PauseJob stops a job and keeps its cursor so it can be resumed
+func (a *App) PauseJob(id int64) string {
+	fmt.Printf("[API] PauseJob called for job %d\n", id)
+	if err := a.jobManager.Pause(id); err != nil {
+		return err.Error()
+	}
+	return "Paused"
+}
+
+// ResumeJob re-queues a paused or failed job from its cursor
+func (a *App) ResumeJob(id int64) string {
+	fmt.Printf("[API] ResumeJob called for job %d\n", id)
+	if err := a.jobManager.Resume(id); err != nil {
+		return err.Error()
+	}
+	return "Resumed"
+}
+
+// CancelJob stops a job permanently
+func (a *App) CancelJob(id int64) string {
+	fmt.Printf("[API] CancelJob called for job %d\n", id)
+	if err := a.jobManager.Cancel(id); err != nil {
+		return err.Error()
+	}
+	return "Cancelled"
+}
+
+// ============================================================================
+// Direct Sync Operations
+// ============================================================================
+
+// Sync types without a job runner, used to stop direct calls
+const (
+	syncTypeAtlasLoot = "atlasloot"
+	syncTypeIcons     = "icons"
+)
+
+// operation is a running direct sync call that can be stopped by type
+type operation struct {
+	syncType string
+	cancel   context.CancelFunc
+}
+
+// beginOperation returns a context for a direct sync call; call done when it returns
+func (a *App) beginOperation(syncType string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(a.ctx)
+
+	a.opsMu.Lock()
+	a.nextOp++
+	id := a.nextOp
+	a.ops[id] = &operation{syncType: syncType, cancel: cancel}
+	a.opsMu.Unlock()
+
+	return ctx, func() {
+		a.opsMu.Lock()
+		delete(a.ops, id)
+		a.opsMu.Unlock()
+		cancel()
+	}
+}
+
+// cancelOperations cancels direct sync calls of one type, or all if syncType is empty
+func (a *App) cancelOperations(syncType string) {
+	a.opsMu.Lock()
+	defer a.opsMu.Unlock()
+	for _, op := range a.ops {
+		if syncType == "" || op.syncType == syncType {
+			op.cancel()
+		}
+	}
+}
+
+// ============================================================================
+// Job Runners
+// ============================================================================
+
+func (a *App) runItemSyncJob(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+	iconDir := filepath.Join(a.DataDir, "icons")
+
+	progressCb := func(current, total int, itemID int, itemName string) {
+		ctl.Progress(current, total)
+		runtime.EventsEmit(a.ctx, "sync:progress", map[string]interface{}{
+			"current":  current,
+			"total":    total,
+			"itemId":   itemID,
+			"itemName": itemName,
+		})
+	}
+
+	// Items are synced by a worker pool, so IDs complete out of order; the
+	// sync checkpoints the ID below which all are done
+	result := a.syncService.FullSyncItems(ctx, job.Params.DelayMs, job.Params.FixIcons, iconDir, job.ResumeFrom(), progressCb, ctl.Checkpoint)
+	if len(result.Errors) > 0 && result.Updated == 0 {
+		runtime.EventsEmit(a.ctx, "sync:item_full:error", result.Message)
+		return result, errors.New(result.Message)
+	}
+	if ctx.Err() == nil {
+		runtime.EventsEmit(a.ctx, "sync:item_full:complete", result.Message)
+	}
+	return result, nil
+}
+
+func (a *App) runSpellSyncJob(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+	iconDir := filepath.Join(a.DataDir, "icons")
+
+	progressCb := func(current, total int, itemID int, itemName string) {
+		ctl.Progress(current, total)
+		ctl.Checkpoint(itemID)
+		runtime.EventsEmit(a.ctx, "sync:spells:progress", map[string]interface{}{
+			"current":  current,
+			"total":    total,
+			"itemId":   itemID,
+			"itemName": itemName,
+		})
+	}
+
+	result := a.syncService.FullSyncSpells(ctx, job.Params.DelayMs, job.Params.FixIcons, iconDir, job.ResumeFrom(), progressCb)
+	if ctx.Err() == nil {
+		runtime.EventsEmit(a.ctx, "sync:spells_full:complete", result.Message)
+	}
+	return result, nil
+}
+
+func (a *App) runQuestSyncJob(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+	progressCb := func(current, total int, itemID int, itemName string) {
+		ctl.Progress(current, total)
+		ctl.Checkpoint(itemID)
+		runtime.EventsEmit(a.ctx, "sync:quests:progress", map[string]interface{}{
+			"current":  current,
+			"total":    total,
+			"itemId":   itemID,
+			"itemName": itemName,
+		})
+	}
+
+	result := a.syncService.FullSyncQuests(ctx, job.Params.DelayMs, job.ResumeFrom(), progressCb)
+	if ctx.Err() == nil {
+		runtime.EventsEmit(a.ctx, "sync:quests_full:complete", result.Message)
+	}
+	return result, nil
+}
+
+func (a *App) runNpcSyncJob(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+	progressCb := func(current, total int, id int, name string) {
+		ctl.Progress(current, total)
+		ctl.Checkpoint(id)
+		runtime.EventsEmit(a.ctx, "sync:npc_full:progress", map[string]interface{}{
+			"current": current,
+			"total":   total,
+			"id":      id,
+		})
+	}
+
+	result := a.npcService.FullSyncNpcs(ctx, job.ResumeFrom(), job.Params.DelayMs, progressCb)
+	if len(result.Errors) > 0 && result.Updated == 0 {
+		fmt.Printf("Error syncing all NPCs: %s\n", result.Message)
+		runtime.EventsEmit(a.ctx, "sync:npc_full:error", result.Message)
+		return result, errors.New(result.Message)
+	}
+	if ctx.Err() == nil {
+		runtime.EventsEmit(a.ctx, "sync:npc_full:complete", "Full NPC sync complete")
+	}
+	return result, nil
+}
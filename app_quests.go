@@ -47,6 +47,16 @@ func (a *App) GetQuestDetail(entry int) (*database.QuestDetail, error) {
 	return q, nil
 }
 
+// GetQuestGraph returns the quest chain around a quest as nodes and edges
+func (a *App) GetQuestGraph(entry int) (*database.QuestGraph, error) {
+	graph, err := a.questRepo.GetQuestGraph(entry)
+	if err != nil {
+		fmt.Printf("Error getting quest graph [%d]: %v\n", entry, err)
+		return nil, err
+	}
+	return graph, nil
+}
+
 // GetQuestCategoryGroups returns all quest category groups (Eastern Kingdoms, Kalimdor, etc.)
 func (a *App) GetQuestCategoryGroups() []*database.QuestCategoryGroup {
 	fmt.Println("[API] GetQuestCategoryGroups called")
@@ -71,16 +81,16 @@ func (a *App) GetQuestCategoriesByGroup(groupID int) []*database.QuestCategoryEn
 	return cats
 }
 
-// GetQuestsByEnhancedCategory returns quests for an enhanced category
-func (a *App) GetQuestsByEnhancedCategory(categoryID int, nameFilter string) []*database.Quest {
-	fmt.Printf("[API] GetQuestsByEnhancedCategory called: %d, filter=%s\n", categoryID, nameFilter)
-	quests, err := a.questRepo.GetQuestsByEnhancedCategory(categoryID, nameFilter)
+// GetQuestsByEnhancedCategory returns a page of quests for an enhanced category
+func (a *App) GetQuestsByEnhancedCategory(categoryID int, nameFilter string, page database.PageQuery) *database.Page[*database.Quest] {
+	fmt.Printf("[API] GetQuestsByEnhancedCategory called: %d, filter=%s page=%+v\n", categoryID, nameFilter, page)
+	result, err := a.questRepo.GetQuestsByEnhancedCategory(categoryID, nameFilter, page)
 	if err != nil {
 		fmt.Printf("[API] Error getting quests: %v\n", err)
-		return []*database.Quest{}
+		return &database.Page[*database.Quest]{Items: []*database.Quest{}}
 	}
-	fmt.Printf("[API] Returning %d quests\n", len(quests))
-	return quests
+	fmt.Printf("[API] Returning %d of %d quests\n", len(result.Items), result.Total)
+	return result
 }
 
 // SyncQuestData syncs quest data from TurtleCraft
@@ -88,7 +98,7 @@ func (a *App) SyncQuestData(entry int) (*database.QuestDetail, error) {
 	fmt.Printf("[API] SyncQuestData called: %d\n", entry)
 
 	// 1. Scrape from TurtleCraft
-	data, err := a.scraper.ScrapeQuestData(entry)
+	data, err := a.scraper.ScrapeQuestData(a.ctx, entry)
 	if err != nil {
 		fmt.Printf("[API] Error scraping quest data: %v\n", err)
 		return nil, err
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"shelllab/backend/database"
+)
+
+// ============================================================================
+// Saved Search & Smart Collection APIs
+// ============================================================================
+
+// SaveSearch saves an AdvancedSearch filter under a name, or updates the
+// saved search with the given ID
+func (a *App) SaveSearch(search database.SavedSearch) (*database.SavedSearch, error) {
+	fmt.Printf("[API] SaveSearch called: id=%d, name='%s'\n", search.ID, search.Name)
+	return a.searchRepo.SaveSearch(&search)
+}
+
+// DeleteSavedSearch removes a saved search
+func (a *App) DeleteSavedSearch(id int) string {
+	fmt.Printf("[API] DeleteSavedSearch called: id=%d\n", id)
+	if err := a.searchRepo.DeleteSearch(id); err != nil {
+		return err.Error()
+	}
+	return "Saved search deleted"
+}
+
+// GetSavedSearches returns all saved searches
+func (a *App) GetSavedSearches() []*database.SavedSearch {
+	searches, err := a.searchRepo.GetSavedSearches()
+	if err != nil {
+		fmt.Printf("[API] GetSavedSearches error: %v\n", err)
+		return []*database.SavedSearch{}
+	}
+	return searches
+}
+
+// GetSmartCollections evaluates every saved search with its item count and
+// the number of items new since it was last viewed
+func (a *App) GetSmartCollections() []*database.SmartCollection {
+	fmt.Println("[API] GetSmartCollections called")
+	collections, err := a.searchRepo.GetSmartCollections()
+	if err != nil {
+		fmt.Printf("[API] GetSmartCollections error: %v\n", err)
+		return []*database.SmartCollection{}
+	}
+	return collections
+}
+
+// GetSmartCollection returns a page of the items of a saved search, marking
+// the ones new since it was last viewed
+func (a *App) GetSmartCollection(id, limit, offset int) (*database.SmartCollectionView, error) {
+	fmt.Printf("[API] GetSmartCollection called: id=%d, offset=%d\n", id, offset)
+	view, err := a.searchRepo.GetSmartCollection(id, limit, offset)
+	if err != nil || view == nil {
+		return view, err
+	}
+	view.Items = a.enrichItemsWithIcons(view.Items)
+	return view, nil
+}
+
+// MarkSmartCollectionViewed clears the new items of a smart collection
+func (a *App) MarkSmartCollectionViewed(id int) string {
+	if err := a.searchRepo.MarkViewed(id); err != nil {
+		fmt.Printf("[API] MarkSmartCollectionViewed error: %v\n", err)
+		return err.Error()
+	}
+	return "Marked as viewed"
+}
@@ -26,14 +26,14 @@ func (a *App) GetSpellSkillsByCategory(categoryID int) []*database.SpellSkill {
 	return skills
 }
 
-// GetSpellsBySkill returns spells for a skill
-func (a *App) GetSpellsBySkill(skillID int, nameFilter string) []*database.Spell {
-	spells, err := a.spellRepo.GetSpellsBySkill(skillID, nameFilter)
+// GetSpellsBySkill returns a page of spells for a skill
+func (a *App) GetSpellsBySkill(skillID int, nameFilter string, page database.PageQuery) *database.Page[*database.Spell] {
+	result, err := a.spellRepo.GetSpellsBySkill(skillID, nameFilter, page)
 	if err != nil {
 		fmt.Printf("[API] Error: %v\n", err)
-		return []*database.Spell{}
+		return &database.Page[*database.Spell]{Items: []*database.Spell{}}
 	}
-	return spells
+	return result
 }
 
 // GetSpellDetail returns full details for a spell
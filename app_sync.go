@@ -1,11 +1,11 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"shelllab/backend/database"
 	"shelllab/backend/services"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // ============================================================================
@@ -26,7 +26,7 @@ func (a *App) FixSingleItemIcon(itemID int) *FixMissingIconsResult {
 
 	iconFixService := services.NewIconFixService(a.db.DB(), filepath.Join(a.DataDir, "icons"))
 
-	success, iconName, err := iconFixService.FixSingleItem(a.db.DB(), itemID)
+	success, iconName, err := iconFixService.FixSingleItem(a.ctx, a.db.DB(), itemID)
 	if err != nil {
 		return &FixMissingIconsResult{
 			TotalMissing: 1,
@@ -58,6 +58,9 @@ func (a *App) FixSingleItemIcon(itemID int) *FixMissingIconsResult {
 func (a *App) FixMissingIcons(iconType string, maxItems int) *FixMissingIconsResult {
 	fmt.Printf("[API] FixMissingIcons called with type=%s, maxItems=%d\n", iconType, maxItems)
 
+	ctx, done := a.beginOperation(syncTypeIcons)
+	defer done()
+
 	iconFixService := services.NewIconFixService(a.db.DB(), filepath.Join(a.DataDir, "icons"))
 
 	var allMissing []services.MissingIconItem
@@ -96,13 +99,18 @@ func (a *App) FixMissingIcons(iconType string, maxItems int) *FixMissingIconsRes
 	fmt.Printf("Fixing %d %s icons...\n", len(itemsToFix), iconType)
 
 	for _, item := range itemsToFix {
+		if ctx.Err() != nil {
+			fmt.Println("[API] FixMissingIcons stopped")
+			break
+		}
+
 		var success bool
 		var iconName string
 
 		if iconType == "spell" {
-			success, iconName, err = iconFixService.FixSingleSpell(a.db.DB(), item.Entry)
+			success, iconName, err = iconFixService.FixSingleSpell(ctx, a.db.DB(), item.Entry)
 		} else {
-			success, iconName, err = iconFixService.FixSingleItem(a.db.DB(), item.Entry)
+			success, iconName, err = iconFixService.FixSingleItem(ctx, a.db.DB(), item.Entry)
 		}
 
 		if err != nil || !success {
@@ -147,10 +155,15 @@ func (a *App) CheckNewItems(maxChecks int, delayMs int) []services.RemoteItem {
 		delayMs = 200
 	}
 
-	a.syncService.ResetStop()
-	items, err := a.syncService.CheckNewItems(maxChecks, delayMs, nil)
+	ctx, done := a.beginOperation(services.JobTypeItems)
+	defer done()
+
+	items, err := a.syncService.CheckNewItems(ctx, maxChecks, delayMs, nil)
 	if err != nil {
+		// Keep whatever was found before the site became unreachable
 		fmt.Printf("[API] Error checking new items: %v\n", err)
+	}
+	if items == nil {
 		return []services.RemoteItem{}
 	}
 
@@ -170,10 +183,15 @@ func (a *App) CheckNewQuests(maxChecks int, delayMs int) []services.RemoteQuest
 		delayMs = 200
 	}
 
-	a.syncService.ResetStop()
-	quests, err := a.syncService.CheckNewQuests(maxChecks, delayMs, nil)
+	ctx, done := a.beginOperation(services.JobTypeQuests)
+	defer done()
+
+	quests, err := a.syncService.CheckNewQuests(ctx, maxChecks, delayMs, nil)
 	if err != nil {
+		// Keep whatever was found before the site became unreachable
 		fmt.Printf("[API] Error checking new quests: %v\n", err)
+	}
+	if quests == nil {
 		return []services.RemoteQuest{}
 	}
 
@@ -203,8 +221,10 @@ func (a *App) GetMissingAtlasLootItems(limit int) []services.MissingItem {
 func (a *App) SyncMissingAtlasLoot(maxItems int, delayMs int) *services.ImportResult {
 	fmt.Printf("[API] SyncMissingAtlasLoot called with maxItems=%d\n", maxItems)
 
-	a.syncService.ResetStop()
-	result, err := a.syncService.ImportMissingItems(maxItems, delayMs)
+	ctx, done := a.beginOperation(syncTypeAtlasLoot)
+	defer done()
+
+	result, err := a.syncService.ImportMissingItems(ctx, maxItems, delayMs)
 	if err != nil {
 		return &services.ImportResult{
 			Errors: []string{err.Error()},
@@ -217,11 +237,12 @@ func (a *App) SyncMissingAtlasLoot(maxItems int, delayMs int) *services.ImportRe
 func (a *App) SyncSingleItem(itemID int) *services.SyncItemResult {
 	fmt.Printf("[API] SyncSingleItem called for item %d\n", itemID)
 
-	return a.syncService.FetchAndImportItem(itemID)
+	return a.syncService.FetchAndImportItem(a.ctx, itemID)
 }
 
 // FullSyncItems re-syncs all Turtle items from turtlecraft.gg
-// startFrom: if > 0, resume sync from this ID (for progress recovery)
+// startFrom: if > 0, start the sync from this ID
+// Runs as a persisted job, see ListJobs/PauseJob/ResumeJob
 func (a *App) FullSyncItems(delayMs int, fixIcons bool, startFrom int) string {
 	fmt.Printf("[API] FullSyncItems called with delayMs=%d, fixIcons=%v, startFrom=%d\n", delayMs, fixIcons, startFrom)
 
@@ -229,29 +250,11 @@ func (a *App) FullSyncItems(delayMs int, fixIcons bool, startFrom int) string {
 		delayMs = 200
 	}
 
-	a.syncService.ResetStop()
-	iconDir := filepath.Join(a.DataDir, "icons")
-
-	go func() {
-		// Create progress callback that emits events to frontend
-		progressCb := func(current, total int, itemID int, itemName string) {
-			runtime.EventsEmit(a.ctx, "sync:progress", map[string]interface{}{
-				"current":  current,
-				"total":    total,
-				"itemId":   itemID,
-				"itemName": itemName,
-			})
-		}
-
-		result := a.syncService.FullSyncItems(delayMs, fixIcons, iconDir, startFrom, progressCb)
-		if len(result.Errors) > 0 && result.Updated == 0 {
-			runtime.EventsEmit(a.ctx, "sync:item_full:error", result.Message)
-		} else {
-			runtime.EventsEmit(a.ctx, "sync:item_full:complete", result.Message)
-		}
-	}()
-
-	return "Started"
+	return a.submitSyncJob(services.JobTypeItems, services.JobParams{
+		DelayMs:   delayMs,
+		FixIcons:  fixIcons,
+		StartFrom: startFrom,
+	})
 }
 
 // FullSyncSpells re-syncs all spells referenced by items
@@ -262,25 +265,11 @@ func (a *App) FullSyncSpells(delayMs int, fixIcons bool, startFrom int) string {
 		delayMs = 200
 	}
 
-	a.syncService.ResetStop()
-	iconDir := filepath.Join(a.DataDir, "icons")
-
-	go func() {
-		// Create progress callback that emits events to frontend
-		progressCb := func(current, total int, itemID int, itemName string) {
-			runtime.EventsEmit(a.ctx, "sync:spells:progress", map[string]interface{}{
-				"current":  current,
-				"total":    total,
-				"itemId":   itemID,
-				"itemName": itemName,
-			})
-		}
-
-		result := a.syncService.FullSyncSpells(delayMs, fixIcons, iconDir, startFrom, progressCb)
-		runtime.EventsEmit(a.ctx, "sync:spells_full:complete", result.Message)
-	}()
-
-	return "Started"
+	return a.submitSyncJob(services.JobTypeSpells, services.JobParams{
+		DelayMs:   delayMs,
+		FixIcons:  fixIcons,
+		StartFrom: startFrom,
+	})
 }
 
 // FullSyncQuests re-syncs all quests
@@ -291,41 +280,156 @@ func (a *App) FullSyncQuests(delayMs int, startFrom int) string {
 		delayMs = 200
 	}
 
-	a.syncService.ResetStop()
-
-	go func() {
-		// Create progress callback that emits events to frontend
-		progressCb := func(current, total int, itemID int, itemName string) {
-			runtime.EventsEmit(a.ctx, "sync:quests:progress", map[string]interface{}{
-				"current":  current,
-				"total":    total,
-				"itemId":   itemID,
-				"itemName": itemName,
-			})
-		}
-
-		result := a.syncService.FullSyncQuests(delayMs, startFrom, progressCb)
-		runtime.EventsEmit(a.ctx, "sync:quests_full:complete", result.Message)
-	}()
-
-	return "Started"
+	return a.submitSyncJob(services.JobTypeQuests, services.JobParams{
+		DelayMs:   delayMs,
+		StartFrom: startFrom,
+	})
 }
 
 func (a *App) SyncSingleSpell(spellID int) *services.SyncSpellResult {
 	fmt.Printf("[API] SyncSingleSpell called for spell %d\n", spellID)
 
 	iconDir := filepath.Join(a.DataDir, "icons")
-	return a.syncService.FetchAndImportSpell(spellID, iconDir)
+	return a.syncService.FetchAndImportSpell(a.ctx, spellID, iconDir)
 }
 
-// StopSync requests all ongoing sync processes to stop
+// StopSync pauses the running sync job and cancels every direct sync call
 func (a *App) StopSync() string {
 	fmt.Println("[API] StopSync called")
-	if a.syncService != nil {
-		a.syncService.RequestStop()
+	if a.jobManager != nil {
+		a.jobManager.PauseRunning()
 	}
-	if a.npcService != nil {
-		a.npcService.RequestStop()
+	a.cancelOperations("")
+	return "Stop requested"
+}
+
+// StopSyncType stops only one kind of sync ("items", "spells", "quests", "npcs", "atlasloot", "icons")
+// Its job is paused so it can be resumed later
+func (a *App) StopSyncType(syncType string) string {
+	fmt.Printf("[API] StopSyncType called for %s\n", syncType)
+	if a.jobManager != nil {
+		a.jobManager.PauseType(syncType)
 	}
+	a.cancelOperations(syncType)
 	return "Stop requested"
 }
+
+// ============================================================================
+// HTTP Cache APIs
+// ============================================================================
+
+// GetHTTPCacheStats returns statistics about the scraped page cache
+func (a *App) GetHTTPCacheStats() services.HTTPCacheStats {
+	fmt.Println("[API] GetHTTPCacheStats called")
+	if a.httpCache == nil {
+		return services.HTTPCacheStats{}
+	}
+	return a.httpCache.Stats()
+}
+
+// SetHTTPCacheMode switches the page cache between "off", "normal" and "replay"
+// In replay mode syncs re-parse recorded pages without touching the network
+func (a *App) SetHTTPCacheMode(mode string) string {
+	fmt.Printf("[API] SetHTTPCacheMode called with mode=%s\n", mode)
+	if a.httpCache == nil {
+		return "Cache not initialized"
+	}
+
+	switch services.CacheMode(mode) {
+	case services.CacheModeOff, services.CacheModeNormal, services.CacheModeReplay:
+		a.httpCache.SetMode(services.CacheMode(mode))
+		return "Cache mode set to " + mode
+	default:
+		return "Unknown cache mode: " + mode
+	}
+}
+
+// ClearHTTPCache deletes every cached page
+func (a *App) ClearHTTPCache() string {
+	fmt.Println("[API] ClearHTTPCache called")
+	if a.httpCache == nil {
+		return "Cache not initialized"
+	}
+	if err := a.httpCache.Clear(); err != nil {
+		return fmt.Sprintf("Failed to clear cache: %v", err)
+	}
+	return "Cache cleared"
+}
+
+// ============================================================================
+// Read Cache APIs
+// ============================================================================
+
+// GetReadCacheStats returns the size and hit counts of the in-memory caches
+// of tooltips, item summaries and spell lookups
+func (a *App) GetReadCacheStats() []database.CacheStats {
+	fmt.Println("[API] GetReadCacheStats called")
+	if a.db == nil {
+		return []database.CacheStats{}
+	}
+	return database.GetCacheStats(a.db)
+}
+
+// ClearReadCache empties the in-memory read caches
+func (a *App) ClearReadCache() string {
+	fmt.Println("[API] ClearReadCache called")
+	if a.db == nil {
+		return "Database not initialized"
+	}
+	database.PurgeCache(a.db)
+	return "Read cache cleared"
+}
+
+// ============================================================================
+// Change Log APIs
+// ============================================================================
+
+// GetRecentChanges returns field-level changes recorded by syncs, newest first
+func (a *App) GetRecentChanges(filter database.ChangeLogFilter) []*database.ChangeLogEntry {
+	fmt.Printf("[API] GetRecentChanges called: type=%s id=%d since=%s\n", filter.EntityType, filter.EntityID, filter.Since)
+	changes, err := a.changeLogRepo.GetRecentChanges(filter)
+	if err != nil {
+		fmt.Printf("[API] GetRecentChanges error: %v\n", err)
+		return []*database.ChangeLogEntry{}
+	}
+	if changes == nil {
+		return []*database.ChangeLogEntry{}
+	}
+	return changes
+}
+
+// GeneratePatchNotes renders recorded sync changes in [since, until) as patch notes
+// format is "markdown" (default), "html" or "json"
+func (a *App) GeneratePatchNotes(since, until, format string) string {
+	fmt.Printf("[API] GeneratePatchNotes called: since=%s until=%s format=%s\n", since, until, format)
+	notes, err := a.patchNotes.FromChangeLog(since, until)
+	if err != nil {
+		return fmt.Sprintf("Failed to generate patch notes: %v", err)
+	}
+	return renderPatchNotes(notes, format)
+}
+
+// GeneratePatchNotesFromSnapshot renders the differences between an older shelllab.db and the current database
+func (a *App) GeneratePatchNotesFromSnapshot(oldDBPath, format string) string {
+	fmt.Printf("[API] GeneratePatchNotesFromSnapshot called: old=%s format=%s\n", oldDBPath, format)
+	notes, err := a.patchNotes.FromSnapshots(oldDBPath, "")
+	if err != nil {
+		return fmt.Sprintf("Failed to generate patch notes: %v", err)
+	}
+	return renderPatchNotes(notes, format)
+}
+
+func renderPatchNotes(notes *services.PatchNotes, format string) string {
+	switch format {
+	case "html":
+		return notes.HTML()
+	case "json":
+		data, err := json.MarshalIndent(notes, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("Failed to encode patch notes: %v", err)
+		}
+		return string(data)
+	default:
+		return notes.Markdown()
+	}
+}
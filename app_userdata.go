@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"shelllab/backend/services"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ============================================================================
+// User Data APIs
+// ============================================================================
+
+// backupDir is where automatic and manual user data backups are kept
+func (a *App) backupDir() string {
+	return filepath.Join(a.DataDir, "backups")
+}
+
+// initUserData starts periodic backups of user.db
+func (a *App) initUserData() {
+	a.userData = services.NewUserDataService(a.db.DB())
+	a.userData.StartAutoBackup(a.ctx, a.backupDir(), services.DefaultBackupInterval, services.DefaultBackupKeep)
+}
+
+// ExportUserData writes favorites and other user tables to a JSON archive.
+// With an empty path a save dialog is shown.
+func (a *App) ExportUserData(path string) string {
+	fmt.Printf("[API] ExportUserData called: %s\n", path)
+
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Export User Data",
+			DefaultFilename: "shelllab-userdata.json",
+			Filters:         []runtime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+		})
+		if err != nil {
+			return fmt.Sprintf("Error: %v", err)
+		}
+		if path == "" {
+			return "Export cancelled"
+		}
+	}
+
+	data, err := a.userData.ExportJSON()
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return fmt.Sprintf("Exported user data to %s", path)
+}
+
+// ImportUserData reads an archive written by ExportUserData. Mode is "merge"
+// (default) or "replace"; replace backs up the current data first.
+// With an empty path an open dialog is shown.
+func (a *App) ImportUserData(path string, mode string) *services.UserDataImportResult {
+	fmt.Printf("[API] ImportUserData called: %s (%s)\n", path, mode)
+
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "Import User Data",
+			Filters: []runtime.FileFilter{{DisplayName: "JSON (*.json)", Pattern: "*.json"}},
+		})
+		if err != nil {
+			return &services.UserDataImportResult{Message: err.Error()}
+		}
+		if path == "" {
+			return &services.UserDataImportResult{Message: "Import cancelled"}
+		}
+	}
+	return a.importUserDataFile(path, mode)
+}
+
+// BackupUserData takes a backup now
+func (a *App) BackupUserData() string {
+	fmt.Println("[API] BackupUserData called")
+
+	path, err := a.userData.Backup(a.backupDir(), services.DefaultBackupKeep)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return fmt.Sprintf("Backed up user data to %s", path)
+}
+
+// GetUserDataBackups lists the backups, newest first
+func (a *App) GetUserDataBackups() []services.UserDataBackup {
+	backups, err := a.userData.ListBackups(a.backupDir())
+	if err != nil {
+		fmt.Printf("[API] GetUserDataBackups error: %v\n", err)
+		return []services.UserDataBackup{}
+	}
+	return backups
+}
+
+// RestoreUserDataBackup replaces the user data with a backup from GetUserDataBackups
+func (a *App) RestoreUserDataBackup(name string) *services.UserDataImportResult {
+	fmt.Printf("[API] RestoreUserDataBackup called: %s\n", name)
+	return a.importUserDataFile(filepath.Join(a.backupDir(), filepath.Base(name)), services.ImportModeReplace)
+}
+
+func (a *App) importUserDataFile(path, mode string) *services.UserDataImportResult {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &services.UserDataImportResult{Message: err.Error()}
+	}
+	archive, err := services.ParseArchive(data)
+	if err != nil {
+		return &services.UserDataImportResult{Message: err.Error()}
+	}
+
+	// Not pruned: the oldest backup may be the very file being restored
+	if mode == services.ImportModeReplace {
+		if _, err := a.userData.Backup(a.backupDir(), 0); err != nil {
+			return &services.UserDataImportResult{Message: fmt.Sprintf("Backup before replace failed: %v", err)}
+		}
+	}
+
+	result, err := a.userData.Import(archive, mode)
+	if err != nil {
+		return &services.UserDataImportResult{Mode: mode, Message: err.Error()}
+	}
+	// Archives from before wishlists have no default list, which replace just cleared
+	if err := a.wishlistRepo.InitSchema(); err != nil {
+		fmt.Printf("[API] Failed to restore the default wishlist: %v\n", err)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"shelllab/backend/database"
+)
+
+// ============================================================================
+// Wishlist APIs
+// ============================================================================
+
+// migrateFavorites moves favorites kept by older versions onto wishlists
+func (a *App) migrateFavorites() {
+	if moved, err := a.wishlistRepo.MigrateFavorites(); err != nil {
+		fmt.Printf("ERROR: Failed to move favorites to wishlists: %v\n", err)
+	} else if moved > 0 {
+		fmt.Printf("✓ Moved %d favorites to wishlists\n", moved)
+	}
+}
+
+// GetWishlists returns all wishlists with their item counts
+func (a *App) GetWishlists() []*database.Wishlist {
+	lists, err := a.wishlistRepo.GetWishlists()
+	if err != nil {
+		fmt.Printf("[API] GetWishlists error: %v\n", err)
+		return []*database.Wishlist{}
+	}
+	return lists
+}
+
+// CreateWishlist adds an empty wishlist
+func (a *App) CreateWishlist(name, description string) string {
+	fmt.Printf("[API] CreateWishlist called: '%s'\n", name)
+	if err := a.wishlistRepo.CreateWishlist(name, description); err != nil {
+		return err.Error()
+	}
+	return "Wishlist created"
+}
+
+// UpdateWishlist renames a wishlist and sets its description
+func (a *App) UpdateWishlist(id int, name, description string) string {
+	fmt.Printf("[API] UpdateWishlist called: id=%d, name='%s'\n", id, name)
+	if err := a.wishlistRepo.UpdateWishlist(id, name, description); err != nil {
+		return err.Error()
+	}
+	return "Wishlist updated"
+}
+
+// DeleteWishlist removes a wishlist and its items; the default list is emptied
+func (a *App) DeleteWishlist(id int) string {
+	fmt.Printf("[API] DeleteWishlist called: id=%d\n", id)
+	if err := a.wishlistRepo.DeleteWishlist(id); err != nil {
+		return err.Error()
+	}
+	return "Wishlist deleted"
+}
+
+// GetWishlistItems returns the wishlist items matching filter
+func (a *App) GetWishlistItems(filter database.WishlistItemFilter) []*database.WishlistItem {
+	items, err := a.wishlistRepo.GetItems(filter)
+	if err != nil {
+		fmt.Printf("[API] GetWishlistItems error: %v\n", err)
+		return []*database.WishlistItem{}
+	}
+	return items
+}
+
+// AddWishlistItem puts an item on a wishlist, or updates it if it is already
+// there for the same character
+func (a *App) AddWishlistItem(item database.WishlistItem) (*database.WishlistItem, error) {
+	fmt.Printf("[API] AddWishlistItem called: item=%d, list='%s', character='%s'\n", item.ItemEntry, item.Wishlist, item.Character)
+	return a.wishlistRepo.AddItem(&item)
+}
+
+// UpdateWishlistItem changes the list, character, priority, notes and source
+// of a wishlist item
+func (a *App) UpdateWishlistItem(item database.WishlistItem) string {
+	fmt.Printf("[API] UpdateWishlistItem called: id=%d\n", item.ID)
+	if err := a.wishlistRepo.UpdateItem(&item); err != nil {
+		return err.Error()
+	}
+	return "Wishlist item updated"
+}
+
+// SetWishlistItemStatus sets the status of a wishlist item (0=Wanted, 1=Obtained, 2=Abandoned)
+func (a *App) SetWishlistItemStatus(id int, status int) string {
+	fmt.Printf("[API] SetWishlistItemStatus called: id=%d, status=%d\n", id, status)
+	if err := a.wishlistRepo.SetItemStatus(id, status); err != nil {
+		return err.Error()
+	}
+	return "Status updated"
+}
+
+// RemoveWishlistItem takes an item off its wishlist
+func (a *App) RemoveWishlistItem(id int) string {
+	fmt.Printf("[API] RemoveWishlistItem called: id=%d\n", id)
+	if err := a.wishlistRepo.RemoveItem(id); err != nil {
+		return err.Error()
+	}
+	return "Removed from wishlist"
+}
+
+// GetWishlistCharacters returns the characters named on wishlist items
+func (a *App) GetWishlistCharacters() []string {
+	names, err := a.wishlistRepo.GetCharacters()
+	if err != nil {
+		fmt.Printf("[API] GetWishlistCharacters error: %v\n", err)
+		return []string{}
+	}
+	if names == nil {
+		names = []string{}
+	}
+	return names
+}
@@ -0,0 +1,413 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"shelllab/backend/database"
+)
+
+// searchLimit is the number of results per type returned by /api/search
+const searchLimit = 50
+
+func (s *Server) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api", s.handleIndex)
+	mux.HandleFunc("GET /api/{$}", s.handleIndex)
+
+	// Items
+	mux.HandleFunc("GET /api/items", s.handleItems)
+	mux.HandleFunc("GET /api/items/classes", s.handleItemClasses)
+	mux.HandleFunc("GET /api/items/{id}", s.handleItem)
+	mux.HandleFunc("GET /api/items/{id}/tooltip", s.handleTooltip)
+	mux.HandleFunc("GET /api/itemsets", s.handleItemSets)
+	mux.HandleFunc("GET /api/itemsets/{id}", s.handleItemSet)
+
+	// Creatures and loot
+	mux.HandleFunc("GET /api/creatures", s.handleCreatures)
+	mux.HandleFunc("GET /api/creatures/types", s.handleCreatureTypes)
+	mux.HandleFunc("GET /api/creatures/{id}", s.handleCreature)
+	mux.HandleFunc("GET /api/creatures/{id}/loot", s.handleCreatureLoot)
+	mux.HandleFunc("GET /api/loot/{id}/tree", s.handleLootTree)
+
+	// Quests
+	mux.HandleFunc("GET /api/quests", s.handleQuests)
+	mux.HandleFunc("GET /api/quests/categories", s.handleQuestCategories)
+	mux.HandleFunc("GET /api/quests/{id}", s.handleQuest)
+	mux.HandleFunc("GET /api/quests/{id}/graph", s.handleQuestGraph)
+
+	// Spells
+	mux.HandleFunc("GET /api/spells", s.handleSpells)
+	mux.HandleFunc("GET /api/spells/categories", s.handleSpellCategories)
+	mux.HandleFunc("GET /api/spells/categories/{id}/skills", s.handleSpellSkills)
+	mux.HandleFunc("GET /api/spells/{id}", s.handleSpell)
+
+	// Objects
+	mux.HandleFunc("GET /api/objects", s.handleObjects)
+	mux.HandleFunc("GET /api/objects/types", s.handleObjectTypes)
+	mux.HandleFunc("GET /api/objects/{id}", s.handleObject)
+
+	// Factions
+	mux.HandleFunc("GET /api/factions", s.handleFactions)
+	mux.HandleFunc("GET /api/factions/{id}", s.handleFaction)
+
+	// AtlasLoot: categories > modules > tables > loot
+	mux.HandleFunc("GET /api/atlasloot", s.handleAtlasCategories)
+	mux.HandleFunc("GET /api/atlasloot/{category}", s.handleAtlasModules)
+	mux.HandleFunc("GET /api/atlasloot/{category}/{module}", s.handleAtlasTables)
+	mux.HandleFunc("GET /api/atlasloot/{category}/{module}/{table}", s.handleAtlasLoot)
+
+	mux.HandleFunc("GET /api/search", s.handleSearch)
+
+	mux.HandleFunc("GET /api/icons/sprites", s.handleIconSprites)
+	mux.HandleFunc("GET /icons/{name}", s.handleIcon)
+	mux.HandleFunc("GET /icons/atlas/{file}", s.handleAtlasSheet)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, map[string]interface{}{
+		"name": "ShellLab API",
+		"endpoints": []string{
+			"/api/items?q=&quality=&class=&subclass=&slot=&minLevel=&maxLevel=&stat=&effect=&sort=&order=",
+			"/api/items/classes", "/api/items/{id}", "/api/items/{id}/tooltip",
+			"/api/itemsets", "/api/itemsets/{id}",
+			"/api/creatures?type=&name= | ?q=", "/api/creatures/types",
+			"/api/creatures/{id}", "/api/creatures/{id}/loot", "/api/loot/{id}/tree",
+			"/api/quests?category= | ?q=", "/api/quests/categories", "/api/quests/{id}",
+			"/api/quests/{id}/graph",
+			"/api/spells?skill=&name= | ?q=", "/api/spells/categories",
+			"/api/spells/categories/{id}/skills", "/api/spells/{id}",
+			"/api/objects?type=&name= | ?q=", "/api/objects/types", "/api/objects/{id}",
+			"/api/factions", "/api/factions/{id}",
+			"/api/atlasloot", "/api/atlasloot/{category}",
+			"/api/atlasloot/{category}/{module}", "/api/atlasloot/{category}/{module}/{table}",
+			"/api/search?q=",
+			"/api/icons/sprites?names=&size=",
+			"/icons/{name}", "/icons/atlas/{file}",
+		},
+	})
+}
+
+// ============================================================================
+// Items
+// ============================================================================
+
+func (s *Server) handleItems(w http.ResponseWriter, r *http.Request) {
+	query := browseQuery(r)
+	limit, offset := query.Limit, query.Offset
+	q := r.URL.Query()
+
+	filter := database.SearchFilter{
+		Query:         q.Get("q"),
+		Quality:       queryInts(r, "quality"),
+		Class:         queryInts(r, "class"),
+		SubClass:      queryInts(r, "subclass"),
+		InventoryType: queryInts(r, "slot"),
+		MinLevel:      queryInt(r, "minLevel", 0),
+		MaxLevel:      queryInt(r, "maxLevel", 0),
+		MinReqLevel:   queryInt(r, "minReqLevel", 0),
+		MaxReqLevel:   queryInt(r, "maxReqLevel", 0),
+		Stats:         queryInts(r, "stat"),
+		Effect:        q.Get("effect"),
+		Sort:          query.Sort,
+		Desc:          query.Desc,
+		Limit:         limit,
+		Offset:        offset,
+	}
+	result, err := s.items.AdvancedSearch(filter)
+	if errors.Is(err, database.ErrInvalidSort) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// AdvancedSearch caps the page size at 200
+	writeJSON(w, r, newPage(result.Items, result.TotalCount, min(limit, 200), offset))
+}
+
+func (s *Server) handleItemClasses(w http.ResponseWriter, r *http.Request) {
+	classes, err := s.items.GetItemClasses()
+	writeList(w, r, classes, err)
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.items.GetItemDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+func (s *Server) handleTooltip(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		tooltip, err := s.items.GetTooltipData(id)
+		writeResult(w, r, tooltip, err)
+	}
+}
+
+func (s *Server) handleItemSets(w http.ResponseWriter, r *http.Request) {
+	sets, err := s.items.GetItemSets()
+	writeList(w, r, sets, err)
+}
+
+func (s *Server) handleItemSet(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.items.GetItemSetDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+// ============================================================================
+// Creatures
+// ============================================================================
+
+func (s *Server) handleCreatures(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Get("q") != "":
+		creatures, err := s.creatures.SearchCreatures(q.Get("q"), MaxLimit)
+		writeList(w, r, creatures, err)
+		return
+	case q.Get("type") == "":
+		writeError(w, http.StatusBadRequest, "q or type is required")
+		return
+	}
+
+	query := browseQuery(r)
+	page, err := s.creatures.GetCreaturesByType(queryInt(r, "type", 0), q.Get("name"), query)
+	writeBrowse(w, r, page, query, err)
+}
+
+func (s *Server) handleCreatureTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := s.creatures.GetCreatureTypes()
+	writeList(w, r, types, err)
+}
+
+func (s *Server) handleCreature(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.creatures.GetCreatureDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+func (s *Server) handleCreatureLoot(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		loot, err := s.loot.GetCreatureLoot(id)
+		writeList(w, r, loot, err)
+	}
+}
+
+func (s *Server) handleLootTree(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		tree, err := s.loot.GetLootTree(id)
+		writeResult(w, r, tree, err)
+	}
+}
+
+// ============================================================================
+// Quests
+// ============================================================================
+
+func (s *Server) handleQuests(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Get("q") != "":
+		quests, err := s.quests.SearchQuests(q.Get("q"))
+		writeList(w, r, quests, err)
+	case q.Get("category") != "":
+		quests, err := s.quests.GetQuestsByCategory(queryInt(r, "category", 0))
+		writeList(w, r, quests, err)
+	default:
+		writeError(w, http.StatusBadRequest, "q or category is required")
+	}
+}
+
+func (s *Server) handleQuestCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.quests.GetQuestCategories()
+	writeList(w, r, categories, err)
+}
+
+func (s *Server) handleQuest(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.quests.GetQuestDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+func (s *Server) handleQuestGraph(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		graph, err := s.quests.GetQuestGraph(id)
+		writeResult(w, r, graph, err)
+	}
+}
+
+// ============================================================================
+// Spells
+// ============================================================================
+
+func (s *Server) handleSpells(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Get("q") != "":
+		spells, err := s.spells.SearchSpells(q.Get("q"))
+		writeList(w, r, spells, err)
+	case q.Get("skill") != "":
+		query := browseQuery(r)
+		page, err := s.spells.GetSpellsBySkill(queryInt(r, "skill", 0), q.Get("name"), query)
+		writeBrowse(w, r, page, query, err)
+	default:
+		writeError(w, http.StatusBadRequest, "q or skill is required")
+	}
+}
+
+func (s *Server) handleSpellCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.spells.GetSpellSkillCategories()
+	writeList(w, r, categories, err)
+}
+
+func (s *Server) handleSpellSkills(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		skills, err := s.spells.GetSpellSkillsByCategory(id)
+		writeList(w, r, skills, err)
+	}
+}
+
+func (s *Server) handleSpell(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		writeResult(w, r, s.spells.GetSpellDetail(id), nil)
+	}
+}
+
+// ============================================================================
+// Objects
+// ============================================================================
+
+func (s *Server) handleObjects(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	switch {
+	case q.Get("q") != "":
+		objects, err := s.objects.SearchObjects(q.Get("q"))
+		writeList(w, r, objects, err)
+	case q.Get("type") != "":
+		query := browseQuery(r)
+		page, err := s.objects.GetObjectsByType(queryInt(r, "type", 0), q.Get("name"), query)
+		writeBrowse(w, r, page, query, err)
+	default:
+		writeError(w, http.StatusBadRequest, "q or type is required")
+	}
+}
+
+func (s *Server) handleObjectTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := s.objects.GetObjectTypes()
+	writeList(w, r, types, err)
+}
+
+func (s *Server) handleObject(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.objects.GetObjectDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+// ============================================================================
+// Factions
+// ============================================================================
+
+func (s *Server) handleFactions(w http.ResponseWriter, r *http.Request) {
+	factions, err := s.factions.GetFactions()
+	writeList(w, r, factions, err)
+}
+
+func (s *Server) handleFaction(w http.ResponseWriter, r *http.Request) {
+	if id, ok := pathID(w, r, "id"); ok {
+		detail, err := s.factions.GetFactionDetail(id)
+		writeResult(w, r, detail, err)
+	}
+}
+
+// ============================================================================
+// AtlasLoot
+// ============================================================================
+
+func (s *Server) handleAtlasCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.atlasLoot.GetCategories()
+	writeList(w, r, categories, err)
+}
+
+func (s *Server) handleAtlasModules(w http.ResponseWriter, r *http.Request) {
+	modules, err := s.atlasLoot.GetModules(r.PathValue("category"))
+	writeList(w, r, modules, err)
+}
+
+func (s *Server) handleAtlasTables(w http.ResponseWriter, r *http.Request) {
+	tables, err := s.atlasLoot.GetTables(r.PathValue("category"), r.PathValue("module"))
+	writeList(w, r, tables, err)
+}
+
+func (s *Server) handleAtlasLoot(w http.ResponseWriter, r *http.Request) {
+	loot, err := s.atlasLoot.GetLootItems(r.PathValue("category"), r.PathValue("module"), r.PathValue("table"))
+	writeList(w, r, loot, err)
+}
+
+// ============================================================================
+// Search
+// ============================================================================
+
+// SearchResults groups /api/search matches by type
+type SearchResults struct {
+	Items     []*database.Item       `json:"items"`
+	Creatures []*database.Creature   `json:"creatures"`
+	Quests    []*database.Quest      `json:"quests"`
+	Spells    []*database.Spell      `json:"spells"`
+	Objects   []*database.GameObject `json:"objects"`
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	results := &SearchResults{}
+	var err error
+	if results.Items, err = s.items.SearchItems(q, searchLimit); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// The remaining types are best effort, like the app's global search
+	results.Creatures, _ = s.creatures.SearchCreatures(q, searchLimit)
+	results.Spells, _ = s.spells.SearchSpells(q)
+	results.Objects, _ = s.objects.SearchObjects(q)
+
+	if id, err := strconv.Atoi(q); err == nil && id > 0 {
+		if quest, _ := s.quests.GetQuestByID(id); quest != nil && quest.Entry > 0 {
+			results.Quests = append(results.Quests, quest)
+		}
+	}
+	quests, _ := s.quests.SearchQuests(q)
+	for _, quest := range quests {
+		if len(results.Quests) == 0 || results.Quests[0].Entry != quest.Entry {
+			results.Quests = append(results.Quests, quest)
+		}
+	}
+
+	results.Items = truncate(results.Items, searchLimit)
+	results.Creatures = truncate(results.Creatures, searchLimit)
+	results.Spells = truncate(results.Spells, searchLimit)
+	results.Objects = truncate(results.Objects, searchLimit)
+	results.Quests = truncate(results.Quests, searchLimit)
+	writeJSON(w, r, results)
+}
+
+func truncate[T any](items []T, n int) []T {
+	if items == nil {
+		return []T{}
+	}
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
@@ -0,0 +1,351 @@
+// Package api serves the game database as a read-only JSON REST API for
+// scripts, bots and spreadsheets
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/database"
+	"shelllab/backend/media"
+)
+
+const (
+	// DefaultLimit is the page size when ?limit is not given
+	DefaultLimit = 100
+	// MaxLimit caps ?limit
+	MaxLimit = 1000
+)
+
+// Page is the envelope for list endpoints
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
+
+// Server exposes the repositories over HTTP
+type Server struct {
+	items     *database.ItemRepository
+	creatures *database.CreatureRepository
+	quests    *database.QuestRepository
+	spells    *database.SpellRepository
+	loot      *database.LootRepository
+	factions  *database.FactionRepository
+	objects   *database.GameObjectRepository
+	atlasLoot *database.AtlasLootRepository
+
+	iconDir    string
+	atlas      *media.Atlas
+	corsOrigin string
+}
+
+// NewServer creates a server over db. Icons are served from iconDir; corsOrigin
+// is sent as Access-Control-Allow-Origin (empty disables CORS headers).
+func NewServer(db *database.SQLiteDB, iconDir, corsOrigin string) *Server {
+	return &Server{
+		items:      database.NewItemRepository(db),
+		creatures:  database.NewCreatureRepository(db),
+		quests:     database.NewQuestRepository(db),
+		spells:     database.NewSpellRepository(db),
+		loot:       database.NewLootRepository(db),
+		factions:   database.NewFactionRepository(db),
+		objects:    database.NewGameObjectRepository(db),
+		atlasLoot:  database.NewAtlasLootRepository(db),
+		iconDir:    iconDir,
+		atlas:      media.NewAtlas(iconDir),
+		corsOrigin: corsOrigin,
+	}
+}
+
+// Handler returns the HTTP handler with all routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.routes(mux)
+	return s.logRequests(s.cors(mux))
+}
+
+// ============================================================================
+// Middleware
+// ============================================================================
+
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.corsOrigin != "" {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", s.corsOrigin)
+			h.Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "If-None-Match")
+			h.Set("Access-Control-Expose-Headers", "ETag")
+			if s.corsOrigin != "*" {
+				h.Add("Vary", "Origin")
+			}
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		fmt.Printf("[HTTP] %s %s %d %s\n", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// ============================================================================
+// Responses
+// ============================================================================
+
+// writeJSON sends v with an ETag of its content and answers 304 when the
+// client already has it
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	body := buf.Bytes()
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", "no-cache")
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// writeResult sends v, mapping missing rows to 404
+func writeResult[T any](w http.ResponseWriter, r *http.Request, v *T, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), err == nil && v == nil:
+		writeError(w, http.StatusNotFound, "not found")
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, r, v)
+	}
+}
+
+// writeList sends a list that the repository returns in full, paginated here
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T, err error) {
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	limit, offset := pageParams(r)
+	total := len(items)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	writeJSON(w, r, newPage(items[start:end], total, limit, offset))
+}
+
+// writeBrowse sends a page from a browse repository; unsupported sort keys
+// are the client's fault
+func writeBrowse[T any](w http.ResponseWriter, r *http.Request, page *database.Page[T], query database.PageQuery, err error) {
+	switch {
+	case errors.Is(err, database.ErrInvalidSort), errors.Is(err, database.ErrInvalidCursor):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, r, newPage(page.Items, page.Total, query.Limit, query.Offset))
+	}
+}
+
+func newPage[T any](items []T, total, limit, offset int) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items:   items,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(items) < total,
+	}
+}
+
+// ============================================================================
+// Request parameters
+// ============================================================================
+
+// pageParams reads ?limit and ?offset
+func pageParams(r *http.Request) (limit, offset int) {
+	limit = queryInt(r, "limit", DefaultLimit)
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	offset = queryInt(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// browseQuery reads ?limit, ?offset, ?sort and ?order=desc
+func browseQuery(r *http.Request) database.PageQuery {
+	limit, offset := pageParams(r)
+	q := r.URL.Query()
+	return database.PageQuery{
+		Limit:  limit,
+		Offset: offset,
+		Sort:   q.Get("sort"),
+		Desc:   strings.EqualFold(q.Get("order"), "desc"),
+	}
+}
+
+func queryInt(r *http.Request, name string, def int) int {
+	if v, err := strconv.Atoi(r.URL.Query().Get(name)); err == nil {
+		return v
+	}
+	return def
+}
+
+// queryInts reads a comma separated list like ?quality=3,4
+func queryInts(r *http.Request, name string) []int {
+	var out []int
+	for _, part := range strings.Split(r.URL.Query().Get(name), ",") {
+		if v, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// pathID reads an integer path value, writing 400 when it is not one
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, r.PathValue(name)))
+		return 0, false
+	}
+	return id, true
+}
+
+// ============================================================================
+// Icons
+// ============================================================================
+
+// iconExtensions are tried in order for names given without an extension
+var iconExtensions = []string{".png", ".jpg", ".jpeg"}
+
+func (s *Server) handleIcon(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(filepath.Base(r.PathValue("name")))
+	if name == "." || name == ".." || strings.HasPrefix(name, ".") {
+		writeError(w, http.StatusBadRequest, "invalid icon name")
+		return
+	}
+
+	candidates := []string{name}
+	if filepath.Ext(name) == "" {
+		candidates = nil
+		for _, ext := range iconExtensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+
+	for _, file := range candidates {
+		path := filepath.Join(s.iconDir, file)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			// Icons never change once downloaded
+			w.Header().Set("Cache-Control", "public, max-age=604800")
+			http.ServeFile(w, r, path)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "icon not found")
+}
+
+// handleIconSprites returns atlas coordinates for ?names=a,b at ?size=
+func (s *Server) handleIconSprites(w http.ResponseWriter, r *http.Request) {
+	size := queryInt(r, "size", media.AtlasSizes[len(media.AtlasSizes)-1])
+	if !media.ValidAtlasSize(size) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("size must be one of %v", media.AtlasSizes))
+		return
+	}
+
+	var names []string
+	for _, name := range strings.Split(r.URL.Query().Get("names"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	writeJSON(w, r, s.atlas.Sprites(names, size, "/icons/atlas/"))
+}
+
+func (s *Server) handleAtlasSheet(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	if file != filepath.Base(file) || strings.HasPrefix(file, ".") {
+		writeError(w, http.StatusBadRequest, "invalid atlas file")
+		return
+	}
+	path := filepath.Join(s.atlas.Dir(), file)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		writeError(w, http.StatusNotFound, "atlas not found")
+		return
+	}
+	// Sprite URLs carry the atlas version, so sheets can be cached like icons
+	w.Header().Set("Cache-Control", "public, max-age=604800")
+	http.ServeFile(w, r, path)
+}
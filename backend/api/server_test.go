@@ -0,0 +1,310 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"shelllab/backend/api"
+	"shelllab/backend/database"
+)
+
+// wolves are five beasts (type 1) and one humanoid that must never show up
+const wolves = `INSERT INTO creature_template (entry, name, level_min, level_max, type) VALUES
+	(101, 'Gray Wolf', 5, 5, 1),
+	(102, 'Timber Wolf', 6, 6, 1),
+	(103, 'Dire Wolf', 10, 10, 1),
+	(104, 'Winter Wolf', 12, 12, 1),
+	(105, 'Worg', 20, 20, 1),
+	(201, 'Wolf Handler', 20, 20, 7)`
+
+// newTestServer serves a fixture database and an icon directory holding
+// inv_sword.png, with a file next to it that must never be served
+func newTestServer(t *testing.T, corsOrigin string) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := database.NewSQLiteDB(filepath.Join(dir, "shelllab.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DB().Exec(wolves); err != nil {
+		t.Fatal(err)
+	}
+
+	iconDir := filepath.Join(dir, "icons")
+	if err := os.Mkdir(iconDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for path, data := range map[string]string{
+		filepath.Join(iconDir, "inv_sword.png"): "sword",
+		filepath.Join(dir, "secret.png"):        "secret",
+	} {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv := httptest.NewServer(api.NewServer(db, iconDir, corsOrigin).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, srv *httptest.Server, method, path string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+type creaturePage struct {
+	Items []struct {
+		Entry int `json:"entry"`
+	} `json:"items"`
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
+
+func TestPaging(t *testing.T) {
+	srv := newTestServer(t, "")
+
+	tests := []struct {
+		name    string
+		path    string
+		entries []int // nil checks only the count, for unordered search results
+		total   int
+		limit   int
+		offset  int
+		hasMore bool
+	}{
+		{"defaults", "/api/creatures?type=1&sort=level", []int{101, 102, 103, 104, 105}, 5, api.DefaultLimit, 0, false},
+		{"first page", "/api/creatures?type=1&sort=level&limit=2", []int{101, 102}, 5, 2, 0, true},
+		{"middle page", "/api/creatures?type=1&sort=level&limit=2&offset=2", []int{103, 104}, 5, 2, 2, true},
+		{"last page", "/api/creatures?type=1&sort=level&limit=2&offset=4", []int{105}, 5, 2, 4, false},
+		{"past the end", "/api/creatures?type=1&limit=2&offset=10", []int{}, 5, 2, 10, false},
+		{"descending", "/api/creatures?type=1&sort=level&order=desc&limit=2", []int{105, 104}, 5, 2, 0, true},
+		{"zero limit uses the default", "/api/creatures?type=1&sort=level&limit=0", []int{101, 102, 103, 104, 105}, 5, api.DefaultLimit, 0, false},
+		{"limit is capped", "/api/creatures?type=1&sort=level&limit=5000", []int{101, 102, 103, 104, 105}, 5, api.MaxLimit, 0, false},
+		{"negative offset starts at zero", "/api/creatures?type=1&sort=level&limit=1&offset=-3", []int{101}, 5, 1, 0, true},
+		{"garbage numbers use the defaults", "/api/creatures?type=1&sort=level&limit=x&offset=y", []int{101, 102, 103, 104, 105}, 5, api.DefaultLimit, 0, false},
+		{"name filter", "/api/creatures?type=1&sort=level&name=timber", []int{102}, 1, api.DefaultLimit, 0, false},
+		{"search list page", "/api/creatures?q=wolf&limit=1&offset=1", nil, 5, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, srv, http.MethodGet, tt.path, nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status %d: %s", resp.StatusCode, body)
+			}
+			var page creaturePage
+			if err := json.Unmarshal([]byte(body), &page); err != nil {
+				t.Fatal(err)
+			}
+			if page.Limit != tt.limit || page.Offset != tt.offset || page.HasMore != tt.hasMore {
+				t.Errorf("limit %d offset %d hasMore %v, want %d %d %v", page.Limit, page.Offset, page.HasMore, tt.limit, tt.offset, tt.hasMore)
+			}
+			if page.Total != tt.total {
+				t.Errorf("total %d, want %d", page.Total, tt.total)
+			}
+			if tt.entries == nil {
+				if len(page.Items) != tt.limit {
+					t.Errorf("got %d items, want %d", len(page.Items), tt.limit)
+				}
+				return
+			}
+			got := make([]int, len(page.Items))
+			for i, c := range page.Items {
+				got[i] = c.Entry
+			}
+			if !slices.Equal(got, tt.entries) {
+				t.Errorf("entries %v, want %v", got, tt.entries)
+			}
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	srv := newTestServer(t, "")
+	const path = "/api/creatures?type=1"
+
+	first, body := get(t, srv, http.MethodGet, path, nil)
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" || body == "" {
+		t.Fatalf("status %d, etag %q, body %q", first.StatusCode, etag, body)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+		empty       bool
+	}{
+		{"same tag", http.MethodGet, etag, http.StatusNotModified, true},
+		{"weak tag", http.MethodGet, "W/" + etag, http.StatusNotModified, true},
+		{"one of a list", http.MethodGet, `"other", ` + etag, http.StatusNotModified, true},
+		{"wildcard", http.MethodGet, "*", http.StatusNotModified, true},
+		{"stale tag", http.MethodGet, `"stale"`, http.StatusOK, false},
+		{"no tag", http.MethodGet, "", http.StatusOK, false},
+		{"head", http.MethodHead, "", http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := map[string]string{}
+			if tt.ifNoneMatch != "" {
+				header["If-None-Match"] = tt.ifNoneMatch
+			}
+			resp, got := get(t, srv, tt.method, path, header)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+			if resp.Header.Get("ETag") != etag {
+				t.Errorf("etag %q, want %q", resp.Header.Get("ETag"), etag)
+			}
+			if (got == "") != tt.empty {
+				t.Errorf("body %q, want empty %v", got, tt.empty)
+			}
+			if !tt.empty && got != body {
+				t.Errorf("body changed between requests")
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		vary   string
+	}{
+		{"disabled", "", ""},
+		{"any origin", "*", ""},
+		{"one origin", "https://example.com", "Origin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t, tt.origin)
+
+			resp, body := get(t, srv, http.MethodOptions, "/api/items/1", map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": "GET",
+			})
+			if resp.StatusCode != http.StatusNoContent || body != "" {
+				t.Errorf("preflight status %d body %q, want 204 and no body", resp.StatusCode, body)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.origin {
+				t.Errorf("allow origin %q, want %q", got, tt.origin)
+			}
+			if got := resp.Header.Get("Vary"); got != tt.vary {
+				t.Errorf("vary %q, want %q", got, tt.vary)
+			}
+
+			resp, _ = get(t, srv, http.MethodGet, "/api/creatures?type=1", nil)
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.origin {
+				t.Errorf("GET allow origin %q, want %q", got, tt.origin)
+			}
+			if tt.origin != "" && resp.Header.Get("Access-Control-Expose-Headers") != "ETag" {
+				t.Error("ETag is not exposed to scripts")
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	srv := newTestServer(t, "")
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/creatures/101", http.StatusOK},
+		{"/api/creatures/999", http.StatusNotFound},
+		{"/api/creatures/abc", http.StatusBadRequest},
+		{"/api/creatures", http.StatusBadRequest},
+		{"/api/creatures?type=1&sort=health", http.StatusBadRequest},
+		{"/api/items?sort=bogus", http.StatusBadRequest},
+		{"/api/items/999", http.StatusNotFound},
+		{"/api/items/1.5", http.StatusBadRequest},
+		{"/api/quests", http.StatusBadRequest},
+		{"/api/spells/999999", http.StatusNotFound},
+		{"/api/search", http.StatusBadRequest},
+		{"/api/icons/sprites?size=7", http.StatusBadRequest},
+		{"/api/nothing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, body := get(t, srv, http.MethodGet, tt.path, nil)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if tt.status == http.StatusOK || tt.path == "/api/nothing" {
+				return
+			}
+			var msg struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(body), &msg); err != nil || msg.Error == "" {
+				t.Errorf("body %q is not a JSON error", body)
+			}
+		})
+	}
+}
+
+func TestIcon(t *testing.T) {
+	srv := newTestServer(t, "")
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"exact name", "/icons/inv_sword.png", http.StatusOK, "sword"},
+		{"without extension", "/icons/inv_sword", http.StatusOK, "sword"},
+		{"any case", "/icons/INV_Sword.PNG", http.StatusOK, "sword"},
+		{"missing", "/icons/inv_axe", http.StatusNotFound, ""},
+		{"parent directory", "/icons/%2E%2E", http.StatusBadRequest, ""},
+		{"escaped traversal", "/icons/..%2Fsecret.png", http.StatusNotFound, ""},
+		{"escaped traversal without extension", "/icons/..%2Fsecret", http.StatusNotFound, ""},
+		{"hidden file", "/icons/.secret.png", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, srv, http.MethodGet, tt.path, nil)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status %d, want %d: %s", resp.StatusCode, tt.status, body)
+			}
+			if tt.status == http.StatusOK && body != tt.body {
+				t.Errorf("body %q, want %q", body, tt.body)
+			}
+			if body == "secret" {
+				t.Error("served a file outside the icon directory")
+			}
+		})
+	}
+}
@@ -2,19 +2,27 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"sync"
 
+	"shelllab/backend/database/repositories"
 	"shelllab/backend/database/schema"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
 )
 
+// UserSchema is the name user.db is attached under. User state (wishlists,
+// characters, settings...) lives there so shelllab.db can be replaced safely.
+const UserSchema = "user"
+
 // SQLiteDB wraps the SQLite database connection
 type SQLiteDB struct {
-	db *sql.DB
-	mu sync.RWMutex
+	db       *sql.DB
+	mu       sync.RWMutex
+	userPath string
 }
 
 // NewSQLiteDB creates a new SQLite database connection
@@ -24,7 +32,49 @@ func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	return configure(db)
+}
 
+// NewReadOnlySQLiteDB opens the game database read-only, with query_only set
+// on every connection. Unlike NewSQLiteDB it leaves the journal mode alone, so
+// nothing is written to the file.
+func NewReadOnlySQLiteDB(dbPath string) (*SQLiteDB, error) {
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro&_pragma=query_only(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(5)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return &SQLiteDB{db: db}, nil
+}
+
+// NewSQLiteDBWithUserData opens the game database with user.db attached to
+// every connection as UserSchema, so user tables can be joined with game data
+func NewSQLiteDBWithUserData(dbPath, userDBPath string) (*SQLiteDB, error) {
+	db := sql.OpenDB(&userDBConnector{
+		driver:   &sqlite.Driver{},
+		dsn:      dbPath + "?_busy_timeout=5000",
+		userPath: userDBPath,
+	})
+
+	s, err := configure(db)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec("PRAGMA " + UserSchema + ".journal_mode=WAL"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set WAL mode on user database: %w", err)
+	}
+	s.userPath = userDBPath
+	return s, nil
+}
+
+// configure applies the shared pool and pragma settings
+func configure(db *sql.DB) (*SQLiteDB, error) {
 	// Set connection pool settings - allow multiple concurrent reads
 	db.SetMaxOpenConns(10)
 	db.SetMaxIdleConns(5)
@@ -42,8 +92,43 @@ func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
 	return &SQLiteDB{db: db}, nil
 }
 
+// userDBConnector opens pooled connections with user.db attached
+type userDBConnector struct {
+	driver   *sqlite.Driver
+	dsn      string
+	userPath string
+}
+
+func (c *userDBConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("sqlite connection does not support exec")
+	}
+	args := []driver.NamedValue{{Ordinal: 1, Value: c.userPath}}
+	if _, err := execer.ExecContext(ctx, "ATTACH DATABASE ? AS "+UserSchema, args); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to attach user database: %w", err)
+	}
+	return conn, nil
+}
+
+func (c *userDBConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// UserDBPath returns the path of the attached user database, empty if none
+func (s *SQLiteDB) UserDBPath() string {
+	return s.userPath
+}
+
 // Close closes the database connection
 func (s *SQLiteDB) Close() error {
+	repositories.DropCache(s.db)
 	return s.db.Close()
 }
 
@@ -72,10 +157,14 @@ func (s *SQLiteDB) InitSchema() error {
 		return fmt.Errorf("failed to create locale schema: %w", err)
 	}
 
-	// Apply Migrations
-	schema.MigrateV2(s.db)
-	schema.MigrateAtlasLoot(s.db)
-	schema.MigratePerformance(s.db)
+	// Apply versioned migrations for databases created by older versions
+	applied, err := schema.NewMigrator(s.db).Migrate()
+	for _, m := range applied {
+		fmt.Printf("✓ Applied schema migration %d: %s\n", m.Version, m.Description)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate schema: %w", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,75 @@
+package database_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"shelllab/backend/database"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestNewReadOnlySQLiteDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shelllab.db")
+	raw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"PRAGMA journal_mode=DELETE",
+		"CREATE TABLE item_template (entry INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO item_template VALUES (1, 'Sword')",
+	} {
+		if _, err := raw.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	raw.Close()
+
+	db, err := database.NewReadOnlySQLiteDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var name string
+	if err := db.DB().QueryRow("SELECT name FROM item_template WHERE entry = 1").Scan(&name); err != nil || name != "Sword" {
+		t.Fatalf("read = %q, %v", name, err)
+	}
+
+	tests := []struct {
+		name string
+		stmt string
+	}{
+		{"insert", "INSERT INTO item_template VALUES (2, 'Shield')"},
+		{"create table", "CREATE TABLE scratch (id INTEGER)"},
+		{"journal mode", "PRAGMA journal_mode=WAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every pooled connection must refuse, not just the first one
+			conns := make([]*sql.Conn, 3)
+			for i := range conns {
+				conn, err := db.DB().Conn(t.Context())
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer conn.Close()
+				conns[i] = conn
+			}
+			for i, conn := range conns {
+				if tt.name == "journal mode" {
+					var mode string
+					if err := conn.QueryRowContext(t.Context(), tt.stmt).Scan(&mode); err == nil && mode == "wal" {
+						t.Errorf("connection %d switched to WAL", i)
+					}
+					continue
+				}
+				if _, err := conn.ExecContext(t.Context(), tt.stmt); err == nil {
+					t.Errorf("connection %d accepted %s", i, tt.stmt)
+				}
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ type ItemClass = models.ItemClass
 type ItemSubClass = models.ItemSubClass
 type InventorySlot = models.InventorySlot
 type ItemDetail = models.ItemDetail
+type ItemSummary = models.ItemSummary
 type CreatureDrop = models.CreatureDrop
 type QuestReward = models.QuestReward
 type ItemSetBrowse = models.ItemSetBrowse
@@ -46,6 +47,9 @@ type QuestRelation = models.QuestRelation
 type QuestCategoryGroup = models.QuestCategoryGroup
 type QuestCategoryEnhanced = models.QuestCategoryEnhanced
 type QuestTemplateEntry = models.QuestTemplateEntry
+type QuestGraph = models.QuestGraph
+type QuestGraphNode = models.QuestGraphNode
+type QuestGraphEdge = models.QuestGraphEdge
 
 type Spell = models.Spell
 type SpellSkillCategory = models.SpellSkillCategory
@@ -57,6 +61,8 @@ type SpellTemplateFull = models.SpellTemplateFull
 type LootItem = models.LootItem
 type LootEntry = models.LootEntry
 type LootTemplateEntry = models.LootTemplateEntry
+type LootTree = models.LootTree
+type LootNode = models.LootNode
 
 type GameObject = models.GameObject
 type ObjectType = models.ObjectType
@@ -83,6 +89,14 @@ type AtlasLootImportCategory = models.AtlasLootImportCategory
 type FavoriteItem = models.FavoriteItem
 type FavoriteCategory = models.FavoriteCategory
 type FavoriteResult = models.FavoriteResult
+type Wishlist = models.Wishlist
+type WishlistItem = models.WishlistItem
+type WishlistItemFilter = models.WishlistItemFilter
+type SavedSearch = models.SavedSearch
+type SmartCollection = models.SmartCollection
+type SmartCollectionView = models.SmartCollectionView
+type ChangeLogEntry = models.ChangeLogEntry
+type ChangeLogFilter = models.ChangeLogFilter
 
 type ZoneEntry = models.ZoneEntry
 type SkillEntry = models.SkillEntry
@@ -90,6 +104,11 @@ type SkillLineAbilityEntry = models.SkillLineAbilityEntry
 type SearchFilter = models.SearchFilter
 type SearchResult = models.SearchResult
 
+type PageQuery = models.PageQuery
+type Page[T any] = models.Page[T]
+
+type CacheStats = models.CacheStats
+
 // === Repository Types ===
 
 type ItemRepository = repositories.ItemRepository
@@ -103,6 +122,9 @@ type CategoryRepository = repositories.CategoryRepository
 type AtlasLootRepository = repositories.AtlasLootRepository
 type LocaleRepository = repositories.LocaleRepository
 type FavoriteRepository = repositories.FavoriteRepository
+type WishlistRepository = repositories.WishlistRepository
+type SavedSearchRepository = repositories.SavedSearchRepository
+type ChangeLogRepository = repositories.ChangeLogRepository
 
 // === Factory Functions ===
 
@@ -150,6 +172,35 @@ func NewFavoriteRepository(db *SQLiteDB) *FavoriteRepository {
 	return repositories.NewFavoriteRepository(db.DB())
 }
 
+func NewWishlistRepository(db *SQLiteDB) *WishlistRepository {
+	return repositories.NewWishlistRepository(db.DB())
+}
+
+func NewSavedSearchRepository(db *SQLiteDB) *SavedSearchRepository {
+	return repositories.NewSavedSearchRepository(db.DB())
+}
+
+func NewChangeLogRepository(db *SQLiteDB) *ChangeLogRepository {
+	return repositories.NewChangeLogRepository(db.DB())
+}
+
+// === Read Cache ===
+
+// GetCacheStats returns the usage of the in-memory read caches of db
+func GetCacheStats(db *SQLiteDB) []CacheStats {
+	return repositories.CacheFor(db.DB()).Stats()
+}
+
+// PurgeCache empties the in-memory read caches of db
+func PurgeCache(db *SQLiteDB) {
+	repositories.PurgeCache(db.DB())
+}
+
+// === Paging ===
+
+var ErrInvalidSort = repositories.ErrInvalidSort
+var ErrInvalidCursor = repositories.ErrInvalidCursor
+
 // === Helper Function Exports ===
 
 var GetClassName = helpers.GetClassName
@@ -157,6 +208,7 @@ var GetSubClassName = helpers.GetSubClassName
 var GetInventoryTypeName = helpers.GetInventoryTypeName
 var GetBondingName = helpers.GetBondingName
 var GetQualityName = helpers.GetQualityName
+var GetStatName = helpers.GetStatName
 var GetCreatureTypeName = helpers.GetCreatureTypeName
 var GetCreatureRankName = helpers.GetCreatureRankName
 var GetTriggerPrefix = helpers.GetTriggerPrefix
@@ -1,6 +1,8 @@
 // Package helpers contains utility functions for database operations
 package helpers
 
+import "fmt"
+
 // GetClassName returns the item class name
 func GetClassName(c int) string {
 	classNames := map[int]string{
@@ -298,6 +300,25 @@ func GetQualityName(quality int) string {
 	}
 }
 
+// GetStatName returns the item stat name for a stat_type value
+func GetStatName(statType int) string {
+	statNames := map[int]string{
+		0: "Mana", 1: "Health", 3: "Agility", 4: "Strength",
+		5: "Intellect", 6: "Spirit", 7: "Stamina",
+		12: "Defense Rating", 13: "Dodge Rating", 14: "Parry Rating",
+		15: "Shield Block Rating", 16: "Melee Hit Rating", 17: "Ranged Hit Rating",
+		18: "Spell Hit Rating", 19: "Melee Critical Rating", 20: "Ranged Critical Rating",
+		21: "Spell Critical Rating", 35: "Resilience Rating", 36: "Haste Rating",
+		37: "Expertise Rating", 38: "Attack Power", 39: "Ranged Attack Power",
+		41: "Spell Healing", 42: "Spell Damage", 43: "Mana Regeneration",
+		44: "Armor Penetration Rating", 45: "Spell Power",
+	}
+	if name, ok := statNames[statType]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown Stat %d", statType)
+}
+
 // GetCreatureTypeName returns the creature type name
 func GetCreatureTypeName(t int) string {
 	typeNames := map[int]string{
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"shelllab/backend/database/repositories"
 )
 
 // AtlasLootImporter handles AtlasLoot data imports
@@ -102,6 +104,7 @@ func (a *AtlasLootImporter) ImportFromLua(luaPath string,
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(a.db, "atlasloot_categories", "atlasloot_modules", "atlasloot_tables", "atlasloot_items")
 
 	// Clear existing
 	tx.Exec("DELETE FROM atlasloot_items")
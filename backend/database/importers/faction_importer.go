@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
 )
 
 // FactionImporter handles faction data imports
@@ -36,6 +37,7 @@ func (f *FactionImporter) ImportFromJSON(jsonPath string) error {
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(f.db, "factions")
 
 	tx.Exec("DELETE FROM factions")
 
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"shelllab/backend/database/repositories"
 )
 
 type GeneratedImporter struct {
@@ -35,6 +37,7 @@ func (i *GeneratedImporter) ImportItemIcons(jsonPath string) error {
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(i.db, "item_template")
 
 	stmt, err := tx.Prepare("UPDATE item_template SET icon_path = ? WHERE display_id = ?")
 	if err != nil {
@@ -99,6 +102,7 @@ func (i *GeneratedImporter) ImportSpellIcons(jsonPath string) error {
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(i.db, "spell_template")
 
 	stmt, err := tx.Prepare("UPDATE spell_template SET iconName = ? WHERE spellIconId = ?")
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
 )
 
 // ItemSetImporter handles item set data imports
@@ -37,6 +38,7 @@ func (i *ItemSetImporter) ImportFromJSON(jsonPath string) error {
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(i.db, "itemsets")
 
 	stmt, err := tx.Prepare(`
 		REPLACE INTO itemsets (
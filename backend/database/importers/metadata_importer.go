@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
 )
 
 // MetadataImporter handles metadata imports (zones, skills, etc.)
@@ -21,6 +22,9 @@ func NewMetadataImporter(db *sql.DB) *MetadataImporter {
 
 // ImportAll handles all metadata imports
 func (m *MetadataImporter) ImportAll(dataDir string) error {
+	defer repositories.InvalidateCache(m.db, "quest_category_groups", "spell_skill_categories",
+		"spell_skills", "spell_skill_spells", "quest_categories_enhanced")
+
 	m.initStaticMetadata()
 
 	// Always check/import skills
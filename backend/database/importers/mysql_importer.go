@@ -1,9 +1,12 @@
 package importers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+
+	"shelllab/backend/database/repositories"
 )
 
 // MySQLImporter handles importing data directly from MySQL to SQLite
@@ -24,7 +27,7 @@ func NewMySQLImporter(sqliteDB *sql.DB, mysqlDB *sql.DB) *MySQLImporter {
 // querySelect: MySQL SELECT query
 // queryInsert: SQLite INSERT query
 // batchSize: number of rows to commit at a time
-func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string, batchSize int) error {
+func (i *MySQLImporter) ImportTable(ctx context.Context, tableName, querySelect, queryInsert string, batchSize int) error {
 	if i.mysqlDB == nil {
 		return fmt.Errorf("mysql connection is nil")
 	}
@@ -32,7 +35,7 @@ func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string,
 	log.Printf("📥 Importing %s from MySQL...", tableName)
 
 	// 1. Query MySQL
-	rows, err := i.mysqlDB.Query(querySelect)
+	rows, err := i.mysqlDB.QueryContext(ctx, querySelect)
 	if err != nil {
 		return fmt.Errorf("failed to query mysql table %s: %w", tableName, err)
 	}
@@ -46,11 +49,12 @@ func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string,
 	colCount := len(cols)
 
 	// 2. Prepare SQLite Transaction
-	tx, err := i.sqliteDB.Begin()
+	tx, err := i.sqliteDB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
+	defer repositories.InvalidateCache(i.sqliteDB, tableName)
 
 	stmt, err := tx.Prepare(queryInsert)
 	if err != nil {
@@ -67,6 +71,9 @@ func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string,
 	}
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("import of %s cancelled: %w", tableName, err)
+		}
 		if err := rows.Scan(valuePtrs...); err != nil {
 			return fmt.Errorf("failed to scan row from %s: %w", tableName, err)
 		}
@@ -81,7 +88,7 @@ func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string,
 				return fmt.Errorf("failed to commit batch for %s: %w", tableName, err)
 			}
 			// Start new transaction
-			tx, err = i.sqliteDB.Begin()
+			tx, err = i.sqliteDB.BeginTx(ctx, nil)
 			if err != nil {
 				return err
 			}
@@ -108,18 +115,18 @@ func (i *MySQLImporter) ImportTable(tableName, querySelect, queryInsert string,
 
 // ImportAllFromMySQL imports all core data from MySQL
 // Each table is checked individually - only empty tables are imported
-func (i *MySQLImporter) ImportAllFromMySQL() error {
+func (i *MySQLImporter) ImportAllFromMySQL(ctx context.Context) error {
 	// 1. Item Template
 	// Note: We select explicit columns to match SQLite schema order
 	itemCols := "entry,class,subclass,name,description,display_id,quality,flags,buy_count,buy_price,sell_price,inventory_type,allowable_class,allowable_race,item_level,required_level,required_skill,required_skill_rank,required_spell,required_honor_rank,required_city_rank,required_reputation_faction,required_reputation_rank,max_count,stackable,container_slots,stat_type1,stat_value1,stat_type2,stat_value2,stat_type3,stat_value3,stat_type4,stat_value4,stat_type5,stat_value5,stat_type6,stat_value6,stat_type7,stat_value7,stat_type8,stat_value8,stat_type9,stat_value9,stat_type10,stat_value10,delay,range_mod,ammo_type,dmg_min1,dmg_max1,dmg_type1,dmg_min2,dmg_max2,dmg_type2,dmg_min3,dmg_max3,dmg_type3,dmg_min4,dmg_max4,dmg_type4,dmg_min5,dmg_max5,dmg_type5,block,armor,holy_res,fire_res,nature_res,frost_res,shadow_res,arcane_res,spellid_1,spelltrigger_1,spellcharges_1,spellppmrate_1,spellcooldown_1,spellcategory_1,spellcategorycooldown_1,spellid_2,spelltrigger_2,spellcharges_2,spellppmrate_2,spellcooldown_2,spellcategory_2,spellcategorycooldown_2,spellid_3,spelltrigger_3,spellcharges_3,spellppmrate_3,spellcooldown_3,spellcategory_3,spellcategorycooldown_3,spellid_4,spelltrigger_4,spellcharges_4,spellppmrate_4,spellcooldown_4,spellcategory_4,spellcategorycooldown_4,spellid_5,spelltrigger_5,spellcharges_5,spellppmrate_5,spellcooldown_5,spellcategory_5,spellcategorycooldown_5,bonding,page_text,page_language,page_material,start_quest,lock_id,material,sheath,random_property,set_id,max_durability,area_bound,map_bound,duration,bag_family,disenchant_id,food_type,min_money_loot,max_money_loot,wrapped_gift,extra_flags,other_team_entry,script_name"
 
-	if err := i.runImportIfEmpty("item_template", itemCols); err != nil {
+	if err := i.runImportIfEmpty(ctx, "item_template", itemCols); err != nil {
 		log.Printf("Warning: Failed to import item_template: %v", err)
 	}
 
 	// 2. Creature Template
 	creatureCols := "entry,display_id1,display_id2,display_id3,display_id4,mount_display_id,name,subname,gossip_menu_id,level_min,level_max,health_min,health_max,mana_min,mana_max,armor,faction,npc_flags,speed_walk,speed_run,scale,detection_range,call_for_help_range,leash_range,`rank`,xp_multiplier,dmg_min,dmg_max,dmg_school,attack_power,dmg_multiplier,base_attack_time,ranged_attack_time,unit_class,unit_flags,dynamic_flags,beast_family,trainer_type,trainer_spell,trainer_class,trainer_race,ranged_dmg_min,ranged_dmg_max,ranged_attack_power,`type`,type_flags,loot_id,pickpocket_loot_id,skinning_loot_id,holy_res,fire_res,nature_res,frost_res,shadow_res,arcane_res,spell_id1,spell_id2,spell_id3,spell_id4,spell_list_id,pet_spell_list_id,spawn_spell_id,auras,gold_min,gold_max,ai_name,movement_type,inhabit_type,civilian,racial_leader,regeneration,equipment_id,trainer_id,vendor_id,mechanic_immune_mask,school_immune_mask,immunity_flags,flags_extra,phase_quest_id,script_name"
-	if err := i.runImportIfEmpty("creature_template", creatureCols); err != nil {
+	if err := i.runImportIfEmpty(ctx, "creature_template", creatureCols); err != nil {
 		log.Printf("Warning: Failed to import creature_template: %v", err)
 	}
 
@@ -129,7 +136,7 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 	var questCount int
 	i.sqliteDB.QueryRow("SELECT COUNT(*) FROM quest_template").Scan(&questCount)
 	if questCount < 500 { // If mostly empty, re-import
-		if err := i.runImport("quest_template", questCols); err != nil {
+		if err := i.runImport(ctx, "quest_template", questCols); err != nil {
 			log.Printf("Warning: Failed to import quest_template: %v", err)
 		}
 	} else {
@@ -145,7 +152,7 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 	var spellCount int
 	i.sqliteDB.QueryRow("SELECT COUNT(*) FROM spell_template").Scan(&spellCount)
 	if spellCount < 100 {
-		if err := i.runImport("spell_template", spellCols); err != nil {
+		if err := i.runImport(ctx, "spell_template", spellCols); err != nil {
 			log.Printf("Warning: Failed to import spell_template: %v", err)
 		}
 	} else {
@@ -154,29 +161,29 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 
 	// 4b. Spell Aux Tables (Aowow Structure)
 	// spell_icons
-	if err := i.runCustomImportIfEmpty("spell_icons", "SELECT id, iconname FROM aowow.aowow_spellicons", "INSERT OR REPLACE INTO spell_icons (id, icon_name) VALUES (?, ?)"); err != nil {
+	if err := i.runCustomImportIfEmpty(ctx, "spell_icons", "SELECT id, iconname FROM aowow.aowow_spellicons", "INSERT OR REPLACE INTO spell_icons (id, icon_name) VALUES (?, ?)"); err != nil {
 		log.Printf("Warning: Failed to import spell_icons: %v", err)
 	}
 	// spell_range
-	if err := i.runCustomImportIfEmpty("spell_range", "SELECT rangeID, rangeMin, rangeMax, name_loc0 FROM aowow.aowow_spellrange", "INSERT OR REPLACE INTO spell_range (id, range_min, range_max, name) VALUES (?, ?, ?, ?)"); err != nil {
+	if err := i.runCustomImportIfEmpty(ctx, "spell_range", "SELECT rangeID, rangeMin, rangeMax, name_loc0 FROM aowow.aowow_spellrange", "INSERT OR REPLACE INTO spell_range (id, range_min, range_max, name) VALUES (?, ?, ?, ?)"); err != nil {
 		log.Printf("Warning: Failed to import spell_range: %v", err)
 	}
 	// spell_durations
-	if err := i.runCustomImportIfEmpty("spell_durations", "SELECT durationID, durationBase FROM aowow.aowow_spellduration", "INSERT OR REPLACE INTO spell_durations (id, duration_base) VALUES (?, ?)"); err != nil {
+	if err := i.runCustomImportIfEmpty(ctx, "spell_durations", "SELECT durationID, durationBase FROM aowow.aowow_spellduration", "INSERT OR REPLACE INTO spell_durations (id, duration_base) VALUES (?, ?)"); err != nil {
 		log.Printf("Warning: Failed to import spell_durations: %v", err)
 	}
 	// spell_radius
-	if err := i.runCustomImportIfEmpty("spell_radius", "SELECT radiusID, radiusBase FROM aowow.aowow_spellradius", "INSERT OR REPLACE INTO spell_radius (id, radius_base) VALUES (?, ?)"); err != nil {
+	if err := i.runCustomImportIfEmpty(ctx, "spell_radius", "SELECT radiusID, radiusBase FROM aowow.aowow_spellradius", "INSERT OR REPLACE INTO spell_radius (id, radius_base) VALUES (?, ?)"); err != nil {
 		log.Printf("Warning: Failed to import spell_radius: %v", err)
 	}
 	// spell_cast_times
-	if err := i.runCustomImportIfEmpty("spell_cast_times", "SELECT id, base FROM aowow.aowow_spellcasttimes", "INSERT OR REPLACE INTO spell_cast_times (id, base) VALUES (?, ?)"); err != nil {
+	if err := i.runCustomImportIfEmpty(ctx, "spell_cast_times", "SELECT id, base FROM aowow.aowow_spellcasttimes", "INSERT OR REPLACE INTO spell_cast_times (id, base) VALUES (?, ?)"); err != nil {
 		log.Printf("Warning: Failed to import spell_cast_times: %v", err)
 	}
 
 	// 5. Gameobject Template
 	goCols := "entry,`type`,displayId,name,faction,flags,size,data0,data1,data2,data3,data4,data5,data6,data7,data8,data9,data10,data11,data12,data13,data14,data15,data16,data17,data18,data19,data20,data21,data22,data23,mingold,maxgold,phase_quest_id,script_name"
-	if err := i.runImportIfEmpty("gameobject_template", goCols); err != nil {
+	if err := i.runImportIfEmpty(ctx, "gameobject_template", goCols); err != nil {
 		log.Printf("Warning: Failed to import gameobject_template: %v", err)
 	}
 
@@ -190,29 +197,29 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 	}
 	// Column names: entry, item, ChanceOrQuestChance, groupid, mincountOrRef, maxcount
 	for _, table := range lootTables {
-		if err := i.runLootImportIfEmpty(table); err != nil {
+		if err := i.runLootImportIfEmpty(ctx, table); err != nil {
 			log.Printf("Warning: Failed to import loot table %s: %v", table, err)
 		}
 	}
 
 	// 7. Item Display Info
-	if err := i.runImportIfEmpty("item_display_info", "ID,icon"); err != nil {
+	if err := i.runImportIfEmpty(ctx, "item_display_info", "ID,icon"); err != nil {
 		log.Printf("Warning: Failed to import item_display_info: %v", err)
 	}
 
 	// 8. NPC Quest Relations
-	if err := i.runImportIfEmpty("creature_questrelation", "id,quest"); err != nil {
+	if err := i.runImportIfEmpty(ctx, "creature_questrelation", "id,quest"); err != nil {
 		log.Printf("Warning: Failed to import creature_questrelation: %v", err)
 	}
-	if err := i.runImportIfEmpty("creature_involvedrelation", "id,quest"); err != nil {
+	if err := i.runImportIfEmpty(ctx, "creature_involvedrelation", "id,quest"); err != nil {
 		log.Printf("Warning: Failed to import creature_involvedrelation: %v", err)
 	}
 
 	// 9. GO Quest Relations
-	if err := i.runImportIfEmpty("gameobject_questrelation", "id,quest"); err != nil {
+	if err := i.runImportIfEmpty(ctx, "gameobject_questrelation", "id,quest"); err != nil {
 		log.Printf("Warning: Failed to import gameobject_questrelation: %v", err)
 	}
-	if err := i.runImportIfEmpty("gameobject_involvedrelation", "id,quest"); err != nil {
+	if err := i.runImportIfEmpty(ctx, "gameobject_involvedrelation", "id,quest"); err != nil {
 		log.Printf("Warning: Failed to import gameobject_involvedrelation: %v", err)
 	}
 
@@ -220,7 +227,7 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 	// Always force import for this table to ensure coordinates are correct, skipping empty check
 	// Note: Mapping areatableID to zoneID as well using SELECT areatableID twice or aliasing if driver supports it.
 	// We select areatableID as the second column to map to zoneID in the INSERT statement.
-	if err := i.ImportTable("aowow_zones",
+	if err := i.ImportTable(ctx, "aowow_zones",
 		"SELECT mapID, areatableID, name_loc0, x_min, x_max, y_min, y_max, areatableID FROM aowow.aowow_zones",
 		"INSERT OR REPLACE INTO aowow_zones (mapID, zoneID, name_loc0, x_min, x_max, y_min, y_max, areatableID) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", 1000); err != nil {
 		log.Printf("Warning: Failed to import aowow_zones: %v", err)
@@ -230,7 +237,7 @@ func (i *MySQLImporter) ImportAllFromMySQL() error {
 }
 
 // runImportIfEmpty imports a table only if it's empty in SQLite
-func (i *MySQLImporter) runImportIfEmpty(table string, cols string) error {
+func (i *MySQLImporter) runImportIfEmpty(ctx context.Context, table string, cols string) error {
 	// Check if table is empty
 	var count int
 	i.sqliteDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count)
@@ -239,10 +246,10 @@ func (i *MySQLImporter) runImportIfEmpty(table string, cols string) error {
 		return nil
 	}
 
-	return i.runImport(table, cols)
+	return i.runImport(ctx, table, cols)
 }
 
-func (i *MySQLImporter) runImport(table string, cols string) error {
+func (i *MySQLImporter) runImport(ctx context.Context, table string, cols string) error {
 	// Construct SELECT query
 	selectQuery := fmt.Sprintf("SELECT %s FROM %s", cols, table)
 
@@ -262,23 +269,23 @@ func (i *MySQLImporter) runImport(table string, cols string) error {
 
 	insertQuery := fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)", table, cols, placeholders)
 
-	return i.ImportTable(table, selectQuery, insertQuery, 1000)
+	return i.ImportTable(ctx, table, selectQuery, insertQuery, 1000)
 }
 
 // runCustomImportIfEmpty allows specifying custom SELECT/INSERT queries
-func (i *MySQLImporter) runCustomImportIfEmpty(table, selectQuery, insertQuery string) error {
+func (i *MySQLImporter) runCustomImportIfEmpty(ctx context.Context, table, selectQuery, insertQuery string) error {
 	var count int
 	i.sqliteDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count)
 	if count > 0 {
 		log.Printf("⏭️  %s already has %d rows, skipping", table, count)
 		return nil
 	}
-	return i.ImportTable(table, selectQuery, insertQuery, 1000)
+	return i.ImportTable(ctx, table, selectQuery, insertQuery, 1000)
 }
 
 // runLootImportIfEmpty handles loot table import
 // Column names now match between MySQL and SQLite
-func (i *MySQLImporter) runLootImportIfEmpty(table string) error {
+func (i *MySQLImporter) runLootImportIfEmpty(ctx context.Context, table string) error {
 	// Check if table is empty
 	var count int
 	i.sqliteDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count)
@@ -288,5 +295,5 @@ func (i *MySQLImporter) runLootImportIfEmpty(table string) error {
 	}
 
 	cols := "entry, item, ChanceOrQuestChance, groupid, mincountOrRef, maxcount"
-	return i.runImport(table, cols)
+	return i.runImport(ctx, table, cols)
 }
@@ -0,0 +1,12 @@
+package models
+
+// CacheStats reports the usage of one in-memory read cache
+type CacheStats struct {
+	Name      string `json:"name"`
+	Entries   int    `json:"entries"`
+	Capacity  int    `json:"capacity"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"` // Entries dropped to stay within Capacity
+	Purges    uint64 `json:"purges"`    // Times the cache was emptied after a write
+}
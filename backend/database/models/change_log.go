@@ -0,0 +1,54 @@
+// Package models contains all database entity definitions
+package models
+
+// Entity types recorded in the change log
+const (
+	ChangeEntityItem  = "item"
+	ChangeEntityQuest = "quest"
+	ChangeEntitySpell = "spell"
+	ChangeEntityNpc   = "npc"
+)
+
+// Change actions: a new record, or a single field that changed on an existing one
+const (
+	ChangeActionAdded   = "added"
+	ChangeActionUpdated = "updated"
+)
+
+// Sources that write to the change log
+const (
+	ChangeSourceTurtlecraft = "turtlecraft" // database.turtlecraft.gg sync
+	ChangeSourceScrape      = "scrape"      // NPC page scrape (turtlecraft + wowhead)
+	ChangeSourceMySQL       = "mysql"       // Local MySQL dev database
+)
+
+// ChangeLogEntry is one recorded change to a synced record.
+// Added records have a single entry with an empty Field.
+type ChangeLogEntry struct {
+	ID         int64  `json:"id"`
+	EntityType string `json:"entityType"`
+	EntityID   int    `json:"entityId"`
+	EntityName string `json:"entityName"`
+	Action     string `json:"action"`
+	Field      string `json:"field"`
+	OldValue   string `json:"oldValue"`
+	NewValue   string `json:"newValue"`
+	Source     string `json:"source"`
+	ChangedAt  string `json:"changedAt"`
+}
+
+// ChangeLogFilter selects change log entries; empty fields match everything.
+// Since (inclusive) and Until (exclusive) compare against ChangedAt, which is UTC RFC3339,
+// so plain dates like "2026-03-01" work too.
+type ChangeLogFilter struct {
+	EntityType string `json:"entityType,omitempty"`
+	EntityID   int    `json:"entityId,omitempty"`
+	Action     string `json:"action,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Search     string `json:"search,omitempty"` // Matches entity name
+	Since      string `json:"since,omitempty"`
+	Until      string `json:"until,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+}
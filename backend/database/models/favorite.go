@@ -1,9 +1,10 @@
 // Package models contains all database entity definitions
 package models
 
-// FavoriteItem represents a user's favorite item
+// FavoriteItem is a wishlist item as the favorites API shows it, with the
+// list name as its category
 type FavoriteItem struct {
-	ID        int    `json:"id"`
+	ID        int    `json:"id"` // WishlistItem.ID
 	ItemEntry int    `json:"itemEntry"`
 	Category  string `json:"category"`
 	AddedAt   string `json:"addedAt"`
@@ -256,6 +256,16 @@ type ItemDrop struct {
 	IconPath string  `json:"iconPath"`
 }
 
+// ItemSummary is the part of an item that lists of loot, rewards and
+// favorites show next to an item link
+type ItemSummary struct {
+	Entry     int    `json:"entry"`
+	Name      string `json:"name"`
+	Quality   int    `json:"quality"`
+	ItemLevel int    `json:"itemLevel"`
+	IconPath  string `json:"iconPath"`
+}
+
 // CreatureDrop represents a creature that drops an item
 type CreatureDrop struct {
 	Entry    int     `json:"entry"`
@@ -11,6 +11,31 @@ type LootItem struct {
 	MaxCount int     `json:"maxCount"`
 }
 
+// LootTree is a loot table with its reference tables nested, as stored
+// rather than flattened into one list of items
+type LootTree struct {
+	LootID int         `json:"lootId"`
+	Nodes  []*LootNode `json:"nodes"`
+}
+
+// LootNode is one row of a loot table: an item, or a reference to another
+// loot table whose rows are its children. Chance is the row's own chance;
+// EffectiveChance multiplies in the chances of the references above it.
+type LootNode struct {
+	ItemID          int         `json:"itemId,omitempty"`
+	Name            string      `json:"name,omitempty"`
+	IconPath        string      `json:"iconPath,omitempty"`
+	Quality         int         `json:"quality"`
+	ReferenceID     int         `json:"referenceId,omitempty"`
+	Chance          float64     `json:"chance"`
+	EffectiveChance float64     `json:"effectiveChance"`
+	GroupID         int         `json:"groupId"`
+	MinCount        int         `json:"minCount"`
+	MaxCount        int         `json:"maxCount"`
+	Cycle           bool        `json:"cycle,omitempty"` // reference back to a table above it, not expanded
+	Children        []*LootNode `json:"children,omitempty"`
+}
+
 // LootEntry represents a loot item with metadata (for AtlasLoot)
 type LootEntry struct {
 	ItemID     int    `json:"itemId"`
@@ -31,6 +31,10 @@ type SearchFilter struct {
 	MaxLevel      int    `json:"maxLevel,omitempty"`
 	MinReqLevel   int    `json:"minReqLevel,omitempty"`
 	MaxReqLevel   int    `json:"maxReqLevel,omitempty"`
+	Stats         []int  `json:"stats,omitempty"`  // Stat types the item must have, e.g. 5 for Intellect
+	Effect        string `json:"effect,omitempty"` // Text in the description of one of the item's spells
+	Sort          string `json:"sort,omitempty"`   // A PageQuery sort key; empty sorts by quality and item level
+	Desc          bool   `json:"desc,omitempty"`
 	Limit         int    `json:"limit"`
 	Offset        int    `json:"offset"`
 }
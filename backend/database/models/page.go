@@ -0,0 +1,30 @@
+package models
+
+// Sort keys accepted by PageQuery.Sort. Each browse list supports the keys
+// that apply to it (objects only sort by name, only items by DPS).
+const (
+	SortName      = "name"
+	SortLevel     = "level"
+	SortQuality   = "quality"
+	SortItemLevel = "itemLevel"
+	SortDPS       = "dps"
+)
+
+// PageQuery selects one page of a browse list. An empty Sort keeps the list's
+// natural order. Cursor, when set, is the NextCursor of the previous page and
+// takes precedence over Offset.
+type PageQuery struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor,omitempty"`
+	Sort   string `json:"sort,omitempty"`
+	Desc   bool   `json:"desc,omitempty"`
+}
+
+// Page is one page of a browse list. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
@@ -64,6 +64,35 @@ type QuestSeriesItem struct {
 	Depth int    `json:"depth"`
 }
 
+// Quest graph edge types. Edges point from the earlier quest to the later one.
+const (
+	QuestEdgeNext       = "next"       // NextQuestInChain
+	QuestEdgePrev       = "prev"       // PrevQuestId: must be completed first
+	QuestEdgePrevActive = "prevActive" // negative PrevQuestId: must be in the quest log
+)
+
+// QuestGraph is the quest chain around Root as nodes and edges, for views
+// that draw branches instead of the indented Series list
+type QuestGraph struct {
+	Root  int               `json:"root"`
+	Nodes []*QuestGraphNode `json:"nodes"`
+	Edges []*QuestGraphEdge `json:"edges"`
+}
+
+// QuestGraphNode is a quest in a QuestGraph
+type QuestGraphNode struct {
+	Entry int    `json:"entry"`
+	Title string `json:"title"`
+	Level int    `json:"level"`
+}
+
+// QuestGraphEdge links two quests of a QuestGraph
+type QuestGraphEdge struct {
+	From int    `json:"from"`
+	To   int    `json:"to"`
+	Type string `json:"type"`
+}
+
 // QuestItem represents an item reward from a quest
 type QuestItem struct {
 	Entry   int    `json:"entry"`
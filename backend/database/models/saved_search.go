@@ -0,0 +1,30 @@
+// Package models contains all database entity definitions
+package models
+
+// SavedSearch is an AdvancedSearch filter kept under a name, with the sort
+// its results are listed in. Limit and Offset of the filter are not saved.
+type SavedSearch struct {
+	ID           int          `json:"id"`
+	Name         string       `json:"name"`
+	Filter       SearchFilter `json:"filter"`
+	Sort         string       `json:"sort,omitempty"` // A PageQuery sort key; empty sorts by quality and item level
+	Desc         bool         `json:"desc,omitempty"`
+	CreatedAt    string       `json:"createdAt"`
+	LastViewedAt string       `json:"lastViewedAt"` // UTC RFC3339, like ChangeLogEntry.ChangedAt
+}
+
+// SmartCollection is a saved search evaluated against the current data.
+// Items count as new when a sync added or changed them since the search was
+// last viewed.
+type SmartCollection struct {
+	Search   *SavedSearch `json:"search"`
+	Count    int          `json:"count"`
+	NewCount int          `json:"newCount"`
+}
+
+// SmartCollectionView is one page of the items in a smart collection
+type SmartCollectionView struct {
+	Collection *SmartCollection `json:"collection"`
+	Items      []*Item          `json:"items"`
+	NewItems   []int            `json:"newItems"` // Entries of Items that are new
+}
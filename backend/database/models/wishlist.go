@@ -0,0 +1,58 @@
+// Package models contains all database entity definitions
+package models
+
+// DefaultWishlist holds favorites added without a category
+const DefaultWishlist = "Favorites"
+
+// Target sources of a wishlist item
+const (
+	WishlistSourceBoss  = "boss"  // SourceEntry is a creature entry
+	WishlistSourceQuest = "quest" // SourceEntry is a quest entry
+)
+
+// Wishlist item statuses, the same as the favorite statuses
+const (
+	WishlistStatusWanted    = 0
+	WishlistStatusObtained  = 1
+	WishlistStatusAbandoned = 2
+)
+
+// Wishlist is a named list of wanted items
+type Wishlist struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+	// Counts of the items on the list
+	ItemCount     int `json:"itemCount"`
+	ObtainedCount int `json:"obtainedCount"`
+}
+
+// WishlistItem is an item on a wishlist. An item can be on several lists,
+// and on the same list once per character.
+type WishlistItem struct {
+	ID          int    `json:"id"`
+	Wishlist    string `json:"wishlist"`
+	ItemEntry   int    `json:"itemEntry"`
+	Character   string `json:"character"` // Empty when the item isn't for a particular character
+	Priority    int    `json:"priority"`  // Higher comes first
+	Notes       string `json:"notes"`
+	SourceType  string `json:"sourceType"` // WishlistSourceBoss, WishlistSourceQuest or empty
+	SourceEntry int    `json:"sourceEntry"`
+	Status      int    `json:"status"`
+	ObtainedAt  string `json:"obtainedAt"` // Set when the status changes to obtained
+	AddedAt     string `json:"addedAt"`
+	// Joined data from item_template and the source
+	SourceName  string `json:"sourceName,omitempty"`
+	ItemName    string `json:"itemName,omitempty"`
+	ItemQuality int    `json:"itemQuality,omitempty"`
+	IconPath    string `json:"iconPath,omitempty"`
+	ItemLevel   int    `json:"itemLevel,omitempty"`
+}
+
+// WishlistItemFilter selects wishlist items; empty fields match everything
+type WishlistItemFilter struct {
+	Wishlist  string `json:"wishlist,omitempty"`
+	Character string `json:"character,omitempty"`
+	ItemEntry int    `json:"itemEntry,omitempty"`
+}
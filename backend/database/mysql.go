@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -13,6 +14,21 @@ type MySQLConnection struct {
 	db *sql.DB
 }
 
+// MySQLDSNFromEnv builds a DSN from MYSQL_USER, MYSQL_PASSWORD, MYSQL_HOST,
+// MYSQL_PORT and MYSQL_DATABASE. Returns "" when MYSQL_USER is not set.
+func MySQLDSNFromEnv() string {
+	if os.Getenv("MYSQL_USER") == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+		os.Getenv("MYSQL_USER"),
+		os.Getenv("MYSQL_PASSWORD"),
+		os.Getenv("MYSQL_HOST"),
+		os.Getenv("MYSQL_PORT"),
+		os.Getenv("MYSQL_DATABASE"),
+	)
+}
+
 // NewMySQLConnection creates a new MySQL database connection
 func NewMySQLConnection(dsn string) (*MySQLConnection, error) {
 	db, err := sql.Open("mysql", dsn)
@@ -0,0 +1,334 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"shelllab/backend/database"
+)
+
+// Benchmarks for the batched loaders against one query per row, on a seeded
+// test database:
+//
+//	go test -run NONE -bench . ./backend/database/repositories
+//
+// The seeded data has roughly the shape of a raid boss loot table and a long
+// quest chain:
+//   - benchItems items, each with its own icon
+//   - creature 1 drops 40 items and 10 reference tables of 20 items, each
+//     referencing two more tables
+//   - quests 1..benchChainLength form one chain, every quest rewarding 4
+//     items and offering 6 choice items
+//   - benchFavorites favorites
+const (
+	benchItems       = 2000
+	benchChainLength = 40
+	benchFavorites   = 200
+)
+
+func openBenchDB(b *testing.B) *database.SQLiteDB {
+	b.Helper()
+	db := openTestDB(b, b.TempDir())
+	favorites := database.NewFavoriteRepository(db)
+	if err := favorites.InitSchema(); err != nil {
+		b.Fatal(err)
+	}
+	if err := seedBenchDB(db.DB()); err != nil {
+		b.Fatal(err)
+	}
+	for i := 1; i <= benchFavorites; i++ {
+		if err := favorites.AddFavorite(i*7, fmt.Sprintf("list %d", i%5)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return db
+}
+
+func seedBenchDB(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exec := func(query string, args ...interface{}) {
+		if err == nil {
+			_, err = tx.Exec(query, args...)
+		}
+	}
+
+	for i := 1; i <= benchItems; i++ {
+		exec("INSERT INTO item_display_info (ID, icon) VALUES (?, ?)", i, fmt.Sprintf("inv_bench_%d", i))
+		exec("INSERT INTO item_template (entry, name, quality, item_level, display_id) VALUES (?, ?, ?, ?, ?)",
+			i, fmt.Sprintf("Bench Item %d", i), i%6, i%80, i)
+	}
+
+	exec("INSERT INTO creature_template (entry, name, loot_id) VALUES (1, 'Bench Boss', 1)")
+	for i := 1; i <= 40; i++ {
+		exec("INSERT INTO creature_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount) VALUES (1, ?, ?, 1, 1)", i, 10.0)
+	}
+	for ref := 1; ref <= 10; ref++ {
+		exec("INSERT INTO creature_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount) VALUES (1, ?, 100, ?, 1)", 1000+ref, -ref)
+		for i := 1; i <= 20; i++ {
+			exec("INSERT INTO reference_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount) VALUES (?, ?, 5, 1, 1)", ref, 20+ref*20+i)
+		}
+		// Two nested references per table
+		for _, nested := range []int{100 + ref, 110 + ref} {
+			exec("INSERT INTO reference_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount) VALUES (?, ?, 50, ?, 1)", ref, 1000+nested, -nested)
+			for i := 1; i <= 20; i++ {
+				exec("INSERT INTO reference_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount) VALUES (?, ?, 5, 1, 1)", nested, 240+(nested-101)*20+i)
+			}
+		}
+	}
+
+	for q := 1; q <= benchChainLength; q++ {
+		next := q + 1
+		if q == benchChainLength {
+			next = 0
+		}
+		args := []interface{}{q, fmt.Sprintf("Bench Quest %d", q), q - 1, next}
+		for i := 0; i < 10; i++ {
+			args = append(args, (q*10+i)%benchItems+1)
+		}
+		exec(`INSERT INTO quest_template (entry, Title, PrevQuestId, NextQuestInChain,
+			RewItemId1, RewItemId2, RewItemId3, RewItemId4,
+			RewChoiceItemId1, RewChoiceItemId2, RewChoiceItemId3, RewChoiceItemId4, RewChoiceItemId5, RewChoiceItemId6)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...)
+	}
+
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// perRowItem is the single item lookup the loaders ran before batching
+const perRowItem = `
+	SELECT i.name, i.quality, COALESCE(idi.icon, '')
+	FROM item_template i
+	LEFT JOIN item_display_info idi ON i.display_id = idi.ID
+	WHERE i.entry = ?
+`
+
+func queryItem(b *testing.B, db *sql.DB, id int) {
+	var name, icon string
+	var quality int
+	if err := db.QueryRow(perRowItem, id).Scan(&name, &quality, &icon); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkGetCreatureLoot compares the batched loader with one query per
+// loot table and per item
+func BenchmarkGetCreatureLoot(b *testing.B) {
+	db := openBenchDB(b)
+	repo := database.NewLootRepository(db)
+	const want = 40 + 10*20 + 20*20
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			database.PurgeCache(db)
+			loot, err := repo.GetCreatureLoot(1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(loot) != want {
+				b.Fatalf("got %d loot items", len(loot))
+			}
+		}
+	})
+
+	b.Run("perRow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			items := make(map[int]bool)
+			walkLootPerRow(b, db.DB(), "creature_loot_template", 1, items, 0)
+			for id := range items {
+				queryItem(b, db.DB(), id)
+			}
+			if len(items) != want {
+				b.Fatalf("got %d loot items", len(items))
+			}
+		}
+	})
+}
+
+// walkLootPerRow reads a loot table and recurses into each reference
+func walkLootPerRow(b *testing.B, db *sql.DB, table string, entry int, items map[int]bool, depth int) {
+	if depth > 10 {
+		return
+	}
+	rows, err := db.Query("SELECT item, mincountOrRef FROM "+table+" WHERE entry = ?", entry)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var refs []int
+	for rows.Next() {
+		var item, minOrRef int
+		if err := rows.Scan(&item, &minOrRef); err != nil {
+			b.Fatal(err)
+		}
+		if minOrRef < 0 {
+			refs = append(refs, -minOrRef)
+		} else {
+			items[item] = true
+		}
+	}
+	rows.Close()
+	for _, ref := range refs {
+		walkLootPerRow(b, db, "reference_loot_template", ref, items, depth+1)
+	}
+}
+
+// BenchmarkGetQuestDetail compares the batched loader with one query per
+// reward item and per quest of the chain
+func BenchmarkGetQuestDetail(b *testing.B) {
+	db := openBenchDB(b)
+	repo := database.NewQuestRepository(db)
+	const entry = benchChainLength / 2
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			database.PurgeCache(db)
+			q, err := repo.GetQuestDetail(entry)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(q.Series) != benchChainLength || len(q.ChoiceItems) != 6 {
+				b.Fatalf("got %d quests in chain, %d choice items", len(q.Series), len(q.ChoiceItems))
+			}
+		}
+	})
+
+	b.Run("perRow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var title string
+			var prev, next int
+			rewards := make([]interface{}, 10)
+			ptrs := []interface{}{&title, &prev, &next}
+			for j := range rewards {
+				ptrs = append(ptrs, &rewards[j])
+			}
+			err := db.DB().QueryRow(`SELECT Title, PrevQuestId, NextQuestInChain,
+				RewItemId1, RewItemId2, RewItemId3, RewItemId4,
+				RewChoiceItemId1, RewChoiceItemId2, RewChoiceItemId3, RewChoiceItemId4, RewChoiceItemId5, RewChoiceItemId6
+				FROM quest_template WHERE entry = ?`, entry).Scan(ptrs...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, id := range rewards {
+				queryItem(b, db.DB(), int(id.(int64)))
+			}
+
+			// Walk the chain backwards, then forwards, one quest at a time;
+			// every forward step also looked for quests naming it as PrevQuestId
+			chain := 1
+			for id := prev; id != 0; chain++ {
+				if err := db.DB().QueryRow("SELECT Title, IFNULL(PrevQuestId, 0) FROM quest_template WHERE entry = ?", id).Scan(&title, &id); err != nil {
+					b.Fatal(err)
+				}
+			}
+			for id, cur := next, entry; id != 0; chain++ {
+				followers, err := db.DB().Query("SELECT entry FROM quest_template WHERE PrevQuestId = ? OR PrevQuestId = ?", cur, -cur)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for followers.Next() {
+				}
+				followers.Close()
+				cur = id
+				if err := db.DB().QueryRow("SELECT Title, IFNULL(NextQuestInChain, 0) FROM quest_template WHERE entry = ?", id).Scan(&title, &id); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if chain != benchChainLength {
+				b.Fatalf("got %d quests in chain", chain)
+			}
+		}
+	})
+}
+
+// BenchmarkGetAllFavorites compares the single joined query with one item
+// query per favorite
+func BenchmarkGetAllFavorites(b *testing.B) {
+	db := openBenchDB(b)
+	repo := database.NewFavoriteRepository(db)
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			favs, err := repo.GetAllFavorites()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(favs) != benchFavorites {
+				b.Fatalf("got %d favorites", len(favs))
+			}
+		}
+	})
+
+	b.Run("perRow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rows, err := db.DB().Query("SELECT item_entry FROM user.wishlist_items ORDER BY wishlist, added_at DESC")
+			if err != nil {
+				b.Fatal(err)
+			}
+			var ids []int
+			for rows.Next() {
+				var id int
+				if err := rows.Scan(&id); err != nil {
+					b.Fatal(err)
+				}
+				ids = append(ids, id)
+			}
+			rows.Close()
+			for _, id := range ids {
+				queryItem(b, db.DB(), id)
+			}
+			if len(ids) != benchFavorites {
+				b.Fatalf("got %d favorites", len(ids))
+			}
+		}
+	})
+}
+
+// BenchmarkItemSummaries compares the batched loader with one query per item
+func BenchmarkItemSummaries(b *testing.B) {
+	db := openBenchDB(b)
+	repo := database.NewItemRepository(db)
+
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i*13 + 1
+	}
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			database.PurgeCache(db) // Measure the query, not the read cache
+			summaries, err := repo.GetItemSummaries(ids)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(summaries) != len(ids) {
+				b.Fatalf("got %d summaries", len(summaries))
+			}
+		}
+	})
+
+	b.Run("perItem", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, id := range ids {
+				var name, icon string
+				var quality int
+				err := db.DB().QueryRow(`
+					SELECT i.name, i.quality, COALESCE(idi.icon, '')
+					FROM item_template i
+					LEFT JOIN item_display_info idi ON i.display_id = idi.ID
+					WHERE i.entry = ?
+				`, id).Scan(&name, &quality, &icon)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,329 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/models"
+)
+
+// ChangeLogRepository records field-level changes made by sync upserts.
+// The log lives in the attached user database when there is one, so it
+// survives replacing shelllab.db with a new release.
+type ChangeLogRepository struct {
+	db     *sql.DB
+	schema string // "user" or "main"
+}
+
+// NewChangeLogRepository creates a new ChangeLogRepository
+func NewChangeLogRepository(db *sql.DB) *ChangeLogRepository {
+	schema := "main"
+	var attached int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_database_list WHERE name = 'user'`).Scan(&attached); err == nil && attached > 0 {
+		schema = "user"
+	}
+	return &ChangeLogRepository{db: db, schema: schema}
+}
+
+// InitSchema creates the change_log table if not exists, and moves the log
+// older versions kept in shelllab.db into user.db
+func (r *ChangeLogRepository) InitSchema() error {
+	schema := fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %[1]s.change_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		entity_type TEXT NOT NULL,
+		entity_id INTEGER NOT NULL,
+		entity_name TEXT DEFAULT '',
+		action TEXT NOT NULL,
+		field TEXT DEFAULT '',
+		old_value TEXT DEFAULT '',
+		new_value TEXT DEFAULT '',
+		source TEXT DEFAULT '',
+		changed_at TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS %[1]s.idx_change_log_changed_at ON change_log(changed_at);
+	CREATE INDEX IF NOT EXISTS %[1]s.idx_change_log_entity ON change_log(entity_type, entity_id);
+	`, r.schema)
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
+	}
+	if r.schema == "main" {
+		return nil
+	}
+
+	var exists int
+	if err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM main.sqlite_master WHERE type = 'table' AND name = 'change_log'").Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return nil
+	}
+	if err := r.migrateMainLog(); err != nil {
+		return fmt.Errorf("failed to move main.change_log to user.db: %w", err)
+	}
+	return nil
+}
+
+// migrateMainLog appends the entries of main.change_log to user.change_log
+// in the order they were recorded and drops the old table
+func (r *ChangeLogRepository) migrateMainLog() error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO user.change_log (entity_type, entity_id, entity_name, action, field, old_value, new_value, source, changed_at)
+		SELECT entity_type, entity_id, entity_name, action, field, old_value, new_value, source, changed_at
+		FROM main.change_log ORDER BY id
+	`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DROP TABLE main.change_log"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Snapshot reads one row as column -> value strings, or nil if it doesn't exist.
+// table and keyColumn must be trusted identifiers.
+func (r *ChangeLogRepository) Snapshot(table, keyColumn string, id int) (map[string]string, error) {
+	rows, err := r.db.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", table, keyColumn), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return ScanRowMap(rows)
+}
+
+// ScanRowMap scans the current row as column -> value strings,
+// formatted the same way for every driver type so values can be compared
+func ScanRowMap(rows *sql.Rows) (map[string]string, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(cols))
+	for i, col := range cols {
+		row[col] = formatValue(values[i])
+	}
+	return row, nil
+}
+
+// RecordDiff stores the differences between two snapshots of the same record.
+// A nil before snapshot records the record as added. Returns the stored entries.
+func (r *ChangeLogRepository) RecordDiff(entityType string, entityID int, entityName, source string, before, after map[string]string) ([]*models.ChangeLogEntry, error) {
+	if after == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	newEntry := func(action, field, oldValue, newValue string) *models.ChangeLogEntry {
+		return &models.ChangeLogEntry{
+			EntityType: entityType,
+			EntityID:   entityID,
+			EntityName: entityName,
+			Action:     action,
+			Field:      field,
+			OldValue:   oldValue,
+			NewValue:   newValue,
+			Source:     source,
+			ChangedAt:  now,
+		}
+	}
+
+	var entries []*models.ChangeLogEntry
+	if before == nil {
+		entries = append(entries, newEntry(models.ChangeActionAdded, "", "", ""))
+	} else {
+		fields := make([]string, 0, len(after))
+		for field := range after {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if before[field] != after[field] {
+				entries = append(entries, newEntry(models.ChangeActionUpdated, field, before[field], after[field]))
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return entries, r.Record(entries)
+}
+
+// Record inserts change log entries in a single transaction
+func (r *ChangeLogRepository) Record(entries []*models.ChangeLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`
+		INSERT INTO %s.change_log (entity_type, entity_id, entity_name, action, field, old_value, new_value, source, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, r.schema))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		res, err := stmt.Exec(e.EntityType, e.EntityID, e.EntityName, e.Action, e.Field, e.OldValue, e.NewValue, e.Source, e.ChangedAt)
+		if err != nil {
+			return err
+		}
+		e.ID, _ = res.LastInsertId()
+	}
+	return tx.Commit()
+}
+
+// GetChangesInRange returns every entry with since <= changed_at < until in the order
+// they were recorded; empty bounds are open
+func (r *ChangeLogRepository) GetChangesInRange(since, until string) ([]*models.ChangeLogEntry, error) {
+	where, args := rangeClause(since, until)
+	query := `SELECT id, entity_type, entity_id, COALESCE(entity_name, ''), action, COALESCE(field, ''),
+		COALESCE(old_value, ''), COALESCE(new_value, ''), COALESCE(source, ''), changed_at
+		FROM ` + r.schema + `.change_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanChanges(rows), nil
+}
+
+// GetRecentChanges returns change log entries matching the filter, newest first
+func (r *ChangeLogRepository) GetRecentChanges(filter models.ChangeLogFilter) ([]*models.ChangeLogEntry, error) {
+	where, args := rangeClause(filter.Since, filter.Until)
+
+	if filter.EntityType != "" {
+		where = append(where, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		where = append(where, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Field != "" {
+		where = append(where, "field = ?")
+		args = append(args, filter.Field)
+	}
+	if filter.Source != "" {
+		where = append(where, "source = ?")
+		args = append(args, filter.Source)
+	}
+	if filter.Search != "" {
+		where = append(where, "entity_name LIKE ?")
+		args = append(args, "%"+filter.Search+"%")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 5000 {
+		limit = 5000
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := `SELECT id, entity_type, entity_id, COALESCE(entity_name, ''), action, COALESCE(field, ''),
+		COALESCE(old_value, ''), COALESCE(new_value, ''), COALESCE(source, ''), changed_at
+		FROM ` + r.schema + `.change_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY changed_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanChanges(rows), nil
+}
+
+// rangeClause builds the changed_at conditions shared by the change queries
+func rangeClause(since, until string) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+	if since != "" {
+		where = append(where, "changed_at >= ?")
+		args = append(args, since)
+	}
+	if until != "" {
+		where = append(where, "changed_at < ?")
+		args = append(args, until)
+	}
+	return where, args
+}
+
+func scanChanges(rows *sql.Rows) []*models.ChangeLogEntry {
+	var entries []*models.ChangeLogEntry
+	for rows.Next() {
+		e := &models.ChangeLogEntry{}
+		if err := rows.Scan(&e.ID, &e.EntityType, &e.EntityID, &e.EntityName, &e.Action, &e.Field,
+			&e.OldValue, &e.NewValue, &e.Source, &e.ChangedAt); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// formatValue renders a scanned SQLite value the same way regardless of driver type
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
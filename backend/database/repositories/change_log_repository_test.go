@@ -0,0 +1,62 @@
+package repositories_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+)
+
+func TestChangeLogSurvivesGameDatabaseSwap(t *testing.T) {
+	dir := t.TempDir()
+	open := func(stmts ...string) *database.SQLiteDB {
+		db := openTestDB(t, dir, stmts...)
+		if err := database.NewChangeLogRepository(db).InitSchema(); err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+
+	// Older versions kept the log in shelllab.db
+	openTestDB(t, dir,
+		`CREATE TABLE main.change_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, entity_type TEXT NOT NULL, entity_id INTEGER NOT NULL,
+			entity_name TEXT DEFAULT '', action TEXT NOT NULL, field TEXT DEFAULT '', old_value TEXT DEFAULT '',
+			new_value TEXT DEFAULT '', source TEXT DEFAULT '', changed_at TEXT NOT NULL)`,
+		`INSERT INTO main.change_log (entity_type, entity_id, entity_name, action, changed_at)
+			VALUES ('item', 1, 'Hearthstone', 'added', '2024-01-01T00:00:00Z')`,
+	).Close()
+
+	db := open()
+	if left := queryStrings(t, db, `SELECT name FROM main.sqlite_master WHERE name = 'change_log'`); len(left) > 0 {
+		t.Error("main.change_log was not dropped")
+	}
+	if err := database.NewChangeLogRepository(db).Record([]*models.ChangeLogEntry{{
+		EntityType: models.ChangeEntityItem, EntityID: 2, EntityName: "Linen Cloth",
+		Action: models.ChangeActionAdded, ChangedAt: "2024-02-01T00:00:00Z",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// A new release replaces shelllab.db, user.db stays
+	if err := os.Remove(filepath.Join(dir, "shelllab.db")); err != nil {
+		t.Fatal(err)
+	}
+	db = open()
+
+	entries, err := database.NewChangeLogRepository(db).GetChangesInRange("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.EntityName)
+	}
+	if want := []string{"Hearthstone", "Linen Cloth"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("change log after swap = %v, want %v", got, want)
+	}
+}
@@ -51,22 +51,35 @@ func (r *CreatureRepository) GetCreatureTypes() ([]*models.CreatureType, error)
 	return types, nil
 }
 
-// GetCreaturesByType returns creatures filtered by type
-func (r *CreatureRepository) GetCreaturesByType(creatureType int, nameFilter string, limit, offset int) ([]*models.Creature, int, error) {
+// creatureSorts are the sort keys of the creature browse list
+var creatureSorts = sortColumns{
+	models.SortName:  "name",
+	models.SortLevel: "level_max",
+}
+
+// GetCreaturesByType returns a page of creatures filtered by type
+func (r *CreatureRepository) GetCreaturesByType(creatureType int, nameFilter string, page models.PageQuery) (*models.Page[*models.Creature], error) {
+	limit, offset, err := pageWindow(page)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := creatureSorts.orderBy(page, "level_max DESC, name, entry", "entry")
+	if err != nil {
+		return nil, err
+	}
+
 	whereClause := "WHERE type = ?"
 	args := []interface{}{creatureType}
 
-	if nameFilter != "" {
-		whereClause += " AND name LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
-	}
+	filter, filterArgs := nameFilterClause("name", "entry", nameFilter)
+	whereClause += filter
+	args = append(args, filterArgs...)
 
 	// Count
 	var count int
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM creature_template %s", whereClause)
-	err := r.db.QueryRow(countQuery, args...).Scan(&count)
-	if err != nil {
-		return nil, 0, err
+	if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+		return nil, err
 	}
 
 	// Data
@@ -77,13 +90,13 @@ func (r *CreatureRepository) GetCreaturesByType(creatureType int, nameFilter str
 			type, rank, faction, npc_flags
 		FROM creature_template
 		%s
-		ORDER BY level_max DESC, name
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, whereClause, orderBy)
 
 	rows, err := r.db.Query(dataQuery, dataArgs...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -107,7 +120,7 @@ func (r *CreatureRepository) GetCreaturesByType(creatureType int, nameFilter str
 		creatures = append(creatures, c)
 	}
 
-	return creatures, count, nil
+	return newPage(creatures, count, offset), nil
 }
 
 // SearchCreatures searches for creatures by name or ID
@@ -358,22 +371,19 @@ func (r *CreatureRepository) getCreatureDetailMySQL(entry int) (*models.Creature
 			var itemID int
 			lRows.Scan(&itemID, &li.Chance, &li.MinCount, &li.MaxCount)
 			li.ItemID = itemID
-
-			// Enrich with local item data
-			var iName, iIcon string
-			var iQual int
-			r.db.QueryRow(`
-				SELECT i.name, i.quality, COALESCE(idi.icon, '') 
-				FROM item_template i 
-				LEFT JOIN item_display_info idi ON i.display_id = idi.ID 
-				WHERE i.entry = ?
-			`, itemID).Scan(&iName, &iQual, &iIcon)
-			li.Name = iName
-			li.Quality = iQual
-			li.IconPath = iIcon
-
 			detail.Loot = append(detail.Loot, li)
 		}
+
+		// Enrich with local item data
+		if summaries, err := loadItemSummaries(r.db, lootItemIDs(detail.Loot)); err == nil {
+			for _, li := range detail.Loot {
+				if s, ok := summaries[li.ItemID]; ok {
+					li.Name = s.Name
+					li.Quality = s.Quality
+					li.IconPath = s.IconPath
+				}
+			}
+		}
 	}
 
 	// 5. Quests (Starts)
@@ -3,106 +3,94 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"shelllab/backend/database/models"
 )
 
-// FavoriteRepository handles favorite item operations
+// FavoriteRepository keeps the favorites API of versions before wishlists
+// working on top of them. A favorite is an item on any wishlist, and its
+// category is the name of the list, empty for the default list.
 type FavoriteRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	lists *WishlistRepository
 }
 
 // NewFavoriteRepository creates a new FavoriteRepository
 func NewFavoriteRepository(db *sql.DB) *FavoriteRepository {
-	return &FavoriteRepository{db: db}
+	return &FavoriteRepository{db: db, lists: NewWishlistRepository(db)}
 }
 
-// InitSchema creates the favorites table if not exists
+// InitSchema creates the wishlist tables favorites are kept in
 func (r *FavoriteRepository) InitSchema() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS favorites (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		item_entry INTEGER NOT NULL,
-		category TEXT DEFAULT '',
-		added_at TEXT NOT NULL,
-		UNIQUE(item_entry)
-	);
-	CREATE INDEX IF NOT EXISTS idx_favorites_item ON favorites(item_entry);
-	CREATE INDEX IF NOT EXISTS idx_favorites_category ON favorites(category);
-	`
-	_, err := r.db.Exec(schema)
-	if err != nil {
-		return err
-	}
-
-	// Try to add status column if it doesn't exist
-	// We ignore the error "duplicate column name" if it already exists
-	_, _ = r.db.Exec(`ALTER TABLE favorites ADD COLUMN status INTEGER DEFAULT 0`)
+	return r.lists.InitSchema()
+}
 
-	return nil
+// categoryList returns the wishlist of a favorites category
+func categoryList(category string) string {
+	if category = strings.TrimSpace(category); category != "" {
+		return category
+	}
+	return models.DefaultWishlist
 }
 
-// AddFavorite adds an item to favorites
+// favoriteColumns selects a wishlist item w as a favorite, with its item summary
+const favoriteColumns = `
+	SELECT w.id, w.item_entry, CASE WHEN w.wishlist = '` + models.DefaultWishlist + `' THEN '' ELSE w.wishlist END,
+	       w.added_at, COALESCE(w.status, 0),
+	       ` + itemSummaryColumns + `
+	FROM user.wishlist_items w
+`
+
+// AddFavorite adds an item to the wishlist named by category, creating the
+// list if needed. An item already on it is left as it is.
 func (r *FavoriteRepository) AddFavorite(itemEntry int, category string) error {
-	now := time.Now().Format(time.RFC3339)
+	list := categoryList(category)
+	if err := r.lists.ensureWishlist(list); err != nil {
+		return err
+	}
 	_, err := r.db.Exec(`
-		INSERT OR REPLACE INTO favorites (item_entry, category, added_at, status)
-		VALUES (?, ?, ?, 0)
-	`, itemEntry, category, now)
+		INSERT OR IGNORE INTO user.wishlist_items (wishlist, item_entry, added_at)
+		VALUES (?, ?, ?)
+	`, list, itemEntry, time.Now().Format(time.RFC3339))
 	return err
 }
 
-// RemoveFavorite removes an item from favorites
+// RemoveFavorite removes an item from every wishlist
 func (r *FavoriteRepository) RemoveFavorite(itemEntry int) error {
-	_, err := r.db.Exec(`DELETE FROM favorites WHERE item_entry = ?`, itemEntry)
+	_, err := r.db.Exec(`DELETE FROM user.wishlist_items WHERE item_entry = ?`, itemEntry)
 	return err
 }
 
-// IsFavorite checks if an item is in favorites
+// IsFavorite checks if an item is on any wishlist
 func (r *FavoriteRepository) IsFavorite(itemEntry int) (bool, error) {
 	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM favorites WHERE item_entry = ?`, itemEntry).Scan(&count)
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM user.wishlist_items WHERE item_entry = ?`, itemEntry).Scan(&count)
 	return count > 0, err
 }
 
-// GetFavorite gets a single favorite by item entry
+// GetFavorite gets the first wishlist entry of an item
 func (r *FavoriteRepository) GetFavorite(itemEntry int) (*models.FavoriteItem, error) {
-	row := r.db.QueryRow(`
-		SELECT f.id, f.item_entry, f.category, f.added_at,
-		       COALESCE(i.name, ''), COALESCE(i.quality, 0), 
-		       COALESCE(di.icon, ''), COALESCE(i.item_level, 0),
-		       COALESCE(f.status, 0)
-		FROM favorites f
-		LEFT JOIN item_template i ON f.item_entry = i.entry
-		LEFT JOIN item_display_info di ON i.display_id = di.id
-		WHERE f.item_entry = ?
-	`, itemEntry)
+	row := r.db.QueryRow(favoriteColumns+itemSummaryJoin("w.item_entry")+`
+		WHERE w.item_entry = ?
+		ORDER BY w.wishlist = ? DESC, w.id
+		LIMIT 1
+	`, itemEntry, models.DefaultWishlist)
 
 	fav := &models.FavoriteItem{}
-	err := row.Scan(
-		&fav.ID, &fav.ItemEntry, &fav.Category, &fav.AddedAt,
-		&fav.ItemName, &fav.ItemQuality, &fav.IconPath, &fav.ItemLevel,
-		&fav.Status,
-	)
+	err := scanFavorite(row, fav)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return fav, err
 }
 
-// GetAllFavorites returns all favorites with item details
+// GetAllFavorites returns the items of every wishlist with item details
 func (r *FavoriteRepository) GetAllFavorites() ([]*models.FavoriteItem, error) {
-	rows, err := r.db.Query(`
-		SELECT f.id, f.item_entry, f.category, f.added_at,
-		       COALESCE(i.name, ''), COALESCE(i.quality, 0), 
-		       COALESCE(di.icon, ''), COALESCE(i.item_level, 0),
-		       COALESCE(f.status, 0)
-		FROM favorites f
-		LEFT JOIN item_template i ON f.item_entry = i.entry
-		LEFT JOIN item_display_info di ON i.display_id = di.id
-		ORDER BY f.category, f.added_at DESC
-	`)
+	rows, err := r.db.Query(favoriteColumns+itemSummaryJoin("w.item_entry")+`
+		ORDER BY w.wishlist = ? DESC, w.wishlist, w.added_at DESC
+	`, models.DefaultWishlist)
 	if err != nil {
 		return nil, err
 	}
@@ -111,11 +99,7 @@ func (r *FavoriteRepository) GetAllFavorites() ([]*models.FavoriteItem, error) {
 	var items []*models.FavoriteItem
 	for rows.Next() {
 		fav := &models.FavoriteItem{}
-		if err := rows.Scan(
-			&fav.ID, &fav.ItemEntry, &fav.Category, &fav.AddedAt,
-			&fav.ItemName, &fav.ItemQuality, &fav.IconPath, &fav.ItemLevel,
-			&fav.Status,
-		); err != nil {
+		if err := scanFavorite(rows, fav); err != nil {
 			fmt.Printf("Error scanning favorite: %v\n", err)
 			continue
 		}
@@ -124,19 +108,12 @@ func (r *FavoriteRepository) GetAllFavorites() ([]*models.FavoriteItem, error) {
 	return items, nil
 }
 
-// GetFavoritesByCategory returns favorites filtered by category
+// GetFavoritesByCategory returns the items of the wishlist named by category
 func (r *FavoriteRepository) GetFavoritesByCategory(category string) ([]*models.FavoriteItem, error) {
-	rows, err := r.db.Query(`
-		SELECT f.id, f.item_entry, f.category, f.added_at,
-		       COALESCE(i.name, ''), COALESCE(i.quality, 0), 
-		       COALESCE(di.icon, ''), COALESCE(i.item_level, 0),
-		       COALESCE(f.status, 0)
-		FROM favorites f
-		LEFT JOIN item_template i ON f.item_entry = i.entry
-		LEFT JOIN item_display_info di ON i.display_id = di.id
-		WHERE f.category = ?
-		ORDER BY f.added_at DESC
-	`, category)
+	rows, err := r.db.Query(favoriteColumns+itemSummaryJoin("w.item_entry")+`
+		WHERE w.wishlist = ?
+		ORDER BY w.added_at DESC
+	`, categoryList(category))
 	if err != nil {
 		return nil, err
 	}
@@ -145,11 +122,7 @@ func (r *FavoriteRepository) GetFavoritesByCategory(category string) ([]*models.
 	var items []*models.FavoriteItem
 	for rows.Next() {
 		fav := &models.FavoriteItem{}
-		if err := rows.Scan(
-			&fav.ID, &fav.ItemEntry, &fav.Category, &fav.AddedAt,
-			&fav.ItemName, &fav.ItemQuality, &fav.IconPath, &fav.ItemLevel,
-			&fav.Status,
-		); err != nil {
+		if err := scanFavorite(rows, fav); err != nil {
 			continue
 		}
 		items = append(items, fav)
@@ -157,14 +130,22 @@ func (r *FavoriteRepository) GetFavoritesByCategory(category string) ([]*models.
 	return items, nil
 }
 
-// GetCategories returns all distinct categories with item counts
+// scanFavorite scans a favoriteColumns row
+func scanFavorite(row interface{ Scan(...interface{}) error }, fav *models.FavoriteItem) error {
+	return row.Scan(
+		&fav.ID, &fav.ItemEntry, &fav.Category, &fav.AddedAt, &fav.Status,
+		&fav.ItemName, &fav.ItemQuality, &fav.ItemLevel, &fav.IconPath,
+	)
+}
+
+// GetCategories returns the wishlists that have items, with item counts
 func (r *FavoriteRepository) GetCategories() ([]*models.FavoriteCategory, error) {
 	rows, err := r.db.Query(`
-		SELECT COALESCE(category, '') as cat, COUNT(*) as cnt
-		FROM favorites
-		GROUP BY category
-		ORDER BY category
-	`)
+		SELECT CASE WHEN wishlist = ? THEN '' ELSE wishlist END AS cat, COUNT(*) AS cnt
+		FROM user.wishlist_items
+		GROUP BY wishlist
+		ORDER BY cat
+	`, models.DefaultWishlist)
 	if err != nil {
 		return nil, err
 	}
@@ -181,21 +162,29 @@ func (r *FavoriteRepository) GetCategories() ([]*models.FavoriteCategory, error)
 	return cats, nil
 }
 
-// UpdateCategory updates the category of a favorite
+// UpdateCategory moves an item onto the wishlist named by category, creating
+// the list if needed. Entries already on that list are replaced.
 func (r *FavoriteRepository) UpdateCategory(itemEntry int, category string) error {
-	_, err := r.db.Exec(`UPDATE favorites SET category = ? WHERE item_entry = ?`, category, itemEntry)
+	list := categoryList(category)
+	if err := r.lists.ensureWishlist(list); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`UPDATE OR REPLACE user.wishlist_items SET wishlist = ? WHERE item_entry = ?`, list, itemEntry)
 	return err
 }
 
-// UpdateStatus updates the status of a favorite
+// UpdateStatus updates the status of an item on every wishlist
 func (r *FavoriteRepository) UpdateStatus(itemEntry int, status int) error {
-	_, err := r.db.Exec(`UPDATE favorites SET status = ? WHERE item_entry = ?`, status, itemEntry)
+	if err := checkWishlistStatus(status); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(setStatusSQL+` WHERE item_entry = ?3`, status, time.Now().Format(time.RFC3339), itemEntry)
 	return err
 }
 
-// GetFavoriteCount returns the total number of favorites
+// GetFavoriteCount returns the number of distinct items on wishlists
 func (r *FavoriteRepository) GetFavoriteCount() (int, error) {
 	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM favorites`).Scan(&count)
+	err := r.db.QueryRow(`SELECT COUNT(DISTINCT item_entry) FROM user.wishlist_items`).Scan(&count)
 	return count, err
 }
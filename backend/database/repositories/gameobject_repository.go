@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"database/sql"
+	"fmt"
 
 	"shelllab/backend/database/models"
 )
@@ -58,12 +59,26 @@ func (r *GameObjectRepository) GetObjectTypes() ([]*models.ObjectType, error) {
 	return types, nil
 }
 
-// GetObjectsByType returns objects filtered by type
-func (r *GameObjectRepository) GetObjectsByType(typeID int, nameFilter string) ([]*models.GameObject, error) {
-	var query string
-	var args []interface{}
+// objectSorts are the sort keys of the object browse list
+var objectSorts = sortColumns{
+	models.SortName: "o.name",
+}
 
-	baseSelect := "SELECT entry, name, type, displayId as display_id, size FROM gameobject_template o"
+// GetObjectsByType returns a page of objects filtered by type. Negative types
+// are the derived categories of GetObjectTypes (-3 herbalism, -4 mining,
+// -5 lockpicking).
+func (r *GameObjectRepository) GetObjectsByType(typeID int, nameFilter string, page models.PageQuery) (*models.Page[*models.GameObject], error) {
+	limit, offset, err := pageWindow(page)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := objectSorts.orderBy(page, "o.name, o.entry", "o.entry")
+	if err != nil {
+		return nil, err
+	}
+
+	var from string
+	var args []interface{}
 
 	if typeID < 0 {
 		var propID int
@@ -75,23 +90,26 @@ func (r *GameObjectRepository) GetObjectsByType(typeID int, nameFilter string) (
 		case -5:
 			propID = 1
 		}
-		query = baseSelect + `
+		from = `gameobject_template o
 			JOIN locks l ON o.data0 = l.id
-			WHERE o.type = 3 AND (l.prop1 = ? OR l.prop2 = ? OR l.prop3 = ? OR l.prop4 = ? OR l.prop5 = ?)
-		`
+			WHERE o.type = 3 AND (l.prop1 = ? OR l.prop2 = ? OR l.prop3 = ? OR l.prop4 = ? OR l.prop5 = ?)`
 		args = append(args, propID, propID, propID, propID, propID)
 	} else {
-		query = baseSelect + " WHERE o.type = ?"
+		from = "gameobject_template o WHERE o.type = ?"
 		args = append(args, typeID)
 	}
 
-	if nameFilter != "" {
-		query += " AND o.name LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
+	filter, filterArgs := nameFilterClause("o.name", "o.entry", nameFilter)
+	from += filter
+	args = append(args, filterArgs...)
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM "+from, args...).Scan(&count); err != nil {
+		return nil, err
 	}
-	query += " ORDER BY o.name LIMIT 10000"
 
-	rows, err := r.db.Query(query, args...)
+	query := fmt.Sprintf("SELECT o.entry, o.name, o.type, o.displayId, o.size FROM %s ORDER BY %s LIMIT ? OFFSET ?", from, orderBy)
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +123,7 @@ func (r *GameObjectRepository) GetObjectsByType(typeID int, nameFilter string) (
 		}
 		objects = append(objects, o)
 	}
-	return objects, nil
+	return newPage(objects, count, offset), nil
 }
 
 // SearchObjects searches for objects by name
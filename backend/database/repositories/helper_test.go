@@ -0,0 +1,50 @@
+package repositories_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"shelllab/backend/database"
+)
+
+// openTestDB opens dir/shelllab.db with dir/user.db attached, creates the
+// game schema and runs stmts. The database is closed when the test ends.
+func openTestDB(tb testing.TB, dir string, stmts ...string) *database.SQLiteDB {
+	tb.Helper()
+	db, err := database.NewSQLiteDBWithUserData(filepath.Join(dir, "shelllab.db"), filepath.Join(dir, "user.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	if err := db.InitSchema(); err != nil {
+		tb.Fatal(err)
+	}
+	for _, stmt := range stmts {
+		if _, err := db.DB().Exec(stmt); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return db
+}
+
+// queryStrings returns the first column of every row of query
+func queryStrings(t *testing.T, db *database.SQLiteDB, query string) []string {
+	t.Helper()
+	rows, err := db.DB().Query(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	result := []string{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
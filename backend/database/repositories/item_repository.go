@@ -16,12 +16,13 @@ import (
 
 // ItemRepository handles item-related database operations
 type ItemRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	cache *ReadCache
 }
 
 // NewItemRepository creates a new item repository
 func NewItemRepository(db *sql.DB) *ItemRepository {
-	return &ItemRepository{db: db}
+	return &ItemRepository{db: db, cache: CacheFor(db)}
 }
 
 // SearchItems searches for items by name
@@ -199,8 +200,8 @@ func (r *ItemRepository) GetItemClasses() ([]*models.ItemClass, error) {
 	return classes, nil
 }
 
-// GetItemsByClass returns items filtered by class and subclass
-func (r *ItemRepository) GetItemsByClass(class, subClass int, nameFilter string, limit, offset int) ([]*models.Item, int, error) {
+// GetItemsByClass returns a page of items filtered by class and subclass
+func (r *ItemRepository) GetItemsByClass(class, subClass int, nameFilter string, page models.PageQuery) (*models.Page[*models.Item], error) {
 	// For weapons (class 2), when querying base types, also include the dedicated Two-Handed subclass
 	// subclass 0 (Axe) -> also include subclass 1 (Two-Handed Axe)
 	// subclass 4 (Mace) -> also include subclass 5 (Two-Handed Mace)
@@ -228,64 +229,11 @@ func (r *ItemRepository) GetItemsByClass(class, subClass int, nameFilter string,
 		args = []interface{}{class, subClass}
 	}
 
-	if nameFilter != "" {
-		whereClause += " AND name LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
-	}
-
-	// Count
-	var count int
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM item_template %s", whereClause)
-	err := r.db.QueryRow(countQuery, args...).Scan(&count)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Data
-	dataArgs := append(args, limit, offset)
-	dataQuery := fmt.Sprintf(`
-		SELECT entry, name, quality, item_level, required_level, class, subclass, inventory_type, COALESCE(d.icon, ''),
-			armor,
-			stat_type1, stat_value1, stat_type2, stat_value2, stat_type3, stat_value3, stat_type4, stat_value4, stat_type5, stat_value5,
-			stat_type6, stat_value6, stat_type7, stat_value7, stat_type8, stat_value8, stat_type9, stat_value9, stat_type10, stat_value10,
-			holy_res, fire_res, nature_res, frost_res, shadow_res, arcane_res
-		FROM item_template t
-		LEFT JOIN item_display_info d ON t.display_id = d.ID
-		%s
-		ORDER BY quality DESC, item_level DESC
-		LIMIT ? OFFSET ?
-	`, whereClause)
-
-	rows, err := r.db.Query(dataQuery, dataArgs...)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var items []*models.Item
-	for rows.Next() {
-		item := &models.Item{}
-		err := rows.Scan(
-			&item.Entry, &item.Name, &item.Quality, &item.ItemLevel,
-			&item.RequiredLevel, &item.Class, &item.SubClass, &item.InventoryType, &item.IconPath,
-			&item.Armor,
-			&item.StatType1, &item.StatValue1, &item.StatType2, &item.StatValue2, &item.StatType3, &item.StatValue3,
-			&item.StatType4, &item.StatValue4, &item.StatType5, &item.StatValue5, &item.StatType6, &item.StatValue6,
-			&item.StatType7, &item.StatValue7, &item.StatType8, &item.StatValue8, &item.StatType9, &item.StatValue9,
-			&item.StatType10, &item.StatValue10,
-			&item.HolyRes, &item.FireRes, &item.NatureRes, &item.FrostRes, &item.ShadowRes, &item.ArcaneRes,
-		)
-		if err != nil {
-			continue
-		}
-		items = append(items, item)
-	}
-
-	return items, count, nil
+	return r.browseItems(whereClause, args, nameFilter, page)
 }
 
-// GetItemsByClassAndSlot returns items filtered by class, subclass, and inventory type
-func (r *ItemRepository) GetItemsByClassAndSlot(class, subClass, inventoryType int, nameFilter string, limit, offset int) ([]*models.Item, int, error) {
+// GetItemsByClassAndSlot returns a page of items filtered by class, subclass, and inventory type
+func (r *ItemRepository) GetItemsByClassAndSlot(class, subClass, inventoryType int, nameFilter string, page models.PageQuery) (*models.Page[*models.Item], error) {
 	// For weapons (class 2), when querying Two-Hand slot (17), also include the dedicated Two-Handed subclass
 	// subclass 0 (Axe) + inv 17 -> also include subclass 1 (Two-Handed Axe)
 	// subclass 4 (Mace) + inv 17 -> also include subclass 5 (Two-Handed Mace)
@@ -313,17 +261,39 @@ func (r *ItemRepository) GetItemsByClassAndSlot(class, subClass, inventoryType i
 		args = []interface{}{class, subClass, inventoryType}
 	}
 
-	if nameFilter != "" {
-		whereClause += " AND name LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
+	return r.browseItems(whereClause, args, nameFilter, page)
+}
+
+// itemSorts are the sort keys of the item browse lists. DPS uses the main
+// damage range, as the tooltip does.
+var itemSorts = sortColumns{
+	models.SortName:      "t.name",
+	models.SortLevel:     "t.required_level",
+	models.SortQuality:   "t.quality",
+	models.SortItemLevel: "t.item_level",
+	models.SortDPS:       "CASE WHEN t.delay > 0 THEN (t.dmg_min1 + t.dmg_max1) * 500.0 / t.delay ELSE 0 END",
+}
+
+// browseItems returns one page of the items matching whereClause
+func (r *ItemRepository) browseItems(whereClause string, args []interface{}, nameFilter string, page models.PageQuery) (*models.Page[*models.Item], error) {
+	limit, offset, err := pageWindow(page)
+	if err != nil {
+		return nil, err
 	}
+	orderBy, err := itemSorts.orderBy(page, "t.quality DESC, t.item_level DESC, t.entry", "t.entry")
+	if err != nil {
+		return nil, err
+	}
+
+	filter, filterArgs := nameFilterClause("t.name", "t.entry", nameFilter)
+	whereClause += filter
+	args = append(args, filterArgs...)
 
 	// Count
 	var count int
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM item_template %s", whereClause)
-	err := r.db.QueryRow(countQuery, args...).Scan(&count)
-	if err != nil {
-		return nil, 0, err
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM item_template t %s", whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+		return nil, err
 	}
 
 	// Data
@@ -337,13 +307,13 @@ func (r *ItemRepository) GetItemsByClassAndSlot(class, subClass, inventoryType i
 		FROM item_template t
 		LEFT JOIN item_display_info d ON t.display_id = d.ID
 		%s
-		ORDER BY quality DESC, item_level DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, whereClause, orderBy)
 
 	rows, err := r.db.Query(dataQuery, dataArgs...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -366,7 +336,7 @@ func (r *ItemRepository) GetItemsByClassAndSlot(class, subClass, inventoryType i
 		items = append(items, item)
 	}
 
-	return items, count, nil
+	return newPage(items, count, offset), nil
 }
 
 // AdvancedSearch performs a multi-dimensional search on items
@@ -378,105 +348,30 @@ func (r *ItemRepository) AdvancedSearch(filter models.SearchFilter) (*models.Sea
 		filter.Limit = 200
 	}
 
-	var conditions []string
-	var args []interface{}
-
-	// Name or ID filter
-	if filter.Query != "" {
-		// Check if query is a number (ID search)
-		if id, err := strconv.Atoi(filter.Query); err == nil {
-			conditions = append(conditions, "entry = ?")
-			args = append(args, id)
-		} else {
-			// Text search by name
-			conditions = append(conditions, "name LIKE ?")
-			args = append(args, "%"+filter.Query+"%")
-		}
-	}
-
-	// Quality filter
-	if len(filter.Quality) > 0 {
-		placeholders := make([]string, len(filter.Quality))
-		for i, q := range filter.Quality {
-			placeholders[i] = "?"
-			args = append(args, q)
-		}
-		conditions = append(conditions, fmt.Sprintf("quality IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	// Class filter
-	if len(filter.Class) > 0 {
-		placeholders := make([]string, len(filter.Class))
-		for i, c := range filter.Class {
-			placeholders[i] = "?"
-			args = append(args, c)
-		}
-		conditions = append(conditions, fmt.Sprintf("class IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	// SubClass filter
-	if len(filter.SubClass) > 0 {
-		placeholders := make([]string, len(filter.SubClass))
-		for i, sc := range filter.SubClass {
-			placeholders[i] = "?"
-			args = append(args, sc)
-		}
-		conditions = append(conditions, fmt.Sprintf("subclass IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	// InventoryType filter
-	if len(filter.InventoryType) > 0 {
-		placeholders := make([]string, len(filter.InventoryType))
-		for i, it := range filter.InventoryType {
-			placeholders[i] = "?"
-			args = append(args, it)
-		}
-		conditions = append(conditions, fmt.Sprintf("inventory_type IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	// Level Range
-	if filter.MinLevel > 0 {
-		conditions = append(conditions, "item_level >= ?")
-		args = append(args, filter.MinLevel)
-	}
-	if filter.MaxLevel > 0 {
-		conditions = append(conditions, "item_level <= ?")
-		args = append(args, filter.MaxLevel)
-	}
-
-	// Required Level Range
-	if filter.MinReqLevel > 0 {
-		conditions = append(conditions, "required_level >= ?")
-		args = append(args, filter.MinReqLevel)
-	}
-	if filter.MaxReqLevel > 0 {
-		conditions = append(conditions, "required_level <= ?")
-		args = append(args, filter.MaxReqLevel)
+	orderBy, err := itemSorts.orderBy(models.PageQuery{Sort: filter.Sort, Desc: filter.Desc}, "t.quality DESC, t.item_level DESC", "t.entry")
+	if err != nil {
+		return nil, err
 	}
 
-	// Build WHERE clause
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause, args := searchWhere(filter)
 
 	// Count query
-	countQuery := "SELECT COUNT(*) FROM item_template " + whereClause
+	countQuery := "SELECT COUNT(*) FROM item_template t " + whereClause
 	var totalCount int
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err = r.db.QueryRow(countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, fmt.Errorf("search count error: %w", err)
 	}
 
 	// Data query
 	dataQuery := fmt.Sprintf(`
-		SELECT entry, name, quality, item_level, required_level, class, subclass, inventory_type, COALESCE(d.icon, '')
+		SELECT t.entry, t.name, t.quality, t.item_level, t.required_level, t.class, t.subclass, t.inventory_type, COALESCE(d.icon, '')
 		FROM item_template t
 		LEFT JOIN item_display_info d ON t.display_id = d.ID
 		%s
-		ORDER BY quality DESC, item_level DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`, whereClause, orderBy)
 
 	// Add limit/offset args
 	args = append(args, filter.Limit, filter.Offset)
@@ -506,6 +401,86 @@ func (r *ItemRepository) AdvancedSearch(filter models.SearchFilter) (*models.Sea
 	}, nil
 }
 
+// searchWhere builds the WHERE clause of an AdvancedSearch over item_template t
+func searchWhere(filter models.SearchFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	// Name or ID filter
+	if filter.Query != "" {
+		// Check if query is a number (ID search)
+		if id, err := strconv.Atoi(filter.Query); err == nil {
+			conditions = append(conditions, "t.entry = ?")
+			args = append(args, id)
+		} else {
+			// Text search by name
+			conditions = append(conditions, "t.name LIKE ?")
+			args = append(args, "%"+filter.Query+"%")
+		}
+	}
+
+	// Quality, class, subclass and slot filters
+	for _, f := range []struct {
+		column string
+		values []int
+	}{
+		{"t.quality", filter.Quality},
+		{"t.class", filter.Class},
+		{"t.subclass", filter.SubClass},
+		{"t.inventory_type", filter.InventoryType},
+	} {
+		if len(f.values) > 0 {
+			in, inArgs := inClause(f.values)
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", f.column, in))
+			args = append(args, inArgs...)
+		}
+	}
+
+	// Level Range
+	if filter.MinLevel > 0 {
+		conditions = append(conditions, "t.item_level >= ?")
+		args = append(args, filter.MinLevel)
+	}
+	if filter.MaxLevel > 0 {
+		conditions = append(conditions, "t.item_level <= ?")
+		args = append(args, filter.MaxLevel)
+	}
+
+	// Required Level Range
+	if filter.MinReqLevel > 0 {
+		conditions = append(conditions, "t.required_level >= ?")
+		args = append(args, filter.MinReqLevel)
+	}
+	if filter.MaxReqLevel > 0 {
+		conditions = append(conditions, "t.required_level <= ?")
+		args = append(args, filter.MaxReqLevel)
+	}
+
+	// Every stat type must be on the item; empty slots have type 0 and value 0
+	for _, stat := range filter.Stats {
+		slots := make([]string, 10)
+		for i := range slots {
+			slots[i] = fmt.Sprintf("(t.stat_type%d = ? AND t.stat_value%d <> 0)", i+1, i+1)
+			args = append(args, stat)
+		}
+		conditions = append(conditions, "("+strings.Join(slots, " OR ")+")")
+	}
+
+	// Spell effect, matched against the descriptions of the item's spells
+	if effect := strings.TrimSpace(filter.Effect); effect != "" {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM spell_template s
+			WHERE s.entry IN (t.spellid_1, t.spellid_2, t.spellid_3, t.spellid_4, t.spellid_5)
+			  AND s.entry > 0 AND s.description LIKE ?)`)
+		args = append(args, "%"+effect+"%")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // GetItemSets returns all item sets for browsing
 func (r *ItemRepository) GetItemSets() ([]*models.ItemSetBrowse, error) {
 	rows, err := r.db.Query(`
@@ -621,8 +596,16 @@ func (r *ItemRepository) GetItemSetDetail(itemSetID int) (*models.ItemSetDetail,
 	return detail, nil
 }
 
-// GetTooltipData generates tooltip information for an item
+// GetTooltipData generates tooltip information for an item. Tooltips are
+// cached and shared between callers, which must not modify them.
 func (r *ItemRepository) GetTooltipData(itemID int) (*models.TooltipData, error) {
+	return r.cache.tooltips.Load(itemID, func() (*models.TooltipData, error) {
+		return r.loadTooltipData(itemID)
+	})
+}
+
+// loadTooltipData builds the tooltip of an item from the database
+func (r *ItemRepository) loadTooltipData(itemID int) (*models.TooltipData, error) {
 	item, err := r.GetItemByID(itemID)
 	if err != nil {
 		return nil, err
@@ -808,9 +791,18 @@ type SpellData struct {
 	MaxAffectedTargets int
 }
 
-// resolveSpellText fetches and formats spell description with parameters
-// Implements complete WoW spell variable replacement system
+// resolveSpellText fetches and formats spell description with parameters,
+// cached by spell
 func (r *ItemRepository) resolveSpellText(spellID int) string {
+	text, _ := r.cache.spellTexts.Load(spellID, func() (string, error) {
+		return r.loadSpellText(spellID)
+	})
+	return text
+}
+
+// loadSpellText fetches and formats spell description with parameters
+// Implements complete WoW spell variable replacement system
+func (r *ItemRepository) loadSpellText(spellID int) (string, error) {
 	var name, description string
 	var data SpellData
 
@@ -839,8 +831,11 @@ func (r *ItemRepository) resolveSpellText(spellID int) string {
 		&data.DmgMultiplier1, &data.MaxAffectedTargets,
 	)
 
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
 	if err != nil {
-		return ""
+		return "", err
 	}
 
 	// Use description if available, otherwise use name
@@ -849,13 +844,13 @@ func (r *ItemRepository) resolveSpellText(spellID int) string {
 		text = name
 	}
 	if text == "" {
-		return ""
+		return "", nil
 	}
 
 	// Replace all variable types
 	text = r.replaceSpellVariables(text, spellID, &data)
 
-	return text
+	return text, nil
 }
 
 // replaceSpellVariables replaces all WoW spell variables in text
@@ -985,16 +980,14 @@ func (r *ItemRepository) replaceSpellVariables(text string, spellID int, data *S
 	// $a1, $a2, $a3 - area/radius
 	for i := 0; i < 3; i++ {
 		if data.RadiusIndex[i] > 0 {
-			var radius int
-			r.db.QueryRow("SELECT radiusBase FROM spell_radius WHERE id = ?", data.RadiusIndex[i]).Scan(&radius)
+			radius := r.cache.spellRadius(data.RadiusIndex[i])
 			text = strings.ReplaceAll(text, fmt.Sprintf("$a%d", i+1), fmt.Sprintf("%d", radius))
 		}
 	}
 
 	// $r - range
 	if data.RangeID > 0 {
-		var rangeMax int
-		r.db.QueryRow("SELECT rangeMax FROM spell_range WHERE id = ?", data.RangeID).Scan(&rangeMax)
+		rangeMax := r.cache.spellRangeMax(data.RangeID)
 		text = strings.ReplaceAll(text, "$r", fmt.Sprintf("%d", rangeMax))
 	}
 
@@ -1021,7 +1014,7 @@ func (r *ItemRepository) replaceOvertimeValues(text string, data *SpellData) str
 	// Get duration in milliseconds
 	var durationBase int
 	if data.DurationIndex > 0 {
-		r.db.QueryRow("SELECT duration_base FROM spell_durations WHERE id = ?", data.DurationIndex).Scan(&durationBase)
+		durationBase = r.cache.spellDurationBase(data.DurationIndex)
 	}
 	if durationBase < 0 {
 		durationBase = -durationBase
@@ -1192,8 +1185,7 @@ func (r *ItemRepository) getSpellDuration(durationIndex int) string {
 		return "duration"
 	}
 
-	var durationBase int
-	r.db.QueryRow("SELECT duration_base FROM spell_durations WHERE id = ?", durationIndex).Scan(&durationBase)
+	durationBase := r.cache.spellDurationBase(durationIndex)
 	if durationBase <= 0 {
 		return "duration"
 	}
@@ -1324,21 +1316,7 @@ func (r *ItemRepository) GetItemDetail(entry int) (*models.ItemDetail, error) {
 
 // formatStat returns a formatted stat string
 func (r *ItemRepository) formatStat(statType, value int) string {
-	statNames := map[int]string{
-		0: "Mana", 1: "Health", 3: "Agility", 4: "Strength",
-		5: "Intellect", 6: "Spirit", 7: "Stamina",
-		12: "Defense Rating", 13: "Dodge Rating", 14: "Parry Rating",
-		15: "Shield Block Rating", 16: "Melee Hit Rating", 17: "Ranged Hit Rating",
-		18: "Spell Hit Rating", 19: "Melee Critical Rating", 20: "Ranged Critical Rating",
-		21: "Spell Critical Rating", 35: "Resilience Rating", 36: "Haste Rating",
-		37: "Expertise Rating", 38: "Attack Power", 39: "Ranged Attack Power",
-		41: "Spell Healing", 42: "Spell Damage", 43: "Mana Regeneration",
-		44: "Armor Penetration Rating", 45: "Spell Power",
-	}
-	name := statNames[statType]
-	if name == "" {
-		name = fmt.Sprintf("Unknown Stat %d", statType)
-	}
+	name := helpers.GetStatName(statType)
 	if value > 0 {
 		return fmt.Sprintf("+%d %s", value, name)
 	}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"shelllab/backend/database/models"
+)
+
+// maxBatchIDs caps the ids bound to one IN (...) query, well below SQLite's
+// host parameter limit
+const maxBatchIDs = 500
+
+// uniqueIDs returns the positive ids in first-seen order, without duplicates
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	var unique []int
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// inClause returns the placeholders and args to bind ids to an IN (...) list
+func inClause(ids []int) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ","), args
+}
+
+// forEachBatch calls fn with ids in batches of at most maxBatchIDs
+func forEachBatch(ids []int, fn func(batch []int) error) error {
+	for start := 0; start < len(ids); start += maxBatchIDs {
+		end := min(start+maxBatchIDs, len(ids))
+		if err := fn(ids[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemSummaryColumns selects name, quality, item level and icon of an item
+// joined by itemSummaryJoin, for lists that read item rows in the same query
+const itemSummaryColumns = "COALESCE(i.name, ''), COALESCE(i.quality, 0), COALESCE(i.item_level, 0), COALESCE(idi.icon, '')"
+
+// itemSummaryJoin joins the item in itemCol as i and its display info as idi
+func itemSummaryJoin(itemCol string) string {
+	return "LEFT JOIN item_template i ON i.entry = " + itemCol + " LEFT JOIN item_display_info idi ON i.display_id = idi.ID"
+}
+
+// loadItemSummaries loads the name, quality, item level and icon of the given
+// items with one query per batch of ids, instead of one query per item.
+// Summaries already in the read cache are not queried again.
+// Items missing from item_template are left out of the result.
+func loadItemSummaries(db *sql.DB, ids []int) (map[int]*models.ItemSummary, error) {
+	cache := CacheFor(db).itemSummaries
+	summaries := make(map[int]*models.ItemSummary, len(ids))
+
+	var missing []int
+	for _, id := range uniqueIDs(ids) {
+		if s, ok := cache.Get(id); ok {
+			summaries[id] = &s
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	gen := cache.generation()
+	err := forEachBatch(missing, func(batch []int) error {
+		in, args := inClause(batch)
+		rows, err := db.Query(fmt.Sprintf(`
+			SELECT i.entry, %s
+			FROM item_template i
+			LEFT JOIN item_display_info idi ON i.display_id = idi.ID
+			WHERE i.entry IN (%s)
+		`, itemSummaryColumns, in), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			s := &models.ItemSummary{}
+			if err := rows.Scan(&s.Entry, &s.Name, &s.Quality, &s.ItemLevel, &s.IconPath); err != nil {
+				return err
+			}
+			summaries[s.Entry] = s
+			cache.addSince(gen, s.Entry, *s)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// GetItemSummaries returns the summaries of the given items keyed by entry
+func (r *ItemRepository) GetItemSummaries(ids []int) (map[int]*models.ItemSummary, error) {
+	return loadItemSummaries(r.db, ids)
+}
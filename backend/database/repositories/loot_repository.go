@@ -33,90 +33,182 @@ func (r *LootRepository) GetCreatureLoot(creatureEntry int) ([]*models.LootItem,
 		return []*models.LootItem{}, nil
 	}
 
-	// 2. Process loot recursively
-	lootMap := make(map[int]*models.LootItem)
-	if err := r.processLoot(lootID, 1.0, false, lootMap, 0); err != nil {
+	// 2. Flatten the loot table and its references
+	rows, err := r.loadLootRows(lootID)
+	if err != nil {
 		return nil, err
 	}
+	lootList := flattenLoot(rows)
 
-	// 3. Convert map to slice and enrich with item info
-	var lootList []*models.LootItem
-	for itemID, item := range lootMap {
-		// Enrich with name, icon, quality
-		var name, icon string
-		var quality int
-		err := r.db.QueryRow(`
-			SELECT i.name, i.quality, COALESCE(idi.icon, '') 
-			FROM item_template i 
-			LEFT JOIN item_display_info idi ON i.display_id = idi.ID 
-			WHERE i.entry = ?
-		`, itemID).Scan(&name, &quality, &icon)
-		if err == nil {
-			item.Name = name
-			item.Quality = quality
-			item.IconPath = icon
-			lootList = append(lootList, item)
+	// 3. Enrich with name, icon and quality, dropping unknown items
+	summaries, err := loadItemSummaries(r.db, lootItemIDs(lootList))
+	if err != nil {
+		return nil, err
+	}
+	var result []*models.LootItem
+	for _, item := range lootList {
+		if s, ok := summaries[item.ItemID]; ok {
+			item.Name = s.Name
+			item.Quality = s.Quality
+			item.IconPath = s.IconPath
+			result = append(result, item)
 		}
 	}
 
-	return lootList, nil
+	return result, nil
 }
 
-// processLoot recursively processes loot tables
-func (r *LootRepository) processLoot(entry int, multiplier float64, isRef bool, results map[int]*models.LootItem, depth int) error {
-	if depth > 10 {
-		return nil // Prevent infinite recursion
+// GetLootTree returns a creature loot table with its reference tables nested
+// below the rows that reference them
+func (r *LootRepository) GetLootTree(lootID int) (*models.LootTree, error) {
+	rows, err := r.loadLootRows(lootID)
+	if err != nil {
+		return nil, err
+	}
+
+	var itemIDs []int
+	for _, row := range rows {
+		if row.minOrRef >= 0 {
+			itemIDs = append(itemIDs, row.item)
+		}
+	}
+	summaries, err := loadItemSummaries(r.db, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &models.LootTree{LootID: lootID, Nodes: []*models.LootNode{}}
+	nodes := make(map[string]*models.LootNode, len(rows))
+	for _, row := range rows {
+		node := &models.LootNode{
+			Chance:          math.Abs(row.chance),
+			EffectiveChance: math.Abs(row.chance) * row.multiplier,
+			GroupID:         row.groupID,
+			MaxCount:        row.maxCount,
+			Cycle:           row.cycle,
+		}
+		if row.minOrRef < 0 {
+			node.ReferenceID = -row.minOrRef
+		} else {
+			node.ItemID = row.item
+			node.MinCount = row.minOrRef
+			if s, ok := summaries[row.item]; ok {
+				node.Name = s.Name
+				node.Quality = s.Quality
+				node.IconPath = s.IconPath
+			}
+		}
+		nodes[row.node] = node
 	}
 
-	tableName := "creature_loot_template"
-	if isRef {
-		tableName = "reference_loot_template"
+	// Attach every row below the reference row it came from
+	for _, row := range rows {
+		node := nodes[row.node]
+		if parent, ok := nodes[row.parent]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			tree.Nodes = append(tree.Nodes, node)
+		}
 	}
+	return tree, nil
+}
+
+// maxLootDepth caps how deep reference tables are followed, on top of the
+// cycle check
+const maxLootDepth = 10
+
+// lootRow is one row of a creature loot table or of a reference table below it
+type lootRow struct {
+	node       string // path of entry:item keys from the creature loot table
+	parent     string // node of the referencing row, empty on the creature loot table
+	item       int
+	minOrRef   int
+	maxCount   int
+	groupID    int
+	chance     float64
+	multiplier float64 // product of the reference chances above the row
+	cycle      bool    // references a table already on its path, not expanded
+}
 
+// loadLootRows loads a creature loot table and every reference table below it
+// with one recursive query, breadth first. Each row carries the reference ids
+// on its path, so a reference back to one of them is returned but not
+// followed again.
+func (r *LootRepository) loadLootRows(lootID int) ([]*lootRow, error) {
 	rows, err := r.db.Query(`
-		SELECT item, ChanceOrQuestChance, mincountOrRef, maxcount, groupid
-		FROM `+tableName+` 
-		WHERE entry = ?
-	`, entry)
+		WITH RECURSIVE loot(node, parent, depth, refs, item, chance, minOrRef, maxcount, groupid, multiplier) AS (
+			SELECT '/' || entry || ':' || item, '', 0, ',',
+			       item, ChanceOrQuestChance, mincountOrRef, maxcount, groupid, 1.0
+			FROM creature_loot_template
+			WHERE entry = ?
+
+			UNION ALL
+
+			SELECT l.node || '/' || r.entry || ':' || r.item, l.node, l.depth + 1, l.refs || r.entry || ',',
+			       r.item, r.ChanceOrQuestChance, r.mincountOrRef, r.maxcount, r.groupid,
+			       l.multiplier * ABS(l.chance) / 100.0
+			FROM loot l
+			JOIN reference_loot_template r ON r.entry = -l.minOrRef
+			WHERE l.minOrRef < 0 AND l.depth < ? AND instr(l.refs, ',' || -l.minOrRef || ',') = 0
+		)
+		SELECT node, parent, item, chance, minOrRef, maxcount, groupid, multiplier,
+		       minOrRef < 0 AND instr(refs, ',' || -minOrRef || ',') > 0
+		FROM loot
+	`, lootID, maxLootDepth)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
+	var result []*lootRow
 	for rows.Next() {
-		var itemID, minOrRef, maxCount, groupID int
-		var chance float64
-		if err := rows.Scan(&itemID, &chance, &minOrRef, &maxCount, &groupID); err != nil {
+		row := &lootRow{}
+		if err := rows.Scan(&row.node, &row.parent, &row.item, &row.chance, &row.minOrRef,
+			&row.maxCount, &row.groupID, &row.multiplier, &row.cycle); err != nil {
 			continue
 		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
 
-		// Calculate actual chance
-		absChance := math.Abs(chance)
-		currentChance := absChance * multiplier
+// flattenLoot sums the chances of every item in a loot table and the
+// reference tables below it
+func flattenLoot(rows []*lootRow) []*models.LootItem {
+	var lootList []*models.LootItem
+	results := make(map[int]*models.LootItem)
 
-		if minOrRef < 0 {
-			// Reference
-			refID := -minOrRef
-			if err := r.processLoot(refID, currentChance/100.0, true, results, depth+1); err != nil {
-				return err
-			}
-		} else {
-			// Item
-			if currentChance < 0.0001 {
-				currentChance = 0.0001
-			}
+	for _, row := range rows {
+		if row.minOrRef < 0 {
+			continue // Reference, its rows follow
+		}
+
+		currentChance := math.Abs(row.chance) * row.multiplier
+		if currentChance < 0.0001 {
+			currentChance = 0.0001
+		}
 
-			if existing, ok := results[itemID]; ok {
-				existing.Chance += currentChance
-			} else {
-				results[itemID] = &models.LootItem{
-					ItemID:   itemID,
-					Chance:   currentChance,
-					MinCount: minOrRef,
-					MaxCount: maxCount,
-				}
+		if existing, ok := results[row.item]; ok {
+			existing.Chance += currentChance
+		} else {
+			item := &models.LootItem{
+				ItemID:   row.item,
+				Chance:   currentChance,
+				MinCount: row.minOrRef,
+				MaxCount: row.maxCount,
 			}
+			results[row.item] = item
+			lootList = append(lootList, item)
 		}
 	}
-	return nil
+	return lootList
+}
+
+// lootItemIDs returns the item ids of a loot list
+func lootItemIDs(loot []*models.LootItem) []int {
+	ids := make([]int, len(loot))
+	for i, item := range loot {
+		ids[i] = item.ItemID
+	}
+	return ids
 }
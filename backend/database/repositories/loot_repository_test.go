@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+)
+
+// lootLines renders a loot tree one row per line, indented below its reference
+func lootLines(nodes []*models.LootNode, indent string) []string {
+	var lines []string
+	for _, n := range nodes {
+		switch {
+		case n.Cycle:
+			lines = append(lines, fmt.Sprintf("%sref %d (cycle)", indent, n.ReferenceID))
+		case n.ReferenceID > 0:
+			lines = append(lines, fmt.Sprintf("%sref %d", indent, n.ReferenceID))
+		default:
+			lines = append(lines, fmt.Sprintf("%sitem %d %.0f%%", indent, n.ItemID, n.EffectiveChance))
+		}
+		lines = append(lines, lootLines(n.Children, indent+"  ")...)
+	}
+	return lines
+}
+
+func TestLootTreeReferenceCycles(t *testing.T) {
+	tests := []struct {
+		name       string
+		references string
+		want       []string
+	}{
+		{
+			name: "self-referencing table",
+			references: `INSERT INTO reference_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef) VALUES
+				(200, 1, 50, 1), (200, 200, 100, -200)`,
+			want: []string{
+				"ref 200",
+				"  item 1 50%",
+				"  ref 200 (cycle)",
+			},
+		},
+		{
+			name: "two tables referencing each other",
+			references: `INSERT INTO reference_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef) VALUES
+				(200, 1, 50, 1), (200, 300, 50, -300),
+				(300, 2, 40, 1), (300, 200, 100, -200)`,
+			want: []string{
+				"ref 200",
+				"  item 1 50%",
+				"  ref 300",
+				"    item 2 20%",
+				"    ref 200 (cycle)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, t.TempDir(),
+				`INSERT INTO creature_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef) VALUES (100, 200, 100, -200)`,
+				tt.references,
+			)
+
+			tree, err := database.NewLootRepository(db).GetLootTree(100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := lootLines(tree.Nodes, ""); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loot tree\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
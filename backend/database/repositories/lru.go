@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"container/list"
+	"sync"
+
+	"shelllab/backend/database/models"
+)
+
+// LRU is a size-limited map that evicts its least recently used entry when
+// full. It is safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	name     string
+	capacity int
+	entries  map[K]*list.Element
+	order    *list.List // Most recently used first
+	gen      uint64     // Bumped by Purge, so loads started before it are not stored
+
+	hits, misses, evictions, purges uint64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU creates an empty cache holding at most capacity entries
+func NewLRU[K comparable, V any](name string, capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		name:     name,
+		capacity: max(capacity, 1),
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached value of key and marks it as recently used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.hits++
+		c.order.MoveToFront(el)
+		return el.Value.(*lruEntry[K, V]).value, true
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+// Load returns the cached value of key, or calls load and caches its result.
+// Errors are returned without being cached. A value loaded while the cache
+// was purged is returned but not cached, as it may predate the write.
+func (c *LRU[K, V]) Load(key K, load func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	gen := c.generation()
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	c.addSince(gen, key, v)
+	return v, nil
+}
+
+// Purge empties the cache
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[K]*list.Element)
+	c.order.Init()
+	c.gen++
+	c.purges++
+}
+
+// Stats returns the size and hit counts of the cache
+func (c *LRU[K, V]) Stats() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return models.CacheStats{
+		Name:      c.name,
+		Entries:   c.order.Len(),
+		Capacity:  c.capacity,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Purges:    c.purges,
+	}
+}
+
+// generation returns the purge count to pass to addSince after a load
+func (c *LRU[K, V]) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// addSince caches a value loaded at generation gen, unless the cache was
+// purged since
+func (c *LRU[K, V]) addSince(gen uint64, key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+		c.evictions++
+	}
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"shelllab/backend/database/models"
+)
+
+const (
+	// DefaultPageSize is used when PageQuery.Limit is not set
+	DefaultPageSize = 100
+	// MaxPageSize caps PageQuery.Limit
+	MaxPageSize = 1000
+)
+
+var (
+	// ErrInvalidSort is returned for a sort key the list doesn't support
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor is returned for a cursor no earlier page returned
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// pageWindow returns the LIMIT and OFFSET for q. Cursors are opaque to
+// callers; for now they carry the offset of the next page.
+func pageWindow(q models.PageQuery) (limit, offset int, err error) {
+	limit = q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	offset = max(q.Offset, 0)
+	if q.Cursor != "" {
+		offset, err = strconv.Atoi(q.Cursor)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCursor, q.Cursor)
+		}
+	}
+	return limit, offset, nil
+}
+
+// newPage wraps the rows of one page
+func newPage[T any](items []T, total, offset int) *models.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := &models.Page[T]{
+		Items:   items,
+		Total:   total,
+		HasMore: offset+len(items) < total,
+	}
+	if page.HasMore {
+		page.NextCursor = strconv.Itoa(offset + len(items))
+	}
+	return page
+}
+
+// sortColumns maps the sort keys a list supports to SQL expressions
+type sortColumns map[string]string
+
+// orderBy returns the ORDER BY expression for q: the list's natural order when
+// no sort key is given, otherwise the sort column followed by tiebreak, so
+// rows with equal values keep a stable order across pages.
+func (c sortColumns) orderBy(q models.PageQuery, natural, tiebreak string) (string, error) {
+	if q.Sort == "" {
+		return natural, nil
+	}
+	expr, ok := c[q.Sort]
+	if !ok {
+		return "", fmt.Errorf("%w %q (supported: %s)", ErrInvalidSort, q.Sort, strings.Join(c.keys(), ", "))
+	}
+	dir := "ASC"
+	if q.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s", expr, dir, tiebreak), nil
+}
+
+func (c sortColumns) keys() []string {
+	var keys []string
+	for _, k := range []string{models.SortName, models.SortLevel, models.SortQuality, models.SortItemLevel, models.SortDPS} {
+		if _, ok := c[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// nameFilterClause matches filter against nameCol, or against entryCol when
+// the filter is a number, like the list filters in the UI do
+func nameFilterClause(nameCol, entryCol, filter string) (string, []interface{}) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return "", nil
+	}
+	if id, err := strconv.Atoi(filter); err == nil {
+		return fmt.Sprintf(" AND (%s LIKE ? OR %s = ?)", nameCol, entryCol), []interface{}{"%" + filter + "%", id}
+	}
+	return fmt.Sprintf(" AND %s LIKE ?", nameCol), []interface{}{"%" + filter + "%"}
+}
@@ -0,0 +1,172 @@
+package repositories_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
+)
+
+// clothItems seeds 1200 cloth armor items of one quality, entries 1..1200
+const clothItems = `WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1200)
+	INSERT INTO item_template (entry, name, class, subclass, quality) SELECT i, 'Cloth ' || i, 4, 1, 2 FROM n`
+
+// daggers seeds four daggers with 7.5, 10, 0 and 15 DPS
+const daggers = `INSERT INTO item_template (entry, name, class, subclass, delay, dmg_min1, dmg_max1) VALUES
+	(5001, 'Dirk', 2, 15, 2000, 10, 20),
+	(5002, 'Stiletto 5001', 2, 15, 1500, 10, 20),
+	(5003, 'Kris', 2, 15, 0, 10, 20),
+	(5004, 'Shiv', 2, 15, 1800, 20, 34)`
+
+// entries returns the entries of a page of items
+func entries(page *models.Page[*models.Item]) []int {
+	ids := []int{}
+	for _, item := range page.Items {
+		ids = append(ids, item.Entry)
+	}
+	return ids
+}
+
+func TestBrowsePageWindow(t *testing.T) {
+	items := database.NewItemRepository(openTestDB(t, t.TempDir(), clothItems))
+
+	tests := []struct {
+		name        string
+		query       models.PageQuery
+		wantLen     int
+		wantFirst   int
+		wantHasMore bool
+		wantNext    string
+		wantErr     error
+	}{
+		{name: "default limit", query: models.PageQuery{}, wantLen: repositories.DefaultPageSize, wantFirst: 1, wantHasMore: true, wantNext: "100"},
+		{name: "limit capped", query: models.PageQuery{Limit: 5000}, wantLen: repositories.MaxPageSize, wantFirst: 1, wantHasMore: true, wantNext: "1000"},
+		{name: "negative offset starts at the top", query: models.PageQuery{Limit: 2, Offset: -5}, wantLen: 2, wantFirst: 1, wantHasMore: true, wantNext: "2"},
+		{name: "cursor takes precedence over offset", query: models.PageQuery{Limit: 2, Offset: 500, Cursor: "10"}, wantLen: 2, wantFirst: 11, wantHasMore: true, wantNext: "12"},
+		{name: "last page", query: models.PageQuery{Limit: 10, Cursor: "1198"}, wantLen: 2, wantFirst: 1199},
+		{name: "cursor past the end", query: models.PageQuery{Cursor: "5000"}, wantLen: 0},
+		{name: "invalid cursor", query: models.PageQuery{Cursor: "abc"}, wantErr: database.ErrInvalidCursor},
+		{name: "negative cursor", query: models.PageQuery{Cursor: "-1"}, wantErr: database.ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := items.GetItemsByClass(4, 1, "", tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if page.Total != 1200 {
+				t.Errorf("total %d, want 1200", page.Total)
+			}
+			if page.Items == nil || len(page.Items) != tt.wantLen {
+				t.Fatalf("%d items, want %d", len(page.Items), tt.wantLen)
+			}
+			if tt.wantLen > 0 && page.Items[0].Entry != tt.wantFirst {
+				t.Errorf("first item %d, want %d", page.Items[0].Entry, tt.wantFirst)
+			}
+			if page.HasMore != tt.wantHasMore || page.NextCursor != tt.wantNext {
+				t.Errorf("hasMore %v, next %q, want %v, %q", page.HasMore, page.NextCursor, tt.wantHasMore, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestBrowseSortTiebreakIsStableAcrossPages(t *testing.T) {
+	items := database.NewItemRepository(openTestDB(t, t.TempDir(), clothItems))
+
+	// Every item has the same quality, so only the tiebreak orders them
+	var got []int
+	query := models.PageQuery{Limit: 70, Sort: models.SortQuality, Desc: true}
+	for {
+		page, err := items.GetItemsByClass(4, 1, "", query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, entries(page)...)
+		if !page.HasMore {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	if len(got) != 1200 {
+		t.Fatalf("walked %d items, want 1200", len(got))
+	}
+	for i, entry := range got {
+		if entry != i+1 {
+			t.Fatalf("item %d of the walk is %d, want %d", i, entry, i+1)
+		}
+	}
+}
+
+func TestBrowseDaggers(t *testing.T) {
+	items := database.NewItemRepository(openTestDB(t, t.TempDir(), daggers))
+
+	tests := []struct {
+		name   string
+		filter string
+		query  models.PageQuery
+		want   []int
+	}{
+		{name: "numeric filter matches the entry and names", filter: "5001", query: models.PageQuery{Sort: models.SortName}, want: []int{5001, 5002}},
+		{name: "text filter", filter: "Kri", want: []int{5003}},
+		{name: "blank filter", filter: "  ", query: models.PageQuery{Sort: models.SortName}, want: []int{5001, 5003, 5004, 5002}},
+		{name: "DPS descending", query: models.PageQuery{Sort: models.SortDPS, Desc: true}, want: []int{5004, 5002, 5001, 5003}},
+		{name: "DPS ascending", query: models.PageQuery{Sort: models.SortDPS}, want: []int{5003, 5001, 5002, 5004}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := items.GetItemsByClass(2, 15, tt.filter, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := entries(page); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items %v, want %v", got, tt.want)
+			}
+			if page.Total != len(tt.want) {
+				t.Errorf("total %d, want %d", page.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestBrowseInvalidSort(t *testing.T) {
+	db := openTestDB(t, t.TempDir())
+	items := database.NewItemRepository(db)
+	creatures := database.NewCreatureRepository(db)
+	objects := database.NewGameObjectRepository(db)
+
+	tests := []struct {
+		name   string
+		browse func(q models.PageQuery) error
+		sort   string
+	}{
+		{name: "unknown item sort", sort: "bogus", browse: func(q models.PageQuery) error {
+			_, err := items.GetItemsByClass(4, 1, "", q)
+			return err
+		}},
+		{name: "creatures have no DPS", sort: models.SortDPS, browse: func(q models.PageQuery) error {
+			_, err := creatures.GetCreaturesByType(7, "", q)
+			return err
+		}},
+		{name: "objects only sort by name", sort: models.SortLevel, browse: func(q models.PageQuery) error {
+			_, err := objects.GetObjectsByType(3, "", q)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.browse(models.PageQuery{Sort: tt.sort}); !errors.Is(err, database.ErrInvalidSort) {
+				t.Errorf("error = %v, want %v", err, database.ErrInvalidSort)
+			}
+		})
+	}
+}
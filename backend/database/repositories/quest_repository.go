@@ -3,6 +3,7 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"shelllab/backend/database/models"
@@ -231,25 +232,44 @@ func (r *QuestRepository) GetQuestCategoriesByGroup(groupID int) ([]*models.Ques
 	return categories, nil
 }
 
-// GetQuestsByEnhancedCategory returns quests for a given category (ZoneOrSort value)
-func (r *QuestRepository) GetQuestsByEnhancedCategory(categoryID int, nameFilter string) ([]*models.Quest, error) {
+// questSorts are the sort keys of the quest browse list
+var questSorts = sortColumns{
+	models.SortName:  "Title",
+	models.SortLevel: "QuestLevel",
+}
+
+// GetQuestsByEnhancedCategory returns a page of quests for a given category (ZoneOrSort value)
+func (r *QuestRepository) GetQuestsByEnhancedCategory(categoryID int, nameFilter string, page models.PageQuery) (*models.Page[*models.Quest], error) {
+	limit, offset, err := pageWindow(page)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := questSorts.orderBy(page, "QuestLevel, Title, entry", "entry")
+	if err != nil {
+		return nil, err
+	}
+
 	whereClause := "WHERE ZoneOrSort = ?"
 	args := []interface{}{categoryID}
 
-	if nameFilter != "" {
-		whereClause += " AND title LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
+	filter, filterArgs := nameFilterClause("Title", "entry", nameFilter)
+	whereClause += filter
+	args = append(args, filterArgs...)
+
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM quest_template "+whereClause, args...).Scan(&count); err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
 		SELECT entry, Title, QuestLevel, MinLevel, Type, ZoneOrSort, RewXP
 		FROM quest_template 
 		%s
-		ORDER BY QuestLevel, Title
-		LIMIT 10000
-	`, whereClause)
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, whereClause, orderBy)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +283,7 @@ func (r *QuestRepository) GetQuestsByEnhancedCategory(categoryID int, nameFilter
 		}
 		quests = append(quests, q)
 	}
-	return quests, nil
+	return newPage(quests, count, offset), nil
 }
 
 // GetQuestCount returns the total number of quests
@@ -331,53 +351,48 @@ func (r *QuestRepository) GetQuestDetail(entry int) (*models.QuestDetail, error)
 	// Resolve Side and Races
 	q.Side, q.RaceNames = resolveSideAndRaces(q.RequiredRaces)
 
-	// Process reward items
+	// Process reward and choice items, with one lookup for all of them
+	summaries, err := loadItemSummaries(r.db, append(rewItems[:], rewChoiceItems[:]...))
+	if err != nil {
+		return nil, err
+	}
+	questItem := func(itemID, count int) *models.QuestItem {
+		item := &models.QuestItem{Entry: itemID, Count: count}
+		if s, ok := summaries[itemID]; ok {
+			item.Name = s.Name
+			item.Icon = s.IconPath
+			item.Quality = s.Quality
+		}
+		return item
+	}
 	for i := 0; i < 4; i++ {
 		if rewItems[i] > 0 {
-			item := &models.QuestItem{Entry: rewItems[i], Count: rewItemCounts[i]}
-			var name, icon string
-			var quality int
-			r.db.QueryRow(`
-				SELECT i.name, COALESCE(idi.icon, ''), i.quality 
-				FROM item_template i 
-				LEFT JOIN item_display_info idi ON i.display_id = idi.ID 
-				WHERE i.entry = ?
-			`, rewItems[i]).Scan(&name, &icon, &quality)
-			item.Name = name
-			item.Icon = icon
-			item.Quality = quality
-			q.RewardItems = append(q.RewardItems, item)
+			q.RewardItems = append(q.RewardItems, questItem(rewItems[i], rewItemCounts[i]))
 		}
 	}
-
-	// Process choice items
 	for i := 0; i < 6; i++ {
 		if rewChoiceItems[i] > 0 {
-			item := &models.QuestItem{Entry: rewChoiceItems[i], Count: rewChoiceItemCounts[i]}
-			var name, icon string
-			var quality int
-			r.db.QueryRow(`
-				SELECT i.name, COALESCE(idi.icon, ''), i.quality 
-				FROM item_template i 
-				LEFT JOIN item_display_info idi ON i.display_id = idi.ID 
-				WHERE i.entry = ?
-			`, rewChoiceItems[i]).Scan(&name, &icon, &quality)
-			item.Name = name
-			item.Icon = icon
-			item.Quality = quality
-			q.ChoiceItems = append(q.ChoiceItems, item)
+			q.ChoiceItems = append(q.ChoiceItems, questItem(rewChoiceItems[i], rewChoiceItemCounts[i]))
 		}
 	}
 
+	// Load the quests before and after this one
+	chain, err := r.loadQuestChain(entry)
+	if err != nil {
+		return nil, err
+	}
+
 	// Process prev quests
 	if prevQuestID != 0 {
 		var title string
-		r.db.QueryRow("SELECT Title FROM quest_template WHERE entry = ?", prevQuestID).Scan(&title)
+		if prev, ok := chain.nodes[prevQuestID]; ok {
+			title = prev.title
+		}
 		q.PrevQuests = append(q.PrevQuests, &models.QuestSeriesItem{Entry: prevQuestID, Title: title})
 	}
 
 	// Build complete quest chain (all quests before and after this one)
-	q.Series = r.buildQuestChain(entry, prevQuestID, nextQuestInChain)
+	q.Series = chain.build(entry, q.Title, prevQuestID, nextQuestInChain)
 
 	// Query Starters (NPCs that give this quest)
 	startersRows, err := r.db.Query(`
@@ -424,27 +439,149 @@ func (r *QuestRepository) GetQuestDetail(entry int) (*models.QuestDetail, error)
 	return q, nil
 }
 
-// buildQuestChain builds a complete quest chain by traversing backwards and forwards
-func (r *QuestRepository) buildQuestChain(currentEntry int, prevQuestID int, nextQuestInChain int) []*models.QuestSeriesItem {
+// questChainNode is a quest_template row loaded for a quest chain
+type questChainNode struct {
+	entry int
+	title string
+	level int
+	prev  int
+	next  int
+}
+
+// questChain holds the quests linked to one quest, so the chain can be walked
+// without a query per quest
+type questChain struct {
+	nodes map[int]*questChainNode
+	// followers of a quest: quests whose PrevQuestId is the quest's entry
+	// (or its negation), in entry order
+	followers map[int][]*questChainNode
+}
+
+// loadQuestChain loads, with one recursive query, the quests reachable from
+// entry backwards through PrevQuestId and forwards through NextQuestInChain
+// or reverse PrevQuestId links. UNION (not UNION ALL) skips quests already
+// found, which detects cycles: a chain that loops back ends there.
+func (r *QuestRepository) loadQuestChain(entry int) (*questChain, error) {
+	rows, err := r.db.Query(`
+		WITH RECURSIVE
+		back(entry) AS (
+			SELECT PrevQuestId FROM quest_template WHERE entry = ? AND PrevQuestId > 0
+			UNION
+			SELECT q.PrevQuestId FROM back JOIN quest_template q ON q.entry = back.entry
+			WHERE q.PrevQuestId > 0
+		),
+		fwd(entry) AS (
+			SELECT ?
+			UNION
+			SELECT q.NextQuestInChain FROM fwd JOIN quest_template q ON q.entry = fwd.entry
+			WHERE q.NextQuestInChain > 0
+			UNION
+			SELECT q.entry FROM fwd JOIN quest_template q ON q.PrevQuestId IN (fwd.entry, -fwd.entry)
+		)
+		SELECT entry, Title, QuestLevel, IFNULL(PrevQuestId, 0), IFNULL(NextQuestInChain, 0)
+		FROM quest_template
+		WHERE entry IN (SELECT entry FROM back UNION SELECT entry FROM fwd)
+		ORDER BY entry
+	`, entry, entry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chain := &questChain{
+		nodes:     make(map[int]*questChainNode),
+		followers: make(map[int][]*questChainNode),
+	}
+	for rows.Next() {
+		node := &questChainNode{}
+		if err := rows.Scan(&node.entry, &node.title, &node.level, &node.prev, &node.next); err != nil {
+			continue
+		}
+		chain.nodes[node.entry] = node
+		switch {
+		case node.prev > 0:
+			chain.followers[node.prev] = append(chain.followers[node.prev], node)
+		case node.prev < 0:
+			chain.followers[-node.prev] = append(chain.followers[-node.prev], node)
+		}
+	}
+	return chain, rows.Err()
+}
+
+// GetQuestGraph returns the quest chain around entry as nodes and edges.
+// Unlike QuestDetail.Series it keeps every link, so branches that join again
+// and chains that loop back show up as such.
+func (r *QuestRepository) GetQuestGraph(entry int) (*models.QuestGraph, error) {
+	chain, err := r.loadQuestChain(entry)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := chain.nodes[entry]; !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	graph := &models.QuestGraph{Root: entry, Nodes: []*models.QuestGraphNode{}, Edges: []*models.QuestGraphEdge{}}
+	type link struct{ from, to int }
+	seen := make(map[link]bool)
+	addEdge := func(from, to int, edgeType string) {
+		if _, ok := chain.nodes[from]; !ok || from == to || seen[link{from, to}] {
+			return
+		}
+		seen[link{from, to}] = true
+		graph.Edges = append(graph.Edges, &models.QuestGraphEdge{From: from, To: to, Type: edgeType})
+	}
+
+	// Nodes are in entry order
+	nodes := chain.sorted()
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, &models.QuestGraphNode{Entry: node.entry, Title: node.title, Level: node.level})
+	}
+	for _, node := range nodes {
+		if node.next > 0 {
+			if _, ok := chain.nodes[node.next]; ok {
+				addEdge(node.entry, node.next, models.QuestEdgeNext)
+			}
+		}
+		switch {
+		case node.prev > 0:
+			addEdge(node.prev, node.entry, models.QuestEdgePrev)
+		case node.prev < 0:
+			addEdge(-node.prev, node.entry, models.QuestEdgePrevActive)
+		}
+	}
+	return graph, nil
+}
+
+// sorted returns the loaded quests in entry order
+func (c *questChain) sorted() []*questChainNode {
+	nodes := make([]*questChainNode, 0, len(c.nodes))
+	for _, node := range c.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].entry < nodes[j].entry })
+	return nodes
+}
+
+// build returns the complete quest chain around the current quest: all quests
+// before it, then the quest itself, then all quests after it
+func (c *questChain) build(currentEntry int, currentTitle string, prevQuestID int, nextQuestInChain int) []*models.QuestSeriesItem {
 	var chain []*models.QuestSeriesItem
 	visited := make(map[int]bool)
 
 	// Traverse backwards to find all previous quests (returns in chronological order: earliest first)
-	prevQuests := r.getQuestChainBackwards(prevQuestID, visited)
+	prevQuests := c.backwards(prevQuestID, visited)
 
 	// Add previous quests in order (already in correct chronological order)
 	chain = append(chain, prevQuests...)
 
 	// Add current quest
-	var currentTitle string
-	r.db.QueryRow("SELECT Title FROM quest_template WHERE entry = ?", currentEntry).Scan(&currentTitle)
 	chain = append(chain, &models.QuestSeriesItem{Entry: currentEntry, Title: currentTitle, Depth: 0})
 	visited[currentEntry] = true
 
 	// Traverse forwards to find all following quests
 	// First try NextQuestInChain, then try reverse lookup (quests that have this as PrevQuestId)
 	// Children start at Depth 1 relative to current quest
-	nextQuests := r.getQuestChainForwards(currentEntry, nextQuestInChain, visited, 0)
+	nextQuests := c.forwards(currentEntry, nextQuestInChain, visited, 0)
 	chain = append(chain, nextQuests...)
 
 	// Only return chain if there's more than just the current quest
@@ -455,83 +592,54 @@ func (r *QuestRepository) buildQuestChain(currentEntry int, prevQuestID int, nex
 	return chain
 }
 
-// getQuestChainBackwards recursively gets all preceding quests
-func (r *QuestRepository) getQuestChainBackwards(questID int, visited map[int]bool) []*models.QuestSeriesItem {
+// backwards recursively gets all preceding quests
+func (c *questChain) backwards(questID int, visited map[int]bool) []*models.QuestSeriesItem {
 	if questID == 0 || visited[questID] {
 		return nil
 	}
 	visited[questID] = true
 
-	var title string
-	var prevID int
-	err := r.db.QueryRow("SELECT Title, IFNULL(PrevQuestId, 0) FROM quest_template WHERE entry = ?", questID).Scan(&title, &prevID)
-	if err != nil {
+	node, ok := c.nodes[questID]
+	if !ok {
 		return nil
 	}
 
 	// Get earlier quests first (recursive)
-	result := r.getQuestChainBackwards(prevID, visited)
+	result := c.backwards(node.prev, visited)
 
 	// Add this quest
-	result = append(result, &models.QuestSeriesItem{Entry: questID, Title: title, Depth: 0})
+	result = append(result, &models.QuestSeriesItem{Entry: questID, Title: node.title, Depth: 0})
 
 	return result
 }
 
-// getQuestChainForwards recursively gets all following quests
+// forwards recursively gets all following quests
 // Uses both NextQuestInChain and reverse lookup (quests that have this as PrevQuestId)
-func (r *QuestRepository) getQuestChainForwards(currentQuestID int, nextQuestInChain int, visited map[int]bool, parentDepth int) []*models.QuestSeriesItem {
+func (c *questChain) forwards(currentQuestID int, nextQuestInChain int, visited map[int]bool, parentDepth int) []*models.QuestSeriesItem {
 	var result []*models.QuestSeriesItem
 	currentDepth := parentDepth + 1
 
 	// Method 1: Use NextQuestInChain if available
 	if nextQuestInChain > 0 && !visited[nextQuestInChain] {
 		visited[nextQuestInChain] = true
-		var title string
-		var nextNext int
-		err := r.db.QueryRow("SELECT Title, IFNULL(NextQuestInChain, 0) FROM quest_template WHERE entry = ?", nextQuestInChain).Scan(&title, &nextNext)
-		if err == nil {
-			result = append(result, &models.QuestSeriesItem{Entry: nextQuestInChain, Title: title, Depth: currentDepth})
+		if node, ok := c.nodes[nextQuestInChain]; ok {
+			result = append(result, &models.QuestSeriesItem{Entry: nextQuestInChain, Title: node.title, Depth: currentDepth})
 			// Continue recursively
-			result = append(result, r.getQuestChainForwards(nextQuestInChain, nextNext, visited, currentDepth)...)
+			result = append(result, c.forwards(nextQuestInChain, node.next, visited, currentDepth)...)
 		}
 		return result
 	}
 
-	// Method 2: Reverse lookup - find quests that have currentQuestID as their PrevQuestId
-	rows, err := r.db.Query("SELECT entry, Title, IFNULL(NextQuestInChain, 0) FROM quest_template WHERE PrevQuestId = ? OR PrevQuestId = ?",
-		currentQuestID, -currentQuestID)
-	if err != nil {
-		return result
-	}
-
-	// Struct to hold temporary results to allow closing rows before recursion
-	type nextQuestInfo struct {
-		entry    int
-		title    string
-		nextNext int
-	}
-	var nextQuests []nextQuestInfo
-
-	for rows.Next() {
-		var info nextQuestInfo
-		if err := rows.Scan(&info.entry, &info.title, &info.nextNext); err != nil {
-			continue
-		}
-		nextQuests = append(nextQuests, info)
-	}
-	rows.Close() // Close rows immediately after scanning
-
-	// Now process recursions
-	for _, info := range nextQuests {
-		if visited[info.entry] {
+	// Method 2: Reverse lookup - quests that have currentQuestID as their PrevQuestId
+	for _, node := range c.followers[currentQuestID] {
+		if visited[node.entry] {
 			continue
 		}
-		visited[info.entry] = true
-		result = append(result, &models.QuestSeriesItem{Entry: info.entry, Title: info.title, Depth: currentDepth})
+		visited[node.entry] = true
+		result = append(result, &models.QuestSeriesItem{Entry: node.entry, Title: node.title, Depth: currentDepth})
 
 		// Continue recursively for this branch
-		result = append(result, r.getQuestChainForwards(info.entry, info.nextNext, visited, currentDepth)...)
+		result = append(result, c.forwards(node.entry, node.next, visited, currentDepth)...)
 	}
 
 	return result
@@ -0,0 +1,39 @@
+package repositories_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/database"
+)
+
+func TestQuestGraphPrevQuestLoop(t *testing.T) {
+	// 1 -> 2 -> 3 -> 1 through PrevQuestId, with 4 branching off 2
+	db := openTestDB(t, t.TempDir(), `INSERT INTO quest_template (entry, Title, PrevQuestId) VALUES
+		(1, 'One', 3), (2, 'Two', 1), (3, 'Three', 2), (4, 'Four', 2), (5, 'Unrelated', 0)`)
+	quests := database.NewQuestRepository(db)
+
+	for _, root := range []int{1, 3, 4} {
+		t.Run(fmt.Sprintf("from quest %d", root), func(t *testing.T) {
+			graph, err := quests.GetQuestGraph(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var nodes, edges []string
+			for _, n := range graph.Nodes {
+				nodes = append(nodes, fmt.Sprintf("%d %s", n.Entry, n.Title))
+			}
+			for _, e := range graph.Edges {
+				edges = append(edges, fmt.Sprintf("%d-%s->%d", e.From, e.Type, e.To))
+			}
+
+			if want := []string{"1 One", "2 Two", "3 Three", "4 Four"}; !reflect.DeepEqual(nodes, want) {
+				t.Errorf("nodes %v, want %v", nodes, want)
+			}
+			if want := []string{"3-prev->1", "1-prev->2", "2-prev->3", "2-prev->4"}; !reflect.DeepEqual(edges, want) {
+				t.Errorf("edges %v, want %v", edges, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+
+	"shelllab/backend/database/models"
+)
+
+// ReadCache holds the lookups that tooltips and detail views repeat for the
+// same rows: item tooltips and summaries, resolved spell texts, spell
+// durations, ranges and radii, and zone names.
+//
+// Every cache lists the tables it is read from. Code writing game data calls
+// InvalidateCache with the tables it wrote, which purges the caches reading
+// any of them.
+type ReadCache struct {
+	db *sql.DB
+
+	tooltips       *LRU[int, *models.TooltipData]
+	itemSummaries  *LRU[int, models.ItemSummary]
+	spellTexts     *LRU[int, string]
+	spellDurations *LRU[int, int]
+	spellRanges    *LRU[int, int]
+	spellRadii     *LRU[int, int]
+	zoneNames      *LRU[ZoneKey, string]
+
+	caches  []cache
+	byTable map[string][]cache
+}
+
+// cache is the part of an LRU the ReadCache purges and reports on
+type cache interface {
+	Purge()
+	Stats() models.CacheStats
+}
+
+// ZoneKey looks up a zone name by zone, falling back to the map
+type ZoneKey struct {
+	ZoneID int
+	MapID  int
+}
+
+func newReadCache(db *sql.DB) *ReadCache {
+	c := &ReadCache{
+		db:             db,
+		tooltips:       NewLRU[int, *models.TooltipData]("tooltips", 2000),
+		itemSummaries:  NewLRU[int, models.ItemSummary]("itemSummaries", 10000),
+		spellTexts:     NewLRU[int, string]("spellTexts", 5000),
+		spellDurations: NewLRU[int, int]("spellDurations", 1000),
+		spellRanges:    NewLRU[int, int]("spellRanges", 500),
+		spellRadii:     NewLRU[int, int]("spellRadii", 500),
+		zoneNames:      NewLRU[ZoneKey, string]("zoneNames", 500),
+		byTable:        make(map[string][]cache),
+	}
+	c.register(c.tooltips, "item_template", "item_display_info", "itemsets",
+		"spell_template", "spell_durations", "spell_range", "spell_radius")
+	c.register(c.itemSummaries, "item_template", "item_display_info")
+	c.register(c.spellTexts, "spell_template", "spell_durations", "spell_range", "spell_radius")
+	c.register(c.spellDurations, "spell_durations")
+	c.register(c.spellRanges, "spell_range")
+	c.register(c.spellRadii, "spell_radius")
+	c.register(c.zoneNames, "aowow_zones", "map_template")
+	return c
+}
+
+func (c *ReadCache) register(lru cache, tables ...string) {
+	c.caches = append(c.caches, lru)
+	for _, table := range tables {
+		c.byTable[table] = append(c.byTable[table], lru)
+	}
+}
+
+// Invalidate purges the caches read from any of the given tables
+func (c *ReadCache) Invalidate(tables ...string) {
+	for _, table := range tables {
+		for _, lru := range c.byTable[strings.ToLower(table)] {
+			lru.Purge()
+		}
+	}
+}
+
+// Purge empties every cache
+func (c *ReadCache) Purge() {
+	for _, lru := range c.caches {
+		lru.Purge()
+	}
+}
+
+// Stats returns the usage of every cache
+func (c *ReadCache) Stats() []models.CacheStats {
+	stats := make([]models.CacheStats, len(c.caches))
+	for i, lru := range c.caches {
+		stats[i] = lru.Stats()
+	}
+	return stats
+}
+
+// ZoneNames caches zone names, which the NPC service resolves from MySQL
+func (c *ReadCache) ZoneNames() *LRU[ZoneKey, string] {
+	return c.zoneNames
+}
+
+// spellDurationBase returns the duration in milliseconds of a spell_durations row
+func (c *ReadCache) spellDurationBase(id int) int {
+	duration, _ := c.spellDurations.Load(id, func() (int, error) {
+		var durationBase int
+		err := c.db.QueryRow("SELECT duration_base FROM spell_durations WHERE id = ?", id).Scan(&durationBase)
+		return durationBase, noRowsAsZero(err)
+	})
+	return duration
+}
+
+// spellRangeMax returns the maximum range of a spell_range row
+func (c *ReadCache) spellRangeMax(id int) int {
+	rangeMax, _ := c.spellRanges.Load(id, func() (int, error) {
+		var rangeMax int
+		err := c.db.QueryRow("SELECT CAST(range_max AS INTEGER) FROM spell_range WHERE id = ?", id).Scan(&rangeMax)
+		return rangeMax, noRowsAsZero(err)
+	})
+	return rangeMax
+}
+
+// spellRadius returns the base radius of a spell_radius row
+func (c *ReadCache) spellRadius(id int) int {
+	radius, _ := c.spellRadii.Load(id, func() (int, error) {
+		var radius int
+		err := c.db.QueryRow("SELECT CAST(radius_base AS INTEGER) FROM spell_radius WHERE id = ?", id).Scan(&radius)
+		return radius, noRowsAsZero(err)
+	})
+	return radius
+}
+
+// noRowsAsZero lets a missing row be cached as zero, like the lookups
+// without a cache read it
+func noRowsAsZero(err error) error {
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// readCaches holds the ReadCache of each open database
+var readCaches sync.Map // *sql.DB -> *ReadCache
+
+// CacheFor returns the read cache of db, creating it on first use
+func CacheFor(db *sql.DB) *ReadCache {
+	if c, ok := readCaches.Load(db); ok {
+		return c.(*ReadCache)
+	}
+	c, _ := readCaches.LoadOrStore(db, newReadCache(db))
+	return c.(*ReadCache)
+}
+
+// InvalidateCache purges the cached reads of the given tables of db. Syncs,
+// importers and patches call it after writing rows.
+func InvalidateCache(db *sql.DB, tables ...string) {
+	if c, ok := readCaches.Load(db); ok {
+		c.(*ReadCache).Invalidate(tables...)
+	}
+}
+
+// PurgeCache empties the read cache of db, for writes that may touch any table
+func PurgeCache(db *sql.DB) {
+	if c, ok := readCaches.Load(db); ok {
+		c.(*ReadCache).Purge()
+	}
+}
+
+// DropCache forgets the read cache of a database being closed
+func DropCache(db *sql.DB) {
+	readCaches.Delete(db)
+}
@@ -0,0 +1,106 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"shelllab/backend/database/repositories"
+)
+
+// cacheEntries returns the number of entries in the named read cache of db
+func cacheEntries(t *testing.T, db *sql.DB, name string) int {
+	t.Helper()
+	for _, s := range repositories.CacheFor(db).Stats() {
+		if s.Name == name {
+			return s.Entries
+		}
+	}
+	t.Fatalf("no read cache named %q", name)
+	return 0
+}
+
+// tooltipName reads the item name through the cached tooltip
+func tooltipName(t *testing.T, db *sql.DB, entry int) string {
+	t.Helper()
+	tooltip, err := repositories.NewItemRepository(db).GetTooltipData(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tooltip.Name
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	lru := repositories.NewLRU[int, string]("test", 2)
+	load := func(v string) func() (string, error) { return func() (string, error) { return v, nil } }
+
+	lru.Load(1, load("one"))
+	lru.Load(2, load("two"))
+	lru.Get(1) // 2 is now the least recently used
+	lru.Load(3, load("three"))
+
+	for key, want := range map[int]bool{1: true, 2: false, 3: true} {
+		if _, ok := lru.Get(key); ok != want {
+			t.Errorf("key %d cached: %v, want %v", key, ok, want)
+		}
+	}
+	if s := lru.Stats(); s.Entries != 2 || s.Evictions != 1 {
+		t.Errorf("%d entries, %d evictions, want 2 and 1", s.Entries, s.Evictions)
+	}
+
+	// A failed load is returned but not cached
+	if _, err := lru.Load(4, func() (string, error) { return "", sql.ErrConnDone }); err == nil {
+		t.Error("expected the load error")
+	}
+	if _, ok := lru.Get(4); ok {
+		t.Error("failed load was cached")
+	}
+}
+
+func TestInvalidateCachePurgesDependentTables(t *testing.T) {
+	db := openTestDB(t, t.TempDir(), `INSERT INTO item_template (entry, name) VALUES (1, 'Linen Cloth')`).DB()
+	zones := repositories.CacheFor(db).ZoneNames()
+	zones.Load(repositories.ZoneKey{ZoneID: 12}, func() (string, error) { return "Elwynn Forest", nil })
+	if got := tooltipName(t, db, 1); got != "Linen Cloth" {
+		t.Fatalf("tooltip name %q", got)
+	}
+
+	// A write the cache was not told about is not seen
+	if _, err := db.Exec(`UPDATE item_template SET name = 'Bolt of Linen Cloth' WHERE entry = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if got := tooltipName(t, db, 1); got != "Linen Cloth" {
+		t.Errorf("tooltip name %q before invalidation, want the cached Linen Cloth", got)
+	}
+
+	// Invalidating an unrelated table keeps the tooltip
+	repositories.InvalidateCache(db, "map_template")
+	if cacheEntries(t, db, "tooltips") != 1 || cacheEntries(t, db, "zoneNames") != 0 {
+		t.Error("map_template purged the wrong caches")
+	}
+
+	zones.Load(repositories.ZoneKey{ZoneID: 12}, func() (string, error) { return "Elwynn Forest", nil })
+	repositories.InvalidateCache(db, "item_template")
+	if cacheEntries(t, db, "tooltips") != 0 || cacheEntries(t, db, "zoneNames") != 1 {
+		t.Error("item_template purged the wrong caches")
+	}
+	if got := tooltipName(t, db, 1); got != "Bolt of Linen Cloth" {
+		t.Errorf("tooltip name %q after invalidation, want Bolt of Linen Cloth", got)
+	}
+}
+
+func TestReadCachesAreKeptPerDatabase(t *testing.T) {
+	first := openTestDB(t, t.TempDir(), `INSERT INTO item_template (entry, name) VALUES (1, 'Linen Cloth')`).DB()
+	second := openTestDB(t, t.TempDir(), `INSERT INTO item_template (entry, name) VALUES (1, 'Wool Cloth')`).DB()
+
+	if got := tooltipName(t, first, 1); got != "Linen Cloth" {
+		t.Errorf("first database tooltip %q, want Linen Cloth", got)
+	}
+	if got := tooltipName(t, second, 1); got != "Wool Cloth" {
+		t.Errorf("second database tooltip %q, want Wool Cloth", got)
+	}
+
+	repositories.InvalidateCache(first, "item_template")
+	if cacheEntries(t, first, "tooltips") != 0 || cacheEntries(t, second, "tooltips") != 1 {
+		t.Error("invalidating one database purged the other")
+	}
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/models"
+)
+
+// SavedSearchRepository keeps named AdvancedSearch filters and evaluates them
+// as smart collections. Saved searches live in the attached user database.
+type SavedSearchRepository struct {
+	db    *sql.DB
+	items *ItemRepository
+}
+
+// NewSavedSearchRepository creates a new SavedSearchRepository
+func NewSavedSearchRepository(db *sql.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db, items: NewItemRepository(db)}
+}
+
+// InitSchema creates the saved_searches table in the user database if not
+// exists. Names are unique, so imports match saved searches by name.
+func (r *SavedSearchRepository) InitSchema() error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS user.saved_searches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		filter TEXT NOT NULL DEFAULT '{}',
+		sort TEXT DEFAULT '',
+		sort_desc INTEGER DEFAULT 0,
+		created_at TEXT NOT NULL,
+		last_viewed_at TEXT NOT NULL,
+		UNIQUE(name)
+	);
+	`
+	_, err := r.db.Exec(schema)
+	return err
+}
+
+// SaveSearch inserts a saved search, or updates its name, filter and sort
+// when ID is set. The filter is stored without Limit, Offset and sort.
+func (r *SavedSearchRepository) SaveSearch(s *models.SavedSearch) (*models.SavedSearch, error) {
+	name := strings.TrimSpace(s.Name)
+	if name == "" {
+		return nil, errors.New("saved search needs a name")
+	}
+	if _, err := itemSorts.orderBy(models.PageQuery{Sort: s.Sort}, "", ""); err != nil {
+		return nil, err
+	}
+	var taken int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM user.saved_searches WHERE name = ? AND id <> ?`, name, s.ID).Scan(&taken); err != nil {
+		return nil, err
+	}
+	if taken > 0 {
+		return nil, fmt.Errorf("a saved search named %q already exists", name)
+	}
+
+	filter := s.Filter
+	filter.Sort, filter.Desc, filter.Limit, filter.Offset = "", false, 0, 0
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ID > 0 {
+		res, err := r.db.Exec(`
+			UPDATE user.saved_searches SET name = ?, filter = ?, sort = ?, sort_desc = ?
+			WHERE id = ?
+		`, name, string(data), s.Sort, s.Desc, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("saved search %d not found", s.ID)
+		}
+		return r.GetSavedSearch(s.ID)
+	}
+
+	// Nothing is new in a search before it is first viewed
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := r.db.Exec(`
+		INSERT INTO user.saved_searches (name, filter, sort, sort_desc, created_at, last_viewed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, name, string(data), s.Sort, s.Desc, now, now)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetSavedSearch(int(id))
+}
+
+// DeleteSearch removes a saved search
+func (r *SavedSearchRepository) DeleteSearch(id int) error {
+	_, err := r.db.Exec(`DELETE FROM user.saved_searches WHERE id = ?`, id)
+	return err
+}
+
+// GetSavedSearch gets a saved search by id, or nil if it doesn't exist
+func (r *SavedSearchRepository) GetSavedSearch(id int) (*models.SavedSearch, error) {
+	row := r.db.QueryRow(`
+		SELECT id, name, filter, COALESCE(sort, ''), COALESCE(sort_desc, 0), created_at, last_viewed_at
+		FROM user.saved_searches
+		WHERE id = ?
+	`, id)
+	s, err := scanSavedSearch(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+// GetSavedSearches returns all saved searches by name
+func (r *SavedSearchRepository) GetSavedSearches() ([]*models.SavedSearch, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, filter, COALESCE(sort, ''), COALESCE(sort_desc, 0), created_at, last_viewed_at
+		FROM user.saved_searches
+		ORDER BY name COLLATE NOCASE, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []*models.SavedSearch
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			fmt.Printf("Error scanning saved search: %v\n", err)
+			continue
+		}
+		searches = append(searches, s)
+	}
+	return searches, rows.Err()
+}
+
+// scanSavedSearch scans a saved_searches row and decodes its filter
+func scanSavedSearch(row interface{ Scan(...interface{}) error }) (*models.SavedSearch, error) {
+	s := &models.SavedSearch{}
+	var filter string
+	if err := row.Scan(&s.ID, &s.Name, &filter, &s.Sort, &s.Desc, &s.CreatedAt, &s.LastViewedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(filter), &s.Filter); err != nil {
+		return nil, fmt.Errorf("saved search %d: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+// GetSmartCollections evaluates every saved search, counting its items and
+// the ones new since it was last viewed
+func (r *SavedSearchRepository) GetSmartCollections() ([]*models.SmartCollection, error) {
+	searches, err := r.GetSavedSearches()
+	if err != nil {
+		return nil, err
+	}
+	collections := make([]*models.SmartCollection, 0, len(searches))
+	for _, s := range searches {
+		c, err := r.evaluate(s)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, c)
+	}
+	return collections, nil
+}
+
+// GetSmartCollection evaluates one saved search and returns a page of its
+// items, or nil if the search doesn't exist. Viewing does not reset the new
+// items; call MarkViewed for that.
+func (r *SavedSearchRepository) GetSmartCollection(id, limit, offset int) (*models.SmartCollectionView, error) {
+	s, err := r.GetSavedSearch(id)
+	if err != nil || s == nil {
+		return nil, err
+	}
+	c, err := r.evaluate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := s.Filter
+	filter.Sort, filter.Desc, filter.Limit, filter.Offset = s.Sort, s.Desc, limit, offset
+	result, err := r.items.AdvancedSearch(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.SmartCollectionView{Collection: c, Items: result.Items, NewItems: []int{}}
+	if view.Items == nil {
+		view.Items = []*models.Item{}
+	}
+	entries := make([]int, len(view.Items))
+	for i, item := range view.Items {
+		entries[i] = item.Entry
+	}
+	if len(entries) > 0 && c.NewCount > 0 {
+		in, args := inClause(entries)
+		rows, err := r.db.Query(`
+			SELECT DISTINCT entity_id FROM user.change_log
+			WHERE entity_type = ? AND changed_at >= ? AND entity_id IN (`+in+`)
+		`, append([]interface{}{models.ChangeEntityItem, s.LastViewedAt}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var entry int
+			if err := rows.Scan(&entry); err != nil {
+				return nil, err
+			}
+			view.NewItems = append(view.NewItems, entry)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// MarkViewed clears the new items of a smart collection
+func (r *SavedSearchRepository) MarkViewed(id int) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := r.db.Exec(`UPDATE user.saved_searches SET last_viewed_at = ? WHERE id = ?`, now, id)
+	return err
+}
+
+// evaluate counts the items matching s, and those a sync added or changed
+// since s was last viewed. Changes in the second of the last view count as
+// new, so none made while viewing are missed.
+func (r *SavedSearchRepository) evaluate(s *models.SavedSearch) (*models.SmartCollection, error) {
+	whereClause, args := searchWhere(s.Filter)
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(t.entry IN (
+			SELECT entity_id FROM user.change_log WHERE entity_type = ? AND changed_at >= ?
+		)), 0)
+		FROM item_template t ` + whereClause
+	args = append([]interface{}{models.ChangeEntityItem, s.LastViewedAt}, args...)
+
+	c := &models.SmartCollection{Search: s}
+	if err := r.db.QueryRow(query, args...).Scan(&c.Count, &c.NewCount); err != nil {
+		return nil, fmt.Errorf("smart collection %q: %w", s.Name, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,166 @@
+package repositories_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+)
+
+// openSavedSearchDB has two epic swords and a rare one
+func openSavedSearchDB(t *testing.T) (*database.SQLiteDB, *database.SavedSearchRepository) {
+	t.Helper()
+	db := openTestDB(t, t.TempDir(),
+		`INSERT INTO item_template (entry, name, class, subclass, quality, item_level) VALUES
+			(1, 'Ashkandi', 2, 8, 4, 77),
+			(2, 'Quel''Serrar', 2, 7, 4, 63),
+			(3, 'Sword of Zeal', 2, 7, 3, 63)`,
+	)
+	for _, init := range []func() error{
+		database.NewChangeLogRepository(db).InitSchema,
+		database.NewSavedSearchRepository(db).InitSchema,
+	} {
+		if err := init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db, database.NewSavedSearchRepository(db)
+}
+
+func TestSaveSearchValidation(t *testing.T) {
+	_, repo := openSavedSearchDB(t)
+	if _, err := repo.SaveSearch(&models.SavedSearch{Name: "Epics", Filter: models.SearchFilter{Quality: []int{4}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		search  models.SavedSearch
+		wantErr string
+		invalid bool // ErrInvalidSort
+	}{
+		{name: "empty name", search: models.SavedSearch{Name: ""}, wantErr: "needs a name"},
+		{name: "blank name", search: models.SavedSearch{Name: "  \t"}, wantErr: "needs a name"},
+		{name: "unknown sort", search: models.SavedSearch{Name: "Swords", Sort: "weight"}, invalid: true},
+		{name: "duplicate name", search: models.SavedSearch{Name: "Epics"}, wantErr: `named "Epics" already exists`},
+		{name: "duplicate after trimming", search: models.SavedSearch{Name: " Epics "}, wantErr: `named "Epics" already exists`},
+		{name: "update of a missing id", search: models.SavedSearch{ID: 99, Name: "Gone"}, wantErr: "saved search 99 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, err := repo.SaveSearch(&tt.search)
+			if err == nil {
+				t.Fatalf("saved %+v", saved)
+			}
+			if tt.invalid != errors.Is(err, database.ErrInvalidSort) {
+				t.Errorf("error %v, want ErrInvalidSort %v", err, tt.invalid)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	searches, err := repo.GetSavedSearches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(searches) != 1 {
+		t.Errorf("%d saved searches after failed saves, want 1", len(searches))
+	}
+}
+
+func TestSaveSearchUpdate(t *testing.T) {
+	_, repo := openSavedSearchDB(t)
+	saved, err := repo.SaveSearch(&models.SavedSearch{
+		Name:   " Swords ",
+		Filter: models.SearchFilter{Class: []int{2}, Sort: models.SortName, Limit: 50, Offset: 100},
+		Sort:   models.SortItemLevel,
+		Desc:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Name != "Swords" || saved.Sort != models.SortItemLevel || !saved.Desc {
+		t.Errorf("saved %+v", saved)
+	}
+	if f := saved.Filter; f.Sort != "" || f.Limit != 0 || f.Offset != 0 || len(f.Class) != 1 {
+		t.Errorf("stored filter %+v, want only the class", f)
+	}
+
+	// Keeping its own name is not a duplicate
+	updated, err := repo.SaveSearch(&models.SavedSearch{ID: saved.ID, Name: "Swords", Filter: models.SearchFilter{SubClass: []int{7}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != saved.ID || updated.Sort != "" || updated.Desc || len(updated.Filter.SubClass) != 1 || len(updated.Filter.Class) != 0 {
+		t.Errorf("updated %+v", updated)
+	}
+	if updated.CreatedAt != saved.CreatedAt {
+		t.Errorf("update changed created_at from %s to %s", saved.CreatedAt, updated.CreatedAt)
+	}
+}
+
+func TestSmartCollectionNewItems(t *testing.T) {
+	db, repo := openSavedSearchDB(t)
+	saved, err := repo.SaveSearch(&models.SavedSearch{Name: "Epics", Filter: models.SearchFilter{Quality: []int{4}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A sync changed one epic and the rare after the search was last viewed
+	if _, err := db.DB().Exec(`UPDATE user.saved_searches SET last_viewed_at = '2024-01-01T00:00:00Z'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.NewChangeLogRepository(db).Record([]*models.ChangeLogEntry{
+		{EntityType: models.ChangeEntityItem, EntityID: 2, Action: models.ChangeActionAdded, ChangedAt: "2024-02-01T00:00:00Z"},
+		{EntityType: models.ChangeEntityItem, EntityID: 3, Action: models.ChangeActionAdded, ChangedAt: "2024-02-01T00:00:00Z"},
+		{EntityType: models.ChangeEntityItem, EntityID: 1, Action: models.ChangeActionAdded, ChangedAt: "2023-12-01T00:00:00Z"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := repo.GetSmartCollection(saved.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := view.Collection; c.Count != 2 || c.NewCount != 1 {
+		t.Errorf("count %d, new %d, want 2 and 1", c.Count, c.NewCount)
+	}
+	if len(view.Items) != 2 || len(view.NewItems) != 1 || view.NewItems[0] != 2 {
+		t.Errorf("%d items, new %v, want 2 items and [2]", len(view.Items), view.NewItems)
+	}
+
+	// Viewing alone keeps the new items
+	if view, err = repo.GetSmartCollection(saved.ID, 10, 0); err != nil || view.Collection.NewCount != 1 {
+		t.Fatalf("second view: %v, %+v", err, view)
+	}
+
+	if err := repo.MarkViewed(saved.ID); err != nil {
+		t.Fatal(err)
+	}
+	collections, err := repo.GetSmartCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 1 || collections[0].Count != 2 || collections[0].NewCount != 0 {
+		t.Errorf("after MarkViewed: %+v", collections[0])
+	}
+	if view, err = repo.GetSmartCollection(saved.ID, 10, 0); err != nil || len(view.NewItems) != 0 {
+		t.Errorf("after MarkViewed: %v, new items %v", err, view.NewItems)
+	}
+}
+
+func TestGetSmartCollectionUnknownID(t *testing.T) {
+	_, repo := openSavedSearchDB(t)
+	view, err := repo.GetSmartCollection(42, 10, 0)
+	if err != nil || view != nil {
+		t.Errorf("GetSmartCollection(42) = %+v, %v, want nil, nil", view, err)
+	}
+	search, err := repo.GetSavedSearch(42)
+	if err != nil || search != nil {
+		t.Errorf("GetSavedSearch(42) = %+v, %v, want nil, nil", search, err)
+	}
+}
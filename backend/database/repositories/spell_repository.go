@@ -128,14 +128,39 @@ func (r *SpellRepository) GetSpellSkillsByCategory(categoryID int) ([]*models.Sp
 	return skills, nil
 }
 
-// GetSpellsBySkill returns all spells for a given skill
-func (r *SpellRepository) GetSpellsBySkill(skillID int, nameFilter string) ([]*models.Spell, error) {
+// spellSorts are the sort keys of the spell browse list
+var spellSorts = sortColumns{
+	models.SortName:  "sp.name",
+	models.SortLevel: "sp.spellLevel",
+}
+
+// GetSpellsBySkill returns a page of spells for a given skill
+func (r *SpellRepository) GetSpellsBySkill(skillID int, nameFilter string, page models.PageQuery) (*models.Page[*models.Spell], error) {
+	limit, offset, err := pageWindow(page)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := spellSorts.orderBy(page, "sp.name, sp.entry", "sp.entry")
+	if err != nil {
+		return nil, err
+	}
+
 	whereClause := "WHERE ss.skill_id = ?"
 	args := []interface{}{skillID}
 
-	if nameFilter != "" {
-		whereClause += " AND sp.name LIKE ?"
-		args = append(args, "%"+nameFilter+"%")
+	filter, filterArgs := nameFilterClause("sp.name", "sp.entry", nameFilter)
+	whereClause += filter
+	args = append(args, filterArgs...)
+
+	var count int
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM spell_template sp
+		INNER JOIN spell_skill_spells ss ON ss.spell_id = sp.entry
+		%s
+	`, whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&count); err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
@@ -145,11 +170,11 @@ func (r *SpellRepository) GetSpellsBySkill(skillID int, nameFilter string) ([]*m
 		INNER JOIN spell_skill_spells ss ON ss.spell_id = sp.entry
 		LEFT JOIN spell_icons si ON sp.spellIconId = si.id
 		%s
-		ORDER BY sp.name
-		LIMIT 10000
-	`, whereClause)
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, whereClause, orderBy)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +193,7 @@ func (r *SpellRepository) GetSpellsBySkill(skillID int, nameFilter string) ([]*m
 		}
 		spells = append(spells, s)
 	}
-	return spells, nil
+	return newPage(spells, count, offset), nil
 }
 
 // GetSpellByID retrieves a single spell by ID
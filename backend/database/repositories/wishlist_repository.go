@@ -0,0 +1,547 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"shelllab/backend/database/models"
+)
+
+// WishlistRepository handles named wishlists and their items.
+// Wishlists live in the attached user database, see database.UserSchema.
+type WishlistRepository struct {
+	db *sql.DB
+}
+
+// NewWishlistRepository creates a new WishlistRepository
+func NewWishlistRepository(db *sql.DB) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+// InitSchema creates the wishlist tables in the user database if not exists,
+// with the default list. Items name their list rather than referencing its
+// id, so user data archives, which leave local ids out, keep them on the
+// right list.
+func (r *WishlistRepository) InitSchema() error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS user.wishlists (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		description TEXT DEFAULT '',
+		created_at TEXT NOT NULL,
+		UNIQUE(name)
+	);
+	CREATE TABLE IF NOT EXISTS user.wishlist_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		wishlist TEXT NOT NULL,
+		item_entry INTEGER NOT NULL,
+		character_name TEXT NOT NULL DEFAULT '',
+		priority INTEGER DEFAULT 0,
+		notes TEXT DEFAULT '',
+		source_type TEXT DEFAULT '',
+		source_entry INTEGER DEFAULT 0,
+		status INTEGER DEFAULT 0,
+		obtained_at TEXT DEFAULT '',
+		added_at TEXT NOT NULL,
+		UNIQUE(wishlist, item_entry, character_name)
+	);
+	CREATE INDEX IF NOT EXISTS user.idx_wishlist_items_item ON wishlist_items(item_entry);
+	CREATE INDEX IF NOT EXISTS user.idx_wishlist_items_character ON wishlist_items(character_name);
+	`
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
+	}
+	// Capitalize names saved before they were normalized. A row that would
+	// then duplicate another is left as it is.
+	if _, err := r.db.Exec(`
+		UPDATE OR IGNORE user.wishlist_items
+		SET character_name = UPPER(SUBSTR(TRIM(character_name), 1, 1)) || LOWER(SUBSTR(TRIM(character_name), 2))
+		WHERE character_name <> UPPER(SUBSTR(TRIM(character_name), 1, 1)) || LOWER(SUBSTR(TRIM(character_name), 2))
+	`); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO user.wishlists (name, created_at) VALUES (?, ?)`,
+		models.DefaultWishlist, time.Now().Format(time.RFC3339))
+	return err
+}
+
+// MigrateFavorites moves the favorites of older versions, kept in user.db or
+// shelllab.db, onto wishlists and drops the old table. Each category becomes
+// a list; favorites without one go to the default list. Returns the number
+// of favorites moved; 0 once the move is done.
+func (r *WishlistRepository) MigrateFavorites() (int64, error) {
+	var moved int64
+	for _, schemaName := range []string{"user", "main"} {
+		var exists int
+		err := r.db.QueryRow(fmt.Sprintf(
+			"SELECT COUNT(*) FROM %s.sqlite_master WHERE type = 'table' AND name = 'favorites'", schemaName)).Scan(&exists)
+		if err != nil {
+			return moved, err
+		}
+		if exists == 0 {
+			continue
+		}
+		n, err := r.migrateFavorites(schemaName)
+		if err != nil {
+			return moved, fmt.Errorf("failed to move %s.favorites to wishlists: %w", schemaName, err)
+		}
+		moved += n
+	}
+	return moved, nil
+}
+
+func (r *WishlistRepository) migrateFavorites(schemaName string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Format(time.RFC3339)
+	if _, err := tx.Exec(fmt.Sprintf(`
+		INSERT OR IGNORE INTO user.wishlists (name, created_at)
+		SELECT DISTINCT TRIM(category), ? FROM %s.favorites
+		WHERE TRIM(COALESCE(category, '')) <> ''
+	`, schemaName), now); err != nil {
+		return 0, err
+	}
+	// The date an item was obtained wasn't recorded, so obtained_at stays empty
+	res, err := tx.Exec(fmt.Sprintf(`
+		INSERT OR IGNORE INTO user.wishlist_items (wishlist, item_entry, status, added_at)
+		SELECT CASE WHEN TRIM(COALESCE(category, '')) = '' THEN ? ELSE TRIM(category) END,
+		       item_entry, COALESCE(status, 0), added_at
+		FROM %s.favorites
+	`, schemaName), models.DefaultWishlist)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s.favorites", schemaName)); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetWishlists returns all wishlists with their item counts, the default
+// list first
+func (r *WishlistRepository) GetWishlists() ([]*models.Wishlist, error) {
+	rows, err := r.db.Query(`
+		SELECT l.id, l.name, COALESCE(l.description, ''), l.created_at,
+		       COUNT(i.id), COALESCE(SUM(i.status = ?), 0)
+		FROM user.wishlists l
+		LEFT JOIN user.wishlist_items i ON i.wishlist = l.name
+		GROUP BY l.id
+		ORDER BY l.name = ? DESC, l.name COLLATE NOCASE
+	`, models.WishlistStatusObtained, models.DefaultWishlist)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []*models.Wishlist
+	for rows.Next() {
+		l := &models.Wishlist{}
+		if err := rows.Scan(&l.ID, &l.Name, &l.Description, &l.CreatedAt, &l.ItemCount, &l.ObtainedCount); err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+	return lists, rows.Err()
+}
+
+// CreateWishlist adds an empty wishlist
+func (r *WishlistRepository) CreateWishlist(name, description string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("wishlist needs a name")
+	}
+	if err := checkWishlistNameFree(r.db, name, 0); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`INSERT INTO user.wishlists (name, description, created_at) VALUES (?, ?, ?)`,
+		name, description, time.Now().Format(time.RFC3339))
+	return err
+}
+
+// UpdateWishlist renames a wishlist and sets its description. The items move
+// along with a rename. The default list keeps its name.
+func (r *WishlistRepository) UpdateWishlist(id int, name, description string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("wishlist needs a name")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	oldName, err := wishlistName(tx, id)
+	if err != nil {
+		return err
+	}
+	if name != oldName {
+		if oldName == models.DefaultWishlist {
+			return errors.New("the default wishlist can't be renamed")
+		}
+		if err := checkWishlistNameFree(tx, name, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE user.wishlist_items SET wishlist = ? WHERE wishlist = ?`, name, oldName); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE user.wishlists SET name = ?, description = ? WHERE id = ?`, name, description, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteWishlist removes a wishlist and its items. The default list can only
+// be emptied.
+func (r *WishlistRepository) DeleteWishlist(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	name, err := wishlistName(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM user.wishlist_items WHERE wishlist = ?`, name); err != nil {
+		return err
+	}
+	if name != models.DefaultWishlist {
+		if _, err := tx.Exec(`DELETE FROM user.wishlists WHERE id = ?`, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// wishlistName returns the name of the wishlist with the given id
+func wishlistName(tx *sql.Tx, id int) (string, error) {
+	var name string
+	err := tx.QueryRow(`SELECT name FROM user.wishlists WHERE id = ?`, id).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("wishlist %d not found", id)
+	}
+	return name, err
+}
+
+// rowQueryer is satisfied by *sql.DB and *sql.Tx
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkWishlistNameFree fails if a wishlist other than id is called name
+func checkWishlistNameFree(q rowQueryer, name string, id int) error {
+	var taken int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM user.wishlists WHERE name = ? AND id <> ?`, name, id).Scan(&taken); err != nil {
+		return err
+	}
+	if taken > 0 {
+		return fmt.Errorf("a wishlist named %q already exists", name)
+	}
+	return nil
+}
+
+// ensureWishlist creates the named wishlist if it doesn't exist
+func (r *WishlistRepository) ensureWishlist(name string) error {
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO user.wishlists (name, created_at) VALUES (?, ?)`,
+		name, time.Now().Format(time.RFC3339))
+	return err
+}
+
+// requireWishlist fails if the named wishlist doesn't exist
+func requireWishlist(q rowQueryer, name string) error {
+	var exists int
+	if err := q.QueryRow(`SELECT COUNT(*) FROM user.wishlists WHERE name = ?`, name).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return fmt.Errorf("wishlist %q not found", name)
+	}
+	return nil
+}
+
+// AddItem puts an item on a wishlist, the default list when none is given.
+// Adding an item that is already on the list for the same character updates
+// its priority, notes and source and keeps its status.
+func (r *WishlistRepository) AddItem(item *models.WishlistItem) (*models.WishlistItem, error) {
+	if item.ItemEntry <= 0 {
+		return nil, errors.New("wishlist item needs an item")
+	}
+	if err := normalizeWishlistItem(item); err != nil {
+		return nil, err
+	}
+	if err := requireWishlist(r.db, item.Wishlist); err != nil {
+		return nil, err
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO user.wishlist_items
+			(wishlist, item_entry, character_name, priority, notes, source_type, source_entry, status, added_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?)
+		ON CONFLICT(wishlist, item_entry, character_name) DO UPDATE SET
+			priority = excluded.priority, notes = excluded.notes,
+			source_type = excluded.source_type, source_entry = excluded.source_entry
+		RETURNING id
+	`, item.Wishlist, item.ItemEntry, item.Character, item.Priority, item.Notes,
+		item.SourceType, item.SourceEntry, time.Now().Format(time.RFC3339)).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetItem(id)
+}
+
+// UpdateItem changes the list, character, priority, notes and source of a
+// wishlist item. Use SetItemStatus for its status.
+func (r *WishlistRepository) UpdateItem(item *models.WishlistItem) error {
+	if err := normalizeWishlistItem(item); err != nil {
+		return err
+	}
+	var conflict int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM user.wishlist_items
+		WHERE wishlist = ? AND character_name = ? AND id <> ?
+		  AND item_entry = (SELECT item_entry FROM user.wishlist_items WHERE id = ?)
+	`, item.Wishlist, item.Character, item.ID, item.ID).Scan(&conflict)
+	if err != nil {
+		return err
+	}
+	if conflict > 0 {
+		return fmt.Errorf("the item is already on %q for that character", item.Wishlist)
+	}
+	if err := requireWishlist(r.db, item.Wishlist); err != nil {
+		return err
+	}
+
+	res, err := r.db.Exec(`
+		UPDATE user.wishlist_items
+		SET wishlist = ?, character_name = ?, priority = ?, notes = ?, source_type = ?, source_entry = ?
+		WHERE id = ?
+	`, item.Wishlist, item.Character, item.Priority, item.Notes, item.SourceType, item.SourceEntry, item.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("wishlist item %d not found", item.ID)
+	}
+	return nil
+}
+
+// normalizeWishlistItem trims the text fields of item, defaults its list and
+// checks its source
+func normalizeWishlistItem(item *models.WishlistItem) error {
+	item.Wishlist = strings.TrimSpace(item.Wishlist)
+	if item.Wishlist == "" {
+		item.Wishlist = models.DefaultWishlist
+	}
+	character, err := normalizeCharacterName(item.Character)
+	if err != nil {
+		return err
+	}
+	item.Character = character
+	item.Notes = strings.TrimSpace(item.Notes)
+
+	switch item.SourceType {
+	case "":
+		item.SourceEntry = 0
+	case models.WishlistSourceBoss, models.WishlistSourceQuest:
+		if item.SourceEntry <= 0 {
+			return fmt.Errorf("%s source needs an entry", item.SourceType)
+		}
+	default:
+		return fmt.Errorf("unknown source type %q (supported: %s, %s)",
+			item.SourceType, models.WishlistSourceBoss, models.WishlistSourceQuest)
+	}
+	return nil
+}
+
+// Character names follow the game's rules: 2 to 12 letters, no spaces
+const (
+	minCharacterName = 2
+	maxCharacterName = 12
+)
+
+// normalizeCharacterName capitalizes a character name the way the game
+// shows it ("thrall" becomes "Thrall"), so one character is never stored
+// under several spellings. An empty name is kept as no character.
+func normalizeCharacterName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	if n := utf8.RuneCountInString(name); n < minCharacterName || n > maxCharacterName {
+		return "", fmt.Errorf("character name %q must be %d to %d letters", name, minCharacterName, maxCharacterName)
+	}
+	runes := []rune(name)
+	for i, c := range runes {
+		if !unicode.IsLetter(c) {
+			return "", fmt.Errorf("character name %q may only contain letters", name)
+		}
+		if i == 0 {
+			runes[i] = unicode.ToUpper(c)
+		} else {
+			runes[i] = unicode.ToLower(c)
+		}
+	}
+	return string(runes), nil
+}
+
+// setStatusSQL sets the status of wishlist items, recording the date an
+// item is first obtained and clearing it when the item is wanted again
+const setStatusSQL = `
+	UPDATE user.wishlist_items
+	SET status = ?1,
+	    obtained_at = CASE
+	        WHEN ?1 <> 1 THEN ''
+	        WHEN COALESCE(obtained_at, '') = '' THEN ?2
+	        ELSE obtained_at
+	    END
+`
+
+// SetItemStatus sets the status of a wishlist item (0=Wanted, 1=Obtained, 2=Abandoned)
+func (r *WishlistRepository) SetItemStatus(id, status int) error {
+	if err := checkWishlistStatus(status); err != nil {
+		return err
+	}
+	res, err := r.db.Exec(setStatusSQL+` WHERE id = ?3`, status, time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("wishlist item %d not found", id)
+	}
+	return nil
+}
+
+func checkWishlistStatus(status int) error {
+	if status < models.WishlistStatusWanted || status > models.WishlistStatusAbandoned {
+		return fmt.Errorf("invalid status %d", status)
+	}
+	return nil
+}
+
+// RemoveItem takes an item off its wishlist
+func (r *WishlistRepository) RemoveItem(id int) error {
+	_, err := r.db.Exec(`DELETE FROM user.wishlist_items WHERE id = ?`, id)
+	return err
+}
+
+// wishlistItemQuery selects wishlist items w with their item summary and the
+// name of their source
+const wishlistItemQuery = `
+	SELECT w.id, w.wishlist, w.item_entry, w.character_name, COALESCE(w.priority, 0), COALESCE(w.notes, ''),
+	       COALESCE(w.source_type, ''), COALESCE(w.source_entry, 0), COALESCE(w.status, 0),
+	       COALESCE(w.obtained_at, ''), w.added_at,
+	       COALESCE(CASE w.source_type
+	           WHEN 'boss' THEN (SELECT name FROM creature_template WHERE entry = w.source_entry)
+	           WHEN 'quest' THEN (SELECT Title FROM quest_template WHERE entry = w.source_entry)
+	       END, ''),
+	       ` + itemSummaryColumns + `
+	FROM user.wishlist_items w
+`
+
+// GetItem gets a wishlist item by id, or nil if it doesn't exist
+func (r *WishlistRepository) GetItem(id int) (*models.WishlistItem, error) {
+	row := r.db.QueryRow(wishlistItemQuery+itemSummaryJoin("w.item_entry")+` WHERE w.id = ?`, id)
+	item := &models.WishlistItem{}
+	err := scanWishlistItem(row, item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return item, err
+}
+
+// GetItems returns the wishlist items matching filter, by list, then
+// highest priority and latest added first
+func (r *WishlistRepository) GetItems(filter models.WishlistItemFilter) ([]*models.WishlistItem, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Wishlist != "" {
+		conditions = append(conditions, "w.wishlist = ?")
+		args = append(args, filter.Wishlist)
+	}
+	if filter.Character != "" {
+		character, err := normalizeCharacterName(filter.Character)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "w.character_name = ?")
+		args = append(args, character)
+	}
+	if filter.ItemEntry > 0 {
+		conditions = append(conditions, "w.item_entry = ?")
+		args = append(args, filter.ItemEntry)
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, models.DefaultWishlist)
+
+	rows, err := r.db.Query(wishlistItemQuery+itemSummaryJoin("w.item_entry")+whereClause+`
+		ORDER BY w.wishlist = ? DESC, w.wishlist COLLATE NOCASE, w.priority DESC, w.added_at DESC, w.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.WishlistItem
+	for rows.Next() {
+		item := &models.WishlistItem{}
+		if err := scanWishlistItem(rows, item); err != nil {
+			fmt.Printf("Error scanning wishlist item: %v\n", err)
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// scanWishlistItem scans a row of wishlistItemQuery
+func scanWishlistItem(row interface{ Scan(...interface{}) error }, item *models.WishlistItem) error {
+	return row.Scan(
+		&item.ID, &item.Wishlist, &item.ItemEntry, &item.Character, &item.Priority, &item.Notes,
+		&item.SourceType, &item.SourceEntry, &item.Status, &item.ObtainedAt, &item.AddedAt,
+		&item.SourceName,
+		&item.ItemName, &item.ItemQuality, &item.ItemLevel, &item.IconPath,
+	)
+}
+
+// GetCharacters returns the characters named on wishlist items
+func (r *WishlistRepository) GetCharacters() ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT character_name FROM user.wishlist_items
+		WHERE character_name <> ''
+		ORDER BY character_name COLLATE NOCASE
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
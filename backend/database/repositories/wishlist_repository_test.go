@@ -0,0 +1,169 @@
+package repositories_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+)
+
+// legacyFavorites is the favorites table of versions before wishlists
+const legacyFavorites = `CREATE TABLE %s.favorites (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_entry INTEGER NOT NULL UNIQUE,
+	category TEXT DEFAULT '',
+	added_at TEXT NOT NULL,
+	status INTEGER DEFAULT 0
+)`
+
+func TestMigrateFavorites(t *testing.T) {
+	tests := []struct {
+		name   string
+		legacy []string
+		moved  int64
+		items  []string
+		lists  []string
+	}{
+		{
+			name:  "no favorites table",
+			items: []string{},
+			lists: []string{"Favorites"},
+		},
+		{
+			name: "favorites in user.db",
+			legacy: []string{
+				fmt.Sprintf(legacyFavorites, "user"),
+				`INSERT INTO user.favorites (item_entry, category, added_at, status) VALUES
+					(10, '', '2024-01-01', 0), (20, ' Raid ', '2024-01-02', 1), (30, 'Raid', '2024-01-03', NULL)`,
+			},
+			moved: 3,
+			items: []string{"Favorites:10:0", "Raid:20:1", "Raid:30:0"},
+			lists: []string{"Favorites", "Raid"},
+		},
+		{
+			name: "favorites in both databases",
+			legacy: []string{
+				fmt.Sprintf(legacyFavorites, "user"),
+				`INSERT INTO user.favorites (item_entry, category, added_at) VALUES (10, '', '2024-01-01')`,
+				// Item 10 is already on the default list, so its shelllab.db copy is dropped
+				fmt.Sprintf(legacyFavorites, "main"),
+				`INSERT INTO main.favorites (item_entry, category, added_at) VALUES
+					(10, NULL, '2023-01-01'), (40, 'Old', '2023-01-02')`,
+			},
+			moved: 2,
+			items: []string{"Favorites:10:0", "Old:40:0"},
+			lists: []string{"Favorites", "Old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, t.TempDir())
+			wishlists := database.NewWishlistRepository(db)
+			if err := wishlists.InitSchema(); err != nil {
+				t.Fatal(err)
+			}
+			for _, stmt := range tt.legacy {
+				if _, err := db.DB().Exec(stmt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			moved, err := wishlists.MigrateFavorites()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if moved != tt.moved {
+				t.Errorf("moved %d favorites, want %d", moved, tt.moved)
+			}
+			if items := queryStrings(t, db, `SELECT wishlist || ':' || item_entry || ':' || status FROM user.wishlist_items ORDER BY 1`); !reflect.DeepEqual(items, tt.items) {
+				t.Errorf("items = %v, want %v", items, tt.items)
+			}
+			if lists := queryStrings(t, db, `SELECT name FROM user.wishlists ORDER BY name`); !reflect.DeepEqual(lists, tt.lists) {
+				t.Errorf("lists = %v, want %v", lists, tt.lists)
+			}
+			for _, schema := range []string{"user", "main"} {
+				if left := queryStrings(t, db, `SELECT name FROM `+schema+`.sqlite_master WHERE name = 'favorites'`); len(left) > 0 {
+					t.Errorf("%s.favorites was not dropped", schema)
+				}
+			}
+
+			if moved, err := wishlists.MigrateFavorites(); err != nil || moved != 0 {
+				t.Errorf("second run moved %d, %v", moved, err)
+			}
+		})
+	}
+}
+
+func TestWishlistCharacterNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		saved   []string
+		want    []string // characters named afterwards
+		wantErr bool
+	}{
+		{name: "no character", saved: []string{"", "  "}, want: []string{}},
+		{name: "spellings of one character", saved: []string{"thrall", " THRALL ", "Thrall"}, want: []string{"Thrall"}},
+		{name: "non-ASCII letters", saved: []string{"ÉLUNÉ"}, want: []string{"Éluné"}},
+		{name: "too short", saved: []string{"X"}, wantErr: true},
+		{name: "too long", saved: []string{"Abcdefghijklm"}, wantErr: true},
+		{name: "space", saved: []string{"Thrall Orc"}, wantErr: true},
+		{name: "digit", saved: []string{"Thrall2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, t.TempDir())
+			wishlists := database.NewWishlistRepository(db)
+			if err := wishlists.InitSchema(); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range tt.saved {
+				_, err := wishlists.AddItem(&models.WishlistItem{ItemEntry: 10, Character: name})
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("AddItem(%q) error = %v, wantErr %v", name, err, tt.wantErr)
+				}
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := queryStrings(t, db, `SELECT DISTINCT character_name FROM user.wishlist_items WHERE character_name <> ''`); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("characters = %v, want %v", got, tt.want)
+			}
+			if n := len(queryStrings(t, db, `SELECT id FROM user.wishlist_items`)); n != 1 {
+				t.Errorf("%d wishlist items, want the spellings to share one", n)
+			}
+			if len(tt.want) > 0 {
+				items, err := wishlists.GetItems(models.WishlistItemFilter{Character: tt.saved[0]})
+				if err != nil || len(items) != 1 {
+					t.Errorf("filter by %q = %d items, %v", tt.saved[0], len(items), err)
+				}
+			}
+		})
+	}
+}
+
+func TestInitSchemaCapitalizesSavedCharacterNames(t *testing.T) {
+	db := openTestDB(t, t.TempDir())
+	wishlists := database.NewWishlistRepository(db)
+	if err := wishlists.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	// Saved before names were normalized; 'THRALL' would duplicate item 10 for Thrall
+	if _, err := db.DB().Exec(`INSERT INTO user.wishlist_items (wishlist, item_entry, character_name, added_at) VALUES
+		('Favorites', 10, 'Thrall', '2024-01-01'), ('Favorites', 10, 'THRALL', '2024-01-01'),
+		('Favorites', 20, ' jaina', '2024-01-01')`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wishlists.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	got := queryStrings(t, db, `SELECT item_entry || ':' || character_name FROM user.wishlist_items ORDER BY 1`)
+	if want := []string{"10:THRALL", "10:Thrall", "20:Jaina"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
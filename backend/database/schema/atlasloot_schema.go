@@ -1,9 +1,5 @@
 package schema
 
-import (
-	"database/sql"
-)
-
 // AtlasLootSchema returns the SQL statements for AtlasLoot tables
 func AtlasLootSchema() string {
 	return `
@@ -72,18 +68,3 @@ func LocaleSchema() string {
 	CREATE INDEX IF NOT EXISTS idx_atlasloot_locale_lang ON atlasloot_locale(language);
 	`
 }
-
-// MigrateAtlasLoot adds new columns for crafting/overrides support
-func MigrateAtlasLoot(db *sql.DB) {
-	// Add columns individually. Ignore errors (assuming error means column exists)
-	cols := []string{
-		"ALTER TABLE atlasloot_items ADD COLUMN spell_id INTEGER DEFAULT 0",
-		"ALTER TABLE atlasloot_items ADD COLUMN override_name TEXT",
-		"ALTER TABLE atlasloot_items ADD COLUMN override_icon TEXT",
-		"ALTER TABLE atlasloot_items ADD COLUMN quality INTEGER DEFAULT 0",
-	}
-
-	for _, q := range cols {
-		db.Exec(q)
-	}
-}
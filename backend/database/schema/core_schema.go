@@ -229,7 +229,13 @@ func CoreSchema() string {
 	CREATE TABLE IF NOT EXISTS creature_metadata (
 		entry INTEGER PRIMARY KEY,
 		map_url TEXT,
-		infobox_json TEXT
+		infobox_json TEXT,
+		model_image_url TEXT,
+		model_image_local TEXT,
+		map_image_local TEXT,
+		zone_name TEXT,
+		x REAL,
+		y REAL
 	);
 
 	-- Indexes for 1:1 tables (created after GeneratedSchema)
@@ -239,6 +245,7 @@ func CoreSchema() string {
 	CREATE INDEX IF NOT EXISTS idx_item_template_display_id ON item_template(display_id);
 	CREATE INDEX IF NOT EXISTS idx_creature_template_name ON creature_template(name);
 	CREATE INDEX IF NOT EXISTS idx_quest_template_title ON quest_template(Title);
+	CREATE INDEX IF NOT EXISTS idx_quest_template_prev ON quest_template(PrevQuestId);
 	CREATE INDEX IF NOT EXISTS idx_spell_template_name ON spell_template(name);
 	CREATE INDEX IF NOT EXISTS idx_spell_template_icon_id ON spell_template(spellIconId);
 
@@ -1,9 +1,5 @@
 package schema
 
-import (
-	"database/sql"
-)
-
 func GeneratedSchema() string {
 	return `
     CREATE TABLE IF NOT EXISTS item_template (
@@ -558,45 +554,3 @@ func GeneratedSchema() string {
 
     `
 }
-
-// MigrateV2 adds new columns for spell slots 4 and 5 to item_template
-func MigrateV2(db *sql.DB) {
-	// Add columns individually. Ignore errors (assuming error means column exists)
-	cols := []string{
-		"ALTER TABLE item_template ADD COLUMN spellid_4 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spelltrigger_4 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcharges_4 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellppmrate_4 REAL DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcooldown_4 INTEGER DEFAULT -1",
-		"ALTER TABLE item_template ADD COLUMN spellcategory_4 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcategorycooldown_4 INTEGER DEFAULT -1",
-		"ALTER TABLE item_template ADD COLUMN spellid_5 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spelltrigger_5 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcharges_5 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellppmrate_5 REAL DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcooldown_5 INTEGER DEFAULT -1",
-		"ALTER TABLE item_template ADD COLUMN spellcategory_5 INTEGER DEFAULT 0",
-		"ALTER TABLE item_template ADD COLUMN spellcategorycooldown_5 INTEGER DEFAULT -1",
-	}
-
-	for _, q := range cols {
-		db.Exec(q)
-	}
-}
-
-// MigratePerformance adds indexes to speed up "Dropped By" and other reverse lookups
-func MigratePerformance(db *sql.DB) {
-	queries := []string{
-		"CREATE INDEX IF NOT EXISTS idx_creature_loot_ref ON creature_loot_template(mincountOrRef)",
-		"CREATE INDEX IF NOT EXISTS idx_reference_loot_item ON reference_loot_template(item)",
-		"CREATE INDEX IF NOT EXISTS idx_gameobject_loot_item ON gameobject_loot_template(item)",
-		"CREATE INDEX IF NOT EXISTS idx_item_loot_item ON item_loot_template(item)",
-		// New Indexes for optimization
-		"CREATE INDEX IF NOT EXISTS idx_creature_template_loot_id ON creature_template(loot_id)",
-		"CREATE INDEX IF NOT EXISTS idx_quest_template_reward1 ON quest_template(RewItemId1)",
-		"CREATE INDEX IF NOT EXISTS idx_quest_template_choice1 ON quest_template(RewChoiceItemId1)",
-	}
-	for _, q := range queries {
-		db.Exec(q)
-	}
-}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Migration is one numbered schema change. Up runs inside a transaction
+// together with the schema_migrations bookkeeping.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(tx *sql.Tx) error
+}
+
+// AppliedMigration is a row of the schema_migrations table
+type AppliedMigration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	AppliedAt   string `json:"appliedAt"`
+}
+
+// Migrator applies pending migrations in version order
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the registered Migrations
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{db: db, migrations: Migrations}
+}
+
+// migrationsTable creates the schema_migrations table
+const migrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		description TEXT DEFAULT '',
+		applied_at TEXT NOT NULL
+	);
+`
+
+// Applied returns the migrations recorded in schema_migrations. A database
+// without the table has nothing applied; it is not created here, so reading
+// the status never writes.
+func (m *Migrator) Applied() ([]AppliedMigration, error) {
+	var exists int
+	if err := m.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, nil
+	}
+	rows, err := m.db.Query("SELECT version, description, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Description, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+// Pending returns the migrations not applied yet, in version order
+func (m *Migrator) Pending() ([]Migration, error) {
+	sorted := make([]Migration, len(m.migrations))
+	copy(sorted, m.migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, mig := range sorted {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies every pending migration, each in its own transaction,
+// and stops at the first failure. Returns the migrations that were applied.
+func (m *Migrator) Migrate() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+	if _, err := m.db.Exec(migrationsTable); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, mig := range pending {
+		tx, err := m.db.Begin()
+		if err != nil {
+			return applied, err
+		}
+		if err := apply(tx, mig); err != nil {
+			tx.Rollback()
+			return applied, err
+		}
+		if err := tx.Commit(); err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Description, err)
+		}
+		applied = append(applied, mig)
+	}
+	return applied, nil
+}
+
+// DryRun runs every pending migration in one transaction and rolls it back,
+// reporting what Migrate would apply and the first migration that would fail
+func (m *Migrator) DryRun() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Rolled back with the migrations
+	if _, err := tx.Exec(migrationsTable); err != nil {
+		return nil, err
+	}
+
+	var ok []Migration
+	for _, mig := range pending {
+		if err := apply(tx, mig); err != nil {
+			return ok, err
+		}
+		ok = append(ok, mig)
+	}
+	return ok, nil
+}
+
+// apply runs one migration and records it
+func apply(tx *sql.Tx, mig Migration) error {
+	if err := mig.Up(tx); err != nil {
+		return fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Description, err)
+	}
+	_, err := tx.Exec("INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)",
+		mig.Version, mig.Description, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Description, err)
+	}
+	return nil
+}
+
+// execAll runs statements in order
+func execAll(tx *sql.Tx, statements ...string) error {
+	for _, q := range statements {
+		if _, err := tx.Exec(q); err != nil {
+			return fmt.Errorf("%s: %w", q, err)
+		}
+	}
+	return nil
+}
+
+// addColumns adds columns missing from an existing table. Tables that don't
+// exist yet are skipped: their CREATE TABLE already has the columns.
+func addColumns(tx *sql.Tx, table string, columns [][2]string) error {
+	existing, err := columnSet(tx, table)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	for _, col := range columns {
+		if existing[col[0]] {
+			continue
+		}
+		q := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, col[0], col[1])
+		if _, err := tx.Exec(q); err != nil {
+			return fmt.Errorf("%s: %w", q, err)
+		}
+	}
+	return nil
+}
+
+// columnSet returns the columns of a table, empty if it doesn't exist
+func columnSet(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		cols[name] = true
+	}
+	return cols, rows.Err()
+}
@@ -0,0 +1,145 @@
+package schema_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"shelllab/backend/database/schema"
+
+	_ "modernc.org/sqlite"
+)
+
+// openMigrateDB creates a database from legacy statements followed by the
+// current CREATE TABLE statements, the way InitSchema leaves an old database
+// right before migrating it
+func openMigrateDB(t *testing.T, legacy ...string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "shelllab.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	stmts := append(legacy, schema.GeneratedSchema(), schema.CoreSchema(), schema.AtlasLootSchema(), schema.LocaleSchema())
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func hasTable(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func columns(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?)", table).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestStatusDoesNotCreateTable(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(m *schema.Migrator) (int, error)
+		want int
+	}{
+		{"Applied", func(m *schema.Migrator) (int, error) { a, err := m.Applied(); return len(a), err }, 0},
+		{"Pending", func(m *schema.Migrator) (int, error) { p, err := m.Pending(); return len(p), err }, len(schema.Migrations)},
+		{"DryRun", func(m *schema.Migrator) (int, error) { p, err := m.DryRun(); return len(p), err }, len(schema.Migrations)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openMigrateDB(t)
+			n, err := tt.call(schema.NewMigrator(db))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.want {
+				t.Errorf("%d migrations reported, want %d", n, tt.want)
+			}
+			if hasTable(t, db, "schema_migrations") {
+				t.Error("schema_migrations was created")
+			}
+		})
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *sql.DB
+	}{
+		{
+			name: "current schema",
+			setup: func(t *testing.T) *sql.DB {
+				return openMigrateDB(t)
+			},
+		},
+		{
+			name: "database from an older version",
+			setup: func(t *testing.T) *sql.DB {
+				return openMigrateDB(t,
+					`CREATE TABLE creature_metadata (entry INTEGER PRIMARY KEY, map_url TEXT, infobox_json TEXT)`,
+					`CREATE TABLE atlasloot_items (id INTEGER PRIMARY KEY AUTOINCREMENT, table_id INTEGER NOT NULL,
+						item_id INTEGER NOT NULL, drop_chance TEXT, sort_order INTEGER DEFAULT 0)`,
+				)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := tt.setup(t)
+			m := schema.NewMigrator(db)
+			if _, err := m.Migrate(); err != nil {
+				t.Fatal(err)
+			}
+			// Both tables end up with every column of the current CREATE TABLE
+			before := map[string]int{"creature_metadata": 9, "atlasloot_items": 9}
+			for table, n := range before {
+				if got := columns(t, db, table); got != n {
+					t.Errorf("%s has %d columns, want %d", table, got, n)
+				}
+			}
+
+			applied, err := m.Migrate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(applied) != 0 {
+				t.Errorf("second run applied %d migrations", len(applied))
+			}
+
+			// Every Up must also be safe to run again on a migrated database
+			if _, err := db.Exec("DELETE FROM schema_migrations"); err != nil {
+				t.Fatal(err)
+			}
+			applied, err = m.Migrate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(applied) != len(schema.Migrations) {
+				t.Errorf("rerun applied %d migrations, want %d", len(applied), len(schema.Migrations))
+			}
+			for table, n := range before {
+				if got := columns(t, db, table); got != n {
+					t.Errorf("%s has %d columns after rerun, want %d", table, got, n)
+				}
+			}
+			if pending, err := m.Pending(); err != nil || len(pending) != 0 {
+				t.Errorf("pending = %v, %v", pending, err)
+			}
+		})
+	}
+}
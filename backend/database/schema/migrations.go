@@ -0,0 +1,90 @@
+package schema
+
+import "database/sql"
+
+// Migrations upgrade databases created by older versions. CREATE TABLE
+// statements always describe the current schema, so every migration must be
+// a no-op on a fresh database. Append new migrations with the next version.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "item_template spell slots 4 and 5",
+		Up: func(tx *sql.Tx) error {
+			return addColumns(tx, "item_template", [][2]string{
+				{"spellid_4", "INTEGER DEFAULT 0"},
+				{"spelltrigger_4", "INTEGER DEFAULT 0"},
+				{"spellcharges_4", "INTEGER DEFAULT 0"},
+				{"spellppmrate_4", "REAL DEFAULT 0"},
+				{"spellcooldown_4", "INTEGER DEFAULT -1"},
+				{"spellcategory_4", "INTEGER DEFAULT 0"},
+				{"spellcategorycooldown_4", "INTEGER DEFAULT -1"},
+				{"spellid_5", "INTEGER DEFAULT 0"},
+				{"spelltrigger_5", "INTEGER DEFAULT 0"},
+				{"spellcharges_5", "INTEGER DEFAULT 0"},
+				{"spellppmrate_5", "REAL DEFAULT 0"},
+				{"spellcooldown_5", "INTEGER DEFAULT -1"},
+				{"spellcategory_5", "INTEGER DEFAULT 0"},
+				{"spellcategorycooldown_5", "INTEGER DEFAULT -1"},
+			})
+		},
+	},
+	{
+		Version:     2,
+		Description: "atlasloot_items crafting and override columns",
+		Up: func(tx *sql.Tx) error {
+			return addColumns(tx, "atlasloot_items", [][2]string{
+				{"spell_id", "INTEGER DEFAULT 0"},
+				{"override_name", "TEXT"},
+				{"override_icon", "TEXT"},
+				{"quality", "INTEGER DEFAULT 0"},
+			})
+		},
+	},
+	{
+		Version:     3,
+		Description: "indexes for Dropped By and other reverse lookups",
+		Up: func(tx *sql.Tx) error {
+			return execAll(tx,
+				"CREATE INDEX IF NOT EXISTS idx_creature_loot_ref ON creature_loot_template(mincountOrRef)",
+				"CREATE INDEX IF NOT EXISTS idx_reference_loot_item ON reference_loot_template(item)",
+				"CREATE INDEX IF NOT EXISTS idx_gameobject_loot_item ON gameobject_loot_template(item)",
+				"CREATE INDEX IF NOT EXISTS idx_item_loot_item ON item_loot_template(item)",
+				"CREATE INDEX IF NOT EXISTS idx_creature_template_loot_id ON creature_template(loot_id)",
+				"CREATE INDEX IF NOT EXISTS idx_quest_template_reward1 ON quest_template(RewItemId1)",
+				"CREATE INDEX IF NOT EXISTS idx_quest_template_choice1 ON quest_template(RewChoiceItemId1)",
+			)
+		},
+	},
+	{
+		Version:     4,
+		Description: "favorites status column",
+		Up: func(tx *sql.Tx) error {
+			return addColumns(tx, "favorites", [][2]string{
+				{"status", "INTEGER DEFAULT 0"},
+			})
+		},
+	},
+	{
+		Version:     5,
+		Description: "creature_metadata image and location columns",
+		Up: func(tx *sql.Tx) error {
+			return addColumns(tx, "creature_metadata", [][2]string{
+				{"model_image_url", "TEXT"},
+				{"model_image_local", "TEXT"},
+				{"map_image_local", "TEXT"},
+				{"zone_name", "TEXT"},
+				{"x", "REAL"},
+				{"y", "REAL"},
+			})
+		},
+	},
+	{
+		Version:     6,
+		Description: "quest_template PrevQuestId index for quest chains",
+		Up: func(tx *sql.Tx) error {
+			return execAll(tx,
+				"CREATE INDEX IF NOT EXISTS idx_quest_template_prev ON quest_template(PrevQuestId)",
+			)
+		},
+	},
+}
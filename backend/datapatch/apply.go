@@ -0,0 +1,350 @@
+package datapatch
+
+import (
+	"crypto/ed25519"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/repositories"
+)
+
+// PatchableTables are the game data tables a patch may write to. User data
+// and the change log live in user.db; sync_jobs and data_version are never touched.
+var PatchableTables = []string{
+	"item_template",
+	"quest_template",
+	"spell_template",
+	"creature_template",
+	"gameobject_template",
+	"creature_loot_template",
+	"gameobject_loot_template",
+	"item_loot_template",
+	"reference_loot_template",
+	"disenchant_loot_template",
+	"creature_questrelation",
+	"creature_involvedrelation",
+	"gameobject_questrelation",
+	"gameobject_involvedrelation",
+	"creature_spawn",
+	"creature_metadata",
+	"item_display_info",
+	"itemsets",
+	"locks",
+	"factions",
+	"aowow_zones",
+	"quest_categories",
+	"quest_categories_enhanced",
+	"quest_category_groups",
+	"categories",
+	"category_items",
+	"spell_icons",
+	"spell_cast_times",
+	"spell_durations",
+	"spell_radius",
+	"spell_range",
+	"spell_skills",
+	"spell_skill_categories",
+	"spell_skill_spells",
+	"atlasloot_modules",
+	"atlasloot_categories",
+	"atlasloot_tables",
+	"atlasloot_items",
+	"atlasloot_locale",
+}
+
+// IsPatchable reports whether a patch may write to the table
+func IsPatchable(table string) bool {
+	for _, t := range PatchableTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// InitSchema creates the data_version table
+func InitSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS data_version (
+			version INTEGER PRIMARY KEY,
+			description TEXT DEFAULT '',
+			checksum TEXT DEFAULT '',
+			applied_at TEXT NOT NULL
+		);
+	`)
+	return err
+}
+
+// CurrentVersion returns the data version of the database, 0 if no patch was applied
+func CurrentVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM data_version").Scan(&version)
+	return version, err
+}
+
+// Stamp marks the database as being at the given data version without
+// changing any data. Used when building the embedded database for a release.
+func Stamp(db *sql.DB, version int, description string) error {
+	_, err := db.Exec(`INSERT OR REPLACE INTO data_version (version, description, checksum, applied_at) VALUES (?, ?, '', ?)`,
+		version, description, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// ApplyResult describes the outcome of applying one patch
+type ApplyResult struct {
+	File        string `json:"file"`
+	FromVersion int    `json:"fromVersion"`
+	ToVersion   int    `json:"toVersion"`
+	Description string `json:"description"`
+	Upserted    int    `json:"upserted"`
+	Deleted     int    `json:"deleted"`
+	Skipped     bool   `json:"skipped"`
+	Error       string `json:"error,omitempty"` // Set by ApplyDir for files that could not be read or verified
+}
+
+// Updater applies verified patches to the game database
+type Updater struct {
+	db   *sql.DB
+	keys []ed25519.PublicKey
+}
+
+// NewUpdater creates an updater trusting the given public keys
+func NewUpdater(db *sql.DB, keys []ed25519.PublicKey) *Updater {
+	return &Updater{db: db, keys: keys}
+}
+
+// ApplyFile verifies and applies a single patch file
+func (u *Updater) ApplyFile(path string) (*ApplyResult, error) {
+	p, checksum, err := ReadFile(path, u.keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	result, err := u.Apply(p, checksum)
+	if result != nil {
+		result.File = filepath.Base(path)
+	}
+	return result, err
+}
+
+// ApplyDir applies every patch in dir that continues the current data version,
+// in version order. Files that can't be read or verified are skipped and
+// reported with their Error set; patches that are already applied are
+// skipped; the first failing patch stops the chain.
+func (u *Updater) ApplyDir(dir string) ([]*ApplyResult, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		file     string
+		patch    *Patch
+		checksum string
+	}
+	var patches []pending
+	var results []*ApplyResult
+	for _, file := range files {
+		p, checksum, err := ReadFile(file, u.keys)
+		if err != nil {
+			results = append(results, &ApplyResult{File: filepath.Base(file), Error: err.Error()})
+			continue
+		}
+		patches = append(patches, pending{file: file, patch: p, checksum: checksum})
+	}
+	sort.Slice(patches, func(i, j int) bool { return patches[i].patch.FromVersion < patches[j].patch.FromVersion })
+
+	for _, pp := range patches {
+		result, err := u.Apply(pp.patch, pp.checksum)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", filepath.Base(pp.file), err)
+		}
+		result.File = filepath.Base(pp.file)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Apply writes a patch in a single transaction and records the new data version.
+// A patch whose target version is already reached is skipped.
+func (u *Updater) Apply(p *Patch, checksum string) (*ApplyResult, error) {
+	result := &ApplyResult{FromVersion: p.FromVersion, ToVersion: p.ToVersion, Description: p.Description}
+
+	current, err := CurrentVersion(u.db)
+	if err != nil {
+		return nil, err
+	}
+	if current >= p.ToVersion {
+		result.Skipped = true
+		return result, nil
+	}
+	if current != p.FromVersion {
+		return nil, fmt.Errorf("patch %d -> %d needs data version %d, database is at %d",
+			p.FromVersion, p.ToVersion, p.FromVersion, current)
+	}
+
+	tx, err := u.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, t := range p.Tables {
+		if !IsPatchable(t.Table) {
+			return nil, fmt.Errorf("table %s cannot be patched", t.Table)
+		}
+		columns, err := localColumns(tx, t.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted, err := deleteRows(tx, t, columns)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Table, err)
+		}
+		upserted, err := upsertRows(tx, t, columns)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Table, err)
+		}
+		result.Deleted += deleted
+		result.Upserted += upserted
+	}
+
+	if _, err := tx.Exec(`INSERT INTO data_version (version, description, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+		p.ToVersion, p.Description, checksum, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for _, t := range p.Tables {
+		repositories.InvalidateCache(u.db, t.Table)
+	}
+	return result, nil
+}
+
+// deleteRows removes rows by their key columns
+func deleteRows(tx *sql.Tx, t TableChanges, columns map[string]bool) (int, error) {
+	if len(t.Deletes) == 0 {
+		return 0, nil
+	}
+	if len(t.Key) == 0 {
+		return 0, fmt.Errorf("deletes without key columns")
+	}
+
+	where := make([]string, len(t.Key))
+	for i, k := range t.Key {
+		if !columns[k] {
+			return 0, fmt.Errorf("unknown key column %s", k)
+		}
+		where[i] = quoteIdent(k) + " = ?"
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdent(t.Table), strings.Join(where, " AND ")))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	deleted := 0
+	for _, row := range t.Deletes {
+		args := make([]interface{}, len(t.Key))
+		for i, k := range t.Key {
+			v, ok := row[k]
+			if !ok {
+				return deleted, fmt.Errorf("delete is missing key column %s", k)
+			}
+			args[i] = v
+		}
+		res, err := stmt.Exec(args...)
+		if err != nil {
+			return deleted, err
+		}
+		n, _ := res.RowsAffected()
+		deleted += int(n)
+	}
+	return deleted, nil
+}
+
+// upsertRows inserts or replaces full rows. Columns the local schema doesn't
+// have are ignored, so patches built against a newer schema still apply.
+func upsertRows(tx *sql.Tx, t TableChanges, columns map[string]bool) (int, error) {
+	stmts := make(map[string]*sql.Stmt)
+	defer func() {
+		for _, stmt := range stmts {
+			stmt.Close()
+		}
+	}()
+
+	for _, row := range t.Upserts {
+		var cols []string
+		for col := range row {
+			if columns[col] {
+				cols = append(cols, col)
+			}
+		}
+		if len(cols) == 0 {
+			continue
+		}
+		sort.Strings(cols)
+
+		sig := strings.Join(cols, ",")
+		stmt, ok := stmts[sig]
+		if !ok {
+			quoted := make([]string, len(cols))
+			for i, c := range cols {
+				quoted[i] = quoteIdent(c)
+			}
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+			var err error
+			stmt, err = tx.Prepare(fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)",
+				quoteIdent(t.Table), strings.Join(quoted, ", "), placeholders))
+			if err != nil {
+				return 0, err
+			}
+			stmts[sig] = stmt
+		}
+
+		args := make([]interface{}, len(cols))
+		for i, c := range cols {
+			args[i] = row[c]
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return 0, err
+		}
+	}
+	return len(t.Upserts), nil
+}
+
+// localColumns returns the column set of a table in the local database
+func localColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", table)
+	}
+	return columns, nil
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,147 @@
+package datapatch
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/dbdiff"
+)
+
+// Build diffs two databases and returns the patch that turns oldDB into newDB.
+// Tables defaults to every patchable table present in both databases.
+func Build(oldDB, newDB *sql.DB, tables []string, fromVersion, toVersion int, description string) (*Patch, error) {
+	if toVersion <= fromVersion {
+		return nil, fmt.Errorf("target version %d must be greater than %d", toVersion, fromVersion)
+	}
+
+	explicit := len(tables) > 0
+	if !explicit {
+		tables = PatchableTables
+	}
+
+	p := &Patch{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Description: description,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, table := range tables {
+		if !IsPatchable(table) {
+			return nil, fmt.Errorf("table %s cannot be patched", table)
+		}
+		key, err := dbdiff.PrimaryKey(newDB, table)
+		if err == nil {
+			_, err = dbdiff.PrimaryKey(oldDB, table)
+		}
+		if err != nil {
+			if explicit {
+				return nil, err
+			}
+			continue
+		}
+
+		changes, err := buildTable(oldDB, newDB, table, key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table, err)
+		}
+		if len(changes.Upserts) > 0 || len(changes.Deletes) > 0 {
+			p.Tables = append(p.Tables, *changes)
+		}
+	}
+	return p, nil
+}
+
+// buildTable collects the deleted keys and the full new rows of added or changed entries
+func buildTable(oldDB, newDB *sql.DB, table string, key []string) (*TableChanges, error) {
+	changes := &TableChanges{Table: table, Key: key}
+	var changed []map[string]string
+	err := dbdiff.DiffTable(oldDB, newDB, table, key, func(before, after map[string]string) {
+		if after == nil {
+			row := make(map[string]interface{}, len(key))
+			for _, k := range key {
+				row[k] = keyValue(before[k])
+			}
+			changes.Deletes = append(changes.Deletes, row)
+			return
+		}
+		changed = append(changed, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return changes, nil
+	}
+
+	// Re-read the rows with their SQLite types so the patch stores numbers as numbers
+	where := make([]string, len(key))
+	for i, k := range key {
+		where[i] = quoteIdent(k) + " = ?"
+	}
+	stmt, err := newDB.Prepare(fmt.Sprintf("SELECT * FROM %s WHERE %s", quoteIdent(table), strings.Join(where, " AND ")))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for _, after := range changed {
+		args := make([]interface{}, len(key))
+		for i, k := range key {
+			args[i] = after[k]
+		}
+		row, err := typedRow(stmt, args)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			changes.Upserts = append(changes.Upserts, row)
+		}
+	}
+	return changes, nil
+}
+
+// typedRow reads a single row keeping int64/float64/string/[]byte values
+func typedRow(stmt *sql.Stmt, args []interface{}) (map[string]interface{}, error) {
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]interface{}, len(cols))
+	for i, col := range cols {
+		if b, ok := values[i].([]byte); ok {
+			row[col] = string(b)
+		} else {
+			row[col] = values[i]
+		}
+	}
+	return row, nil
+}
+
+// keyValue turns a scanned key back into a number when it is one
+func keyValue(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	return s
+}
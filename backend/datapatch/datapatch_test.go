@@ -0,0 +1,214 @@
+package datapatch_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/datapatch"
+
+	_ "modernc.org/sqlite"
+)
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func encode(t *testing.T, p *datapatch.Patch, key ed25519.PrivateKey) []byte {
+	t.Helper()
+	data, err := datapatch.Encode(p, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func itemPatch(from, to int, upserts []map[string]interface{}, deletes []map[string]interface{}) *datapatch.Patch {
+	return &datapatch.Patch{
+		FromVersion: from,
+		ToVersion:   to,
+		Tables: []datapatch.TableChanges{{
+			Table:   "item_template",
+			Key:     []string{"entry"},
+			Upserts: upserts,
+			Deletes: deletes,
+		}},
+	}
+}
+
+func TestDecode(t *testing.T) {
+	pub, priv := newKey(t)
+	otherPub, otherPriv := newKey(t)
+	valid := encode(t, itemPatch(1, 2, nil, nil), priv)
+
+	tampered := append([]byte(nil), valid...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		data    []byte
+		keys    []ed25519.PublicKey
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "trusted key", data: valid, keys: []ed25519.PublicKey{pub}},
+		{name: "any trusted key", data: valid, keys: []ed25519.PublicKey{otherPub, pub}},
+		{name: "untrusted key", data: valid, keys: []ed25519.PublicKey{otherPub}, wantErr: datapatch.ErrBadSignature},
+		{name: "no keys", data: valid, wantErr: datapatch.ErrBadSignature},
+		{name: "signed by another key", data: encode(t, itemPatch(1, 2, nil, nil), otherPriv), keys: []ed25519.PublicKey{pub}, wantErr: datapatch.ErrBadSignature},
+		{name: "tampered payload", data: tampered, keys: []ed25519.PublicKey{pub}, wantErr: datapatch.ErrBadSignature},
+		{name: "not a patch", data: []byte("hello"), keys: []ed25519.PublicKey{pub}, anyErr: true},
+		{name: "backwards versions", data: encode(t, itemPatch(2, 2, nil, nil), priv), keys: []ed25519.PublicKey{pub}, anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, checksum, err := datapatch.Decode(tt.data, tt.keys)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p.FromVersion != 1 || p.ToVersion != 2 || checksum == "" {
+					t.Errorf("decoded %d -> %d, checksum %q", p.FromVersion, p.ToVersion, checksum)
+				}
+			}
+		})
+	}
+}
+
+func openPatchDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "shelllab.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, stmt := range []string{
+		`CREATE TABLE item_template (entry INTEGER PRIMARY KEY, name TEXT, quality INTEGER DEFAULT 0)`,
+		`INSERT INTO item_template (entry, name) VALUES (1, 'Old Sword'), (2, 'Removed Shield'), (3, 'Untouched Helm')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := datapatch.InitSchema(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := datapatch.Stamp(db, 1, "release"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func items(t *testing.T, db *sql.DB) map[int]string {
+	t.Helper()
+	rows, err := db.Query(`SELECT entry, name FROM item_template`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	result := make(map[int]string)
+	for rows.Next() {
+		var entry int
+		var name string
+		if err := rows.Scan(&entry, &name); err != nil {
+			t.Fatal(err)
+		}
+		result[entry] = name
+	}
+	return result
+}
+
+func TestApplyDir(t *testing.T) {
+	pub, priv := newKey(t)
+	_, otherPriv := newKey(t)
+
+	first := itemPatch(1, 2,
+		[]map[string]interface{}{{"entry": 1, "name": "New Sword", "quality": 3}, {"entry": 4, "name": "Added Ring"}},
+		[]map[string]interface{}{{"entry": 2}})
+	second := itemPatch(2, 3, []map[string]interface{}{{"entry": 4, "name": "Renamed Ring"}}, nil)
+	forged := itemPatch(3, 4, []map[string]interface{}{{"entry": 3, "name": "Forged Helm"}}, nil)
+
+	files := map[string][]byte{
+		"1-2.slpatch":    encode(t, first, priv),
+		"2-3.slpatch":    encode(t, second, priv),
+		"3-4.slpatch":    encode(t, forged, otherPriv),
+		"broken.slpatch": []byte("not a patch"),
+		"ignored.txt":    []byte("not a patch either"),
+	}
+
+	db := openPatchDB(t)
+	dir := t.TempDir()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	updater := datapatch.NewUpdater(db, []ed25519.PublicKey{pub})
+
+	results, err := updater.ApplyDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome := make(map[string]string)
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			outcome[r.File] = "error"
+		case r.Skipped:
+			outcome[r.File] = "skipped"
+		default:
+			outcome[r.File] = "applied"
+		}
+	}
+	want := map[string]string{
+		"1-2.slpatch":    "applied",
+		"2-3.slpatch":    "applied",
+		"3-4.slpatch":    "error",
+		"broken.slpatch": "error",
+	}
+	if !reflect.DeepEqual(outcome, want) {
+		t.Errorf("outcome = %v, want %v", outcome, want)
+	}
+
+	wantItems := map[int]string{1: "New Sword", 3: "Untouched Helm", 4: "Renamed Ring"}
+	if got := items(t, db); !reflect.DeepEqual(got, wantItems) {
+		t.Errorf("items = %v, want %v", got, wantItems)
+	}
+	if v, err := datapatch.CurrentVersion(db); err != nil || v != 3 {
+		t.Errorf("version = %d, %v, want 3", v, err)
+	}
+
+	// A second run finds nothing new to apply
+	results, err = updater.ApplyDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Error == "" && !r.Skipped {
+			t.Errorf("%s applied twice", r.File)
+		}
+	}
+	if got := items(t, db); !reflect.DeepEqual(got, wantItems) {
+		t.Errorf("items after rerun = %v, want %v", got, wantItems)
+	}
+}
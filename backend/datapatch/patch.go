@@ -0,0 +1,175 @@
+// Package datapatch ships game data fixes as signed, compressed row-level
+// patches, so existing databases can be updated without replacing them
+package datapatch
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// FileExt is the extension of patch files picked up from the patches directory
+const FileExt = ".slpatch"
+
+// magic starts every patch file, followed by the ed25519 signature of the gzip payload
+var magic = []byte("SLPATCH1")
+
+// ErrBadSignature is returned when a patch was not signed by a trusted key
+var ErrBadSignature = errors.New("data patch signature is not valid")
+
+// Patch moves game data from one data version to the next
+type Patch struct {
+	FromVersion int            `json:"fromVersion"`
+	ToVersion   int            `json:"toVersion"`
+	Description string         `json:"description"`
+	CreatedAt   string         `json:"createdAt"`
+	Tables      []TableChanges `json:"tables"`
+}
+
+// TableChanges are the rows to write and delete in one table.
+// Upserts are full rows; Deletes only hold the Key columns.
+type TableChanges struct {
+	Table   string                   `json:"table"`
+	Key     []string                 `json:"key"`
+	Upserts []map[string]interface{} `json:"upserts,omitempty"`
+	Deletes []map[string]interface{} `json:"deletes,omitempty"`
+}
+
+// RowCount returns the number of upserted and deleted rows
+func (p *Patch) RowCount() (upserts, deletes int) {
+	for _, t := range p.Tables {
+		upserts += len(t.Upserts)
+		deletes += len(t.Deletes)
+	}
+	return upserts, deletes
+}
+
+// GenerateKey creates a new signing key pair, base64 encoded
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid data patch public key")
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// ParsePrivateKey decodes a base64 ed25519 private key
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(b) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid data patch private key")
+	}
+	return ed25519.PrivateKey(b), nil
+}
+
+// Encode compresses and signs a patch
+func Encode(p *Patch, key ed25519.PrivateKey) ([]byte, error) {
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	if err := json.NewEncoder(gz).Encode(p); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(magic)+ed25519.SignatureSize+payload.Len())
+	out = append(out, magic...)
+	out = append(out, ed25519.Sign(key, payload.Bytes())...)
+	return append(out, payload.Bytes()...), nil
+}
+
+// Decode verifies a patch against the trusted keys and decompresses it.
+// Also returns the SHA-256 checksum of the file for the data_version history.
+func Decode(data []byte, keys []ed25519.PublicKey) (*Patch, string, error) {
+	header := len(magic) + ed25519.SignatureSize
+	if len(data) < header || !bytes.Equal(data[:len(magic)], magic) {
+		return nil, "", fmt.Errorf("not a data patch file")
+	}
+	sig, payload := data[len(magic):header], data[header:]
+
+	trusted := false
+	for _, key := range keys {
+		if ed25519.Verify(key, payload, sig) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return nil, "", ErrBadSignature
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	defer gz.Close()
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var p Patch
+	if err := dec.Decode(&p); err != nil {
+		return nil, "", fmt.Errorf("invalid data patch: %w", err)
+	}
+	if p.ToVersion <= p.FromVersion {
+		return nil, "", fmt.Errorf("invalid data patch: version %d -> %d", p.FromVersion, p.ToVersion)
+	}
+	for i := range p.Tables {
+		normalizeRows(p.Tables[i].Upserts)
+		normalizeRows(p.Tables[i].Deletes)
+	}
+
+	sum := sha256.Sum256(data)
+	return &p, hex.EncodeToString(sum[:]), nil
+}
+
+// ReadFile reads and verifies a patch file
+func ReadFile(path string, keys []ed25519.PublicKey) (*Patch, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return Decode(data, keys)
+}
+
+// normalizeRows turns JSON numbers back into int64/float64 so SQLite stores them with the right type
+func normalizeRows(rows []map[string]interface{}) {
+	for _, row := range rows {
+		for col, v := range row {
+			n, ok := v.(json.Number)
+			if !ok {
+				continue
+			}
+			if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+				row[col] = i
+			} else if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+				row[col] = f
+			} else {
+				row[col] = string(n)
+			}
+		}
+	}
+}
@@ -0,0 +1,600 @@
+// Package dbdiff compares two ShellLab SQLite databases table by table
+package dbdiff
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/repositories"
+
+	_ "modernc.org/sqlite"
+)
+
+// DefaultTables are the tables compared when Options.Tables is empty
+var DefaultTables = []string{
+	"item_template",
+	"quest_template",
+	"spell_template",
+	"creature_template",
+	"creature_loot_template",
+	"reference_loot_template",
+	"gameobject_loot_template",
+	"item_loot_template",
+	"disenchant_loot_template",
+	"atlasloot_categories",
+	"atlasloot_modules",
+	"atlasloot_tables",
+	"atlasloot_items",
+}
+
+// naturalKey replaces a table's autoincrement primary key for diffing. The
+// surrogate id differs between two imports of the same data, so it is
+// neither used as the key nor compared.
+type naturalKey struct {
+	Columns   []string
+	Surrogate string
+}
+
+// naturalKeys lists tables whose primary key is only an insertion counter
+var naturalKeys = map[string]naturalKey{
+	"atlasloot_items": {Columns: []string{"table_id", "sort_order"}, Surrogate: "id"},
+}
+
+// labelColumns are tried in order to give rows a readable name
+var labelColumns = []string{"name", "Title", "display_name", "table_key"}
+
+// Options controls what is compared and how much detail is kept
+type Options struct {
+	Tables  []string // Defaults to DefaultTables
+	MaxRows int      // Rows listed per table and kind (counts are always exact), 0 = unlimited
+}
+
+// ColumnChange is one changed column of a row
+type ColumnChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// RowRef identifies an added or removed row
+type RowRef struct {
+	Key   string `json:"key"`
+	Label string `json:"label,omitempty"`
+}
+
+// RowChange is a row present in both databases with different values
+type RowChange struct {
+	Key     string         `json:"key"`
+	Label   string         `json:"label,omitempty"`
+	Columns []ColumnChange `json:"columns"`
+}
+
+// TableDiff holds the differences for one table
+type TableDiff struct {
+	Table          string      `json:"table"`
+	Key            []string    `json:"key"`
+	AddedCount     int         `json:"addedCount"`
+	RemovedCount   int         `json:"removedCount"`
+	ChangedCount   int         `json:"changedCount"`
+	Added          []RowRef    `json:"added"`
+	Removed        []RowRef    `json:"removed"`
+	Changed        []RowChange `json:"changed"`
+	ColumnsAdded   []string    `json:"columnsAdded,omitempty"`
+	ColumnsRemoved []string    `json:"columnsRemoved,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// HasChanges reports whether the table differs at all
+func (t *TableDiff) HasChanges() bool {
+	return t.AddedCount > 0 || t.RemovedCount > 0 || t.ChangedCount > 0 ||
+		len(t.ColumnsAdded) > 0 || len(t.ColumnsRemoved) > 0 || t.Error != ""
+}
+
+// Result is the full comparison of two databases
+type Result struct {
+	Old         string       `json:"old"`
+	New         string       `json:"new"`
+	GeneratedAt string       `json:"generatedAt"`
+	Tables      []*TableDiff `json:"tables"`
+}
+
+// OpenReadOnly opens a shelllab.db file without allowing writes
+func OpenReadOnly(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// CompareFiles opens two database files read-only and compares them
+func CompareFiles(oldPath, newPath string, opts Options) (*Result, error) {
+	oldDB, err := OpenReadOnly(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	defer oldDB.Close()
+
+	newDB, err := OpenReadOnly(newPath)
+	if err != nil {
+		return nil, err
+	}
+	defer newDB.Close()
+
+	result, err := Compare(oldDB, newDB, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Old, result.New = oldPath, newPath
+	return result, nil
+}
+
+// Compare diffs every table in opts.Tables by primary key.
+// Problems with a single table are reported in its TableDiff.Error.
+func Compare(oldDB, newDB *sql.DB, opts Options) (*Result, error) {
+	tables := opts.Tables
+	if len(tables) == 0 {
+		tables = DefaultTables
+	}
+
+	result := &Result{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Tables:      []*TableDiff{},
+	}
+	for _, table := range tables {
+		result.Tables = append(result.Tables, compareTable(oldDB, newDB, table, opts.MaxRows))
+	}
+	return result, nil
+}
+
+func compareTable(oldDB, newDB *sql.DB, table string, maxRows int) *TableDiff {
+	td := &TableDiff{
+		Table:   table,
+		Added:   []RowRef{},
+		Removed: []RowRef{},
+		Changed: []RowChange{},
+	}
+
+	oldCols, err := tableColumns(oldDB, table)
+	if err != nil {
+		td.Error = fmt.Sprintf("old database: %v", err)
+		return td
+	}
+	newCols, err := tableColumns(newDB, table)
+	if err != nil {
+		td.Error = fmt.Sprintf("new database: %v", err)
+		return td
+	}
+	td.ColumnsAdded = missingFrom(newCols, oldCols)
+	td.ColumnsRemoved = missingFrom(oldCols, newCols)
+
+	key, err := DiffKey(newDB, table)
+	if err != nil {
+		td.Error = err.Error()
+		return td
+	}
+	td.Key = key
+
+	keep := func(n int) bool { return maxRows <= 0 || n < maxRows }
+
+	err = DiffTable(oldDB, newDB, table, key, func(before, after map[string]string) {
+		switch {
+		case before == nil:
+			if keep(td.AddedCount) {
+				td.Added = append(td.Added, RowRef{Key: rowKey(key, after), Label: rowLabel(after)})
+			}
+			td.AddedCount++
+		case after == nil:
+			if keep(td.RemovedCount) {
+				td.Removed = append(td.Removed, RowRef{Key: rowKey(key, before), Label: rowLabel(before)})
+			}
+			td.RemovedCount++
+		default:
+			if keep(td.ChangedCount) {
+				td.Changed = append(td.Changed, RowChange{
+					Key:     rowKey(key, after),
+					Label:   rowLabel(after),
+					Columns: ChangedColumns(before, after),
+				})
+			}
+			td.ChangedCount++
+		}
+	})
+	if err != nil {
+		td.Error = err.Error()
+	}
+	return td
+}
+
+// DiffTable walks both tables ordered by key and calls fn for every added
+// (before == nil), removed (after == nil) or changed row. Only columns present
+// in both databases are compared.
+func DiffTable(oldDB, newDB *sql.DB, table string, key []string, fn func(before, after map[string]string)) error {
+	if len(key) == 0 {
+		return fmt.Errorf("no key columns for %s", table)
+	}
+	quoted := make([]string, len(key))
+	types := make([]string, len(key))
+	for i, k := range key {
+		quoted[i] = quoteIdent(k)
+		types[i] = fmt.Sprintf("typeof(%s) AS %s", quoted[i], quoteIdent(typeColumn(i)))
+	}
+	// The key types come along so the merge below can order rows exactly as SQLite does
+	query := fmt.Sprintf("SELECT *, %s FROM %s ORDER BY %s",
+		strings.Join(types, ", "), quoteIdent(table), strings.Join(quoted, ", "))
+
+	surrogate := ""
+	if nk, ok := naturalKeys[table]; ok && !containsString(key, nk.Surrogate) {
+		surrogate = nk.Surrogate
+	}
+
+	oldRows, err := oldDB.Query(query)
+	if err != nil {
+		return fmt.Errorf("old database: %w", err)
+	}
+	defer oldRows.Close()
+	newRows, err := newDB.Query(query)
+	if err != nil {
+		return fmt.Errorf("new database: %w", err)
+	}
+	defer newRows.Close()
+
+	next := func(rows *sql.Rows) (*keyedRow, error) {
+		if !rows.Next() {
+			return nil, rows.Err()
+		}
+		values, err := repositories.ScanRowMap(rows)
+		if err != nil {
+			return nil, err
+		}
+		row := &keyedRow{values: values, types: make([]string, len(key))}
+		for i := range key {
+			row.types[i] = values[typeColumn(i)]
+			delete(values, typeColumn(i))
+		}
+		if surrogate != "" {
+			delete(values, surrogate)
+		}
+		return row, nil
+	}
+
+	oldRow, err := next(oldRows)
+	if err != nil {
+		return err
+	}
+	newRow, err := next(newRows)
+	if err != nil {
+		return err
+	}
+
+	for oldRow != nil || newRow != nil {
+		cmp := 0
+		switch {
+		case oldRow == nil:
+			cmp = 1
+		case newRow == nil:
+			cmp = -1
+		default:
+			cmp = compareKeys(key, oldRow, newRow)
+		}
+
+		switch {
+		case cmp < 0:
+			fn(oldRow.values, nil)
+			oldRow, err = next(oldRows)
+		case cmp > 0:
+			fn(nil, newRow.values)
+			newRow, err = next(newRows)
+		default:
+			if len(ChangedColumns(oldRow.values, newRow.values)) > 0 {
+				fn(oldRow.values, newRow.values)
+			}
+			if oldRow, err = next(oldRows); err == nil {
+				newRow, err = next(newRows)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChangedColumns lists the columns present in both rows whose values differ
+func ChangedColumns(before, after map[string]string) []ColumnChange {
+	var changes []ColumnChange
+	for col, newValue := range after {
+		oldValue, ok := before[col]
+		if ok && oldValue != newValue {
+			changes = append(changes, ColumnChange{Column: col, Old: oldValue, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Column < changes[j].Column })
+	return changes
+}
+
+// PrimaryKey returns the primary key columns of a table, or every column
+// for tables declared without one
+func PrimaryKey(db *sql.DB, table string) ([]string, error) {
+	_, key, err := tableInfo(db, table)
+	return key, err
+}
+
+// DiffKey returns the columns that identify the same row across two databases:
+// the natural key for tables keyed by an autoincrement id, otherwise the primary key
+func DiffKey(db *sql.DB, table string) ([]string, error) {
+	key, err := PrimaryKey(db, table)
+	if err != nil {
+		return nil, err
+	}
+	if nk, ok := naturalKeys[table]; ok {
+		return nk.Columns, nil
+	}
+	return key, nil
+}
+
+func tableColumns(db *sql.DB, table string) ([]string, error) {
+	cols, _, err := tableInfo(db, table)
+	return cols, err
+}
+
+// tableInfo reads a table's columns and primary key columns in key order
+func tableInfo(db *sql.DB, table string) ([]string, []string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(table)))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var cols []string
+	pkPos := make(map[string]int)
+	for rows.Next() {
+		var cid, notNull, pos int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pos); err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, name)
+		if pos > 0 {
+			pkPos[name] = pos
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(cols) == 0 {
+		return nil, nil, fmt.Errorf("table %s does not exist", table)
+	}
+	if len(pkPos) == 0 {
+		return cols, cols, nil
+	}
+
+	var key []string
+	for col := range pkPos {
+		key = append(key, col)
+	}
+	sort.Slice(key, func(i, j int) bool { return pkPos[key[i]] < pkPos[key[j]] })
+	return cols, key, nil
+}
+
+// missingFrom returns the entries of a that are not in b
+func missingFrom(a, b []string) []string {
+	seen := make(map[string]bool, len(b))
+	for _, s := range b {
+		seen[s] = true
+	}
+	var missing []string
+	for _, s := range a {
+		if !seen[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// keyedRow is a scanned row plus the SQLite storage class of each key column
+type keyedRow struct {
+	values map[string]string
+	types  []string
+}
+
+// typeColumn names the extra column carrying typeof() of the i-th key column
+func typeColumn(i int) string {
+	return fmt.Sprintf("__dbdiff_type_%d", i)
+}
+
+// typeRank orders SQLite storage classes: NULL, then numbers, then text, then blobs
+func typeRank(t string) int {
+	switch t {
+	case "null":
+		return 0
+	case "integer", "real":
+		return 1
+	case "text":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// compareKeys orders rows the way SQLite's ORDER BY orders the key columns:
+// by storage class first, numbers numerically, text and blobs byte-wise
+func compareKeys(key []string, a, b *keyedRow) int {
+	for i, k := range key {
+		ar, br := typeRank(a.types[i]), typeRank(b.types[i])
+		if ar != br {
+			if ar < br {
+				return -1
+			}
+			return 1
+		}
+		av, bv := a.values[k], b.values[k]
+		switch ar {
+		case 0:
+			continue
+		case 1:
+			if c := compareNumbers(a.types[i], av, b.types[i], bv); c != 0 {
+				return c
+			}
+		default:
+			if c := strings.Compare(av, bv); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// compareNumbers compares two numeric key values, exactly for integers
+func compareNumbers(at, av, bt, bv string) int {
+	if at == "integer" && bt == "integer" {
+		ai, aErr := strconv.ParseInt(av, 10, 64)
+		bi, bErr := strconv.ParseInt(bv, 10, 64)
+		if aErr == nil && bErr == nil {
+			switch {
+			case ai < bi:
+				return -1
+			case ai > bi:
+				return 1
+			}
+			return 0
+		}
+	}
+	af, _ := strconv.ParseFloat(av, 64)
+	bf, _ := strconv.ParseFloat(bv, 64)
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func rowKey(key []string, row map[string]string) string {
+	parts := make([]string, len(key))
+	for i, k := range key {
+		parts[i] = k + "=" + row[k]
+	}
+	return strings.Join(parts, ",")
+}
+
+func rowLabel(row map[string]string) string {
+	for _, col := range labelColumns {
+		if v := row[col]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// JSON renders the result as indented JSON
+func (r *Result) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown renders the result as a release-notes friendly report
+func (r *Result) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Database diff\n\n")
+	if r.Old != "" || r.New != "" {
+		fmt.Fprintf(&sb, "`%s` → `%s`\n\n", r.Old, r.New)
+	}
+
+	sb.WriteString("| Table | Added | Removed | Changed |\n")
+	sb.WriteString("|---|---:|---:|---:|\n")
+	for _, t := range r.Tables {
+		if t.Error != "" {
+			fmt.Fprintf(&sb, "| %s | - | - | error: %s |\n", t.Table, t.Error)
+			continue
+		}
+		fmt.Fprintf(&sb, "| %s | %d | %d | %d |\n", t.Table, t.AddedCount, t.RemovedCount, t.ChangedCount)
+	}
+	sb.WriteString("\n")
+
+	for _, t := range r.Tables {
+		if t.Error != "" || !t.HasChanges() {
+			continue
+		}
+		fmt.Fprintf(&sb, "## %s\n\n", t.Table)
+		if len(t.ColumnsAdded) > 0 {
+			fmt.Fprintf(&sb, "Columns added: %s\n\n", strings.Join(t.ColumnsAdded, ", "))
+		}
+		if len(t.ColumnsRemoved) > 0 {
+			fmt.Fprintf(&sb, "Columns removed: %s\n\n", strings.Join(t.ColumnsRemoved, ", "))
+		}
+		writeRefs(&sb, "Added", t.Added, t.AddedCount)
+		writeRefs(&sb, "Removed", t.Removed, t.RemovedCount)
+
+		if t.ChangedCount > 0 {
+			fmt.Fprintf(&sb, "### Changed (%d)\n\n", t.ChangedCount)
+			for _, c := range t.Changed {
+				fmt.Fprintf(&sb, "- **%s**%s\n", c.Key, labelSuffix(c.Label))
+				for _, col := range c.Columns {
+					fmt.Fprintf(&sb, "  - `%s`: %s → %s\n", col.Column, mdValue(col.Old), mdValue(col.New))
+				}
+			}
+			if more := t.ChangedCount - len(t.Changed); more > 0 {
+				fmt.Fprintf(&sb, "- _…and %d more_\n", more)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeRefs(sb *strings.Builder, title string, refs []RowRef, count int) {
+	if count == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "### %s (%d)\n\n", title, count)
+	for _, ref := range refs {
+		fmt.Fprintf(sb, "- %s%s\n", ref.Key, labelSuffix(ref.Label))
+	}
+	if more := count - len(refs); more > 0 {
+		fmt.Fprintf(sb, "- _…and %d more_\n", more)
+	}
+	sb.WriteString("\n")
+}
+
+func labelSuffix(label string) string {
+	if label == "" {
+		return ""
+	}
+	return " " + label
+}
+
+// mdValue keeps long or multi-line values on one readable line
+func mdValue(v string) string {
+	if v == "" {
+		return "_(empty)_"
+	}
+	v = strings.Join(strings.Fields(v), " ")
+	if len(v) > 80 {
+		v = v[:80] + "…"
+	}
+	return "`" + strings.ReplaceAll(v, "`", "'") + "`"
+}
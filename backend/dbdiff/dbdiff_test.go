@@ -0,0 +1,143 @@
+package dbdiff_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/dbdiff"
+)
+
+func openTestDB(t *testing.T, name string, stmts ...string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return db
+}
+
+func refKeys(refs []dbdiff.RowRef) []string {
+	keys := []string{}
+	for _, r := range refs {
+		keys = append(keys, r.Key)
+	}
+	return keys
+}
+
+func changeKeys(changes []dbdiff.RowChange) []string {
+	keys := []string{}
+	for _, c := range changes {
+		keys = append(keys, c.Key)
+	}
+	return keys
+}
+
+func TestCompareMergeJoin(t *testing.T) {
+	// The key column has no affinity, so NULL, numbers and text sort by storage class
+	const mixedSchema = `CREATE TABLE kv (k PRIMARY KEY, v TEXT)`
+	const atlasSchema = `CREATE TABLE atlasloot_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		table_id INTEGER NOT NULL,
+		item_id INTEGER NOT NULL,
+		sort_order INTEGER DEFAULT 0
+	)`
+
+	tests := []struct {
+		name    string
+		table   string
+		old     []string
+		new     []string
+		key     []string
+		added   []string
+		removed []string
+		changed []string
+	}{
+		{
+			name:  "identical",
+			table: "kv",
+			old:   []string{mixedSchema, `INSERT INTO kv VALUES (1, 'a'), ('1', 'b')`},
+			new:   []string{mixedSchema, `INSERT INTO kv VALUES ('1', 'b'), (1, 'a')`},
+			key:   []string{"k"},
+		},
+		{
+			name:  "mixed storage classes",
+			table: "kv",
+			old: []string{mixedSchema, `INSERT INTO kv VALUES
+				(NULL, 'null'), (2, 'two'), (10, 'ten'), ('10a', 'text'), ('9', 'nine'), ('abc', 'abc')`},
+			new: []string{mixedSchema, `INSERT INTO kv VALUES
+				(NULL, 'null'), (2, 'two'), ('10a', 'text'), ('9', 'NINE'), ('abc', 'abc'), ('b', 'new')`},
+			key:     []string{"k"},
+			added:   []string{"k=b"},
+			removed: []string{"k=10"},
+			changed: []string{"k=9"},
+		},
+		{
+			name:    "large integers compare exactly",
+			table:   "kv",
+			old:     []string{mixedSchema, `INSERT INTO kv VALUES (9007199254740992, 'a'), (9007199254740993, 'b')`},
+			new:     []string{mixedSchema, `INSERT INTO kv VALUES (9007199254740993, 'b')`},
+			key:     []string{"k"},
+			removed: []string{"k=9007199254740992"},
+		},
+		{
+			name:  "atlasloot items ignore autoincrement ids",
+			table: "atlasloot_items",
+			old: []string{atlasSchema, `INSERT INTO atlasloot_items (id, table_id, item_id, sort_order) VALUES
+				(1, 1, 100, 0), (2, 1, 101, 1), (3, 2, 200, 0)`},
+			new: []string{atlasSchema, `INSERT INTO atlasloot_items (id, table_id, item_id, sort_order) VALUES
+				(7, 2, 200, 0), (8, 1, 100, 0), (9, 1, 102, 1), (10, 2, 201, 1)`},
+			key:     []string{"table_id", "sort_order"},
+			added:   []string{"table_id=2,sort_order=1"},
+			changed: []string{"table_id=1,sort_order=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDB := openTestDB(t, "old.db", tt.old...)
+			newDB := openTestDB(t, "new.db", tt.new...)
+
+			result, err := dbdiff.Compare(oldDB, newDB, dbdiff.Options{Tables: []string{tt.table}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			td := result.Tables[0]
+			if td.Error != "" {
+				t.Fatal(td.Error)
+			}
+			if !reflect.DeepEqual(td.Key, tt.key) {
+				t.Errorf("key = %v, want %v", td.Key, tt.key)
+			}
+			want := func(s []string) []string {
+				if s == nil {
+					return []string{}
+				}
+				return s
+			}
+			if got := refKeys(td.Added); !reflect.DeepEqual(got, want(tt.added)) {
+				t.Errorf("added = %v, want %v", got, tt.added)
+			}
+			if got := refKeys(td.Removed); !reflect.DeepEqual(got, want(tt.removed)) {
+				t.Errorf("removed = %v, want %v", got, tt.removed)
+			}
+			if got := changeKeys(td.Changed); !reflect.DeepEqual(got, want(tt.changed)) {
+				t.Errorf("changed = %v, want %v", got, tt.changed)
+			}
+			for _, c := range td.Changed {
+				for _, col := range c.Columns {
+					if col.Column == "id" {
+						t.Errorf("%s: surrogate id reported as changed", c.Key)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package media
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// AtlasDir is the directory under the icon directory that holds the sprite
+// atlases, so they ship and extract with the icons
+const AtlasDir = "atlas"
+
+// AtlasManifestFile lists the atlas sheets and the cell of every icon
+const AtlasManifestFile = "manifest.json"
+
+// AtlasSizes are the icon sizes atlases are built at: 18px for dense lists,
+// 36px for regular rows (and 18px on high-DPI screens)
+var AtlasSizes = []int{18, 36}
+
+// atlasColumns is the number of icons per sheet row; a sheet holds at most
+// atlasColumns² icons
+const atlasColumns = 64
+
+// atlasQuality is the JPEG quality of the sheets. Icons are opaque artwork,
+// where JPEG is a quarter of the size of PNG.
+const atlasQuality = 90
+
+// AtlasManifest describes a set of atlases. Icons occupy the same cell at
+// every size.
+type AtlasManifest struct {
+	GeneratedAt string         `json:"generatedAt"`
+	Version     int64          `json:"version"` // Unix time of the build, for cache busting
+	Columns     int            `json:"columns"`
+	PerSheet    int            `json:"perSheet"`
+	Sizes       []int          `json:"sizes"`
+	Count       int            `json:"count"`
+	Icons       map[string]int `json:"icons"` // icon name → cell
+}
+
+// Sprite locates an icon in an atlas sheet. Width and Height are the sheet
+// dimensions, for CSS background-size.
+type Sprite struct {
+	Sheet  string `json:"sheet"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Size   int    `json:"size"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// AtlasSheetFile returns the file name of a sheet
+func AtlasSheetFile(size, sheet int) string {
+	return fmt.Sprintf("icons_%d_%d.jpg", size, sheet)
+}
+
+// Sheets returns the number of sheets per size
+func (m *AtlasManifest) Sheets() int {
+	return (m.Count + m.PerSheet - 1) / m.PerSheet
+}
+
+// sheetBounds returns the pixel size of a sheet; the last one is only as
+// tall as its rows
+func (m *AtlasManifest) sheetBounds(sheet, size int) (int, int) {
+	cells := min(m.PerSheet, m.Count-sheet*m.PerSheet)
+	cols := min(cells, m.Columns)
+	rows := (cells + m.Columns - 1) / m.Columns
+	return cols * size, rows * size
+}
+
+// Sprite returns where an icon is drawn at size, or nil when the icon or the
+// size is not in the atlases. The sheet is relative to the atlas directory.
+func (m *AtlasManifest) Sprite(name string, size int) *Sprite {
+	cell, ok := m.Icons[IconName(name)]
+	if !ok || !containsInt(m.Sizes, size) {
+		return nil
+	}
+	sheet := cell / m.PerSheet
+	pos := cell % m.PerSheet
+	w, h := m.sheetBounds(sheet, size)
+	return &Sprite{
+		Sheet:  AtlasSheetFile(size, sheet),
+		X:      pos % m.Columns * size,
+		Y:      pos / m.Columns * size,
+		Size:   size,
+		Width:  w,
+		Height: h,
+	}
+}
+
+// BuildAtlases packs every icon in iconDir into sheets of the given sizes
+// under iconDir/atlas. Icons that fail to decode are skipped.
+func BuildAtlases(iconDir string, sizes []int) (*AtlasManifest, error) {
+	names, files, err := atlasSources(iconDir)
+	if err != nil {
+		return nil, err
+	}
+
+	icons := make([]image.Image, 0, len(names))
+	manifest := &AtlasManifest{
+		Columns:  atlasColumns,
+		PerSheet: atlasColumns * atlasColumns,
+		Sizes:    sizes,
+		Icons:    make(map[string]int, len(names)),
+	}
+	for i, name := range names {
+		img, err := decodeFile(files[i])
+		if err != nil {
+			fmt.Printf("[Atlas] ⚠ Skipping %s: %v\n", filepath.Base(files[i]), err)
+			continue
+		}
+		manifest.Icons[name] = len(icons)
+		icons = append(icons, img)
+	}
+	manifest.Count = len(icons)
+	if manifest.Count == 0 {
+		return nil, fmt.Errorf("no icons in %s", iconDir)
+	}
+
+	outDir := filepath.Join(iconDir, AtlasDir)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+
+	for _, size := range sizes {
+		for sheet := 0; sheet < manifest.Sheets(); sheet++ {
+			w, h := manifest.sheetBounds(sheet, size)
+			dst := image.NewRGBA(image.Rect(0, 0, w, h))
+			// Transparent icons end up on the UI's dark background
+			draw.Draw(dst, dst.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+			first := sheet * manifest.PerSheet
+			last := min(first+manifest.PerSheet, manifest.Count)
+			for cell := first; cell < last; cell++ {
+				pos := cell - first
+				x, y := pos%manifest.Columns*size, pos/manifest.Columns*size
+				src := icons[cell]
+				draw.CatmullRom.Scale(dst, image.Rect(x, y, x+size, y+size), src, src.Bounds(), draw.Over, nil)
+			}
+
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: atlasQuality}); err != nil {
+				return nil, err
+			}
+			if err := writeAtomic(filepath.Join(outDir, AtlasSheetFile(size, sheet)), buf.Bytes()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The manifest goes last so readers never see it point at missing sheets
+	now := time.Now().UTC()
+	manifest.GeneratedAt = now.Format(time.RFC3339)
+	manifest.Version = now.Unix()
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeAtomic(filepath.Join(outDir, AtlasManifestFile), data); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// atlasSources returns the sorted icon names in iconDir and their files. An
+// icon saved under several extensions uses the one Cache.Path would serve.
+func atlasSources(iconDir string) ([]string, []string, error) {
+	entries, err := os.ReadDir(iconDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rank := func(file string) int {
+		ext := strings.ToLower(filepath.Ext(file))
+		for i, e := range Extensions {
+			if e == ext {
+				return i
+			}
+		}
+		return len(Extensions)
+	}
+
+	byName := make(map[string]string)
+	for _, e := range entries {
+		file := e.Name()
+		if e.IsDir() || strings.HasPrefix(file, ".") || !isImageFile(file) {
+			continue
+		}
+		name := IconName(file)
+		if current, ok := byName[name]; !ok || rank(file) < rank(current) {
+			byName[name] = file
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(iconDir, byName[name])
+	}
+	return names, files, nil
+}
+
+func decodeFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// ValidAtlasSize reports whether atlases are built at size
+func ValidAtlasSize(size int) bool {
+	return containsInt(AtlasSizes, size)
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Atlas reads the manifest of iconDir/atlas, reloading it when a rebuild
+// replaces it
+type Atlas struct {
+	dir      string
+	mu       sync.Mutex
+	manifest *AtlasManifest
+	modTime  time.Time
+}
+
+// NewAtlas creates a reader for the atlases of iconDir
+func NewAtlas(iconDir string) *Atlas {
+	return &Atlas{dir: filepath.Join(iconDir, AtlasDir)}
+}
+
+// Dir returns the atlas directory
+func (a *Atlas) Dir() string {
+	return a.dir
+}
+
+// Manifest returns the current manifest, or nil when no atlases were built
+func (a *Atlas) Manifest() *AtlasManifest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	path := filepath.Join(a.dir, AtlasManifestFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		a.manifest = nil
+		return nil
+	}
+	if a.manifest != nil && info.ModTime().Equal(a.modTime) {
+		return a.manifest
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var m AtlasManifest
+	if err := json.Unmarshal(data, &m); err != nil || m.PerSheet <= 0 || m.Columns <= 0 {
+		fmt.Printf("[Atlas] ⚠ Ignoring invalid %s: %v\n", path, err)
+		return nil
+	}
+	a.manifest, a.modTime = &m, info.ModTime()
+	return a.manifest
+}
+
+// Sprites returns the sprites of the given icon names at size, keyed by the
+// names as passed. Names not in the atlases are left out, so callers fall
+// back to the single icon. Sheets are prefixed with urlPrefix and carry the
+// build version so a rebuild is not masked by cached sheets.
+func (a *Atlas) Sprites(names []string, size int, urlPrefix string) map[string]*Sprite {
+	sprites := make(map[string]*Sprite)
+	m := a.Manifest()
+	if m == nil {
+		return sprites
+	}
+	for _, name := range names {
+		if _, done := sprites[name]; done {
+			continue
+		}
+		if s := m.Sprite(name, size); s != nil {
+			s.Sheet = fmt.Sprintf("%s%s?v=%d", urlPrefix, s.Sheet, m.Version)
+			sprites[name] = s
+		}
+	}
+	return sprites
+}
@@ -0,0 +1,132 @@
+package media_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shelllab/backend/media"
+)
+
+// atlasIcons are solid fixture icons, far enough apart to tell through JPEG
+var atlasIcons = map[string]color.RGBA{
+	"inv_axe":    {255, 0, 0, 255},
+	"inv_bow":    {0, 255, 0, 255},
+	"inv_chest":  {0, 0, 255, 255},
+	"inv_dagger": {255, 255, 255, 255},
+	"inv_gem":    {255, 255, 0, 255},
+}
+
+// writeIcon saves a 64px icon of c as file in dir
+func writeIcon(t *testing.T, dir, file string, c color.RGBA) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	var err error
+	if strings.HasSuffix(file, ".jpg") {
+		err = jpeg.Encode(&buf, img, nil)
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// near reports whether two colours are within JPEG error of each other
+func near(a color.Color, b color.RGBA) bool {
+	r, g, bl, _ := a.RGBA()
+	diff := func(x uint32, y uint8) bool {
+		d := int(x>>8) - int(y)
+		return d > -40 && d < 40
+	}
+	return diff(r, b.R) && diff(g, b.G) && diff(bl, b.B)
+}
+
+func TestBuildAtlases(t *testing.T) {
+	dir := t.TempDir()
+	for name, c := range atlasIcons {
+		writeIcon(t, dir, name+".png", c)
+	}
+	// Cache.Path serves the PNG, so the atlas has to use it too
+	writeIcon(t, dir, "inv_axe.jpg", color.RGBA{0, 0, 0, 255})
+	if err := os.WriteFile(filepath.Join(dir, "broken.png"), []byte("not an image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := media.BuildAtlases(dir, media.AtlasSizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Count != len(atlasIcons) || len(manifest.Icons) != len(atlasIcons) {
+		t.Fatalf("packed %d icons (%v), want %d", manifest.Count, manifest.Icons, len(atlasIcons))
+	}
+	if _, ok := manifest.Icons["broken"]; ok {
+		t.Error("packed an icon that does not decode")
+	}
+
+	for _, size := range media.AtlasSizes {
+		sheets := map[string]image.Image{}
+		for name, want := range atlasIcons {
+			s := manifest.Sprite(name+".PNG", size)
+			if s == nil {
+				t.Fatalf("%s at %dpx: no sprite", name, size)
+			}
+			sheet, ok := sheets[s.Sheet]
+			if !ok {
+				f, err := os.Open(filepath.Join(dir, media.AtlasDir, s.Sheet))
+				if err != nil {
+					t.Fatal(err)
+				}
+				sheet, err = jpeg.Decode(f)
+				f.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				sheets[s.Sheet] = sheet
+			}
+
+			if b := sheet.Bounds(); b.Dx() != s.Width || b.Dy() != s.Height {
+				t.Errorf("%s: sheet is %dx%d, manifest says %dx%d", s.Sheet, b.Dx(), b.Dy(), s.Width, s.Height)
+			}
+			if s.Size != size || s.X%size != 0 || s.Y%size != 0 || s.X+size > s.Width || s.Y+size > s.Height {
+				t.Errorf("%s at %dpx: cell %+v is off the grid", name, size, s)
+				continue
+			}
+			// Every pixel of the cell but the resampled edges is the icon
+			for y := s.Y + 2; y < s.Y+size-2; y++ {
+				for x := s.X + 2; x < s.X+size-2; x++ {
+					if got := sheet.At(x, y); !near(got, want) {
+						t.Fatalf("%s at %dpx: pixel (%d,%d) is %v, want %v", name, size, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+
+	if s := manifest.Sprite("inv_axe", 24); s != nil {
+		t.Errorf("sprite at an unbuilt size: %+v", s)
+	}
+	if s := manifest.Sprite("broken", 36); s != nil {
+		t.Errorf("sprite for a skipped icon: %+v", s)
+	}
+
+	sprites := media.NewAtlas(dir).Sprites([]string{"INV_Gem", "inv_missing"}, 18, "/icons/atlas/")
+	if len(sprites) != 1 || sprites["INV_Gem"] == nil {
+		t.Fatalf("sprites = %v, want only INV_Gem", sprites)
+	}
+	if got, want := sprites["INV_Gem"].Sheet, "/icons/atlas/"+media.AtlasSheetFile(18, 0)+"?v="; !strings.HasPrefix(got, want) {
+		t.Errorf("sheet URL %q, want prefix %q", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package media
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// iconReferences select every icon name the game data uses
+var iconReferences = []string{
+	`SELECT icon FROM item_display_info`,
+	`SELECT iconName FROM spell_template`,
+	`SELECT icon_name FROM spell_icons`,
+	`SELECT override_icon FROM atlasloot_items`,
+}
+
+// imagePaths and imageURLs select the local paths and source URLs of NPC images
+var (
+	imagePaths = []string{
+		`SELECT model_image_local FROM creature_metadata`,
+		`SELECT map_image_local FROM creature_metadata`,
+	}
+	imageURLs = []string{
+		`SELECT model_image_url FROM creature_metadata`,
+		`SELECT map_url FROM creature_metadata`,
+	}
+)
+
+// GCResult reports what GC removed (or would remove on a dry run)
+type GCResult struct {
+	Kind      Kind     `json:"kind"`
+	Scanned   int      `json:"scanned"`
+	Removed   []string `json:"removed"`
+	Bytes     int64    `json:"bytes"`
+	StaleRows int      `json:"staleRows"`
+	DryRun    bool     `json:"dryRun"`
+}
+
+// GC removes files no database row references, along with index rows whose
+// file is gone and leftovers of interrupted writes.
+//
+// Icons are only collected when the cache downloaded them itself: icons that
+// shipped with the app are left alone, since the UI also uses icons by name.
+// Every file in an image directory is collected once no NPC refers to it.
+func (c *Cache) GC(dryRun bool) (*GCResult, error) {
+	result := &GCResult{Kind: c.kind, Removed: []string{}, DryRun: dryRun}
+
+	indexed, err := c.indexedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var keep map[string]bool
+	if c.kind == KindIcon {
+		keep, err = c.referencedIcons(indexed)
+	} else {
+		keep, err = c.referencedImages(indexed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	onDisk := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		name := f.Name()
+		onDisk[name] = true
+
+		leftover := strings.HasPrefix(name, tempPrefix)
+		if !leftover && !isImageFile(name) {
+			continue
+		}
+		result.Scanned++
+		if !leftover && keep[name] {
+			continue
+		}
+		if c.kind == KindIcon && !leftover && len(indexed[name]) == 0 {
+			continue
+		}
+
+		if info, err := f.Info(); err == nil {
+			result.Bytes += info.Size()
+		}
+		result.Removed = append(result.Removed, name)
+		delete(onDisk, name)
+		if dryRun {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+
+	// Index rows of removed or missing files
+	for file, keys := range indexed {
+		if onDisk[file] {
+			continue
+		}
+		result.StaleRows += len(keys)
+		if dryRun {
+			continue
+		}
+		for _, key := range keys {
+			if _, err := c.db.Exec(`DELETE FROM media_files WHERE kind = ? AND key = ?`, c.kind, key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// indexedFiles maps the indexed file names of this cache to their keys. An
+// image downloaded from several URLs has several keys.
+func (c *Cache) indexedFiles() (map[string][]string, error) {
+	rows, err := c.db.Query(`SELECT key, file FROM media_files WHERE kind = ?`, c.kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media index: %w", err)
+	}
+	defer rows.Close()
+
+	files := make(map[string][]string)
+	for rows.Next() {
+		var key, file string
+		if err := rows.Scan(&key, &file); err != nil {
+			return nil, err
+		}
+		files[file] = append(files[file], key)
+	}
+	return files, rows.Err()
+}
+
+// referencedIcons returns the file names of indexed icons the game data uses
+func (c *Cache) referencedIcons(indexed map[string][]string) (map[string]bool, error) {
+	names, err := queryStrings(c.db, iconReferences)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(names))
+	for _, n := range names {
+		used[IconName(n)] = true
+	}
+
+	return usedFiles(indexed, used, make(map[string]bool)), nil
+}
+
+// referencedImages returns the file names NPC metadata points at, directly by
+// local path or through the source URL of an indexed image
+func (c *Cache) referencedImages(indexed map[string][]string) (map[string]bool, error) {
+	paths, err := queryStrings(c.db, imagePaths)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := queryStrings(c.db, imageURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool)
+	for _, p := range paths {
+		// Local paths were stored as full paths; only the file name matters
+		keep[filepath.Base(strings.ReplaceAll(p, `\`, "/"))] = true
+	}
+	used := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		used[u] = true
+	}
+	return usedFiles(indexed, used, keep), nil
+}
+
+// usedFiles adds the indexed files with a used key to keep
+func usedFiles(indexed map[string][]string, used, keep map[string]bool) map[string]bool {
+	for file, keys := range indexed {
+		for _, key := range keys {
+			if used[key] {
+				keep[file] = true
+				break
+			}
+		}
+	}
+	return keep
+}
+
+// queryStrings collects the non-empty values of single-column queries
+func queryStrings(db *sql.DB, queries []string) ([]string, error) {
+	var values []string
+	for _, q := range queries {
+		rows, err := db.Query(q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read references (%s): %w", q, err)
+		}
+		for rows.Next() {
+			var v sql.NullString
+			if err := rows.Scan(&v); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if v.String != "" {
+				values = append(values, v.String)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func isImageFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package media_test
+
+import (
+	"bytes"
+	"database/sql"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"shelllab/backend/media"
+
+	_ "modernc.org/sqlite"
+)
+
+// openMediaDB opens an in-memory database with media_files and the tables GC reads references from
+func openMediaDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := media.InitSchema(db); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE item_display_info (id INTEGER PRIMARY KEY, icon TEXT)`,
+		`CREATE TABLE spell_template (entry INTEGER PRIMARY KEY, iconName TEXT)`,
+		`CREATE TABLE spell_icons (id INTEGER PRIMARY KEY, icon_name TEXT)`,
+		`CREATE TABLE atlasloot_items (id INTEGER PRIMARY KEY, override_icon TEXT)`,
+		`CREATE TABLE creature_metadata (entry INTEGER PRIMARY KEY, model_image_url TEXT, model_image_local TEXT, map_url TEXT, map_image_local TEXT)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// pngOf encodes a w x h image, so different sizes give different content
+func pngOf(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func put(t *testing.T, c *media.Cache, key string, data []byte, sourceURL string) *media.Entry {
+	t.Helper()
+	entry, err := c.Put(key, data, sourceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dirFiles lists the file names in dir
+func dirFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// indexedKeys lists the media_files keys of kind
+func indexedKeys(t *testing.T, db *sql.DB, kind media.Kind) []string {
+	t.Helper()
+	rows, err := db.Query(`SELECT key FROM media_files WHERE kind = ? ORDER BY key`, kind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestGCIcons(t *testing.T) {
+	tests := []struct {
+		name      string
+		dryRun    bool
+		wantFiles []string
+		wantKeys  []string
+	}{
+		{
+			name:      "collect",
+			wantFiles: []string{"inv_shield_03.png", "inv_sword_01.png", "spell_fire_01.png"},
+			wantKeys:  []string{"inv_sword_01", "spell_fire_01"},
+		},
+		{
+			name:      "dry run",
+			dryRun:    true,
+			wantFiles: []string{".download-123", "inv_axe_02.png", "inv_shield_03.png", "inv_sword_01.png", "spell_fire_01.png"},
+			wantKeys:  []string{"inv_axe_02", "inv_gone", "inv_sword_01", "spell_fire_01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openMediaDB(t)
+			dir := t.TempDir()
+			icons := media.New(db, media.KindIcon, dir, nil)
+
+			// Referenced by item and spell data, in the paths and case the game uses
+			put(t, icons, "inv_sword_01", pngOf(t, 1, 1), "")
+			put(t, icons, "spell_fire_01", pngOf(t, 1, 1), "")
+			exec(t, db, `INSERT INTO item_display_info (id, icon) VALUES (1, 'INV_Sword_01')`)
+			exec(t, db, `INSERT INTO spell_template (entry, iconName) VALUES (1, 'Interface\Icons\Spell_Fire_01')`)
+			// Downloaded but no longer used
+			put(t, icons, "inv_axe_02", pngOf(t, 1, 1), "")
+			// Shipped with the app: not indexed, not referenced
+			writeFile(t, dir, "inv_shield_03.png", pngOf(t, 1, 1))
+			// Left behind by an interrupted write
+			writeFile(t, dir, ".download-123", []byte("partial"))
+			// Indexed, but the file was deleted
+			put(t, icons, "inv_gone", pngOf(t, 1, 1), "")
+			if err := os.Remove(filepath.Join(dir, "inv_gone.png")); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := icons.GC(tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(result.Removed)
+			if want := []string{".download-123", "inv_axe_02.png"}; !reflect.DeepEqual(result.Removed, want) {
+				t.Errorf("removed %v, want %v", result.Removed, want)
+			}
+			if result.StaleRows != 2 {
+				t.Errorf("%d stale rows, want 2 (inv_axe_02 and inv_gone)", result.StaleRows)
+			}
+			if got := dirFiles(t, dir); !reflect.DeepEqual(got, tt.wantFiles) {
+				t.Errorf("files left %v, want %v", got, tt.wantFiles)
+			}
+			if got := indexedKeys(t, db, media.KindIcon); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("index keys left %v, want %v", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestGCImages(t *testing.T) {
+	db := openMediaDB(t)
+	dir := t.TempDir()
+	images := media.New(db, media.KindImage, dir, nil)
+
+	// Referenced through the source URL of the index row
+	model := put(t, images, "https://example.com/model.png", pngOf(t, 1, 1), "https://example.com/model.png")
+	// Referenced by the stored local path only
+	mapImage := put(t, images, "https://example.com/map.png", pngOf(t, 2, 2), "https://example.com/map.png")
+	exec(t, db, `INSERT INTO creature_metadata (entry, model_image_url, map_image_local) VALUES (1, ?, ?)`,
+		"https://example.com/model.png", `C:\ShellLab\data\npc_images\`+mapImage.File)
+	// No NPC refers to it any more
+	unused := put(t, images, "https://example.com/old.png", pngOf(t, 3, 3), "https://example.com/old.png")
+	// Image files are collected even when not indexed
+	writeFile(t, dir, "orphan.png", pngOf(t, 4, 4))
+	writeFile(t, dir, ".download-456", []byte("partial"))
+
+	result, err := images.GC(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(result.Removed)
+	wantRemoved := []string{".download-456", "orphan.png", unused.File}
+	sort.Strings(wantRemoved)
+	if !reflect.DeepEqual(result.Removed, wantRemoved) {
+		t.Errorf("removed %v, want %v", result.Removed, wantRemoved)
+	}
+	if result.StaleRows != 1 {
+		t.Errorf("%d stale rows, want 1", result.StaleRows)
+	}
+
+	wantFiles := []string{model.File, mapImage.File}
+	sort.Strings(wantFiles)
+	if got := dirFiles(t, dir); !reflect.DeepEqual(got, wantFiles) {
+		t.Errorf("files left %v, want %v", got, wantFiles)
+	}
+	if got, want := indexedKeys(t, db, media.KindImage), []string{"https://example.com/map.png", "https://example.com/model.png"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("index keys left %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,313 @@
+// Package media stores downloaded icons and images. Every download goes
+// through the same pipeline: the type is sniffed from the content, corrupt or
+// non-image responses are rejected, files are written atomically and each file
+// is recorded in the media_files table.
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Kind says how a cache names its files
+type Kind string
+
+const (
+	// KindIcon files are named after the game's icon name (inv_sword_01.jpg),
+	// the key used by item and spell data
+	KindIcon Kind = "icon"
+	// KindImage files are named after the SHA-256 of their content, so the same
+	// image served from several URLs is stored once. The key is the source URL.
+	KindImage Kind = "image"
+)
+
+// MaxSize caps downloaded files
+const MaxSize = 8 << 20
+
+// Icon download sources, tried in order by FetchIcon. Turtle WoW first for its
+// custom icons.
+const (
+	TurtleIconPNG = "https://database.turtlecraft.gg/images/icons/large/%s.png"
+	TurtleIconJPG = "https://database.turtlecraft.gg/images/icons/large/%s.jpg"
+	WowheadIcon   = "https://wow.zamimg.com/images/wow/icons/large/%s.jpg"
+	TrinityIcon   = "https://aowow.trinitycore.info/static/images/wow/icons/large/%s.jpg"
+)
+
+// IconSources lists the icon download sources in the order they are tried
+var IconSources = []string{TurtleIconPNG, TurtleIconJPG, WowheadIcon, TrinityIcon}
+
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+
+// Doer sends HTTP requests (services.HttpClient satisfies it)
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Entry is a row of the media_files index
+type Entry struct {
+	Kind      Kind   `json:"kind"`
+	Key       string `json:"key"`
+	File      string `json:"file"`
+	Hash      string `json:"hash"`
+	MimeType  string `json:"mimeType"`
+	Size      int    `json:"size"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	SourceURL string `json:"sourceUrl"`
+	FetchedAt string `json:"fetchedAt"`
+}
+
+// Cache is one media directory
+type Cache struct {
+	db     *sql.DB
+	kind   Kind
+	dir    string
+	client Doer
+}
+
+// New creates a cache of kind over dir, indexed in db
+func New(db *sql.DB, kind Kind, dir string, client Doer) *Cache {
+	return &Cache{db: db, kind: kind, dir: dir, client: client}
+}
+
+// InitSchema creates the media_files table
+func InitSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS media_files (
+			kind TEXT NOT NULL,
+			key TEXT NOT NULL,
+			file TEXT NOT NULL,
+			hash TEXT NOT NULL,
+			mime_type TEXT NOT NULL,
+			size INTEGER NOT NULL,
+			width INTEGER NOT NULL DEFAULT 0,
+			height INTEGER NOT NULL DEFAULT 0,
+			source_url TEXT NOT NULL DEFAULT '',
+			fetched_at TEXT NOT NULL,
+			PRIMARY KEY (kind, key)
+		);
+		CREATE INDEX IF NOT EXISTS idx_media_files_hash ON media_files(hash);
+	`)
+	return err
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Kind returns how the cache names its files
+func (c *Cache) Kind() Kind {
+	return c.kind
+}
+
+// IconName normalises an icon name or path ("Interface\Icons\INV_Sword_01")
+// to the file name stem used on disk
+func IconName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Path returns the local file for key, or "" when it is not cached
+func (c *Cache) Path(key string) string {
+	if c.kind == KindIcon {
+		name := IconName(key)
+		if name == "" {
+			return ""
+		}
+		for _, ext := range Extensions {
+			p := filepath.Join(c.dir, name+ext)
+			if info, err := os.Stat(p); err == nil && !info.IsDir() {
+				return p
+			}
+		}
+		return ""
+	}
+
+	var file string
+	err := c.db.QueryRow(`SELECT file FROM media_files WHERE kind = ? AND key = ?`, c.kind, key).Scan(&file)
+	if err != nil {
+		return ""
+	}
+	p := filepath.Join(c.dir, file)
+	if _, err := os.Stat(p); err != nil {
+		// Deleted behind our back; forget it so it is fetched again
+		c.db.Exec(`DELETE FROM media_files WHERE kind = ? AND key = ?`, c.kind, key)
+		return ""
+	}
+	return p
+}
+
+// Put validates data and stores it under key. sourceURL is recorded in the index.
+func (c *Cache) Put(key string, data []byte, sourceURL string) (*Entry, error) {
+	info, err := Validate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	entry := &Entry{
+		Kind:      c.kind,
+		Key:       key,
+		Hash:      hex.EncodeToString(sum[:]),
+		MimeType:  info.MimeType,
+		Size:      len(data),
+		Width:     info.Width,
+		Height:    info.Height,
+		SourceURL: sourceURL,
+		FetchedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	switch c.kind {
+	case KindIcon:
+		entry.Key = IconName(key)
+		if entry.Key == "" {
+			return nil, fmt.Errorf("invalid icon name %q", key)
+		}
+		entry.File = entry.Key + info.Ext
+	default:
+		entry.File = entry.Hash + info.Ext
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.dir, entry.File)
+	// Content-addressed files never change once written
+	if _, err := os.Stat(path); err != nil || c.kind == KindIcon {
+		if err := writeAtomic(path, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.kind == KindIcon {
+		// One file per icon: drop copies saved under another extension
+		for _, ext := range Extensions {
+			if other := filepath.Join(c.dir, entry.Key+ext); other != path {
+				os.Remove(other)
+			}
+		}
+	}
+
+	_, err = c.db.Exec(`
+		INSERT INTO media_files (kind, key, file, hash, mime_type, size, width, height, source_url, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(kind, key) DO UPDATE SET
+			file = excluded.file, hash = excluded.hash, mime_type = excluded.mime_type,
+			size = excluded.size, width = excluded.width, height = excluded.height,
+			source_url = excluded.source_url, fetched_at = excluded.fetched_at
+	`, entry.Kind, entry.Key, entry.File, entry.Hash, entry.MimeType, entry.Size, entry.Width, entry.Height, entry.SourceURL, entry.FetchedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", entry.File, err)
+	}
+	return entry, nil
+}
+
+// Fetch returns the local file for key, downloading it from urls in order
+// when it is not cached
+func (c *Cache) Fetch(ctx context.Context, key string, urls ...string) (string, error) {
+	if p := c.Path(key); p != "" {
+		return p, nil
+	}
+
+	err := errors.New("no source URL")
+	for _, url := range urls {
+		var data []byte
+		data, err = Download(ctx, c.client, url)
+		if err != nil {
+			continue
+		}
+		var entry *Entry
+		entry, err = c.Put(key, data, url)
+		if err != nil {
+			continue
+		}
+		return filepath.Join(c.dir, entry.File), nil
+	}
+	return "", fmt.Errorf("failed to fetch %s: %w", key, err)
+}
+
+// FetchIcon returns the local file of an icon, downloading it from
+// IconSources when it is not cached
+func (c *Cache) FetchIcon(ctx context.Context, name string) (string, error) {
+	name = IconName(name)
+	urls := make([]string, len(IconSources))
+	for i, source := range IconSources {
+		urls[i] = fmt.Sprintf(source, name)
+	}
+	return c.Fetch(ctx, name, urls...)
+}
+
+// FetchImage returns the local copy of an image URL, downloading it when it
+// is not cached
+func (c *Cache) FetchImage(ctx context.Context, url string) (string, error) {
+	return c.Fetch(ctx, url, url)
+}
+
+// Download fetches url and returns its body once it passes Validate
+func Download(ctx context.Context, client Doer, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+	if _, err := Validate(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// writeAtomic writes data next to path and renames it into place, so a
+// half-written file is never visible
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// CreateTemp makes the file private; cached files are plain data files
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,102 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Extensions are the file extensions the cache writes, in lookup order
+var Extensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp"}
+
+// tempPrefix marks files being written; GC removes leftovers
+const tempPrefix = ".download-"
+
+var (
+	// ErrNotImage is returned for content that is not PNG, JPEG, GIF or WebP,
+	// typically an HTML error page served with status 200
+	ErrNotImage = errors.New("not an image")
+	// ErrTruncated is returned for images cut off mid-download
+	ErrTruncated = errors.New("truncated image")
+	// ErrTooLarge is returned for downloads over MaxSize
+	ErrTooLarge = fmt.Errorf("image larger than %d bytes", MaxSize)
+)
+
+// Info describes validated image content
+type Info struct {
+	Ext      string
+	MimeType string
+	Width    int
+	Height   int
+}
+
+var (
+	pngMagic  = []byte("\x89PNG\r\n\x1a\n")
+	pngEnd    = []byte("IEND\xae\x42\x60\x82")
+	jpegMagic = []byte{0xff, 0xd8, 0xff}
+	jpegEnd   = []byte{0xff, 0xd9}
+)
+
+// Sniff returns the extension and MIME type from the file's magic bytes,
+// ignoring whatever Content-Type or URL extension it was served with
+func Sniff(data []byte) (ext, mimeType string, ok bool) {
+	switch {
+	case bytes.HasPrefix(data, pngMagic):
+		return ".png", "image/png", true
+	case bytes.HasPrefix(data, jpegMagic):
+		return ".jpg", "image/jpeg", true
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return ".gif", "image/gif", true
+	case len(data) >= 12 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return ".webp", "image/webp", true
+	}
+	return "", "", false
+}
+
+// Validate checks that data is a complete image: a known format, a readable
+// header with non-zero dimensions and the format's end marker
+func Validate(data []byte) (*Info, error) {
+	ext, mimeType, ok := Sniff(data)
+	if !ok {
+		return nil, ErrNotImage
+	}
+
+	if !complete(ext, data) {
+		return nil, ErrTruncated
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt %s: %w", ext, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("corrupt %s: %dx%d", ext, cfg.Width, cfg.Height)
+	}
+
+	return &Info{Ext: ext, MimeType: mimeType, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// complete checks the end marker of a format, which a cut-off download lacks
+func complete(ext string, data []byte) bool {
+	switch ext {
+	case ".png":
+		return bytes.HasSuffix(data, pngEnd)
+	case ".jpg":
+		// Some encoders pad after the EOI marker
+		return bytes.HasSuffix(bytes.TrimRight(data, "\x00"), jpegEnd)
+	case ".gif":
+		return data[len(data)-1] == 0x3b
+	case ".webp":
+		// The RIFF header holds the size of the rest of the file
+		return int(binary.LittleEndian.Uint32(data[4:8]))+8 <= len(data)
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"shelllab/backend/media"
+)
+
+const (
+	// assetMaxAge is how long the webview may reuse an image without revalidating
+	assetMaxAge = 7 * 24 * time.Hour
+	// assetMissTTL is how long a failed remote fetch is remembered
+	assetMissTTL = 10 * time.Minute
+	// assetFetchTimeout bounds a remote fetch, which outlives the request that started it
+	assetFetchTimeout = 30 * time.Second
+)
+
+// AssetHandler serves images to the webview as plain URLs, so the frontend
+// doesn't need a base64 IPC round-trip per image:
+//
+//	/icons/<name>              data/icons, then embedded icons, then the icon sources
+//	/icons/atlas/<file>        icon sprite atlases (media.BuildAtlases), local then embedded
+//	/local-icons/<name>.jpg    same as /icons, for existing frontend URLs
+//	/npc/<name>?src=<url>      data/npc_images, then embedded images, then src
+//
+// Fetched images go through the media caches so they are served locally next time.
+type AssetHandler struct {
+	icons      *media.Cache
+	images     *media.Cache
+	embedIcons fs.FS
+	embedAtlas fs.FS
+	embedNpc   fs.FS
+	startedAt  time.Time
+	mu         sync.Mutex
+	inflight   map[string]*sync.WaitGroup
+	misses     map[string]time.Time
+}
+
+// NewAssetHandler creates a handler over the icon and NPC image caches. The
+// embedded filesystems hold the icons and NPC images shipped with the binary
+// and may be nil.
+func NewAssetHandler(icons, images *media.Cache, embedIcons, embedNpc fs.FS) *AssetHandler {
+	var embedAtlas fs.FS
+	if embedIcons != nil {
+		embedAtlas, _ = fs.Sub(embedIcons, media.AtlasDir)
+	}
+	return &AssetHandler{
+		icons:      icons,
+		images:     images,
+		embedIcons: embedIcons,
+		embedAtlas: embedAtlas,
+		embedNpc:   embedNpc,
+		startedAt:  time.Now(),
+		inflight:   make(map[string]*sync.WaitGroup),
+		misses:     make(map[string]time.Time),
+	}
+}
+
+// imageHosts are the sites NPC images are scraped from; ?src is limited to them
+var imageHosts = []string{"turtlecraft.gg", "turtle-wow.org", "zamimg.com", "wowhead.com", "trinitycore.info"}
+
+func isImageHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range imageHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *AssetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/icons/atlas/"):
+		h.serveAtlas(w, r, strings.TrimPrefix(r.URL.Path, "/icons/atlas/"))
+	case strings.HasPrefix(r.URL.Path, "/icons/"):
+		h.serveIcon(w, r, strings.TrimPrefix(r.URL.Path, "/icons/"))
+	case strings.HasPrefix(r.URL.Path, "/local-icons/"):
+		h.serveIcon(w, r, strings.TrimPrefix(r.URL.Path, "/local-icons/"))
+	case strings.HasPrefix(r.URL.Path, "/npc/"):
+		h.serveNpcImage(w, r, strings.TrimPrefix(r.URL.Path, "/npc/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *AssetHandler) serveIcon(w http.ResponseWriter, r *http.Request, name string) {
+	name, ok := cleanAssetName(name)
+	if !ok {
+		http.Error(w, "invalid name", http.StatusBadRequest)
+		return
+	}
+	// Icon names are case-insensitive; the requested extension is only a hint
+	name = strings.ToLower(strings.TrimSuffix(name, path.Ext(name)))
+
+	if h.serveLocal(w, r, h.icons.Dir(), h.embedIcons, name) {
+		return
+	}
+
+	h.fetchOnce("icon:"+name, func(ctx context.Context) bool {
+		_, err := h.icons.FetchIcon(ctx, name)
+		return err == nil
+	})
+
+	if !h.serveFile(w, r, h.icons.Path(name)) {
+		h.notFound(w)
+	}
+}
+
+// serveAtlas serves an atlas sheet or manifest. Sprite URLs carry the atlas
+// version, so the usual max-age is safe across rebuilds.
+func (h *AssetHandler) serveAtlas(w http.ResponseWriter, r *http.Request, name string) {
+	name, ok := cleanAssetName(name)
+	if !ok || path.Ext(name) == "" {
+		http.Error(w, "invalid name", http.StatusBadRequest)
+		return
+	}
+	if !h.serveLocal(w, r, filepath.Join(h.icons.Dir(), media.AtlasDir), h.embedAtlas, name) {
+		h.notFound(w)
+	}
+}
+
+func (h *AssetHandler) serveNpcImage(w http.ResponseWriter, r *http.Request, name string) {
+	name, ok := cleanAssetName(name)
+	if !ok {
+		http.Error(w, "invalid name", http.StatusBadRequest)
+		return
+	}
+	base := strings.TrimSuffix(name, path.Ext(name))
+
+	if h.serveLocal(w, r, h.images.Dir(), h.embedNpc, name) || h.serveLocal(w, r, h.images.Dir(), h.embedNpc, base) {
+		return
+	}
+
+	src := r.URL.Query().Get("src")
+	u, err := url.Parse(src)
+	if src == "" || err != nil || (u.Scheme != "https" && u.Scheme != "http") || !isImageHost(u.Hostname()) {
+		h.notFound(w)
+		return
+	}
+
+	// Downloaded images are stored by content hash and found by source URL
+	if h.serveFile(w, r, h.images.Path(src)) {
+		return
+	}
+	h.fetchOnce("npc:"+src, func(ctx context.Context) bool {
+		_, err := h.images.FetchImage(ctx, src)
+		return err == nil
+	})
+
+	if !h.serveFile(w, r, h.images.Path(src)) {
+		h.notFound(w)
+	}
+}
+
+// cleanAssetName rejects anything that is not a plain file name
+func cleanAssetName(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	return name, true
+}
+
+// serveLocal serves name (with or without extension) from dir, then from the
+// embedded fallback. Returns false when neither has it.
+func (h *AssetHandler) serveLocal(w http.ResponseWriter, r *http.Request, dir string, embedded fs.FS, name string) bool {
+	candidates := []string{name}
+	if path.Ext(name) == "" {
+		candidates = candidates[:0]
+		for _, ext := range media.Extensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+
+	for _, file := range candidates {
+		if h.serveFile(w, r, filepath.Join(dir, file)) {
+			return true
+		}
+	}
+
+	if embedded == nil {
+		return false
+	}
+	for _, file := range candidates {
+		data, err := fs.ReadFile(embedded, file)
+		if err != nil {
+			continue
+		}
+		// Embedded files have no modtime; they change only with the binary
+		h.serveContent(w, r, file, h.startedAt, int64(len(data)), bytes.NewReader(data))
+		return true
+	}
+	return false
+}
+
+// serveFile serves a file from disk. Returns false when it doesn't exist.
+func (h *AssetHandler) serveFile(w http.ResponseWriter, r *http.Request, file string) bool {
+	if file == "" {
+		return false
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+	h.serveContent(w, r, filepath.Base(file), info.ModTime(), info.Size(), f)
+	return true
+}
+
+func (h *AssetHandler) serveContent(w http.ResponseWriter, r *http.Request, name string, modTime time.Time, size int64, content io.ReadSeeker) {
+	header := w.Header()
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(assetMaxAge.Seconds())))
+	header.Set("ETag", fmt.Sprintf(`"%x-%x"`, modTime.Unix(), size))
+	http.ServeContent(w, r, name, modTime, content)
+}
+
+func (h *AssetHandler) notFound(w http.ResponseWriter) {
+	// Don't let the webview cache misses; the image may be fetched later
+	w.Header().Set("Cache-Control", "no-store")
+	http.Error(w, "not found", http.StatusNotFound)
+}
+
+// fetchOnce runs fetch for key unless another request is already fetching it
+// or it failed recently; concurrent callers wait for the running fetch
+func (h *AssetHandler) fetchOnce(key string, fetch func(ctx context.Context) bool) {
+	h.mu.Lock()
+	if missed, ok := h.misses[key]; ok && time.Since(missed) < assetMissTTL {
+		h.mu.Unlock()
+		return
+	}
+	if wg, ok := h.inflight[key]; ok {
+		h.mu.Unlock()
+		wg.Wait()
+		return
+	}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	h.inflight[key] = wg
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), assetFetchTimeout)
+	ok := fetch(ctx)
+	cancel()
+
+	h.mu.Lock()
+	delete(h.inflight, key)
+	if ok {
+		delete(h.misses, key)
+	} else {
+		h.misses[key] = time.Now()
+	}
+	h.mu.Unlock()
+	wg.Done()
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"fmt"
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
+	"strconv"
+)
+
+// changeRecorder stores the diff of a tracked row once the upsert is done
+type changeRecorder func(name string) []*models.ChangeLogEntry
+
+// trackChanges snapshots a row before an upsert and returns a recorder that
+// diffs it against the row afterwards and writes the result to change_log
+func trackChanges(changes *repositories.ChangeLogRepository, entityType, source, table, keyColumn string, id int) changeRecorder {
+	return track(changes, entityType, source, table, keyColumn, id, false)
+}
+
+// trackDetailChanges is trackChanges for a table holding extra columns of a
+// record stored elsewhere (e.g. creature_metadata for creature_template).
+// A new detail row is recorded as updated fields, never as an added record.
+func trackDetailChanges(changes *repositories.ChangeLogRepository, entityType, source, table, keyColumn string, id int) changeRecorder {
+	return track(changes, entityType, source, table, keyColumn, id, true)
+}
+
+func track(changes *repositories.ChangeLogRepository, entityType, source, table, keyColumn string, id int, detail bool) changeRecorder {
+	before, err := changes.Snapshot(table, keyColumn, id)
+	if err != nil {
+		fmt.Printf("[ChangeLog] ⚠ Failed to read %s %d before sync: %v\n", table, id, err)
+		return func(string) []*models.ChangeLogEntry { return nil }
+	}
+	if before == nil && detail {
+		before = map[string]string{keyColumn: strconv.Itoa(id)}
+	}
+
+	return func(name string) []*models.ChangeLogEntry {
+		after, err := changes.Snapshot(table, keyColumn, id)
+		if err != nil {
+			fmt.Printf("[ChangeLog] ⚠ Failed to read %s %d after sync: %v\n", table, id, err)
+			return nil
+		}
+		entries, err := changes.RecordDiff(entityType, id, name, source, before, after)
+		if err != nil {
+			fmt.Printf("[ChangeLog] ⚠ Failed to record changes for %s %d: %v\n", entityType, id, err)
+		}
+		return entries
+	}
+}
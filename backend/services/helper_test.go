@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"shelllab/backend/database"
+)
+
+// fakeClient is an HttpClient answering every request with its func
+type fakeClient func(req *http.Request) (*http.Response, error)
+
+func (f fakeClient) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (f fakeClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f(req)
+}
+
+// respond answers req with status and body
+func respond(req *http.Request, status int, body string) (*http.Response, error) {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+// itemPages serves an item page for every ID in names and a 404 for anything else
+func itemPages(names map[int]string) fakeClient {
+	return func(req *http.Request) (*http.Response, error) {
+		id, _ := strconv.Atoi(req.URL.Query().Get("item"))
+		name, ok := names[id]
+		if !ok {
+			return respond(req, http.StatusNotFound, "")
+		}
+		return respond(req, http.StatusOK, "<title>"+name+" - Items - Turtle WoW Database</title>")
+	}
+}
+
+// openTestDB opens a game database with user.db attached, creates the game,
+// change log, wishlist and saved search schemas and runs stmts
+func openTestDB(t *testing.T, stmts ...string) *database.SQLiteDB {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := database.NewSQLiteDBWithUserData(filepath.Join(dir, "shelllab.db"), filepath.Join(dir, "user.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, init := range []func() error{
+		db.InitSchema,
+		database.NewChangeLogRepository(db).InitSchema,
+		database.NewWishlistRepository(db).InitSchema,
+		database.NewSavedSearchRepository(db).InitSchema,
+	} {
+		if err := init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, stmt := range stmts {
+		if _, err := db.DB().Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheMode controls how HTTPCache treats the network
+type CacheMode string
+
+const (
+	CacheModeOff    CacheMode = "off"    // Always hit the network, never store
+	CacheModeNormal CacheMode = "normal" // Serve fresh entries, revalidate stale ones
+	CacheModeReplay CacheMode = "replay" // Serve only from disk, never hit the network
+)
+
+// ErrCacheMiss is returned in replay mode when a page was never recorded
+var ErrCacheMiss = fmt.Errorf("http cache miss in replay mode")
+
+// negativeTTL bounds how long a recorded 404 is trusted outside replay mode
+const negativeTTL = 24 * time.Hour
+
+// cacheEntry is the metadata stored next to each cached page
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	StatusCode   int       `json:"statusCode"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"lastModified"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// HTTPCacheStats reports cache usage for the UI
+type HTTPCacheStats struct {
+	Mode        string `json:"mode"`
+	Dir         string `json:"dir"`
+	TTLHours    int    `json:"ttlHours"`
+	Entries     int    `json:"entries"`
+	SizeBytes   int64  `json:"sizeBytes"`
+	Hits        int64  `json:"hits"`
+	Misses      int64  `json:"misses"`
+	Revalidated int64  `json:"revalidated"`
+}
+
+// HTTPCache is a disk-backed HttpClient that records raw pages by URL.
+// Only GET requests are cached; everything else passes straight through.
+type HTTPCache struct {
+	client HttpClient
+	dir    string
+	ttl    time.Duration
+
+	mu          sync.RWMutex
+	mode        CacheMode
+	hits        int64
+	misses      int64
+	revalidated int64
+}
+
+// NewHTTPCache creates a cache storing pages under dir, wrapping client
+func NewHTTPCache(client HttpClient, dir string, ttl time.Duration) *HTTPCache {
+	os.MkdirAll(dir, 0755)
+	return &HTTPCache{
+		client: client,
+		dir:    dir,
+		ttl:    ttl,
+		mode:   CacheModeNormal,
+	}
+}
+
+// SetMode switches the cache mode
+func (c *HTTPCache) SetMode(mode CacheMode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mode = mode
+}
+
+// Mode returns the current cache mode
+func (c *HTTPCache) Mode() CacheMode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mode
+}
+
+// IsReplay returns true when the cache never touches the network
+func (c *HTTPCache) IsReplay() bool {
+	return c.Mode() == CacheModeReplay
+}
+
+// Get issues a GET request through the cache
+func (c *HTTPCache) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do executes a request, serving GETs from disk when possible. Outside off
+// mode a 404, live or cached, comes back as ErrNotFound.
+func (c *HTTPCache) Do(req *http.Request) (*http.Response, error) {
+	mode := c.Mode()
+	if req.Method != "GET" || mode == CacheModeOff {
+		return c.client.Do(req)
+	}
+
+	url := req.URL.String()
+	entry, body := c.load(url)
+
+	if mode == CacheModeReplay {
+		if entry == nil {
+			c.count(&c.misses)
+			return nil, fmt.Errorf("%w: %s", ErrCacheMiss, url)
+		}
+		c.count(&c.hits)
+		if entry.isNegative() {
+			return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+		}
+		return entry.response(req, body), nil
+	}
+
+	if entry != nil && time.Since(entry.FetchedAt) < entry.ttl(c.ttl) {
+		c.count(&c.hits)
+		if entry.isNegative() {
+			return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+		}
+		return entry.response(req, body), nil
+	}
+
+	// A stale 404 is simply fetched again
+	if entry != nil && entry.isNegative() {
+		entry = nil
+	}
+
+	// Stale entry: ask the server whether it changed
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Serve the stale copy rather than failing when the site is unreachable
+		if entry != nil && IsTransient(err) {
+			c.count(&c.hits)
+			return entry.response(req, body), nil
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.FetchedAt = time.Now()
+		c.saveMeta(url, entry)
+		c.count(&c.revalidated)
+		return entry.response(req, body), nil
+	}
+
+	c.count(&c.misses)
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		// Record the miss so replay runs see the same 404 instead of ErrCacheMiss
+		negative := &cacheEntry{URL: url, StatusCode: resp.StatusCode, FetchedAt: time.Now()}
+		if err := c.save(url, negative, nil); err != nil {
+			fmt.Printf("[HTTPCache] ⚠ Failed to store %s: %v\n", url, err)
+		}
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	newEntry := &cacheEntry{
+		URL:          url,
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}
+	if err := c.save(url, newEntry, data); err != nil {
+		fmt.Printf("[HTTPCache] ⚠ Failed to store %s: %v\n", url, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// Invalidate removes a single URL from the cache
+func (c *HTTPCache) Invalidate(url string) {
+	base := c.path(url)
+	os.Remove(base + ".html")
+	os.Remove(base + ".json")
+}
+
+// Clear removes every cached page
+func (c *HTTPCache) Clear() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		os.Remove(filepath.Join(c.dir, e.Name()))
+	}
+	return nil
+}
+
+// Stats returns cache usage counters and disk footprint
+func (c *HTTPCache) Stats() HTTPCacheStats {
+	c.mu.RLock()
+	stats := HTTPCacheStats{
+		Mode:        string(c.mode),
+		Dir:         c.dir,
+		TTLHours:    int(c.ttl.Hours()),
+		Hits:        c.hits,
+		Misses:      c.misses,
+		Revalidated: c.revalidated,
+	}
+	c.mu.RUnlock()
+
+	entries, _ := os.ReadDir(c.dir)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(e.Name(), ".json") {
+			stats.Entries++
+		}
+		stats.SizeBytes += info.Size()
+	}
+	return stats
+}
+
+func (c *HTTPCache) count(counter *int64) {
+	c.mu.Lock()
+	*counter++
+	c.mu.Unlock()
+}
+
+// path returns the file path prefix for a URL (without extension)
+func (c *HTTPCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func (c *HTTPCache) load(url string) (*cacheEntry, []byte) {
+	base := c.path(url)
+	meta, err := os.ReadFile(base + ".json")
+	if err != nil {
+		return nil, nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil || entry.URL != url {
+		return nil, nil
+	}
+	body, err := os.ReadFile(base + ".html")
+	if err != nil {
+		return nil, nil
+	}
+	return &entry, body
+}
+
+func (c *HTTPCache) save(url string, entry *cacheEntry, body []byte) error {
+	base := c.path(url)
+	if err := writeFileAtomic(base+".html", body); err != nil {
+		return err
+	}
+	return c.saveMeta(url, entry)
+}
+
+func (c *HTTPCache) saveMeta(url string, entry *cacheEntry) error {
+	meta, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.path(url)+".json", meta)
+}
+
+// isNegative reports whether the entry records a 404 rather than a page
+func (e *cacheEntry) isNegative() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// ttl returns how long the entry stays fresh, capping negative entries at negativeTTL
+func (e *cacheEntry) ttl(pageTTL time.Duration) time.Duration {
+	if e.isNegative() && negativeTTL < pageTTL {
+		return negativeTTL
+	}
+	return pageTTL
+}
+
+// response builds a synthetic response from a cached page
+func (e *cacheEntry) response(req *http.Request, body []byte) *http.Response {
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	if e.ETag != "" {
+		header.Set("ETag", e.ETag)
+	}
+	if e.LastModified != "" {
+		header.Set("Last-Modified", e.LastModified)
+	}
+	header.Set("X-Cache", "HIT")
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// writeFileAtomic writes data to a temp file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"shelllab/backend/services"
+)
+
+// newPageServer serves /page with an ETag it honours on revalidation and 404s everything else
+func newPageServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/page" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "page")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestHTTPCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		path         string
+		first        services.CacheMode // empty skips the recording request
+		second       services.CacheMode
+		wantErr      error
+		wantStatus   int
+		wantRequests int64
+		hits         int64
+		misses       int64
+		revalidated  int64
+	}{
+		{
+			name: "fresh hit", ttl: time.Hour, path: "/page",
+			first: services.CacheModeNormal, second: services.CacheModeNormal,
+			wantStatus: 200, wantRequests: 1, hits: 1, misses: 1,
+		},
+		{
+			name: "stale entry revalidated", ttl: time.Nanosecond, path: "/page",
+			first: services.CacheModeNormal, second: services.CacheModeNormal,
+			wantStatus: 200, wantRequests: 2, misses: 1, revalidated: 1,
+		},
+		{
+			name: "replay hit", ttl: time.Nanosecond, path: "/page",
+			first: services.CacheModeNormal, second: services.CacheModeReplay,
+			wantStatus: 200, wantRequests: 1, hits: 1, misses: 1,
+		},
+		{
+			name: "replay miss", ttl: time.Hour, path: "/page",
+			second:  services.CacheModeReplay,
+			wantErr: services.ErrCacheMiss, misses: 1,
+		},
+		{
+			name: "off mode does not store", ttl: time.Hour, path: "/page",
+			first: services.CacheModeOff, second: services.CacheModeReplay,
+			wantErr: services.ErrCacheMiss, wantRequests: 1, misses: 1,
+		},
+		{
+			name: "live 404", ttl: time.Hour, path: "/missing",
+			second:  services.CacheModeNormal,
+			wantErr: services.ErrNotFound, wantRequests: 1, misses: 1,
+		},
+		{
+			name: "fresh 404", ttl: time.Hour, path: "/missing",
+			first: services.CacheModeNormal, second: services.CacheModeNormal,
+			wantErr: services.ErrNotFound, wantRequests: 1, hits: 1, misses: 1,
+		},
+		{
+			name: "replayed 404", ttl: time.Hour, path: "/missing",
+			first: services.CacheModeNormal, second: services.CacheModeReplay,
+			wantErr: services.ErrNotFound, wantRequests: 1, hits: 1, misses: 1,
+		},
+		{
+			name: "stale 404 fetched again", ttl: time.Nanosecond, path: "/missing",
+			first: services.CacheModeNormal, second: services.CacheModeNormal,
+			wantErr: services.ErrNotFound, wantRequests: 2, misses: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newPageServer(t)
+			cache := services.NewHTTPCache(srv.Client(), t.TempDir(), tt.ttl)
+			url := srv.URL + tt.path
+
+			if tt.first != "" {
+				cache.SetMode(tt.first)
+				resp, err := cache.Get(url)
+				switch {
+				case errors.Is(err, services.ErrNotFound):
+				case err != nil:
+					t.Fatal(err)
+				default:
+					resp.Body.Close()
+				}
+			}
+
+			cache.SetMode(tt.second)
+			resp, err := cache.Get(url)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatal(err)
+			default:
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+				if tt.wantStatus == 200 && string(body) != "page" {
+					t.Errorf("body = %q", body)
+				}
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("server saw %d requests, want %d", got, tt.wantRequests)
+			}
+			stats := cache.Stats()
+			if stats.Hits != tt.hits || stats.Misses != tt.misses || stats.Revalidated != tt.revalidated {
+				t.Errorf("hits/misses/revalidated = %d/%d/%d, want %d/%d/%d",
+					stats.Hits, stats.Misses, stats.Revalidated, tt.hits, tt.misses, tt.revalidated)
+			}
+		})
+	}
+}
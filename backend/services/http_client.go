@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNotFound marks a page that really does not exist (HTTP 404 or a "not found" page),
+// as opposed to a transient failure that should not count as a miss
+var ErrNotFound = errors.New("not found")
+
+// RetryError is returned when a request still failed after every retry
+type RetryError struct {
+	URL        string
+	Attempts   int
+	StatusCode int   // Last HTTP status (429/5xx), 0 for network errors
+	Err        error // Last network error, nil for HTTP status failures
+}
+
+func (e *RetryError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("request to %s failed after %d attempts: %v", e.URL, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("request to %s failed after %d attempts: HTTP %d", e.URL, e.Attempts, e.StatusCode)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a network/server failure rather than a real miss
+func IsTransient(err error) bool {
+	var retryErr *RetryError
+	return errors.As(err, &retryErr)
+}
+
+// hostLimiter is a token bucket whose rate adapts to server pushback
+type hostLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // Current tokens per second
+	maxRate float64
+	minRate float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+}
+
+func newHostLimiter(rate float64, burst int) *hostLimiter {
+	return &hostLimiter{
+		rate:    rate,
+		maxRate: rate,
+		minRate: math.Min(0.5, rate),
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done
+func (l *hostLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// slowDown halves the rate after a 429/5xx
+func (l *hostLimiter) slowDown() {
+	l.mu.Lock()
+	l.rate = math.Max(l.minRate, l.rate/2)
+	l.mu.Unlock()
+}
+
+// speedUp slowly recovers the rate after a successful request
+func (l *hostLimiter) speedUp() {
+	l.mu.Lock()
+	l.rate = math.Min(l.maxRate, l.rate*1.05)
+	l.mu.Unlock()
+}
+
+// RateLimitedClient is an HttpClient with a per-host token bucket and
+// exponential backoff with jitter on 429, 5xx and timeouts
+type RateLimitedClient struct {
+	client      *http.Client
+	maxRetries  int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	defaultRate float64
+	burst       int
+
+	mu        sync.Mutex
+	limiters  map[string]*hostLimiter
+	hostRates map[string]float64
+}
+
+// NewRateLimitedClient creates a client allowing defaultRate requests per second per host
+func NewRateLimitedClient(timeout time.Duration, defaultRate float64, burst int) *RateLimitedClient {
+	return &RateLimitedClient{
+		client:      &http.Client{Timeout: timeout},
+		maxRetries:  4,
+		baseDelay:   500 * time.Millisecond,
+		maxDelay:    30 * time.Second,
+		defaultRate: defaultRate,
+		burst:       burst,
+		limiters:    make(map[string]*hostLimiter),
+		hostRates:   make(map[string]float64),
+	}
+}
+
+var (
+	sharedClient     *RateLimitedClient
+	sharedClientOnce sync.Once
+)
+
+// SharedHTTPClient returns the process-wide client used by every sync path,
+// so all workers and services share the same per-host budget
+func SharedHTTPClient() *RateLimitedClient {
+	sharedClientOnce.Do(func() {
+		sharedClient = NewRateLimitedClient(20*time.Second, 8, 8)
+		sharedClient.SetHostRate("database.turtlecraft.gg", 4)
+	})
+	return sharedClient
+}
+
+// SetHostRate overrides the requests per second allowed for a host
+func (c *RateLimitedClient) SetHostRate(host string, rate float64) {
+	if rate <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hostRates[host] = rate
+	delete(c.limiters, host)
+}
+
+func (c *RateLimitedClient) limiter(host string) *hostLimiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if l, ok := c.limiters[host]; ok {
+		return l
+	}
+	rate := c.defaultRate
+	if r, ok := c.hostRates[host]; ok {
+		rate = r
+	}
+	l := newHostLimiter(rate, c.burst)
+	c.limiters[host] = l
+	return l
+}
+
+// Get issues a GET request
+func (c *RateLimitedClient) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do executes a request, waiting for the host's rate limit and retrying transient failures.
+// 404 and other 4xx responses are returned as-is; exhausted retries return a *RetryError.
+func (c *RateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	limiter := c.limiter(req.URL.Host)
+	canRetry := req.Body == nil || req.GetBody != nil
+
+	var lastStatus int
+	var lastErr error
+	attempts := 0
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		attempts++
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.client.Do(req)
+		var retryAfter time.Duration
+
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !isRetryableNetError(err) {
+				return nil, err
+			}
+			lastErr, lastStatus = err, 0
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			limiter.slowDown()
+			lastErr, lastStatus = nil, resp.StatusCode
+		default:
+			limiter.speedUp()
+			return resp, nil
+		}
+
+		if !canRetry || attempt == c.maxRetries {
+			break
+		}
+
+		delay := c.backoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		fmt.Printf("[HTTP] ⚠ %s (attempt %d/%d), retrying in %v\n", describeFailure(lastStatus, lastErr), attempt+1, c.maxRetries+1, delay.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return nil, &RetryError{
+		URL:        req.URL.String(),
+		Attempts:   attempts,
+		StatusCode: lastStatus,
+		Err:        lastErr,
+	}
+}
+
+// getWithContext issues a GET through client that is aborted as soon as ctx is done
+func getWithContext(ctx context.Context, client HttpClient, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// backoff returns an exponential delay with jitter in [d/2, d]
+func (c *RateLimitedClient) backoff(attempt int) time.Duration {
+	d := c.baseDelay * time.Duration(1<<attempt)
+	if d > c.maxDelay {
+		d = c.maxDelay
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// isRetryableNetError treats timeouts and connection failures as transient
+func isRetryableNetError(err error) bool {
+	// *url.Error itself satisfies net.Error, so look at what it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func describeFailure(status int, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("HTTP %d", status)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedClientRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // Replied in order, the last one repeats
+		retryAfter   string
+		wantStatus   int
+		wantRequests int64
+		wantRetryErr bool
+	}{
+		{name: "429 then success", statuses: []int{429, 200}, wantStatus: 200, wantRequests: 2},
+		{name: "zero Retry-After uses backoff", statuses: []int{503, 503, 200}, retryAfter: "0", wantStatus: 200, wantRequests: 3},
+		{name: "404 is not retried", statuses: []int{404}, wantStatus: 404, wantRequests: 1},
+		{name: "persistent 503 gives up", statuses: []int{503}, wantRequests: 5, wantRetryErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1)) - 1
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			client := NewRateLimitedClient(5*time.Second, 1000, 10)
+			client.baseDelay = time.Millisecond
+
+			resp, err := client.Get(srv.URL)
+			if tt.wantRetryErr {
+				var retryErr *RetryError
+				if !errors.As(err, &retryErr) {
+					t.Fatalf("err = %v, want *RetryError", err)
+				}
+				if retryErr.Attempts != client.maxRetries+1 || retryErr.StatusCode != 503 {
+					t.Errorf("attempts %d, status %d", retryErr.Attempts, retryErr.StatusCode)
+				}
+				if !IsTransient(err) {
+					t.Error("RetryError not reported as transient")
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("server saw %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{name: "empty", value: ""},
+		{name: "seconds", value: "3", min: 3 * time.Second, max: 3 * time.Second},
+		{name: "garbage", value: "soon"},
+		{name: "http date", value: time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), min: 8 * time.Second, max: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want [%v, %v]", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	t.Run("burst then wait", func(t *testing.T) {
+		l := newHostLimiter(20, 2)
+		start := time.Now()
+		for i := 0; i < 2; i++ {
+			if err := l.wait(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+			t.Errorf("burst took %v", elapsed)
+		}
+		if err := l.wait(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		// One token at 20/s takes 50ms to refill
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("third request waited only %v", elapsed)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		l := newHostLimiter(0.1, 1)
+		if err := l.wait(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		rate   float64
+		adjust func(l *hostLimiter)
+		want   float64
+	}{
+		{name: "slow down halves", rate: 8, adjust: (*hostLimiter).slowDown, want: 4},
+		{name: "slow down floor", rate: 8, adjust: func(l *hostLimiter) {
+			for i := 0; i < 10; i++ {
+				l.slowDown()
+			}
+		}, want: 0.5},
+		{name: "floor never exceeds the configured rate", rate: 0.2, adjust: (*hostLimiter).slowDown, want: 0.2},
+		{name: "speed up capped", rate: 8, adjust: func(l *hostLimiter) {
+			l.slowDown()
+			for i := 0; i < 100; i++ {
+				l.speedUp()
+			}
+		}, want: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newHostLimiter(tt.rate, 1)
+			tt.adjust(l)
+			if l.rate != tt.want {
+				t.Errorf("rate = %v, want %v", l.rate, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -13,13 +14,15 @@ import (
 	"time"
 
 	"shelllab/backend/database"
+	"shelllab/backend/database/repositories"
+	"shelllab/backend/media"
 )
 
 // IconService handles downloading icons
 type IconService struct {
 	db        *database.SQLiteDB
 	outputDir string
-	client    *http.Client
+	icons     *media.Cache
 }
 
 // NewIconService creates a new IconService
@@ -30,17 +33,15 @@ func NewIconService(db *database.SQLiteDB) *IconService {
 	return &IconService{
 		db:        db,
 		outputDir: outputDir,
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
+		icons:     media.New(db.DB(), media.KindIcon, outputDir, SharedHTTPClient()),
 	}
 }
 
 // StartDownload initiates the download process in background
-func (s *IconService) StartDownload() {
+func (s *IconService) StartDownload(ctx context.Context) {
 	go func() {
 		fmt.Println("[IconService] Starting background icon download...")
-		if err := s.downloadProcess(); err != nil {
+		if err := s.downloadProcess(ctx); err != nil {
 			fmt.Printf("[IconService] Error: %v\n", err)
 		} else {
 			fmt.Println("[IconService] Download complete.")
@@ -48,7 +49,7 @@ func (s *IconService) StartDownload() {
 	}()
 }
 
-func (s *IconService) downloadProcess() error {
+func (s *IconService) downloadProcess(ctx context.Context) error {
 	// Ensure directory exists
 	if err := os.MkdirAll(s.outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output dir: %w", err)
@@ -83,13 +84,7 @@ func (s *IconService) downloadProcess() error {
 	// 2. Filter out existing icons
 	var toDownload []string
 	for name := range iconNames {
-		pathJPG := filepath.Join(s.outputDir, name+".jpg")
-		pathPNG := filepath.Join(s.outputDir, name+".png")
-		_, errJPG := os.Stat(pathJPG)
-		_, errPNG := os.Stat(pathPNG)
-
-		// Only download if NEITHER exists
-		if os.IsNotExist(errJPG) && os.IsNotExist(errPNG) {
+		if s.icons.Path(name) == "" {
 			toDownload = append(toDownload, name)
 		}
 	}
@@ -105,14 +100,6 @@ func (s *IconService) downloadProcess() error {
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, 10) // Concurrency limit
 
-	// Sources to try in order - try PNG first for Turtle WoW custom icons, then fallback to JPG
-	sources := []string{
-		"https://database.turtlecraft.gg/images/icons/large/%s.png",           // Turtle WoW Database (PNG)
-		"https://database.turtlecraft.gg/images/icons/large/%s.jpg",           // Turtle WoW Database (JPG fallback)
-		"https://wow.zamimg.com/images/wow/icons/large/%s.jpg",                // Wowhead CDN (supports Classic)
-		"https://aowow.trinitycore.info/static/images/wow/icons/large/%s.jpg", // Trinity Aowow
-	}
-
 	var successCount, failCount int
 	var mu sync.Mutex
 
@@ -123,14 +110,8 @@ func (s *IconService) downloadProcess() error {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			success := false
-			for _, srcFmt := range sources {
-				url := fmt.Sprintf(srcFmt, iconName)
-				if err := s.downloadFile(url, iconName); err == nil {
-					success = true
-					break
-				}
-			}
+			_, err := s.icons.FetchIcon(ctx, iconName)
+			success := err == nil
 
 			mu.Lock()
 			if success {
@@ -152,72 +133,6 @@ func (s *IconService) downloadProcess() error {
 	return nil
 }
 
-func (s *IconService) downloadFile(url, name string) error {
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status %d", resp.StatusCode)
-	}
-
-	// Check content type and determine correct extension
-	ct := resp.Header.Get("Content-Type")
-	if !strings.Contains(ct, "image") {
-		return fmt.Errorf("invalid content type: %s", ct)
-	}
-
-	// Determine extension from Content-Type
-	ext := ".jpg"
-	if strings.Contains(ct, "png") {
-		ext = ".png"
-	}
-
-	filename := filepath.Join(s.outputDir, name+ext)
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	_, err = io.Copy(file, resp.Body)
-	return err
-}
-
-// DownloadSingleIcon downloads a single icon from URL to destination path
-func (s *IconService) DownloadSingleIcon(url, destPath string) error {
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("HTTP status %d", resp.StatusCode)
-	}
-
-	file, err := os.Create(destPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		os.Remove(destPath) // Clean up on error
-		return err
-	}
-
-	return nil
-}
-
 // ============================================================================
 // Icon Fix Methods
 // ============================================================================
@@ -225,22 +140,20 @@ func (s *IconService) DownloadSingleIcon(url, destPath string) error {
 // IconFixService handles fetching and fixing missing item icons
 type IconFixService struct {
 	db      *sql.DB
-	iconDir string
+	icons   *media.Cache
 	baseURL string
 	delayMs int
-	client  *http.Client
+	client  HttpClient
 }
 
 // NewIconFixService creates a new icon fix service
 func NewIconFixService(db *sql.DB, iconDir string) *IconFixService {
 	return &IconFixService{
 		db:      db,
-		iconDir: iconDir,
+		icons:   media.New(db, media.KindIcon, iconDir, SharedHTTPClient()),
 		baseURL: "https://database.turtlecraft.gg/?item=",
 		delayMs: 500, // Be nice to the server
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		client:  SharedHTTPClient(),
 	}
 }
 
@@ -302,10 +215,10 @@ func (s *IconFixService) GetMissingSpellIcons() ([]MissingIconItem, error) {
 }
 
 // FetchIconFromWebsite fetches icon name from Turtle WoW database website
-func (s *IconFixService) FetchIconFromWebsite(entry int) (string, error) {
+func (s *IconFixService) FetchIconFromWebsite(ctx context.Context, entry int) (string, error) {
 	url := fmt.Sprintf("%s%d", s.baseURL, entry)
 
-	resp, err := s.client.Get(url)
+	resp, err := getWithContext(ctx, s.client, url)
 	if err != nil {
 		return "", err
 	}
@@ -362,66 +275,13 @@ func (s *IconFixService) UpdateIconPath(entry int, iconName string) error {
 		INSERT INTO item_display_info (ID, icon) VALUES (?, ?)
 		ON CONFLICT(ID) DO UPDATE SET icon = excluded.icon
 	`, displayID, iconName)
-	return err
-}
-
-const (
-	TurtleIconPNG = "https://database.turtlecraft.gg/images/icons/large/%s.png"
-	TurtleIconJPG = "https://database.turtlecraft.gg/images/icons/large/%s.jpg"
-	WowheadIcon   = "https://wow.zamimg.com/images/wow/icons/large/%s.jpg"
-	TrinityIcon   = "https://aowow.trinitycore.info/static/images/wow/icons/large/%s.jpg"
-)
-
-// downloadIconFromSources attempts to download an icon from multiple sources
-func (s *IconFixService) downloadIconFromSources(iconName string) error {
-	sources := []string{
-		fmt.Sprintf(TurtleIconPNG, iconName),
-		fmt.Sprintf(TurtleIconJPG, iconName),
-		fmt.Sprintf(WowheadIcon, iconName),
-		fmt.Sprintf(TrinityIcon, iconName),
-	}
-
-	for _, url := range sources {
-		if err := s.downloadFile(url, iconName); err == nil {
-			return nil
-		}
-	}
-	return fmt.Errorf("failed to download icon %s from all sources", iconName)
-}
-
-// downloadFile downloads a single file and saves it with correct extension
-func (s *IconFixService) downloadFile(url, name string) error {
-	resp, err := s.client.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("status %d", resp.StatusCode)
-	}
-
-	// Determine extension from Content-Type
-	ct := resp.Header.Get("Content-Type")
-	ext := ".jpg"
-	if strings.Contains(ct, "png") {
-		ext = ".png"
-	}
-
-	filename := filepath.Join(s.iconDir, name+ext)
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	_, err = io.Copy(file, resp.Body)
+	repositories.InvalidateCache(s.db, "item_display_info")
 	return err
 }
 
 // FixSingleItem fixes icon for a single item (complete workflow)
 // Returns: success, iconName, error
-func (s *IconFixService) FixSingleItem(db *sql.DB, itemID int) (bool, string, error) {
+func (s *IconFixService) FixSingleItem(ctx context.Context, db *sql.DB, itemID int) (bool, string, error) {
 	// Check if item exists and join display info
 	var currentIcon string
 	err := db.QueryRow(`
@@ -448,13 +308,8 @@ func (s *IconFixService) FixSingleItem(db *sql.DB, itemID int) (bool, string, er
 	needFetch := true
 
 	if currentIcon != "" && !isPlaceholder {
-		// If DB has a valid icon, check if the file actually exists (JPG or PNG)
-		pathJPG := filepath.Join(s.iconDir, currentIconLower+".jpg")
-		pathPNG := filepath.Join(s.iconDir, currentIconLower+".png")
-		_, errJPG := os.Stat(pathJPG)
-		_, errPNG := os.Stat(pathPNG)
-
-		if errJPG == nil || errPNG == nil {
+		// If DB has a valid icon, check if the file actually exists
+		if s.icons.Path(currentIconLower) != "" {
 			return false, "", fmt.Errorf("[v2] already has valid icon: %s", currentIcon)
 		}
 		// File missing, skip fetch and use current value to redownload
@@ -464,7 +319,7 @@ func (s *IconFixService) FixSingleItem(db *sql.DB, itemID int) (bool, string, er
 
 	if needFetch {
 		// Fetch icon name from website
-		fetchedName, err := s.FetchIconFromWebsite(itemID)
+		fetchedName, err := s.FetchIconFromWebsite(ctx, itemID)
 		if err != nil {
 			return false, "", err
 		}
@@ -497,18 +352,10 @@ func (s *IconFixService) FixSingleItem(db *sql.DB, itemID int) (bool, string, er
 		}
 	}
 
-	// Check if file already exists with either extension before downloading
-	iconPathJPG := filepath.Join(s.iconDir, iconName+".jpg")
-	iconPathPNG := filepath.Join(s.iconDir, iconName+".png")
-	_, errJPG := os.Stat(iconPathJPG)
-	_, errPNG := os.Stat(iconPathPNG)
-
-	if os.IsNotExist(errJPG) && os.IsNotExist(errPNG) {
-		// Use shared download logic
-		if err := s.downloadIconFromSources(iconName); err != nil {
-			// Don't fail the whole operation if download fails, as we updated the DB
-			fmt.Printf("Warning: Failed to download icon %s: %v\n", iconName, err)
-		}
+	// Fetch is a no-op when the file is already cached
+	if _, err := s.icons.FetchIcon(ctx, iconName); err != nil {
+		// Don't fail the whole operation if download fails, as we updated the DB
+		fmt.Printf("Warning: Failed to download icon %s: %v\n", iconName, err)
 	}
 
 	return true, iconName, nil
@@ -521,12 +368,13 @@ func (s *IconFixService) UpdateSpellIcon(spellID int, iconName string) error {
 		SET iconName = ? 
 		WHERE entry = ?
 	`, iconName, spellID)
+	repositories.InvalidateCache(s.db, "spell_template")
 	return err
 }
 
 // FixSingleSpell fixes icon for a single spell (complete workflow)
 // Returns: success, iconName, error
-func (s *IconFixService) FixSingleSpell(db *sql.DB, spellID int) (bool, string, error) {
+func (s *IconFixService) FixSingleSpell(ctx context.Context, db *sql.DB, spellID int) (bool, string, error) {
 	// Check if spell exists
 	var currentIcon string
 	err := db.QueryRow("SELECT COALESCE(iconName, '') FROM spell_template WHERE entry = ?", spellID).Scan(&currentIcon)
@@ -549,12 +397,7 @@ func (s *IconFixService) FixSingleSpell(db *sql.DB, spellID int) (bool, string,
 	needFetch := true
 
 	if currentIcon != "" && !isPlaceholder {
-		pathJPG := filepath.Join(s.iconDir, currentIconLower+".jpg")
-		pathPNG := filepath.Join(s.iconDir, currentIconLower+".png")
-		_, errJPG := os.Stat(pathJPG)
-		_, errPNG := os.Stat(pathPNG)
-
-		if errJPG == nil || errPNG == nil {
+		if s.icons.Path(currentIconLower) != "" {
 			return false, "", fmt.Errorf("already has valid icon: %s", currentIcon)
 		}
 		iconName = currentIconLower
@@ -564,7 +407,7 @@ func (s *IconFixService) FixSingleSpell(db *sql.DB, spellID int) (bool, string,
 	if needFetch {
 		// Fetch icon name from website (note: spell uses ?spell= parameter)
 		url := fmt.Sprintf("https://database.turtlecraft.gg/?spell=%d", spellID)
-		resp, err := s.client.Get(url)
+		resp, err := getWithContext(ctx, s.client, url)
 		if err != nil {
 			return false, "", err
 		}
@@ -618,16 +461,8 @@ func (s *IconFixService) FixSingleSpell(db *sql.DB, spellID int) (bool, string,
 		}
 	}
 
-	// Check if file already exists with either extension before downloading
-	iconPathJPG := filepath.Join(s.iconDir, iconName+".jpg")
-	iconPathPNG := filepath.Join(s.iconDir, iconName+".png")
-	_, errJPG := os.Stat(iconPathJPG)
-	_, errPNG := os.Stat(iconPathPNG)
-
-	if os.IsNotExist(errJPG) && os.IsNotExist(errPNG) {
-		if err := s.downloadIconFromSources(iconName); err != nil {
-			fmt.Printf("Warning: Failed to download icon %s: %v\n", iconName, err)
-		}
+	if _, err := s.icons.FetchIcon(ctx, iconName); err != nil {
+		fmt.Printf("Warning: Failed to download icon %s: %v\n", iconName, err)
 	}
 
 	return true, iconName, nil
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/repositories"
+	"shelllab/backend/database/schema"
+)
+
+const (
+	// SeverityError marks data the app cannot display correctly
+	SeverityError = "error"
+	// SeverityWarning marks dangling references that are usually harmless
+	SeverityWarning = "warning"
+)
+
+// IntegrityCheck is one consistency rule over the game database
+type IntegrityCheck struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	// Hint explains what --fix does, or how to repair the data otherwise
+	Hint string `json:"hint"`
+
+	// find returns the number of offending rows and up to limit samples
+	find func(db *sql.DB, limit int) (int, []string, error)
+	// fix repairs the offending rows, nil when there is no safe repair
+	fix func(db *sql.DB) (int64, error)
+}
+
+// Fixable reports whether the check has a safe automatic repair
+func (c *IntegrityCheck) Fixable() bool {
+	return c.fix != nil
+}
+
+// IntegrityResult is the outcome of one check
+type IntegrityResult struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Severity    string   `json:"severity"`
+	Count       int      `json:"count"`
+	Samples     []string `json:"samples"`
+	Fixable     bool     `json:"fixable"`
+	Fixed       int64    `json:"fixed"`
+	Hint        string   `json:"hint"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// IntegrityReport is the outcome of a run
+type IntegrityReport struct {
+	CheckedAt string            `json:"checkedAt"`
+	Results   []IntegrityResult `json:"results"`
+	Errors    int               `json:"errors"`
+	Warnings  int               `json:"warnings"`
+}
+
+// IntegrityService runs the check catalogue against shelllab.db
+type IntegrityService struct {
+	db *sql.DB
+}
+
+// NewIntegrityService creates a new IntegrityService
+func NewIntegrityService(db *sql.DB) *IntegrityService {
+	return &IntegrityService{db: db}
+}
+
+// Run executes the checks with the given IDs (all when empty), collecting up
+// to samples example rows each. With fix, fixable checks are repaired and
+// re-counted.
+func (s *IntegrityService) Run(ids []string, samples int, fix bool) (*IntegrityReport, error) {
+	checks, err := SelectIntegrityChecks(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IntegrityReport{CheckedAt: time.Now().UTC().Format(time.RFC3339), Results: []IntegrityResult{}}
+	for _, c := range checks {
+		r := IntegrityResult{
+			ID:          c.ID,
+			Description: c.Description,
+			Severity:    c.Severity,
+			Fixable:     c.Fixable(),
+			Hint:        c.Hint,
+			Samples:     []string{},
+		}
+
+		count, found, err := c.find(s.db, samples)
+		if err == nil && fix && count > 0 && c.fix != nil {
+			if r.Fixed, err = c.fix(s.db); err == nil {
+				fmt.Printf("[Integrity] ✓ %s: repaired %d\n", c.ID, r.Fixed)
+				repositories.PurgeCache(s.db)
+				count, found, err = c.find(s.db, samples)
+			}
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		r.Count = count
+		if found != nil {
+			r.Samples = found
+		}
+
+		if r.Count > 0 || r.Error != "" {
+			if c.Severity == SeverityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		}
+		report.Results = append(report.Results, r)
+	}
+	return report, nil
+}
+
+// SelectIntegrityChecks returns the checks with the given IDs, all when empty
+func SelectIntegrityChecks(ids []string) ([]IntegrityCheck, error) {
+	all := IntegrityChecks()
+	if len(ids) == 0 {
+		return all, nil
+	}
+
+	byID := make(map[string]IntegrityCheck, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	var checks []IntegrityCheck
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown check %q", id)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// IntegrityChecks returns the check catalogue
+func IntegrityChecks() []IntegrityCheck {
+	checks := []IntegrityCheck{
+		{
+			ID:          "sqlite_integrity",
+			Description: "SQLite quick_check reports corruption",
+			Severity:    SeverityError,
+			Hint:        "Restore shelllab.db from the release or a backup",
+			find:        quickCheck,
+		},
+		{
+			ID:          "schema_migrations",
+			Description: "Schema migrations not applied",
+			Severity:    SeverityError,
+			Hint:        "--fix applies the pending migrations",
+			find:        pendingMigrations,
+			fix: func(db *sql.DB) (int64, error) {
+				applied, err := schema.NewMigrator(db).Migrate()
+				return int64(len(applied)), err
+			},
+		},
+		{
+			ID:          "item_missing_icon",
+			Description: "Items whose display_id has no item_display_info icon",
+			Severity:    SeverityWarning,
+			Hint:        "Run Fix Missing Icons from the Settings page",
+			find: sqlCheck(`
+				SELECT i.entry, i.name || ' (display_id ' || i.display_id || ')'
+				FROM item_template i
+				LEFT JOIN item_display_info di ON di.ID = i.display_id
+				WHERE i.display_id > 0 AND (di.ID IS NULL OR di.icon IS NULL OR di.icon = '')`),
+		},
+		{
+			ID:          "spell_missing_icon",
+			Description: "Spells with a spellIconId missing from spell_icons",
+			Severity:    SeverityWarning,
+			Hint:        "Re-import spell_icons or sync the spells",
+			find: sqlCheck(`
+				SELECT s.entry, s.name || ' (spellIconId ' || s.spellIconId || ')'
+				FROM spell_template s
+				WHERE s.spellIconId > 0 AND (s.iconName IS NULL OR s.iconName = '')
+				  AND NOT EXISTS (SELECT 1 FROM spell_icons si WHERE si.id = s.spellIconId)`),
+		},
+		{
+			ID:          "creature_missing_loot",
+			Description: "Creatures with a loot_id but no creature_loot_template rows",
+			Severity:    SeverityWarning,
+			Hint:        "Sync the NPC or re-import loot from MySQL",
+			find: sqlCheck(`
+				SELECT c.entry, c.name || ' (loot_id ' || c.loot_id || ')'
+				FROM creature_template c
+				WHERE c.loot_id > 0
+				  AND NOT EXISTS (SELECT 1 FROM creature_loot_template cl WHERE cl.entry = c.loot_id)`),
+		},
+		{
+			ID:          "quest_missing_prev",
+			Description: "Quests whose PrevQuestId does not exist",
+			Severity:    SeverityWarning,
+			Hint:        "Sync the quest chain",
+			find: sqlCheck(`
+				SELECT q.entry, q.Title || ' (PrevQuestId ' || q.PrevQuestId || ')'
+				FROM quest_template q
+				WHERE q.PrevQuestId != 0
+				  AND NOT EXISTS (SELECT 1 FROM quest_template p WHERE p.entry = ABS(q.PrevQuestId))`),
+		},
+		{
+			ID:          "quest_missing_next",
+			Description: "Quests whose NextQuestInChain does not exist",
+			Severity:    SeverityWarning,
+			Hint:        "Sync the quest chain",
+			find: sqlCheck(`
+				SELECT q.entry, q.Title || ' (NextQuestInChain ' || q.NextQuestInChain || ')'
+				FROM quest_template q
+				WHERE q.NextQuestInChain > 0
+				  AND NOT EXISTS (SELECT 1 FROM quest_template n WHERE n.entry = q.NextQuestInChain)`),
+		},
+		{
+			ID:          "quest_relation_orphan",
+			Description: "Quest giver/ender rows pointing to missing quests or creatures",
+			Severity:    SeverityWarning,
+			Hint:        "Re-import quest relations from MySQL",
+			find: sqlCheck(`
+				SELECT r.id, 'starts quest ' || r.quest FROM creature_questrelation r
+				WHERE NOT EXISTS (SELECT 1 FROM quest_template q WHERE q.entry = r.quest)
+				   OR NOT EXISTS (SELECT 1 FROM creature_template c WHERE c.entry = r.id)
+				UNION ALL
+				SELECT r.id, 'ends quest ' || r.quest FROM creature_involvedrelation r
+				WHERE NOT EXISTS (SELECT 1 FROM quest_template q WHERE q.entry = r.quest)
+				   OR NOT EXISTS (SELECT 1 FROM creature_template c WHERE c.entry = r.id)`),
+		},
+		{
+			ID:          "atlasloot_missing_item",
+			Description: "AtlasLoot entries whose item_id is not in item_template",
+			Severity:    SeverityWarning,
+			Hint:        "Sync the items or re-import AtlasLoot",
+			find: sqlCheck(`
+				SELECT a.item_id, COALESCE(t.table_key, 'table ' || a.table_id)
+				FROM atlasloot_items a
+				LEFT JOIN atlasloot_tables t ON t.id = a.table_id
+				WHERE a.item_id > 0
+				  AND NOT EXISTS (SELECT 1 FROM item_template i WHERE i.entry = a.item_id)`),
+		},
+		{
+			ID:          "category_missing_item",
+			Description: "Category entries whose item_id is not in item_template",
+			Severity:    SeverityWarning,
+			Hint:        "Sync the items or re-import categories",
+			find: sqlCheck(`
+				SELECT ci.item_id, 'category ' || ci.category_id
+				FROM category_items ci
+				WHERE NOT EXISTS (SELECT 1 FROM item_template i WHERE i.entry = ci.item_id)`),
+		},
+		{
+			ID:          "creature_metadata_orphan",
+			Description: "Scraped creature_metadata for creatures that do not exist",
+			Severity:    SeverityWarning,
+			Hint:        "--fix deletes the orphan rows; they are re-scraped if the creature returns",
+			find: sqlCheck(`
+				SELECT m.entry, COALESCE(m.zone_name, '')
+				FROM creature_metadata m
+				WHERE NOT EXISTS (SELECT 1 FROM creature_template c WHERE c.entry = m.entry)`),
+			fix: sqlFix(`DELETE FROM creature_metadata
+				WHERE NOT EXISTS (SELECT 1 FROM creature_template c WHERE c.entry = creature_metadata.entry)`),
+		},
+	}
+
+	// Dangling item and reference rows in every loot table
+	for _, table := range []string{
+		"creature_loot_template",
+		"gameobject_loot_template",
+		"item_loot_template",
+		"reference_loot_template",
+		"disenchant_loot_template",
+	} {
+		name := strings.TrimSuffix(table, "_loot_template")
+		checks = append(checks,
+			IntegrityCheck{
+				ID:          name + "_loot_missing_item",
+				Description: fmt.Sprintf("%s rows pointing to items not in item_template", table),
+				Severity:    SeverityWarning,
+				Hint:        "Sync the items or re-import loot from MySQL",
+				find: sqlCheck(fmt.Sprintf(`
+					SELECT l.entry, 'item ' || l.item
+					FROM %s l
+					WHERE l.mincountOrRef >= 0
+					  AND NOT EXISTS (SELECT 1 FROM item_template i WHERE i.entry = l.item)`, table)),
+			},
+			IntegrityCheck{
+				ID:          name + "_loot_missing_reference",
+				Description: fmt.Sprintf("%s rows pointing to missing reference_loot_template entries", table),
+				Severity:    SeverityWarning,
+				Hint:        "Re-import reference_loot_template from MySQL",
+				find: sqlCheck(fmt.Sprintf(`
+					SELECT l.entry, 'reference ' || -l.mincountOrRef
+					FROM %s l
+					WHERE l.mincountOrRef < 0
+					  AND NOT EXISTS (SELECT 1 FROM reference_loot_template r WHERE r.entry = -l.mincountOrRef)`, table)),
+			},
+		)
+	}
+	return checks
+}
+
+// sqlCheck builds a finder from a query selecting (id, description) per offending row
+func sqlCheck(query string) func(db *sql.DB, limit int) (int, []string, error) {
+	return func(db *sql.DB, limit int) (int, []string, error) {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM (" + query + ")").Scan(&count); err != nil {
+			return 0, nil, err
+		}
+		if count == 0 || limit <= 0 {
+			return count, nil, nil
+		}
+
+		rows, err := db.Query(fmt.Sprintf("%s LIMIT %d", query, limit))
+		if err != nil {
+			return count, nil, err
+		}
+		defer rows.Close()
+
+		var samples []string
+		for rows.Next() {
+			var id, desc sql.NullString
+			if err := rows.Scan(&id, &desc); err != nil {
+				return count, samples, err
+			}
+			samples = append(samples, fmt.Sprintf("%s: %s", id.String, desc.String))
+		}
+		return count, samples, rows.Err()
+	}
+}
+
+// sqlFix builds a repair from a single statement, run in a transaction
+func sqlFix(statement string) func(db *sql.DB) (int64, error) {
+	return func(db *sql.DB) (int64, error) {
+		tx, err := db.Begin()
+		if err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+
+		res, err := tx.Exec(statement)
+		if err != nil {
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return res.RowsAffected()
+	}
+}
+
+// quickCheck runs PRAGMA quick_check
+func quickCheck(db *sql.DB, limit int) (int, []string, error) {
+	rows, err := db.Query("PRAGMA quick_check")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	count := 0
+	var samples []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return count, samples, err
+		}
+		if msg == "ok" {
+			continue
+		}
+		count++
+		if len(samples) < limit {
+			samples = append(samples, msg)
+		}
+	}
+	return count, samples, rows.Err()
+}
+
+// pendingMigrations lists schema migrations not applied yet
+func pendingMigrations(db *sql.DB, limit int) (int, []string, error) {
+	pending, err := schema.NewMigrator(db).Pending()
+	if err != nil {
+		return 0, nil, err
+	}
+	var samples []string
+	for _, m := range pending {
+		if len(samples) < limit {
+			samples = append(samples, fmt.Sprintf("%d: %s", m.Version, m.Description))
+		}
+	}
+	return len(pending), samples, nil
+}
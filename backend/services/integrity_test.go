@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"shelllab/backend/services"
+)
+
+// integrityFixture breaks every check once, next to rows that are fine
+var integrityFixture = []string{
+	`INSERT INTO item_display_info (ID, icon) VALUES (20, 'inv_sword_04')`,
+	`INSERT INTO item_template (entry, name, display_id) VALUES (1, 'Iconless Sword', 10), (2, 'Sword', 20)`,
+	`INSERT INTO spell_icons (id, icon_name) VALUES (8, 'spell_fire')`,
+	`INSERT INTO spell_template (entry, name, spellIconId, iconName) VALUES
+		(100, 'Lost Spell', 7, ''), (101, 'Fireball', 8, ''), (102, 'Named Icon', 9, 'spell_frost')`,
+	`INSERT INTO creature_template (entry, name, loot_id) VALUES (300, 'Lootless', 300), (301, 'Looter', 301)`,
+	`INSERT INTO quest_template (entry, Title, PrevQuestId, NextQuestInChain) VALUES
+		(400, 'Orphan Follow-up', 999, 0), (401, 'Dead End', 0, 998), (402, 'Chained', -401, 400)`,
+	`INSERT INTO creature_questrelation (id, quest) VALUES (301, 997), (301, 400)`,
+	`INSERT INTO creature_involvedrelation (id, quest) VALUES (301, 401)`,
+	`INSERT INTO atlasloot_categories (id, name, display_name) VALUES (1, 'Instances', 'Instances')`,
+	`INSERT INTO atlasloot_modules (id, category_id, name, display_name) VALUES (1, 1, 'MoltenCore', 'Molten Core')`,
+	`INSERT INTO atlasloot_tables (id, module_id, table_key, display_name) VALUES (1, 1, 'MCRagnaros', 'Ragnaros')`,
+	`INSERT INTO atlasloot_items (table_id, item_id) VALUES (1, 996), (1, 2)`,
+	`INSERT INTO category_items (category_id, item_id) VALUES (5, 995), (5, 2)`,
+	`INSERT INTO creature_metadata (entry, zone_name) VALUES (900, 'Nowhere'), (301, 'Elwynn Forest')`,
+	`INSERT INTO creature_loot_template (entry, item, mincountOrRef) VALUES (301, 2, 1), (1, 994, 1), (1, 0, -993), (2, 0, -50)`,
+	`INSERT INTO gameobject_loot_template (entry, item, mincountOrRef) VALUES (1, 994, 1), (1, 0, -993), (2, 0, -50)`,
+	`INSERT INTO item_loot_template (entry, item, mincountOrRef) VALUES (1, 994, 1), (1, 0, -993), (2, 0, -50)`,
+	`INSERT INTO reference_loot_template (entry, item, mincountOrRef) VALUES (50, 2, 1), (1, 994, 1), (1, 0, -993)`,
+	`INSERT INTO disenchant_loot_template (entry, item, mincountOrRef) VALUES (1, 994, 1), (1, 0, -993), (2, 0, -50)`,
+}
+
+// integritySamples are the samples each check finds in integrityFixture
+var integritySamples = map[string][]string{
+	"sqlite_integrity":         nil,
+	"schema_migrations":        nil,
+	"item_missing_icon":        {"1: Iconless Sword (display_id 10)"},
+	"spell_missing_icon":       {"100: Lost Spell (spellIconId 7)"},
+	"creature_missing_loot":    {"300: Lootless (loot_id 300)"},
+	"quest_missing_prev":       {"400: Orphan Follow-up (PrevQuestId 999)"},
+	"quest_missing_next":       {"401: Dead End (NextQuestInChain 998)"},
+	"quest_relation_orphan":    {"301: starts quest 997"},
+	"atlasloot_missing_item":   {"996: MCRagnaros"},
+	"category_missing_item":    {"995: category 5"},
+	"creature_metadata_orphan": {"900: Nowhere"},
+}
+
+func init() {
+	for _, name := range []string{"creature", "gameobject", "item", "reference", "disenchant"} {
+		integritySamples[name+"_loot_missing_item"] = []string{"1: item 994"}
+		integritySamples[name+"_loot_missing_reference"] = []string{"1: reference 993"}
+	}
+}
+
+func TestIntegrityChecks(t *testing.T) {
+	db := openTestDB(t, integrityFixture...)
+	report, err := services.NewIntegrityService(db.DB()).Run(nil, 5, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Results) != len(integritySamples) {
+		t.Errorf("ran %d checks, want %d", len(report.Results), len(integritySamples))
+	}
+	for _, r := range report.Results {
+		t.Run(r.ID, func(t *testing.T) {
+			want, ok := integritySamples[r.ID]
+			if !ok {
+				t.Fatal("check has no fixture row")
+			}
+			if r.Error != "" {
+				t.Fatal(r.Error)
+			}
+			if r.Count != len(want) {
+				t.Errorf("count %d, want %d", r.Count, len(want))
+			}
+			if !slices.Equal(r.Samples, want) {
+				t.Errorf("samples %q, want %q", r.Samples, want)
+			}
+			if r.Fixed != 0 {
+				t.Errorf("fixed %d rows without --fix", r.Fixed)
+			}
+		})
+	}
+	if want := len(integritySamples) - 2; report.Warnings != want || report.Errors != 0 {
+		t.Errorf("%d errors, %d warnings, want 0 and %d", report.Errors, report.Warnings, want)
+	}
+}
+
+func TestIntegritySampleLimit(t *testing.T) {
+	db := openTestDB(t, integrityFixture...)
+	if _, err := db.DB().Exec(`INSERT INTO creature_metadata (entry, zone_name) VALUES (901, 'Nowhere'), (902, 'Nowhere')`); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := services.NewIntegrityService(db.DB()).Run([]string{"creature_metadata_orphan"}, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := report.Results[0]; r.Count != 3 || len(r.Samples) != 2 {
+		t.Errorf("count %d with %d samples, want 3 with 2", r.Count, len(r.Samples))
+	}
+
+	if _, err := services.NewIntegrityService(db.DB()).Run([]string{"no_such_check"}, 2, false); err == nil {
+		t.Error("ran an unknown check")
+	}
+}
+
+func TestIntegrityFix(t *testing.T) {
+	db := openTestDB(t, integrityFixture...)
+	report, err := services.NewIntegrityService(db.DB()).Run(nil, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range report.Results {
+		want := len(integritySamples[r.ID])
+		if r.ID == "creature_metadata_orphan" {
+			if r.Fixed != 1 || r.Count != 0 || len(r.Samples) != 0 {
+				t.Errorf("%s: fixed %d, re-counted %d %q, want 1 fixed and none left", r.ID, r.Fixed, r.Count, r.Samples)
+			}
+			continue
+		}
+		if r.Fixed != 0 || r.Count != want {
+			t.Errorf("%s: fixed %d, count %d, want 0 fixed and %d left", r.ID, r.Fixed, r.Count, want)
+		}
+	}
+
+	var entries []string
+	rows, err := db.DB().Query(`SELECT entry || ' ' || zone_name FROM creature_metadata ORDER BY entry`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e string
+		if err := rows.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if got := strings.Join(entries, ", "); got != "301 Elwynn Forest" {
+		t.Errorf("creature_metadata left %q, want only the existing creature", got)
+	}
+}
@@ -0,0 +1,591 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JobState is the lifecycle state of a sync job
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobPaused    JobState = "paused"
+	JobCompleted JobState = "completed"
+	JobFailed    JobState = "failed"
+	JobCancelled JobState = "cancelled"
+)
+
+// Job types handled by the scheduler
+const (
+	JobTypeItems  = "items"
+	JobTypeSpells = "spells"
+	JobTypeQuests = "quests"
+	JobTypeNpcs   = "npcs"
+)
+
+// maxJobErrors caps how many error messages are kept per job
+const maxJobErrors = 50
+
+// JobParams holds the options a job was submitted with
+type JobParams struct {
+	DelayMs   int  `json:"delayMs"`
+	FixIcons  bool `json:"fixIcons"`
+	StartFrom int  `json:"startFrom"`
+}
+
+// SyncJob is a persisted, resumable sync run
+type SyncJob struct {
+	ID         int64     `json:"id"`
+	Type       string    `json:"type"`
+	Params     JobParams `json:"params"`
+	State      JobState  `json:"state"`
+	Cursor     int       `json:"cursor"` // Last ID fully processed, resume starts after it
+	Total      int       `json:"total"`
+	Processed  int       `json:"processed"`
+	Updated    int       `json:"updated"`
+	Failed     int       `json:"failed"`
+	Errors     []string  `json:"errors"`
+	Message    string    `json:"message"`
+	CreatedAt  string    `json:"createdAt"`
+	StartedAt  string    `json:"startedAt,omitempty"`
+	UpdatedAt  string    `json:"updatedAt"`
+	FinishedAt string    `json:"finishedAt,omitempty"`
+}
+
+// IsActive returns true if the job has not reached a terminal state
+func (j *SyncJob) IsActive() bool {
+	return j.State == JobQueued || j.State == JobRunning || j.State == JobPaused
+}
+
+// ResumeFrom returns the first ID the job should process
+func (j *SyncJob) ResumeFrom() int {
+	if j.Cursor > 0 {
+		return j.Cursor + 1
+	}
+	return j.Params.StartFrom
+}
+
+// JobRunner executes a job starting at job.ResumeFrom(), reporting progress through ctl.
+// It must return promptly once ctx is cancelled.
+type JobRunner func(ctx context.Context, job *SyncJob, ctl *JobControl) (*FullSyncResult, error)
+
+// JobControl lets a runner checkpoint progress while it works
+type JobControl struct {
+	manager  *JobManager
+	job      *SyncJob
+	base     int // Processed count carried over from earlier runs
+	lastSave time.Time
+}
+
+// Progress records that current of total IDs are done
+func (c *JobControl) Progress(current, total int) {
+	c.manager.mu.Lock()
+	c.job.Processed = c.base + current
+	c.job.Total = c.base + total
+	c.manager.mu.Unlock()
+	c.saveThrottled()
+}
+
+// Checkpoint records that every ID up to and including id is done, so a
+// resumed run starts after it. Runners that finish IDs out of order (worker
+// pools) must pass a contiguous watermark, not the latest ID.
+func (c *JobControl) Checkpoint(id int) {
+	c.manager.mu.Lock()
+	if id > c.job.Cursor {
+		c.job.Cursor = id
+	}
+	c.manager.mu.Unlock()
+	c.saveThrottled()
+}
+
+// saveThrottled persists the job at most once a second
+func (c *JobControl) saveThrottled() {
+	c.manager.mu.Lock()
+	save := time.Since(c.lastSave) >= time.Second
+	if save {
+		c.lastSave = time.Now()
+	}
+	c.manager.mu.Unlock()
+
+	if save {
+		c.manager.save(c.job)
+		c.manager.notify(c.job)
+	}
+}
+
+// runningJob tracks the job currently executing
+type runningJob struct {
+	id       int64
+	cancel   context.CancelFunc
+	stopAs   JobState // Set by Pause/Cancel before cancelling
+	control  *JobControl
+	finished chan struct{}
+}
+
+// JobManager persists sync jobs and runs them one at a time so two syncs
+// never write the same tables concurrently
+type JobManager struct {
+	db      *sql.DB
+	runners map[string]JobRunner
+
+	mu      sync.Mutex
+	current *runningJob
+	wake    chan struct{}
+
+	// OnUpdate is called whenever a job changes (e.g. to emit a frontend event)
+	OnUpdate func(job *SyncJob)
+}
+
+// NewJobManager creates a new job manager
+func NewJobManager(db *sql.DB) *JobManager {
+	return &JobManager{
+		db:      db,
+		runners: make(map[string]JobRunner),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// InitSchema creates the sync_jobs table
+func (m *JobManager) InitSchema() error {
+	_, err := m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS sync_jobs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type TEXT NOT NULL,
+			params TEXT NOT NULL DEFAULT '{}',
+			state TEXT NOT NULL,
+			cursor INTEGER NOT NULL DEFAULT 0,
+			total INTEGER NOT NULL DEFAULT 0,
+			processed INTEGER NOT NULL DEFAULT 0,
+			updated INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0,
+			errors TEXT NOT NULL DEFAULT '[]',
+			message TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			started_at TEXT,
+			updated_at TEXT NOT NULL,
+			finished_at TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_sync_jobs_state ON sync_jobs(state);
+	`)
+	return err
+}
+
+// Register sets the runner for a job type
+func (m *JobManager) Register(jobType string, runner JobRunner) {
+	m.runners[jobType] = runner
+}
+
+// Start re-queues jobs interrupted by a restart and launches the scheduler
+func (m *JobManager) Start(ctx context.Context) {
+	now := time.Now().Format(time.RFC3339)
+	res, err := m.db.Exec(`UPDATE sync_jobs SET state = ?, message = 'Resumed after restart', updated_at = ? WHERE state = ?`,
+		JobQueued, now, JobRunning)
+	if err != nil {
+		fmt.Printf("[Jobs] ⚠ Failed to recover interrupted jobs: %v\n", err)
+	} else if n, _ := res.RowsAffected(); n > 0 {
+		fmt.Printf("[Jobs] Resuming %d interrupted job(s)\n", n)
+	}
+
+	go m.loop(ctx)
+	m.signal()
+}
+
+// Submit queues a new job. Only one active job per type is allowed.
+func (m *JobManager) Submit(jobType string, params JobParams) (*SyncJob, error) {
+	if _, ok := m.runners[jobType]; !ok {
+		return nil, fmt.Errorf("unknown job type: %s", jobType)
+	}
+
+	var existing int64
+	err := m.db.QueryRow(`SELECT id FROM sync_jobs WHERE type = ? AND state IN (?, ?, ?) LIMIT 1`,
+		jobType, JobQueued, JobRunning, JobPaused).Scan(&existing)
+	if err == nil {
+		return nil, fmt.Errorf("a %s sync is already active (job %d)", jobType, existing)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	paramsJSON, _ := json.Marshal(params)
+	now := time.Now().Format(time.RFC3339)
+	res, err := m.db.Exec(`INSERT INTO sync_jobs (type, params, state, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		jobType, string(paramsJSON), JobQueued, now, now)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := res.LastInsertId()
+
+	job, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("[Jobs] Queued %s job %d\n", jobType, id)
+	m.notify(job)
+	m.signal()
+	return job, nil
+}
+
+// Get returns a single job, with live counters if it is running
+func (m *JobManager) Get(id int64) (*SyncJob, error) {
+	m.mu.Lock()
+	if m.current != nil && m.current.id == id {
+		job := *m.current.control.job
+		m.mu.Unlock()
+		return &job, nil
+	}
+	m.mu.Unlock()
+
+	row := m.db.QueryRow(`SELECT `+jobColumns+` FROM sync_jobs WHERE id = ?`, id)
+	return scanJob(row)
+}
+
+// List returns the most recent jobs, newest first
+func (m *JobManager) List(limit int) ([]*SyncJob, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := m.db.Query(`SELECT `+jobColumns+` FROM sync_jobs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*SyncJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	// Overlay live counters of the running job
+	m.mu.Lock()
+	if m.current != nil {
+		for i, job := range jobs {
+			if job.ID == m.current.id {
+				live := *m.current.control.job
+				jobs[i] = &live
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	return jobs, nil
+}
+
+// Pause stops a queued or running job, keeping its cursor for later
+func (m *JobManager) Pause(id int64) error {
+	return m.stop(id, JobPaused)
+}
+
+// Cancel stops a job for good
+func (m *JobManager) Cancel(id int64) error {
+	return m.stop(id, JobCancelled)
+}
+
+// PauseRunning pauses whatever job is currently executing
+func (m *JobManager) PauseRunning() bool {
+	m.mu.Lock()
+	cur := m.current
+	m.mu.Unlock()
+	if cur == nil {
+		return false
+	}
+	return m.Pause(cur.id) == nil
+}
+
+// PauseType pauses the active job of one type, if any
+func (m *JobManager) PauseType(jobType string) bool {
+	var id int64
+	err := m.db.QueryRow(`SELECT id FROM sync_jobs WHERE type = ? AND state IN (?, ?) LIMIT 1`,
+		jobType, JobQueued, JobRunning).Scan(&id)
+	if err != nil {
+		return false
+	}
+	return m.Pause(id) == nil
+}
+
+// Resume re-queues a paused (or failed) job from its cursor
+func (m *JobManager) Resume(id int64) error {
+	job, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	if job.State != JobPaused && job.State != JobFailed {
+		return fmt.Errorf("job %d is %s, only paused or failed jobs can be resumed", id, job.State)
+	}
+
+	var conflict int64
+	err = m.db.QueryRow(`SELECT id FROM sync_jobs WHERE type = ? AND id != ? AND state IN (?, ?) LIMIT 1`,
+		job.Type, id, JobQueued, JobRunning).Scan(&conflict)
+	if err == nil {
+		return fmt.Errorf("another %s sync is already active (job %d)", job.Type, conflict)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// A failed run left its error in Message and at the end of Errors
+	if n := len(job.Errors); job.State == JobFailed && n > 0 && job.Errors[n-1] == job.Message {
+		job.Errors = job.Errors[:n-1]
+	}
+	job.FinishedAt = ""
+	job.State = JobQueued
+	job.Message = fmt.Sprintf("Resuming from %d", job.ResumeFrom())
+	if err := m.save(job); err != nil {
+		return err
+	}
+	m.notify(job)
+	m.signal()
+	return nil
+}
+
+func (m *JobManager) stop(id int64, state JobState) error {
+	m.mu.Lock()
+	if m.current != nil && m.current.id == id {
+		cur := m.current
+		cur.stopAs = state
+		m.mu.Unlock()
+		cur.cancel()
+		<-cur.finished
+		return nil
+	}
+
+	// Still holding the lock: run claims queued jobs under it too, so the
+	// job cannot start between reading its state and storing the new one
+	job, err := m.stopStored(id, state)
+	m.mu.Unlock()
+	if err != nil || job == nil {
+		return err
+	}
+	m.notify(job)
+	return nil
+}
+
+// stopStored sets the state of a job that is not running. Returns nil if
+// there was nothing to change. Called with m.mu held.
+func (m *JobManager) stopStored(id int64, state JobState) (*SyncJob, error) {
+	job, err := scanJob(m.db.QueryRow(`SELECT `+jobColumns+` FROM sync_jobs WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsActive() {
+		return nil, fmt.Errorf("job %d is already %s", id, job.State)
+	}
+	if state == JobPaused && job.State == JobPaused {
+		return nil, nil
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	job.State = state
+	job.UpdatedAt = now
+	if state == JobCancelled {
+		job.FinishedAt = now
+	}
+	_, err = m.db.Exec(`UPDATE sync_jobs SET state = ?, updated_at = ?, finished_at = ? WHERE id = ?`,
+		job.State, job.UpdatedAt, nullIfEmpty(job.FinishedAt), id)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (m *JobManager) signal() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop runs queued jobs one after another until ctx is done
+func (m *JobManager) loop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+		}
+
+		for ctx.Err() == nil {
+			job, err := m.nextQueued()
+			if err != nil || job == nil {
+				break
+			}
+			if err := m.run(ctx, job); err != nil {
+				fmt.Printf("[Jobs] ⚠ Failed to start job %d: %v\n", job.ID, err)
+				break
+			}
+		}
+	}
+}
+
+func (m *JobManager) nextQueued() (*SyncJob, error) {
+	row := m.db.QueryRow(`SELECT `+jobColumns+` FROM sync_jobs WHERE state = ? ORDER BY id LIMIT 1`, JobQueued)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// run claims a queued job and executes it. A job paused or cancelled since
+// it was read is skipped; the error is only for failing to claim it.
+func (m *JobManager) run(parent context.Context, job *SyncJob) error {
+	runner := m.runners[job.Type]
+	if runner == nil {
+		job.State = JobFailed
+		job.Message = fmt.Sprintf("No runner registered for %s", job.Type)
+		m.save(job)
+		m.notify(job)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	now := time.Now().Format(time.RFC3339)
+	job.State = JobRunning
+	if job.StartedAt == "" {
+		job.StartedAt = now
+	}
+	ctl := &JobControl{manager: m, job: job, base: job.Processed, lastSave: time.Now()}
+	cur := &runningJob{id: job.ID, cancel: cancel, control: ctl, finished: make(chan struct{})}
+	defer close(cur.finished)
+
+	// Claim the job and publish it as current in one step, so stop either
+	// finds it running or has already moved it out of the queue
+	m.mu.Lock()
+	res, err := m.db.Exec(`UPDATE sync_jobs SET state = ? WHERE id = ? AND state = ?`, JobRunning, job.ID, JobQueued)
+	if err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	m.current = cur
+	m.mu.Unlock()
+	m.save(job)
+	m.notify(job)
+
+	fmt.Printf("[Jobs] ▶ Running %s job %d from %d\n", job.Type, job.ID, job.ResumeFrom())
+	result, err := runner(ctx, job, ctl)
+
+	m.mu.Lock()
+	m.current = nil
+	stopAs := cur.stopAs
+	if result != nil {
+		if result.LastSyncedID > job.Cursor {
+			job.Cursor = result.LastSyncedID
+		}
+		job.Updated += result.Updated
+		job.Failed += result.Failed
+		job.Message = result.Message
+		job.Errors = appendJobErrors(job.Errors, result.Errors)
+	}
+	switch {
+	case stopAs != "":
+		job.State = stopAs
+		if stopAs == JobPaused {
+			job.Message = fmt.Sprintf("Paused, will resume from %d", job.ResumeFrom())
+		} else {
+			job.Message = "Cancelled by user"
+		}
+	case parent.Err() != nil:
+		// App shutting down: leave it running so Start re-queues it
+		job.State = JobRunning
+	case err != nil:
+		job.State = JobFailed
+		job.Message = err.Error()
+		job.Errors = appendJobErrors(job.Errors, []string{err.Error()})
+	default:
+		job.State = JobCompleted
+	}
+	if job.State == JobCompleted || job.State == JobCancelled || job.State == JobFailed {
+		job.FinishedAt = time.Now().Format(time.RFC3339)
+	}
+	m.mu.Unlock()
+
+	m.save(job)
+	m.notify(job)
+	fmt.Printf("[Jobs] ■ %s job %d %s (cursor %d)\n", job.Type, job.ID, job.State, job.Cursor)
+	return nil
+}
+
+func (m *JobManager) save(job *SyncJob) error {
+	m.mu.Lock()
+	paramsJSON, _ := json.Marshal(job.Params)
+	errorsJSON, _ := json.Marshal(job.Errors)
+	job.UpdatedAt = time.Now().Format(time.RFC3339)
+	args := []interface{}{
+		string(job.State), string(paramsJSON), job.Cursor, job.Total, job.Processed, job.Updated, job.Failed,
+		string(errorsJSON), job.Message, nullIfEmpty(job.StartedAt), job.UpdatedAt, nullIfEmpty(job.FinishedAt), job.ID,
+	}
+	m.mu.Unlock()
+
+	_, err := m.db.Exec(`
+		UPDATE sync_jobs SET state = ?, params = ?, cursor = ?, total = ?, processed = ?, updated = ?, failed = ?,
+			errors = ?, message = ?, started_at = ?, updated_at = ?, finished_at = ?
+		WHERE id = ?`, args...)
+	if err != nil {
+		fmt.Printf("[Jobs] ⚠ Failed to save job %d: %v\n", job.ID, err)
+	}
+	return err
+}
+
+func (m *JobManager) notify(job *SyncJob) {
+	if m.OnUpdate == nil {
+		return
+	}
+	m.mu.Lock()
+	snapshot := *job
+	m.mu.Unlock()
+	m.OnUpdate(&snapshot)
+}
+
+const jobColumns = `id, type, params, state, cursor, total, processed, updated, failed, errors, message,
+	created_at, COALESCE(started_at, ''), updated_at, COALESCE(finished_at, '')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (*SyncJob, error) {
+	var job SyncJob
+	var state, paramsJSON, errorsJSON string
+	err := row.Scan(&job.ID, &job.Type, &paramsJSON, &state, &job.Cursor, &job.Total, &job.Processed,
+		&job.Updated, &job.Failed, &errorsJSON, &job.Message, &job.CreatedAt, &job.StartedAt, &job.UpdatedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.State = JobState(state)
+	json.Unmarshal([]byte(paramsJSON), &job.Params)
+	json.Unmarshal([]byte(errorsJSON), &job.Errors)
+	if job.Errors == nil {
+		job.Errors = []string{}
+	}
+	return &job, nil
+}
+
+func appendJobErrors(existing, more []string) []string {
+	existing = append(existing, more...)
+	if len(existing) > maxJobErrors {
+		existing = existing[len(existing)-maxJobErrors:]
+	}
+	return existing
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
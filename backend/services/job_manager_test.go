@@ -0,0 +1,323 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"shelllab/backend/services"
+
+	_ "modernc.org/sqlite"
+)
+
+const lastJobID = 10
+
+// blockingRunner checkpoints IDs up to lastJobID in order. A fresh run stops
+// before blockAt and waits for its context to be cancelled, the way a real
+// sync is interrupted mid-run. Every run reports the ID it started from.
+func blockingRunner(blockAt int, started chan<- int) services.JobRunner {
+	return func(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+		from := job.ResumeFrom()
+		fresh := job.Cursor == 0
+		started <- from
+		for id := from; id <= lastJobID; id++ {
+			if id == blockAt && fresh {
+				<-ctx.Done()
+				return &services.FullSyncResult{LastSyncedID: id - 1}, nil
+			}
+			ctl.Progress(id-from+1, lastJobID-from+1)
+			ctl.Checkpoint(id)
+		}
+		return &services.FullSyncResult{LastSyncedID: lastJobID, Message: "done"}, nil
+	}
+}
+
+// waitForJob polls until cond holds for the stored job row
+func waitForJob(t *testing.T, db *sql.DB, id int64, cond func(state string, cursor int) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var state string
+		var cursor int
+		if err := db.QueryRow(`SELECT state, cursor FROM sync_jobs WHERE id = ?`, id).Scan(&state, &cursor); err != nil {
+			t.Fatal(err)
+		}
+		if cond(state, cursor) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not reach the expected state", id)
+}
+
+func TestJobResume(t *testing.T) {
+	const blockAt = 5
+
+	tests := []struct {
+		name string
+		// interrupt stops the blocked first run and returns the manager that should finish the job
+		interrupt func(t *testing.T, db *sql.DB, m *services.JobManager, stopApp context.CancelFunc, id int64, started chan int) *services.JobManager
+	}{
+		{
+			name: "pause and resume",
+			interrupt: func(t *testing.T, db *sql.DB, m *services.JobManager, stopApp context.CancelFunc, id int64, started chan int) *services.JobManager {
+				if err := m.Pause(id); err != nil {
+					t.Fatal(err)
+				}
+				job, err := m.Get(id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if job.State != services.JobPaused || job.ResumeFrom() != blockAt {
+					t.Errorf("paused job is %s, resumes from %d, want %s from %d", job.State, job.ResumeFrom(), services.JobPaused, blockAt)
+				}
+				if err := m.Resume(id); err != nil {
+					t.Fatal(err)
+				}
+				return m
+			},
+		},
+		{
+			name: "app restart re-queues the running job",
+			interrupt: func(t *testing.T, db *sql.DB, m *services.JobManager, stopApp context.CancelFunc, id int64, started chan int) *services.JobManager {
+				stopApp()
+				// Shutting down saves the cursor but leaves the job running for Start to pick up
+				waitForJob(t, db, id, func(state string, cursor int) bool {
+					return state == string(services.JobRunning) && cursor == blockAt-1
+				})
+
+				restarted := services.NewJobManager(db)
+				restarted.Register(services.JobTypeItems, blockingRunner(blockAt, started))
+				restarted.Start(t.Context())
+				return restarted
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t).DB()
+			started := make(chan int, 2)
+			m := services.NewJobManager(db)
+			if err := m.InitSchema(); err != nil {
+				t.Fatal(err)
+			}
+			m.Register(services.JobTypeItems, blockingRunner(blockAt, started))
+			appCtx, stopApp := context.WithCancel(t.Context())
+			defer stopApp()
+			m.Start(appCtx)
+
+			job, err := m.Submit(services.JobTypeItems, services.JobParams{StartFrom: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from := <-started; from != 1 {
+				t.Fatalf("first run started from %d, want 1", from)
+			}
+
+			m = tt.interrupt(t, db, m, stopApp, job.ID, started)
+			select {
+			case from := <-started:
+				if from != blockAt {
+					t.Errorf("resumed run started from %d, want %d", from, blockAt)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("job was not resumed")
+			}
+
+			waitForJob(t, db, job.ID, func(state string, cursor int) bool { return state == string(services.JobCompleted) })
+			done, err := m.Get(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if done.Cursor != lastJobID || done.Processed != lastJobID {
+				t.Errorf("cursor %d, processed %d, want %d", done.Cursor, done.Processed, lastJobID)
+			}
+		})
+	}
+}
+
+func TestResumeFailedJob(t *testing.T) {
+	db := openTestDB(t).DB()
+	m := services.NewJobManager(db)
+	if err := m.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	// The first run fails, the resumed one waits for release
+	runs := make(chan int, 2)
+	release := make(chan struct{})
+	m.Register(services.JobTypeItems, func(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+		runs <- job.ResumeFrom()
+		if job.Cursor == 0 {
+			ctl.Checkpoint(3)
+			return nil, errors.New("site is down")
+		}
+		<-release
+		return &services.FullSyncResult{LastSyncedID: lastJobID}, nil
+	})
+	m.Start(t.Context())
+
+	job, err := m.Submit(services.JobTypeItems, services.JobParams{StartFrom: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-runs
+	waitForJob(t, db, job.ID, func(state string, cursor int) bool { return state == string(services.JobFailed) })
+
+	if err := m.Resume(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if from := <-runs; from != 4 {
+		t.Errorf("resumed run started from %d, want 4", from)
+	}
+	resumed, err := m.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.State == services.JobFailed || resumed.FinishedAt != "" || slices.Contains(resumed.Errors, "site is down") {
+		t.Errorf("resumed job is %s, finished at %q, errors %v, want the failure cleared",
+			resumed.State, resumed.FinishedAt, resumed.Errors)
+	}
+
+	close(release)
+	waitForJob(t, db, job.ID, func(state string, cursor int) bool { return state == string(services.JobCompleted) })
+}
+
+// pausingClient answers every item, spell and quest page with a 404, except
+// that the first request for blockOn is reported on inFlight and hangs until
+// it is cancelled. requested returns the IDs asked for so far.
+func pausingClient(blockOn int, inFlight chan<- int) (client fakeClient, requested func() []int) {
+	var mu sync.Mutex
+	var ids []int
+	client = func(req *http.Request) (*http.Response, error) {
+		var id int
+		for _, key := range []string{"item", "spell", "quest"} {
+			if v := req.URL.Query().Get(key); v != "" {
+				id, _ = strconv.Atoi(v)
+			}
+		}
+		mu.Lock()
+		ids = append(ids, id)
+		first := slices.Index(ids, id) == len(ids)-1
+		mu.Unlock()
+
+		if id == blockOn && first {
+			inFlight <- id
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return respond(req, http.StatusNotFound, "")
+	}
+	requested = func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(ids)
+	}
+	return client, requested
+}
+
+func TestSyncJobRefetchesInFlightID(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobType string
+		rows    string
+		run     func(ctx context.Context, s *services.SyncService, job *services.SyncJob, ctl *services.JobControl) *services.FullSyncResult
+	}{
+		{
+			name:    "items",
+			jobType: services.JobTypeItems,
+			rows:    `INSERT INTO item_template (entry) VALUES (1), (2), (3), (4), (5)`,
+			run: func(ctx context.Context, s *services.SyncService, job *services.SyncJob, ctl *services.JobControl) *services.FullSyncResult {
+				progressCb := func(current, total int, itemID int, itemName string) { ctl.Progress(current, total) }
+				return s.FullSyncItems(ctx, 0, false, "", job.ResumeFrom(), progressCb, ctl.Checkpoint)
+			},
+		},
+		{
+			name:    "spells",
+			jobType: services.JobTypeSpells,
+			rows:    `INSERT INTO item_template (entry, spellid_1, spellid_2, spellid_3) VALUES (1, 1, 2, 0), (2, 3, 0, 0), (3, 4, 5, 0)`,
+			run: func(ctx context.Context, s *services.SyncService, job *services.SyncJob, ctl *services.JobControl) *services.FullSyncResult {
+				progressCb := func(current, total int, spellID int, name string) {
+					ctl.Progress(current, total)
+					ctl.Checkpoint(spellID)
+				}
+				return s.FullSyncSpells(ctx, 1, false, "", job.ResumeFrom(), progressCb)
+			},
+		},
+		{
+			name:    "quests",
+			jobType: services.JobTypeQuests,
+			rows:    `INSERT INTO quest_template (entry) VALUES (1), (2), (3), (4), (5)`,
+			run: func(ctx context.Context, s *services.SyncService, job *services.SyncJob, ctl *services.JobControl) *services.FullSyncResult {
+				progressCb := func(current, total int, questID int, title string) {
+					ctl.Progress(current, total)
+					ctl.Checkpoint(questID)
+				}
+				return s.FullSyncQuests(ctx, 1, job.ResumeFrom(), progressCb)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, tt.rows).DB()
+			inFlight := make(chan int, 1)
+			client, requested := pausingClient(3, inFlight)
+			syncService := services.NewSyncService(db)
+			syncService.SetClient(client)
+			syncService.SetWorkers(1)
+
+			m := services.NewJobManager(db)
+			if err := m.InitSchema(); err != nil {
+				t.Fatal(err)
+			}
+			m.Register(tt.jobType, func(ctx context.Context, job *services.SyncJob, ctl *services.JobControl) (*services.FullSyncResult, error) {
+				return tt.run(ctx, syncService, job, ctl), nil
+			})
+			m.Start(t.Context())
+
+			job, err := m.Submit(tt.jobType, services.JobParams{StartFrom: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-inFlight:
+			case <-time.After(5 * time.Second):
+				t.Fatal("ID 3 was never fetched")
+			}
+			if err := m.Pause(job.ID); err != nil {
+				t.Fatal(err)
+			}
+			paused, err := m.Get(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if paused.Cursor != 2 || paused.Updated != 0 || paused.Failed != 2 {
+				t.Errorf("paused at cursor %d with %d updated, %d failed, want cursor 2 with 0 updated, 2 failed",
+					paused.Cursor, paused.Updated, paused.Failed)
+			}
+
+			if err := m.Resume(job.ID); err != nil {
+				t.Fatal(err)
+			}
+			waitForJob(t, db, job.ID, func(state string, cursor int) bool { return state == string(services.JobCompleted) })
+			done, err := m.Get(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if done.Cursor != 5 || done.Updated != 0 || done.Failed != 5 {
+				t.Errorf("finished at cursor %d with %d updated, %d failed, want cursor 5 with 0 updated, 5 failed",
+					done.Cursor, done.Updated, done.Failed)
+			}
+			if got, want := requested(), []int{1, 2, 3, 3, 4, 5}; !slices.Equal(got, want) {
+				t.Errorf("requested IDs %v, want %v", got, want)
+			}
+		})
+	}
+}
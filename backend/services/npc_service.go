@@ -2,29 +2,26 @@ package services
 
 import (
 	"context"
-	"crypto/md5"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
 	"path/filepath"
 	"shelllab/backend/database"
-	"strings"
-	"sync/atomic"
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
+	"shelllab/backend/media"
 	"time"
 )
 
 type NpcService struct {
-	sqlite        *sql.DB
-	mysql         *database.MySQLConnection
-	scraper       *ScraperService
-	itemRepo      *database.ItemRepository
-	creatureRepo  *database.CreatureRepository
-	dataDir       string // Path to data directory for storing images
-	stopRequested atomic.Bool
+	sqlite       *sql.DB
+	mysql        *database.MySQLConnection
+	scraper      *ScraperService
+	itemRepo     *database.ItemRepository
+	creatureRepo *database.CreatureRepository
+	images       *media.Cache // Model and map images, in data/npc_images
+	changes      *repositories.ChangeLogRepository
 }
 
 func NewNpcService(sqlite *sql.DB, mysql *database.MySQLConnection, scraper *ScraperService, itemRepo *database.ItemRepository, creatureRepo *database.CreatureRepository, dataDir string) *NpcService {
@@ -34,7 +31,8 @@ func NewNpcService(sqlite *sql.DB, mysql *database.MySQLConnection, scraper *Scr
 		scraper:      scraper,
 		itemRepo:     itemRepo,
 		creatureRepo: creatureRepo,
-		dataDir:      dataDir,
+		images:       media.New(sqlite, media.KindImage, filepath.Join(dataDir, "npc_images"), SharedHTTPClient()),
+		changes:      repositories.NewChangeLogRepository(sqlite),
 	}
 }
 
@@ -77,6 +75,7 @@ type NpcFullDetails struct {
 	ZoneName      string            `json:"zoneName"` // New
 	X             float64           `json:"x"`        // New
 	Y             float64           `json:"y"`        // New
+	LootID        int               `json:"lootId"`
 	Loot          []NpcLoot         `json:"loot"`
 	Quests        []NpcQuest        `json:"quests"`
 	Abilities     []NpcAbility      `json:"abilities"`
@@ -201,6 +200,7 @@ func (s *NpcService) loadFromSQLite(entry int) (*NpcFullDetails, error) {
 	if lootID == 0 {
 		lootID = entry
 	}
+	details.LootID = lootID
 
 	// Fetch loot (Direct + Reference)
 	rows, err := s.sqlite.Query(`
@@ -335,6 +335,7 @@ func (s *NpcService) syncCreatureFromMySQL(entry int) error {
 	}
 
 	// Insert into SQLite (UPSERT)
+	recordChanges := trackChanges(s.changes, models.ChangeEntityNpc, models.ChangeSourceMySQL, "creature_template", "entry", entry)
 	_, err = s.sqlite.Exec(`
 		INSERT INTO creature_template (entry, name, subname, level_min, level_max, health_max, mana_max,
 			faction, rank, type, display_id1, gold_min, gold_max,
@@ -355,6 +356,8 @@ func (s *NpcService) syncCreatureFromMySQL(entry int) error {
 	if err != nil {
 		return fmt.Errorf("failed to insert creature into SQLite: %w", err)
 	}
+	repositories.InvalidateCache(s.sqlite, "creature_template")
+	recordChanges(name)
 
 	// Sync spawn coordinates from MySQL creature table
 	s.syncCreatureSpawnsFromMySQL(entry)
@@ -371,6 +374,13 @@ func (s *NpcService) syncCreatureFromMySQL(entry int) error {
 	return nil
 }
 
+// creatureName returns the local name of a creature for change log entries
+func (s *NpcService) creatureName(entry int) string {
+	var name string
+	s.sqlite.QueryRow("SELECT COALESCE(name, '') FROM creature_template WHERE entry = ?", entry).Scan(&name)
+	return name
+}
+
 // syncCreatureSpawnsFromMySQL syncs creature spawn coordinates from MySQL creature table
 func (s *NpcService) syncCreatureSpawnsFromMySQL(entry int) {
 	if s.mysql == nil {
@@ -537,21 +547,17 @@ func (s *NpcService) convertWorldToMapCoords(mapId, zoneId int, worldX, worldY f
 
 // getZoneNameFromID attempts to get zone name from zone ID
 func (s *NpcService) getZoneNameFromID(zoneId, mapId int) string {
-	// Try to get zone name from aowow_zones table in MySQL
+	// Try to get zone name from MySQL, cached as every spawn in a zone asks again
 	if s.mysql != nil {
-		var zoneName string
-		err := s.mysql.DB().QueryRow(`
-			SELECT name_loc0 FROM aowow.aowow_zones WHERE areatableID = ?
-		`, zoneId).Scan(&zoneName)
-		if err == nil && zoneName != "" {
-			return zoneName
+		key := repositories.ZoneKey{ZoneID: zoneId, MapID: mapId}
+		zoneName, err := repositories.CacheFor(s.sqlite).ZoneNames().Load(key, func() (string, error) {
+			// A missing row is cached, a failed query is not
+			return s.queryZoneName(zoneId, mapId)
+		})
+		if err != nil {
+			fmt.Printf("Warning: Could not look up zone %d (map %d) in MySQL: %v\n", zoneId, mapId, err)
 		}
-
-		// Fallback: Try map_template for instance maps
-		err = s.mysql.DB().QueryRow(`
-			SELECT map_name FROM map_template WHERE entry = ?
-		`, mapId).Scan(&zoneName)
-		if err == nil && zoneName != "" {
+		if zoneName != "" {
 			return zoneName
 		}
 	}
@@ -590,6 +596,32 @@ func (s *NpcService) getZoneNameFromID(zoneId, mapId int) string {
 	return ""
 }
 
+// queryZoneName reads a zone name from the aowow_zones table in MySQL,
+// falling back to map_template for instance maps
+func (s *NpcService) queryZoneName(zoneId, mapId int) (string, error) {
+	var zoneName string
+	aowowErr := s.mysql.DB().QueryRow(`
+		SELECT name_loc0 FROM aowow.aowow_zones WHERE areatableID = ?
+	`, zoneId).Scan(&zoneName)
+	if aowowErr == nil && zoneName != "" {
+		return zoneName, nil
+	}
+
+	err := s.mysql.DB().QueryRow(`
+		SELECT map_name FROM map_template WHERE entry = ?
+	`, mapId).Scan(&zoneName)
+	if err == nil && zoneName != "" {
+		return zoneName, nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if aowowErr != nil && !errors.Is(aowowErr, sql.ErrNoRows) {
+		return "", aowowErr
+	}
+	return "", nil
+}
+
 // syncSpellFromMySQL syncs a single spell from MySQL to SQLite
 func (s *NpcService) syncSpellFromMySQL(spellID int) {
 	// Check if already exists with description (simple check)
@@ -613,6 +645,7 @@ func (s *NpcService) syncSpellFromMySQL(spellID int) {
 	}
 
 	// Insert into SQLite
+	recordChanges := trackChanges(s.changes, models.ChangeEntitySpell, models.ChangeSourceMySQL, "spell_template", "entry", spellID)
 	_, err = s.sqlite.Exec(`
 		INSERT INTO spell_template (entry, name, description, spellIconId) VALUES (?, ?, ?, ?)
 		ON CONFLICT(entry) DO UPDATE SET name=excluded.name, description=excluded.description, spellIconId=excluded.spellIconId
@@ -620,6 +653,9 @@ func (s *NpcService) syncSpellFromMySQL(spellID int) {
 
 	if err != nil {
 		fmt.Printf("Warning: Failed to save spell %d to SQLite: %v\n", spellID, err)
+	} else {
+		repositories.InvalidateCache(s.sqlite, "spell_template")
+		recordChanges(name)
 	}
 
 	// Sync Icon if needed
@@ -640,7 +676,7 @@ func (s *NpcService) syncSpellFromMySQL(spellID int) {
 }
 
 // SyncAllCreatureSpawns syncs spawn points for all creatures
-func (s *NpcService) SyncAllCreatureSpawns(progressCb func(current, total int, id int)) error {
+func (s *NpcService) SyncAllCreatureSpawns(ctx context.Context, progressCb func(current, total int, id int)) error {
 	if s.mysql == nil {
 		return fmt.Errorf("no mysql connection")
 	}
@@ -662,6 +698,9 @@ func (s *NpcService) SyncAllCreatureSpawns(progressCb func(current, total int, i
 
 	total := len(entries)
 	for i, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		s.syncCreatureSpawnsFromMySQL(entry)
 		if progressCb != nil && i%10 == 0 { // Update every 10 items
 			progressCb(i+1, total, entry)
@@ -675,12 +714,17 @@ func (s *NpcService) SyncAllCreatureSpawns(progressCb func(current, total int, i
 	return nil
 }
 
-// FullSyncNpcs performs a full sync (scrape + DB) for all NPCs starting from a specific ID
-func (s *NpcService) FullSyncNpcs(startFrom int, delayMs int, progressCb func(current, total int, id int)) error {
+// FullSyncNpcs performs a full sync (scrape + DB) for all NPCs starting from a specific ID.
+// An NPC cut off by ctx is neither counted nor reported, so LastSyncedID is
+// always safe to resume after.
+func (s *NpcService) FullSyncNpcs(ctx context.Context, startFrom int, delayMs int, progressCb ProgressCallback) *FullSyncResult {
 	// Get all entries starting from startFrom
 	rows, err := s.sqlite.Query("SELECT entry FROM creature_template WHERE entry >= ? ORDER BY entry", startFrom)
 	if err != nil {
-		return err
+		return &FullSyncResult{
+			Message: fmt.Sprintf("Error querying NPCs: %v", err),
+			Errors:  []string{err.Error()},
+		}
 	}
 	defer rows.Close()
 
@@ -693,78 +737,125 @@ func (s *NpcService) FullSyncNpcs(startFrom int, delayMs int, progressCb func(cu
 	}
 
 	total := len(entries)
+	result := &FullSyncResult{
+		TotalItems:  total,
+		Errors:      []string{},
+		StartFromID: startFrom,
+	}
+
 	for i, entry := range entries {
 		// Check for stop request
-		if s.IsStopped() {
-			return nil
+		if ctx.Err() != nil {
+			result.Message = "Sync stopped by user"
+			return result
 		}
 
 		// Perform full sync (scrape + DB)
-		s.SyncNpcData(entry)
+		err := s.SyncNpcData(ctx, entry)
+		if ctx.Err() != nil {
+			// Cut off by a pause: don't count or report it, so a resumed run syncs it again
+			result.Message = "Sync stopped by user"
+			return result
+		}
+		if err != nil {
+			result.Failed++
+			if len(result.Errors) < 10 {
+				result.Errors = append(result.Errors, fmt.Sprintf("NPC %d: %v", entry, err))
+			}
+		} else {
+			result.Updated++
+		}
+		result.LastSyncedID = entry
 
 		if progressCb != nil {
-			progressCb(i+1, total, entry)
+			progressCb(i+1, total, entry, s.creatureName(entry))
 		}
 
 		if delayMs > 0 {
-			time.Sleep(time.Duration(delayMs) * time.Millisecond)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Duration(delayMs) * time.Millisecond):
+			}
 		}
 	}
 
-	return nil
+	result.Message = fmt.Sprintf("Full NPC sync complete: %d synced, %d failed", result.Updated, result.Failed)
+	return result
 }
 
-func (s *NpcService) SyncNpcData(entry int) error {
+// SyncNpcData scrapes the metadata of an NPC and syncs its template, loot,
+// quests and spawns from MySQL. A failed scrape keeps the stored metadata and
+// is returned once the MySQL sync is done.
+func (s *NpcService) SyncNpcData(ctx context.Context, entry int) error {
 	// A. Scrape Wowhead for Metadata
-	scrapedData, err := s.scraper.ScrapeNpcData(entry)
-	if err != nil {
-		fmt.Printf("Scrape failed: %v\n", err)
-		scrapedData = &ScrapedNpcData{Infobox: make(map[string]string)}
+	// A failed or cut-off scrape must not overwrite the stored metadata with
+	// an empty row; a cancelled scrape may even return one source's half
+	scrapedData, scrapeErr := s.scraper.ScrapeNpcData(ctx, entry)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if scrapeErr != nil {
+		scrapeErr = fmt.Errorf("failed to scrape NPC %d: %w", entry, scrapeErr)
+		fmt.Printf("[SyncNpcData] %v, keeping its metadata\n", scrapeErr)
+	} else if err := s.saveMetadata(ctx, entry, scrapedData); err != nil {
+		return err
 	}
 
-	// B. Download images to local storage
-	npcImagesDir := filepath.Join(s.dataDir, "npc_images")
-	if err := os.MkdirAll(npcImagesDir, 0755); err != nil {
-		fmt.Printf("Warning: Failed to create npc_images directory: %v\n", err)
+	// B. Sync from MySQL (if available)
+	if s.mysql != nil {
+		s.syncFromMySQL(entry)
+	} else {
+		// Even if MySQL is missing, try to generate spawn from scraped metadata
+		fmt.Printf("[SyncNpcData] No MySQL connection. Attempting to use scraped spawn data for %d...\n", entry)
+		s.syncCreatureSpawnsFromMySQL(entry)
 	}
 
-	// Download model image using Hash-based filename for deduplication
+	return scrapeErr
+}
+
+// SetImageClient sets the client NPC model and map images are downloaded with
+func (s *NpcService) SetImageClient(client HttpClient) {
+	s.images = media.New(s.sqlite, media.KindImage, s.images.Dir(), client)
+}
+
+// saveMetadata downloads the images of scraped NPC metadata and stores it.
+// A failed image download keeps the local copy stored before.
+func (s *NpcService) saveMetadata(ctx context.Context, entry int, scrapedData *ScrapedNpcData) error {
+	// Download images to local storage (content-addressed, so shared images are stored once)
 	localModelPath := ""
 	if scrapedData.ModelImageURL != "" {
-		localModelPath = s.downloadImage(scrapedData.ModelImageURL, npcImagesDir, "")
+		localModelPath = s.downloadImage(ctx, scrapedData.ModelImageURL)
 		if localModelPath != "" {
 			fmt.Printf("[DEBUG] Model image synced: %s\n", localModelPath)
 		}
 	}
 
-	// Download map image using Hash-based filename for deduplication
 	localMapPath := ""
 	if scrapedData.MapURL != "" {
-		localMapPath = s.downloadImage(scrapedData.MapURL, npcImagesDir, "")
+		localMapPath = s.downloadImage(ctx, scrapedData.MapURL)
 		if localMapPath != "" {
 			fmt.Printf("[DEBUG] Map image synced: %s\n", localMapPath)
 		}
 	}
 
+	// Image downloads cut off by ctx would store empty local paths
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
 	// Store Metadata to SQLite
-	// Ensure columns exist (quick dirty adjustment)
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN model_image_url TEXT")
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN model_image_local TEXT")
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN map_image_local TEXT")
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN zone_name TEXT")
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN x REAL")
-	_, _ = s.sqlite.Exec("ALTER TABLE creature_metadata ADD COLUMN y REAL")
 
 	infoboxBytes, _ := json.Marshal(scrapedData.Infobox)
-	_, err = s.sqlite.Exec(`
+	recordMetadata := trackDetailChanges(s.changes, models.ChangeEntityNpc, models.ChangeSourceScrape, "creature_metadata", "entry", entry)
+	_, err := s.sqlite.Exec(`
 		INSERT INTO creature_metadata (entry, map_url, infobox_json, model_image_url, model_image_local, map_image_local, zone_name, x, y)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(entry) DO UPDATE SET
 			map_url = excluded.map_url,
 			infobox_json = excluded.infobox_json,
 			model_image_url = excluded.model_image_url,
-			model_image_local = excluded.model_image_local,
-			map_image_local = excluded.map_image_local,
+			model_image_local = COALESCE(NULLIF(excluded.model_image_local, ''), model_image_local),
+			map_image_local = COALESCE(NULLIF(excluded.map_image_local, ''), map_image_local),
 			zone_name = excluded.zone_name,
 			x = excluded.x,
 			y = excluded.y
@@ -772,135 +863,133 @@ func (s *NpcService) SyncNpcData(entry int) error {
 	if err != nil {
 		return fmt.Errorf("failed to save metadata: %w", err)
 	}
-	// ...
-
-	// B. Sync from MySQL (if available)
-	if s.mysql != nil {
-		// 1. creature_template
-		// Read 20+ columns needed or just use `SELECT *` map?
-		// For simplicity, let's fetch key columns including spells
-		var name, subname string
-		var lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction int
-		var typeId, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax int
-		var dmgMin, dmgMax float64
-
-		// Note: Column names in MySQL might differ slightly (e.g. Health vs health_max)
-		// ShellLab uses `creature_template` structure.
-		// Let's assume standard names.
-		query := `
-			SELECT 
-				name, subname, loot_id, 
-				spell1, spell2, spell3, spell4, 
-				minlevel, maxlevel, maxhealth, maxmana, 
-				rank, faction_A, type,
-				mindmg, maxdmg, armor,
-				resistance1, resistance2, resistance3, resistance4, resistance5, resistance6,
-				modelid1, mingold, maxgold
-			FROM creature_template WHERE entry = ?`
-
-		// Adjust query based on actual MySQL schema if needed.
-		// Trying a best-effort simpler query matching what we usually have.
-		err = s.mysql.DB().QueryRow(query, entry).Scan(
-			&name, &subname, &lootID,
-			&s1, &s2, &s3, &s4,
-			&minLvl, &maxLvl, &hpMax, &manaMax,
-			&rank, &faction, &typeId,
-			&dmgMin, &dmgMax, &armor,
-			&holy, &fire, &nature, &frost, &shadow, &arcane,
-			&displayId, &goldMin, &goldMax,
-		)
+	recordMetadata(s.creatureName(entry))
+	return nil
+}
 
-		if err == nil {
-			// Update SQLite creature_template
-			// We use INSERT OR REPLACE to update all these stats
-			_, _ = s.sqlite.Exec(`
-				UPDATE creature_template SET 
-					name=?, subname=?, loot_id=?,
-					spell_id1=?, spell_id2=?, spell_id3=?, spell_id4=?,
-					level_min=?, level_max=?, health_max=?, mana_max=?,
-					rank=?, faction=?, type=?,
-					dmg_min=?, dmg_max=?, armor=?,
-					holy_res=?, fire_res=?, nature_res=?, frost_res=?, shadow_res=?, arcane_res=?,
-					display_id1=?, gold_min=?, gold_max=?
-				WHERE entry=?
-			`, name, subname, lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction, typeId,
-				dmgMin, dmgMax, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax, entry)
-
-			// If it didn't exist (updated 0 rows), insert it
-			// This might fail if row doesn't exist.
-			// Ideally we rely on the large import, but for dev sync:
-			_, _ = s.sqlite.Exec(`
-				INSERT INTO creature_template 
-				(entry, name, subname, loot_id, spell_id1, spell_id2, spell_id3, spell_id4, level_min, level_max, health_max, mana_max, rank, faction, type,
-				 dmg_min, dmg_max, armor, holy_res, fire_res, nature_res, frost_res, shadow_res, arcane_res, display_id1, gold_min, gold_max)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-				ON CONFLICT(entry) DO UPDATE SET
-					name=excluded.name, subname=excluded.subname, loot_id=excluded.loot_id,
-					spell_id1=excluded.spell_id1, spell_id2=excluded.spell_id2, spell_id3=excluded.spell_id3, spell_id4=excluded.spell_id4,
-					dmg_min=excluded.dmg_min, dmg_max=excluded.dmg_max, display_id1=excluded.display_id1,
-					gold_min=excluded.gold_min, gold_max=excluded.gold_max
-			`, entry, name, subname, lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction, typeId,
-				dmgMin, dmgMax, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax)
-		}
+// syncFromMySQL copies the template, loot, quest relations and spawns of an NPC from MySQL
+func (s *NpcService) syncFromMySQL(entry int) {
+	// 1. creature_template
+	// Read 20+ columns needed or just use `SELECT *` map?
+	// For simplicity, let's fetch key columns including spells
+	var name, subname string
+	var lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction int
+	var typeId, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax int
+	var dmgMin, dmgMax float64
+
+	// Note: Column names in MySQL might differ slightly (e.g. Health vs health_max)
+	// ShellLab uses `creature_template` structure.
+	// Let's assume standard names.
+	query := `
+		SELECT 
+			name, subname, loot_id, 
+			spell1, spell2, spell3, spell4, 
+			minlevel, maxlevel, maxhealth, maxmana, 
+			rank, faction_A, type,
+			mindmg, maxdmg, armor,
+			resistance1, resistance2, resistance3, resistance4, resistance5, resistance6,
+			modelid1, mingold, maxgold
+		FROM creature_template WHERE entry = ?`
+
+	// Adjust query based on actual MySQL schema if needed.
+	// Trying a best-effort simpler query matching what we usually have.
+	err := s.mysql.DB().QueryRow(query, entry).Scan(
+		&name, &subname, &lootID,
+		&s1, &s2, &s3, &s4,
+		&minLvl, &maxLvl, &hpMax, &manaMax,
+		&rank, &faction, &typeId,
+		&dmgMin, &dmgMax, &armor,
+		&holy, &fire, &nature, &frost, &shadow, &arcane,
+		&displayId, &goldMin, &goldMax,
+	)
 
-		// 2. Loot
-		if lootID > 0 {
-			// Fetch from MySQL loot tables and insert into SQLite creature_loot_template
-			// Note: ensure column names match MySQL `creature_loot_template`
-			lRows, lErr := s.mysql.DB().Query("SELECT Item, Chance, MinCount, MaxCount, GroupId FROM creature_loot_template WHERE Entry = ?", lootID)
-			if lErr == nil {
-				defer lRows.Close()
-				s.sqlite.Exec("DELETE FROM creature_loot_template WHERE entry = ?", entry)
-
-				for lRows.Next() {
-					var item, min, max, group int
-					var chance float64
-					if err := lRows.Scan(&item, &chance, &min, &max, &group); err == nil {
-						s.sqlite.Exec(`
-							INSERT INTO creature_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount, groupid)
-							VALUES (?, ?, ?, ?, ?, ?)
-						`, entry, item, chance, min, max, group)
-					}
+	if err == nil {
+		recordChanges := trackChanges(s.changes, models.ChangeEntityNpc, models.ChangeSourceMySQL, "creature_template", "entry", entry)
+
+		// Update SQLite creature_template
+		// We use INSERT OR REPLACE to update all these stats
+		_, _ = s.sqlite.Exec(`
+			UPDATE creature_template SET 
+				name=?, subname=?, loot_id=?,
+				spell_id1=?, spell_id2=?, spell_id3=?, spell_id4=?,
+				level_min=?, level_max=?, health_max=?, mana_max=?,
+				rank=?, faction=?, type=?,
+				dmg_min=?, dmg_max=?, armor=?,
+				holy_res=?, fire_res=?, nature_res=?, frost_res=?, shadow_res=?, arcane_res=?,
+				display_id1=?, gold_min=?, gold_max=?
+			WHERE entry=?
+		`, name, subname, lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction, typeId,
+			dmgMin, dmgMax, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax, entry)
+
+		// If it didn't exist (updated 0 rows), insert it
+		// This might fail if row doesn't exist.
+		// Ideally we rely on the large import, but for dev sync:
+		_, _ = s.sqlite.Exec(`
+			INSERT INTO creature_template 
+			(entry, name, subname, loot_id, spell_id1, spell_id2, spell_id3, spell_id4, level_min, level_max, health_max, mana_max, rank, faction, type,
+			 dmg_min, dmg_max, armor, holy_res, fire_res, nature_res, frost_res, shadow_res, arcane_res, display_id1, gold_min, gold_max)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(entry) DO UPDATE SET
+				name=excluded.name, subname=excluded.subname, loot_id=excluded.loot_id,
+				spell_id1=excluded.spell_id1, spell_id2=excluded.spell_id2, spell_id3=excluded.spell_id3, spell_id4=excluded.spell_id4,
+				dmg_min=excluded.dmg_min, dmg_max=excluded.dmg_max, display_id1=excluded.display_id1,
+				gold_min=excluded.gold_min, gold_max=excluded.gold_max
+		`, entry, name, subname, lootID, s1, s2, s3, s4, minLvl, maxLvl, hpMax, manaMax, rank, faction, typeId,
+			dmgMin, dmgMax, armor, holy, fire, nature, frost, shadow, arcane, displayId, goldMin, goldMax)
+		recordChanges(name)
+	}
+
+	// 2. Loot
+	if lootID > 0 {
+		// Fetch from MySQL loot tables and insert into SQLite creature_loot_template
+		// Note: ensure column names match MySQL `creature_loot_template`
+		lRows, lErr := s.mysql.DB().Query("SELECT Item, Chance, MinCount, MaxCount, GroupId FROM creature_loot_template WHERE Entry = ?", lootID)
+		if lErr == nil {
+			defer lRows.Close()
+			s.sqlite.Exec("DELETE FROM creature_loot_template WHERE entry = ?", entry)
+
+			for lRows.Next() {
+				var item, min, max, group int
+				var chance float64
+				if err := lRows.Scan(&item, &chance, &min, &max, &group); err == nil {
+					s.sqlite.Exec(`
+						INSERT INTO creature_loot_template (entry, item, ChanceOrQuestChance, mincountOrRef, maxcount, groupid)
+						VALUES (?, ?, ?, ?, ?, ?)
+					`, entry, item, chance, min, max, group)
 				}
 			}
 		}
+	}
 
-		// 3. Quests (Starts/Ends)
-		// Starts
-		qsRows, qsErr := s.mysql.DB().Query("SELECT quest FROM creature_questrelation WHERE id = ?", entry)
-		if qsErr == nil {
-			defer qsRows.Close()
-			s.sqlite.Exec("DELETE FROM creature_questrelation WHERE id = ?", entry)
-			for qsRows.Next() {
-				var q int
-				if err := qsRows.Scan(&q); err == nil {
-					s.sqlite.Exec("INSERT INTO creature_questrelation (id, quest) VALUES (?,?)", entry, q)
-				}
+	// 3. Quests (Starts/Ends)
+	// Starts
+	qsRows, qsErr := s.mysql.DB().Query("SELECT quest FROM creature_questrelation WHERE id = ?", entry)
+	if qsErr == nil {
+		defer qsRows.Close()
+		s.sqlite.Exec("DELETE FROM creature_questrelation WHERE id = ?", entry)
+		for qsRows.Next() {
+			var q int
+			if err := qsRows.Scan(&q); err == nil {
+				s.sqlite.Exec("INSERT INTO creature_questrelation (id, quest) VALUES (?,?)", entry, q)
 			}
 		}
-		// Ends
-		qeRows, qeErr := s.mysql.DB().Query("SELECT quest FROM creature_involvedrelation WHERE id = ?", entry)
-		if qeErr == nil {
-			defer qeRows.Close()
-			s.sqlite.Exec("DELETE FROM creature_involvedrelation WHERE id = ?", entry)
-			for qeRows.Next() {
-				var q int
-				if err := qeRows.Scan(&q); err == nil {
-					s.sqlite.Exec("INSERT INTO creature_involvedrelation (id, quest) VALUES (?,?)", entry, q)
-				}
+	}
+	// Ends
+	qeRows, qeErr := s.mysql.DB().Query("SELECT quest FROM creature_involvedrelation WHERE id = ?", entry)
+	if qeErr == nil {
+		defer qeRows.Close()
+		s.sqlite.Exec("DELETE FROM creature_involvedrelation WHERE id = ?", entry)
+		for qeRows.Next() {
+			var q int
+			if err := qeRows.Scan(&q); err == nil {
+				s.sqlite.Exec("INSERT INTO creature_involvedrelation (id, quest) VALUES (?,?)", entry, q)
 			}
 		}
-
-		// 4. Sync spawn coordinates from creature table
-		fmt.Printf("[SyncNpcData] Syncing spawn coordinates for creature %d...\n", entry)
-		s.syncCreatureSpawnsFromMySQL(entry)
-	} else {
-		// Even if MySQL is missing, try to generate spawn from scraped metadata
-		fmt.Printf("[SyncNpcData] No MySQL connection. Attempting to use scraped spawn data for %d...\n", entry)
-		s.syncCreatureSpawnsFromMySQL(entry)
 	}
 
-	return nil
+	// 4. Sync spawn coordinates from creature table
+	fmt.Printf("[SyncNpcData] Syncing spawn coordinates for creature %d...\n", entry)
+	s.syncCreatureSpawnsFromMySQL(entry)
 }
 
 // GetNpcDetailsContext adds a context-aware version if needed for Wails
@@ -908,86 +997,13 @@ func (s *NpcService) GetNpcDetailsContext(ctx context.Context, entry int) (*NpcF
 	return s.GetNpcDetails(entry)
 }
 
-// downloadImage downloads an image from URL and saves it locally using MD5 hash of URL as filename for deduplication
-func (s *NpcService) downloadImage(url string, dir string, _ string) string {
-	if url == "" {
-		return ""
-	}
-
-	// Compute MD5 hash of URL for deduplication
-	hash := md5.Sum([]byte(url))
-	hashName := hex.EncodeToString(hash[:])
-
-	// Determine file extension from URL
-	ext := ".jpg"
-	if strings.Contains(strings.ToLower(url), ".png") {
-		ext = ".png"
-	} else if strings.Contains(strings.ToLower(url), ".gif") {
-		ext = ".gif"
-	} else if strings.Contains(strings.ToLower(url), ".webp") {
-		ext = ".webp"
-	}
-
-	localPath := filepath.Join(dir, hashName+ext)
-
-	// Skip if file already exists (DEDUPLICATION)
-	if _, err := os.Stat(localPath); err == nil {
-		return localPath
-	}
-
-	// Download the image
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		fmt.Printf("Failed to create request for %s: %v\n", url, err)
-		return ""
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-	resp, err := client.Do(req)
+// downloadImage returns the local copy of an image URL, downloading it into
+// the image cache when needed. Returns "" when the download fails.
+func (s *NpcService) downloadImage(ctx context.Context, url string) string {
+	path, err := s.images.FetchImage(ctx, url)
 	if err != nil {
 		fmt.Printf("Failed to download image from %s: %v\n", url, err)
 		return ""
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		fmt.Printf("Failed to download image from %s: HTTP %d\n", url, resp.StatusCode)
-		return ""
-	}
-
-	// Create file
-	file, err := os.Create(localPath)
-	if err != nil {
-		fmt.Printf("Failed to create file %s: %v\n", localPath, err)
-		return ""
-	}
-	defer file.Close()
-
-	// Copy data
-	_, err = io.Copy(file, resp.Body)
-	if err != nil {
-		fmt.Printf("Failed to save image to %s: %v\n", localPath, err)
-		os.Remove(localPath) // Clean up partial file
-		return ""
-	}
-
-	return localPath
-}
-
-// RequestStop signals the sync process to stop
-func (s *NpcService) RequestStop() {
-	s.stopRequested.Store(true)
-}
-
-// IsStopped returns true if stop was requested
-func (s *NpcService) IsStopped() bool {
-	return s.stopRequested.Load()
-}
-
-// ResetStop resets the stop signal
-func (s *NpcService) ResetStop() {
-	s.stopRequested.Store(false)
+	return path
 }
@@ -0,0 +1,130 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"shelllab/backend/services"
+)
+
+// scrapeClient fails every request, or with block set, hangs until the
+// request is cancelled and reports it on inFlight
+func scrapeClient(block bool, inFlight chan<- struct{}) fakeClient {
+	return func(req *http.Request) (*http.Response, error) {
+		if !block {
+			return nil, errors.New("offline")
+		}
+		select {
+		case inFlight <- struct{}{}:
+		default:
+		}
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+}
+
+func TestSyncNpcDataKeepsMetadataOnFailedScrape(t *testing.T) {
+	tests := []struct {
+		name  string
+		block bool
+	}{
+		{name: "scrape failed", block: false},
+		{name: "scrape cancelled", block: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t,
+				`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger')`,
+				`INSERT INTO creature_metadata (entry, map_url, infobox_json, model_image_url, model_image_local, map_image_local, zone_name, x, y)
+					VALUES (1, 'map', '{"Level":"11"}', 'model', 'model.png', 'map.png', 'Elwynn Forest', 25.5, 78.1)`,
+			).DB()
+
+			inFlight := make(chan struct{}, 1)
+			scraper := services.NewScraperService()
+			scraper.Client = scrapeClient(tt.block, inFlight)
+			npcs := services.NewNpcService(db, nil, scraper, nil, nil, t.TempDir())
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			if tt.block {
+				go func() {
+					select {
+					case <-inFlight:
+					case <-time.After(5 * time.Second):
+					}
+					cancel()
+				}()
+			}
+
+			if err := npcs.SyncNpcData(ctx, 1); err == nil {
+				t.Error("expected an error")
+			}
+			var zone, infobox string
+			if err := db.QueryRow(`SELECT zone_name, infobox_json FROM creature_metadata WHERE entry = 1`).Scan(&zone, &infobox); err != nil {
+				t.Fatal(err)
+			}
+			if zone != "Elwynn Forest" || infobox != `{"Level":"11"}` {
+				t.Errorf("metadata overwritten: zone %q, infobox %s", zone, infobox)
+			}
+		})
+	}
+}
+
+func TestSyncNpcDataKeepsLocalImagesOnFailedDownload(t *testing.T) {
+	db := openTestDB(t,
+		`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger')`,
+		`INSERT INTO creature_metadata (entry, model_image_url, model_image_local, map_image_local)
+			VALUES (1, 'https://img.example/old.jpg', 'model.png', 'map.png')`,
+	).DB()
+
+	// Pages link a new model image that can't be downloaded
+	client := fakeClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "img.example" {
+			return respond(req, http.StatusNotFound, "")
+		}
+		return respond(req, http.StatusOK, `<html><head><meta property="og:image" content="https://img.example/new.jpg"></head>
+			<body><ul><li>Level: 11</li></ul></body></html>`)
+	})
+	scraper := services.NewScraperService()
+	scraper.Client = client
+	npcs := services.NewNpcService(db, nil, scraper, nil, nil, t.TempDir())
+	npcs.SetImageClient(client)
+
+	if err := npcs.SyncNpcData(t.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+	var url, model, mapImage string
+	if err := db.QueryRow(`SELECT model_image_url, model_image_local, map_image_local FROM creature_metadata WHERE entry = 1`).Scan(&url, &model, &mapImage); err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://img.example/new.jpg" {
+		t.Errorf("model image URL %q, want the scraped one", url)
+	}
+	if model != "model.png" || mapImage != "map.png" {
+		t.Errorf("local images %q, %q, want model.png, map.png kept", model, mapImage)
+	}
+}
+
+func TestFullSyncNpcsCountsFailures(t *testing.T) {
+	db := openTestDB(t,
+		`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger'), (2, 'Kobold Miner'), (3, 'Defias Thug')`,
+	).DB()
+	scraper := services.NewScraperService()
+	scraper.Client = scrapeClient(false, nil)
+	npcs := services.NewNpcService(db, nil, scraper, nil, nil, t.TempDir())
+
+	var reported []int
+	result := npcs.FullSyncNpcs(t.Context(), 2, 0, func(current, total, id int, name string) {
+		reported = append(reported, id)
+	})
+	if result.Updated != 0 || result.Failed != 2 || result.LastSyncedID != 3 {
+		t.Errorf("updated %d, failed %d, last %d, want 0, 2, 3", result.Updated, result.Failed, result.LastSyncedID)
+	}
+	if len(reported) != 2 {
+		t.Errorf("progress reported for %v, want NPCs 2 and 3", reported)
+	}
+}
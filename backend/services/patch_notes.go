@@ -0,0 +1,810 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/database/helpers"
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
+	"shelllab/backend/dbdiff"
+)
+
+// Patch note severities, most important first
+const (
+	SeverityMajor    = "major"
+	SeverityMinor    = "minor"
+	SeverityCosmetic = "cosmetic"
+)
+
+var severityOrder = []string{SeverityMajor, SeverityMinor, SeverityCosmetic}
+
+var severityRank = map[string]int{SeverityMajor: 0, SeverityMinor: 1, SeverityCosmetic: 2}
+
+var severityTitles = map[string]string{SeverityMajor: "Major", SeverityMinor: "Minor", SeverityCosmetic: "Cosmetic"}
+
+// IDRange is a run of consecutive entity IDs
+type IDRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (r IDRange) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// PatchNote summarises the changes to one record
+type PatchNote struct {
+	EntityType string   `json:"entityType"`
+	EntityID   int      `json:"entityId"`
+	Name       string   `json:"name"`
+	Severity   string   `json:"severity"`
+	Changes    []string `json:"changes"`
+}
+
+// PatchNotesSection groups the notes for one entity type
+type PatchNotesSection struct {
+	EntityType   string       `json:"entityType"`
+	Title        string       `json:"title"`
+	Label        string       `json:"label"`
+	Added        []IDRange    `json:"added"`
+	AddedCount   int          `json:"addedCount"`
+	Removed      []IDRange    `json:"removed"`
+	RemovedCount int          `json:"removedCount"`
+	Notes        []*PatchNote `json:"notes"`
+}
+
+// PatchNotes is a generated report of what changed between two points in time
+type PatchNotes struct {
+	Title       string               `json:"title"`
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	GeneratedAt string               `json:"generatedAt"`
+	Sections    []*PatchNotesSection `json:"sections"`
+}
+
+// entityDiff is the net before/after state of one record
+type entityDiff struct {
+	ID      int
+	Name    string
+	Added   bool
+	Removed bool
+	Before  map[string]string
+	After   map[string]string
+}
+
+// patchEntity describes how one entity type is stored and summarised
+type patchEntity struct {
+	Type     string
+	Title    string
+	Label    string
+	Table    string
+	NameCol  string
+	Details  []string // tables keyed by entry holding more columns of the same record
+	describe func(b *noteBuilder)
+}
+
+var patchEntities = []patchEntity{
+	{models.ChangeEntityItem, "Items", "Item", "item_template", "name", nil, describeItem},
+	{models.ChangeEntityQuest, "Quests", "Quest", "quest_template", "Title", nil, describeQuest},
+	{models.ChangeEntitySpell, "Spells", "Spell", "spell_template", "name", nil, describeSpell},
+	{models.ChangeEntityNpc, "NPCs", "NPC", "creature_template", "name", []string{"creature_metadata"}, describeNpc},
+}
+
+// PatchNotesService builds patch notes from the change log or from two database files
+type PatchNotesService struct {
+	db      *sql.DB
+	changes *repositories.ChangeLogRepository
+}
+
+// NewPatchNotesService creates a new patch notes service
+func NewPatchNotesService(db *sql.DB) *PatchNotesService {
+	return &PatchNotesService{
+		db:      db,
+		changes: repositories.NewChangeLogRepository(db),
+	}
+}
+
+// FromChangeLog builds patch notes from changes recorded in [since, until).
+// Several syncs of the same record inside the range are merged into one net change.
+func (s *PatchNotesService) FromChangeLog(since, until string) (*PatchNotes, error) {
+	entries, err := s.changes.GetChangesInRange(since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make(map[string]map[int]*entityDiff)
+	for _, e := range entries {
+		byID := diffs[e.EntityType]
+		if byID == nil {
+			byID = make(map[int]*entityDiff)
+			diffs[e.EntityType] = byID
+		}
+		d := byID[e.EntityID]
+		if d == nil {
+			d = &entityDiff{ID: e.EntityID, Before: map[string]string{}, After: map[string]string{}}
+			byID[e.EntityID] = d
+		}
+		if e.EntityName != "" {
+			d.Name = e.EntityName
+		}
+		if e.Action == models.ChangeActionAdded {
+			d.Added = true
+			continue
+		}
+		if _, seen := d.Before[e.Field]; !seen {
+			d.Before[e.Field] = e.OldValue
+		}
+		d.After[e.Field] = e.NewValue
+	}
+
+	result := make(map[string][]*entityDiff)
+	for _, entity := range patchEntities {
+		for _, d := range diffs[entity.Type] {
+			for field, old := range d.Before {
+				if d.After[field] == old {
+					delete(d.Before, field)
+					delete(d.After, field)
+				}
+			}
+			if !d.Added && len(d.After) == 0 {
+				continue
+			}
+
+			// Fill in unchanged columns (e.g. a stat type next to a changed stat value)
+			current, err := snapshotRecord(s.changes, entity, d.ID)
+			if err != nil {
+				return nil, err
+			}
+			d.Before = overlay(current, d.Before)
+			d.After = overlay(current, d.After)
+			result[entity.Type] = append(result[entity.Type], d)
+		}
+	}
+
+	to := until
+	if to == "" {
+		to = time.Now().UTC().Format("2006-01-02")
+	}
+	from := since
+	if from == "" {
+		from = "the beginning"
+	}
+	title := fmt.Sprintf("Turtle WoW changes %s to %s", from, to)
+	return buildPatchNotes(title, from, to, result, s.spellNamer(s.db)), nil
+}
+
+// FromSnapshots builds patch notes by comparing two shelllab.db files.
+// An empty newPath compares against the open database.
+func (s *PatchNotesService) FromSnapshots(oldPath, newPath string) (*PatchNotes, error) {
+	oldDB, err := dbdiff.OpenReadOnly(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	defer oldDB.Close()
+
+	newDB := s.db
+	newLabel := "current database"
+	if newPath != "" {
+		newDB, err = dbdiff.OpenReadOnly(newPath)
+		if err != nil {
+			return nil, err
+		}
+		defer newDB.Close()
+		newLabel = newPath
+	}
+
+	current := repositories.NewChangeLogRepository(newDB)
+	result := make(map[string][]*entityDiff)
+	for _, entity := range patchEntities {
+		entity := entity
+		byID := make(map[int]*entityDiff)
+		err := dbdiff.DiffTable(oldDB, newDB, entity.Table, []string{"entry"}, func(before, after map[string]string) {
+			d := &entityDiff{Before: before, After: after, Added: before == nil, Removed: after == nil}
+			row := after
+			if row == nil {
+				row = before
+			}
+			d.ID, d.Name = atoi(row["entry"]), row[entity.NameCol]
+			byID[d.ID] = d
+			result[entity.Type] = append(result[entity.Type], d)
+		})
+		if err != nil {
+			fmt.Printf("[PatchNotes] ⚠ Skipping %s: %v\n", entity.Table, err)
+		}
+
+		// A changed detail row is a field change of its record, even if the record itself is unchanged
+		for _, table := range entity.Details {
+			var details []*entityDiff
+			err := dbdiff.DiffTable(oldDB, newDB, table, []string{"entry"}, func(before, after map[string]string) {
+				if before == nil {
+					before = blankRow(after)
+				}
+				if after == nil {
+					after = blankRow(before)
+				}
+				details = append(details, &entityDiff{ID: atoi(after["entry"]), Before: before, After: after})
+			})
+			if err != nil {
+				fmt.Printf("[PatchNotes] ⚠ Skipping %s: %v\n", table, err)
+				continue
+			}
+
+			// Read unchanged records only once the diff has released its rows
+			for _, detail := range details {
+				d := byID[detail.ID]
+				if d == nil {
+					row, err := current.Snapshot(entity.Table, "entry", detail.ID)
+					if err != nil {
+						fmt.Printf("[PatchNotes] ⚠ Failed to read %s %d: %v\n", entity.Table, detail.ID, err)
+					}
+					d = &entityDiff{ID: detail.ID, Name: row[entity.NameCol], Before: row, After: overlay(row, nil)}
+					byID[d.ID] = d
+					result[entity.Type] = append(result[entity.Type], d)
+				}
+				d.Before = overlay(d.Before, detail.Before)
+				d.After = overlay(d.After, detail.After)
+			}
+		}
+	}
+
+	title := fmt.Sprintf("Database changes %s to %s", oldPath, newLabel)
+	return buildPatchNotes(title, oldPath, newLabel, result, s.spellNamer(newDB)), nil
+}
+
+// snapshotRecord reads a record's current row merged with its detail rows
+func snapshotRecord(changes *repositories.ChangeLogRepository, entity patchEntity, id int) (map[string]string, error) {
+	row, err := changes.Snapshot(entity.Table, "entry", id)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range entity.Details {
+		detail, err := changes.Snapshot(table, "entry", id)
+		if err != nil {
+			return nil, err
+		}
+		row = overlay(detail, row)
+	}
+	return row, nil
+}
+
+// blankRow stands in for a missing detail row: the same columns, all empty
+func blankRow(row map[string]string) map[string]string {
+	blank := make(map[string]string, len(row))
+	for col := range row {
+		blank[col] = ""
+	}
+	blank["entry"] = row["entry"]
+	return blank
+}
+
+// spellNamer looks up spell names for describing changed item and NPC spells
+func (s *PatchNotesService) spellNamer(db *sql.DB) func(int) string {
+	return func(id int) string {
+		var name string
+		db.QueryRow("SELECT COALESCE(name, '') FROM spell_template WHERE entry = ?", id).Scan(&name)
+		if name == "" {
+			return fmt.Sprintf("Spell %d", id)
+		}
+		return name
+	}
+}
+
+// buildPatchNotes turns per-type diffs into sorted sections
+func buildPatchNotes(title, from, to string, diffs map[string][]*entityDiff, spellName func(int) string) *PatchNotes {
+	notes := &PatchNotes{
+		Title:       title,
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Sections:    []*PatchNotesSection{},
+	}
+
+	for _, entity := range patchEntities {
+		section := &PatchNotesSection{
+			EntityType: entity.Type,
+			Title:      entity.Title,
+			Label:      entity.Label,
+			Notes:      []*PatchNote{},
+		}
+
+		var added, removed []int
+		for _, d := range diffs[entity.Type] {
+			switch {
+			case d.Added:
+				added = append(added, d.ID)
+			case d.Removed:
+				removed = append(removed, d.ID)
+			default:
+				b := &noteBuilder{d: d, handled: map[string]bool{"entry": true}, spellName: spellName}
+				entity.describe(b)
+				if note := b.note(entity.Type); note != nil {
+					section.Notes = append(section.Notes, note)
+				}
+			}
+		}
+
+		section.Added, section.AddedCount = toRanges(added), len(added)
+		section.Removed, section.RemovedCount = toRanges(removed), len(removed)
+		sort.Slice(section.Notes, func(i, j int) bool {
+			a, b := section.Notes[i], section.Notes[j]
+			if severityRank[a.Severity] != severityRank[b.Severity] {
+				return severityRank[a.Severity] < severityRank[b.Severity]
+			}
+			return a.EntityID < b.EntityID
+		})
+
+		if section.AddedCount > 0 || section.RemovedCount > 0 || len(section.Notes) > 0 {
+			notes.Sections = append(notes.Sections, section)
+		}
+	}
+	return notes
+}
+
+// toRanges collapses IDs into runs of consecutive IDs
+func toRanges(ids []int) []IDRange {
+	ranges := []IDRange{}
+	if len(ids) == 0 {
+		return ranges
+	}
+	sort.Ints(ids)
+	current := IDRange{From: ids[0], To: ids[0]}
+	for _, id := range ids[1:] {
+		if id == current.To+1 {
+			current.To = id
+			continue
+		}
+		ranges = append(ranges, current)
+		current = IDRange{From: id, To: id}
+	}
+	return append(ranges, current)
+}
+
+// ============================================================================
+// Change descriptions
+// ============================================================================
+
+type patchChange struct {
+	severity string
+	text     string
+}
+
+// noteBuilder collects human readable changes for one record
+type noteBuilder struct {
+	d         *entityDiff
+	handled   map[string]bool
+	changes   []patchChange
+	spellName func(int) string
+}
+
+// changed reports whether any of the fields changed and marks them handled
+func (b *noteBuilder) changed(fields ...string) bool {
+	found := false
+	for _, f := range fields {
+		b.handled[f] = true
+		if b.d.Before[f] != b.d.After[f] {
+			found = true
+		}
+	}
+	return found
+}
+
+// changedPrefix is changed for every column starting with one of the prefixes
+func (b *noteBuilder) changedPrefix(prefixes ...string) bool {
+	var fields []string
+	for col := range b.d.After {
+		for _, p := range prefixes {
+			if strings.HasPrefix(col, p) {
+				fields = append(fields, col)
+				break
+			}
+		}
+	}
+	return b.changed(fields...)
+}
+
+func (b *noteBuilder) was(field string) string { return b.d.Before[field] }
+func (b *noteBuilder) now(field string) string { return b.d.After[field] }
+
+func (b *noteBuilder) add(severity, format string, args ...interface{}) {
+	b.changes = append(b.changes, patchChange{severity, fmt.Sprintf(format, args...)})
+}
+
+// value adds "label old → new" when field changed
+func (b *noteBuilder) value(severity, field, label string) {
+	if b.changed(field) {
+		b.add(severity, "%s %s → %s", label, b.was(field), b.now(field))
+	}
+}
+
+// delta adds "+N label" for a numeric field
+func (b *noteBuilder) delta(severity, field, label string) {
+	if b.changed(field) {
+		if d := atoi(b.now(field)) - atoi(b.was(field)); d != 0 {
+			b.add(severity, "%+d %s", d, label)
+		}
+	}
+}
+
+// rename reports a changed name column
+func (b *noteBuilder) rename(field string) {
+	if b.changed(field) && b.was(field) != "" {
+		b.add(SeverityMajor, "Renamed from %q", b.was(field))
+	}
+}
+
+// rest reports every changed field no describer handled
+func (b *noteBuilder) rest(severity string) {
+	var fields []string
+	for f := range b.d.After {
+		if !b.handled[f] && b.d.Before[f] != b.d.After[f] {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		b.add(severity, "%s %s → %s", f, shorten(b.was(f), 40), shorten(b.now(f), 40))
+	}
+}
+
+// note returns the collected changes ordered by severity, or nil if nothing is worth reporting
+func (b *noteBuilder) note(entityType string) *PatchNote {
+	if len(b.changes) == 0 {
+		return nil
+	}
+	sort.SliceStable(b.changes, func(i, j int) bool {
+		return severityRank[b.changes[i].severity] < severityRank[b.changes[j].severity]
+	})
+	note := &PatchNote{
+		EntityType: entityType,
+		EntityID:   b.d.ID,
+		Name:       b.d.Name,
+		Severity:   b.changes[0].severity,
+	}
+	for _, c := range b.changes {
+		note.Changes = append(note.Changes, c.text)
+	}
+	return note
+}
+
+var resistanceColumns = []struct{ col, name string }{
+	{"holy_res", "Holy Resistance"},
+	{"fire_res", "Fire Resistance"},
+	{"nature_res", "Nature Resistance"},
+	{"frost_res", "Frost Resistance"},
+	{"shadow_res", "Shadow Resistance"},
+	{"arcane_res", "Arcane Resistance"},
+}
+
+func describeItem(b *noteBuilder) {
+	b.rename("name")
+	if b.changed("quality") {
+		b.add(SeverityMajor, "Quality %s → %s", helpers.GetQualityName(atoi(b.was("quality"))), helpers.GetQualityName(atoi(b.now("quality"))))
+	}
+	b.value(SeverityMajor, "item_level", "Item level")
+	b.value(SeverityMajor, "required_level", "Required level")
+
+	// Stats are compared as totals per stat type, so moving a stat between slots is not a change
+	var statFields []string
+	for i := 1; i <= 10; i++ {
+		statFields = append(statFields, fmt.Sprintf("stat_type%d", i), fmt.Sprintf("stat_value%d", i))
+	}
+	if b.changed(statFields...) {
+		before, after := itemStats(b.d.Before), itemStats(b.d.After)
+		for _, t := range unionKeys(before, after) {
+			if d := after[t] - before[t]; d != 0 {
+				b.add(SeverityMajor, "%+d %s", d, helpers.GetStatName(t))
+			}
+		}
+	}
+
+	b.delta(SeverityMajor, "armor", "Armor")
+	for _, r := range resistanceColumns {
+		b.delta(SeverityMajor, r.col, r.name)
+	}
+	if b.changed("dmg_min1", "dmg_max1") {
+		b.add(SeverityMajor, "Damage %s-%s → %s-%s", b.was("dmg_min1"), b.was("dmg_max1"), b.now("dmg_min1"), b.now("dmg_max1"))
+	}
+	if b.changed("delay") {
+		b.add(SeverityMajor, "Speed %.2f → %.2f", atof(b.was("delay"))/1000, atof(b.now("delay"))/1000)
+	}
+	for i := 1; i <= 5; i++ {
+		b.spellSwap(fmt.Sprintf("spellid_%d", i), "Effect")
+	}
+
+	if b.changed("sell_price") {
+		b.add(SeverityMinor, "Sell price %s → %s", formatMoney(atoi(b.was("sell_price"))), formatMoney(atoi(b.now("sell_price"))))
+	}
+	if b.changed("description") {
+		b.add(SeverityCosmetic, "Description updated")
+	}
+	if b.changed("display_id") {
+		b.add(SeverityCosmetic, "Appearance changed")
+	}
+	b.rest(SeverityMinor)
+}
+
+func describeQuest(b *noteBuilder) {
+	b.rename("Title")
+	b.value(SeverityMajor, "QuestLevel", "Level")
+	b.value(SeverityMajor, "MinLevel", "Required level")
+	if b.changed("RewXP") {
+		b.add(SeverityMajor, "XP reward %s → %s", b.was("RewXP"), b.now("RewXP"))
+	}
+	if b.changed("RewOrReqMoney") {
+		b.add(SeverityMajor, "Money reward %s → %s", formatMoney(atoi(b.was("RewOrReqMoney"))), formatMoney(atoi(b.now("RewOrReqMoney"))))
+	}
+	if b.changedPrefix("RewItem", "RewChoiceItem", "RewSpell", "RewRep", "RewMail") {
+		b.add(SeverityMajor, "Reward changed")
+	}
+	if b.changedPrefix("Req") {
+		b.add(SeverityMajor, "Objectives changed")
+	}
+	if b.changedPrefix("Details", "Objectives", "OfferRewardText", "RequestItemsText", "EndText", "ObjectiveText") {
+		b.add(SeverityCosmetic, "Quest text updated")
+	}
+	b.rest(SeverityMinor)
+}
+
+func describeSpell(b *noteBuilder) {
+	b.rename("name")
+	if b.changed("description") {
+		b.add(SeverityMinor, "Tooltip: %s", shorten(b.now("description"), 120))
+	}
+	if b.changed("spellIconId") {
+		b.add(SeverityCosmetic, "Icon changed")
+	}
+	b.rest(SeverityMinor)
+}
+
+func describeNpc(b *noteBuilder) {
+	b.rename("name")
+	if b.changed("level_min", "level_max") {
+		b.add(SeverityMajor, "Level %s → %s", levelRange(b.was("level_min"), b.was("level_max")), levelRange(b.now("level_min"), b.now("level_max")))
+	}
+	if b.changed("rank") {
+		b.add(SeverityMajor, "Rank %s → %s", helpers.GetCreatureRankName(atoi(b.was("rank"))), helpers.GetCreatureRankName(atoi(b.now("rank"))))
+	}
+	b.value(SeverityMajor, "health_max", "Health")
+	if b.changed("dmg_min", "dmg_max") {
+		b.add(SeverityMajor, "Damage %s-%s → %s-%s", b.was("dmg_min"), b.was("dmg_max"), b.now("dmg_min"), b.now("dmg_max"))
+	}
+	for i := 1; i <= 4; i++ {
+		b.spellSwap(fmt.Sprintf("spell_id%d", i), "Ability")
+	}
+	if b.changed("loot_id") {
+		b.add(SeverityMajor, "Loot table changed")
+	}
+
+	b.value(SeverityMinor, "mana_max", "Mana")
+	b.value(SeverityMinor, "subname", "Title")
+	b.delta(SeverityMinor, "armor", "Armor")
+	for _, r := range resistanceColumns {
+		b.delta(SeverityMinor, r.col, r.name)
+	}
+	if b.changed("gold_min", "gold_max") {
+		b.add(SeverityMinor, "Money drop %s-%s → %s-%s",
+			formatMoney(atoi(b.was("gold_min"))), formatMoney(atoi(b.was("gold_max"))),
+			formatMoney(atoi(b.now("gold_min"))), formatMoney(atoi(b.now("gold_max"))))
+	}
+
+	// creature_metadata columns recorded by the NPC scraper
+	if b.changed("zone_name", "x", "y", "map_url", "map_image_local") {
+		b.add(SeverityCosmetic, "Location updated")
+	}
+	if b.changed("model_image_url", "model_image_local", "display_id1", "display_id2", "display_id3", "display_id4") {
+		b.add(SeverityCosmetic, "Model updated")
+	}
+	if b.changed("infobox_json") {
+		b.add(SeverityCosmetic, "Info box updated")
+	}
+	b.rest(SeverityMinor)
+}
+
+// spellSwap describes a spell slot that gained, lost or replaced a spell
+func (b *noteBuilder) spellSwap(field, label string) {
+	if !b.changed(field) {
+		return
+	}
+	oldID, newID := atoi(b.was(field)), atoi(b.now(field))
+	switch {
+	case oldID == 0:
+		b.add(SeverityMajor, "%s added: %s", label, b.spellName(newID))
+	case newID == 0:
+		b.add(SeverityMajor, "%s removed: %s", label, b.spellName(oldID))
+	default:
+		b.add(SeverityMajor, "%s changed: %s → %s", label, b.spellName(oldID), b.spellName(newID))
+	}
+}
+
+// itemStats totals stat values by stat type
+func itemStats(row map[string]string) map[int]int {
+	stats := make(map[int]int)
+	for i := 1; i <= 10; i++ {
+		value := atoi(row[fmt.Sprintf("stat_value%d", i)])
+		if value != 0 {
+			stats[atoi(row[fmt.Sprintf("stat_type%d", i)])] += value
+		}
+	}
+	return stats
+}
+
+func unionKeys(a, b map[int]int) []int {
+	seen := make(map[int]bool)
+	var keys []int
+	for _, m := range []map[int]int{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// overlay returns a copy of base with values replaced by changes
+func overlay(base, changes map[string]string) map[string]string {
+	row := make(map[string]string, len(base)+len(changes))
+	for k, v := range base {
+		row[k] = v
+	}
+	for k, v := range changes {
+		row[k] = v
+	}
+	return row
+}
+
+func levelRange(min, max string) string {
+	if min == max {
+		return min
+	}
+	return min + "-" + max
+}
+
+// formatMoney renders copper as "1g 2s 3c"
+func formatMoney(copper int) string {
+	sign := ""
+	if copper < 0 {
+		sign, copper = "-", -copper
+	}
+	var parts []string
+	if g := copper / 10000; g > 0 {
+		parts = append(parts, fmt.Sprintf("%dg", g))
+	}
+	if s := copper / 100 % 100; s > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", s))
+	}
+	if c := copper % 100; c > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%dc", c))
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+func shorten(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
+
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func atoi(s string) int {
+	return int(atof(s))
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// Markdown renders the patch notes for forum and Discord posts
+func (p *PatchNotes) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", p.Title)
+	if len(p.Sections) == 0 {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	for _, section := range p.Sections {
+		fmt.Fprintf(&sb, "## %s\n\n", section.Title)
+		if section.AddedCount > 0 {
+			fmt.Fprintf(&sb, "**New %s:** %s (%d)\n\n", strings.ToLower(section.Title), joinRanges(section.Added), section.AddedCount)
+		}
+		if section.RemovedCount > 0 {
+			fmt.Fprintf(&sb, "**Removed %s:** %s (%d)\n\n", strings.ToLower(section.Title), joinRanges(section.Removed), section.RemovedCount)
+		}
+		for _, severity := range severityOrder {
+			notes := section.NotesWithSeverity(severity)
+			if len(notes) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "### %s changes\n\n", severityTitles[severity])
+			for _, n := range notes {
+				fmt.Fprintf(&sb, "- %s %d %s: %s\n", section.Label, n.EntityID, n.Name, strings.Join(n.Changes, "; "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&sb, "_Generated %s_\n", p.GeneratedAt)
+	return sb.String()
+}
+
+var patchNotesHTML = template.Must(template.New("patchnotes").Funcs(template.FuncMap{
+	"ranges": joinRanges,
+	"lower":  strings.ToLower,
+	"join":   strings.Join,
+	"title":  func(severity string) string { return severityTitles[severity] },
+}).Parse(`<h1>{{.Title}}</h1>
+{{- range .Sections}}
+{{- $section := .}}
+<h2>{{.Title}}</h2>
+{{- if .AddedCount}}
+<p><strong>New {{lower .Title}}:</strong> {{ranges .Added}} ({{.AddedCount}})</p>
+{{- end}}
+{{- if .RemovedCount}}
+<p><strong>Removed {{lower .Title}}:</strong> {{ranges .Removed}} ({{.RemovedCount}})</p>
+{{- end}}
+{{- range $.Severities}}
+{{- $notes := $section.NotesWithSeverity .}}
+{{- if $notes}}
+<h3 class="severity-{{.}}">{{title .}} changes</h3>
+<ul>
+{{- range $notes}}
+<li>{{$section.Label}} {{.EntityID}} {{.Name}}: {{join .Changes "; "}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- else}}
+<p>No changes.</p>
+{{- end}}
+<p><em>Generated {{.GeneratedAt}}</em></p>
+`))
+
+// HTML renders the patch notes as an HTML fragment
+func (p *PatchNotes) HTML() string {
+	var buf bytes.Buffer
+	data := struct {
+		*PatchNotes
+		Severities []string
+	}{p, severityOrder}
+	if err := patchNotesHTML.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<p>Failed to render patch notes: %s</p>", template.HTMLEscapeString(err.Error()))
+	}
+	return buf.String()
+}
+
+// NotesWithSeverity returns the section's notes of one severity
+func (s *PatchNotesSection) NotesWithSeverity(severity string) []*PatchNote {
+	var notes []*PatchNote
+	for _, n := range s.Notes {
+		if n.Severity == severity {
+			notes = append(notes, n)
+		}
+	}
+	return notes
+}
+
+func joinRanges(ranges []IDRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ", ")
+}
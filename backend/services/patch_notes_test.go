@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/schema"
+	"shelllab/backend/services"
+)
+
+// section returns the patch notes section for entityType, or nil
+func section(notes *services.PatchNotes, entityType string) *services.PatchNotesSection {
+	for _, s := range notes.Sections {
+		if s.EntityType == entityType {
+			return s
+		}
+	}
+	return nil
+}
+
+// noteTexts renders every note of a section as "id: change; change"
+func noteTexts(s *services.PatchNotesSection) []string {
+	texts := []string{}
+	for _, n := range s.Notes {
+		texts = append(texts, fmt.Sprintf("%d: %s", n.EntityID, strings.Join(n.Changes, "; ")))
+	}
+	return texts
+}
+
+func TestPatchNotesFromChangeLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		inserts    []string
+		sync       func(t *testing.T, db *sql.DB)
+		entityType string
+		wantAdded  []services.IDRange
+		wantNotes  []string
+	}{
+		{
+			name:    "item found by the new-item scan",
+			inserts: []string{`INSERT INTO item_template (entry, name) VALUES (1, 'Hearthstone')`},
+			sync: func(t *testing.T, db *sql.DB) {
+				syncService := services.NewSyncService(db)
+				syncService.SetClient(itemPages(map[int]string{2: "Linen Cloth"}))
+				if _, err := syncService.CheckNewItems(t.Context(), 3, 0, nil); err != nil {
+					t.Fatal(err)
+				}
+			},
+			entityType: models.ChangeEntityItem,
+			wantAdded:  []services.IDRange{{From: 2, To: 2}},
+			wantNotes:  []string{},
+		},
+		{
+			name:    "first scrape of a known NPC",
+			inserts: []string{`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger')`},
+			sync: func(t *testing.T, db *sql.DB) {
+				scraper := services.NewScraperService()
+				// Every NPC page has an info box holding one row
+				scraper.Client = fakeClient(func(req *http.Request) (*http.Response, error) {
+					return respond(req, http.StatusOK, "<html><body><ul><li>Level: 11</li></ul></body></html>")
+				})
+				npcs := services.NewNpcService(db, nil, scraper, nil, nil, t.TempDir())
+				if err := npcs.SyncNpcData(t.Context(), 1); err != nil {
+					t.Fatal(err)
+				}
+			},
+			entityType: models.ChangeEntityNpc,
+			wantAdded:  []services.IDRange{},
+			wantNotes:  []string{"1: Location updated; Info box updated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, tt.inserts...).DB()
+			tt.sync(t, db)
+
+			notes, err := services.NewPatchNotesService(db).FromChangeLog("", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := section(notes, tt.entityType)
+			if s == nil {
+				t.Fatalf("no %s section in %+v", tt.entityType, notes.Sections)
+			}
+			if !reflect.DeepEqual(s.Added, tt.wantAdded) {
+				t.Errorf("added %v, want %v", s.Added, tt.wantAdded)
+			}
+			if got := noteTexts(s); !reflect.DeepEqual(got, tt.wantNotes) {
+				t.Errorf("notes %q, want %q", got, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestPatchNotesFromSnapshotsIncludesNpcMetadata(t *testing.T) {
+	oldPath := filepath.Join(t.TempDir(), "old.db")
+	oldDB, err := sql.Open("sqlite", oldPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		schema.GeneratedSchema(),
+		schema.CoreSchema(),
+		`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger'), (2, 'Kobold Miner')`,
+		`INSERT INTO creature_metadata (entry, zone_name) VALUES (2, 'Elwynn Forest')`,
+	} {
+		if _, err := oldDB.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldDB.Close()
+
+	db := openTestDB(t,
+		`INSERT INTO creature_template (entry, name) VALUES (1, 'Hogger'), (2, 'Kobold Miner')`,
+		`INSERT INTO creature_metadata (entry, zone_name) VALUES (1, 'Elwynn Forest')`,
+	).DB()
+
+	notes, err := services.NewPatchNotesService(db).FromSnapshots(oldPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := section(notes, models.ChangeEntityNpc)
+	if s == nil {
+		t.Fatalf("no NPC section in %+v", notes.Sections)
+	}
+	if s.AddedCount != 0 || s.RemovedCount != 0 {
+		t.Errorf("%d added, %d removed, want metadata rows reported as changes", s.AddedCount, s.RemovedCount)
+	}
+	if got, want := noteTexts(s), []string{"1: Location updated", "2: Location updated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("notes %q, want %q", got, want)
+	}
+	if s.Notes[0].Name != "Hogger" {
+		t.Errorf("note named %q, want Hogger", s.Notes[0].Name)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"shelllab/backend/parsers"
@@ -20,7 +21,7 @@ type ScraperService struct {
 // NewScraperService creates a new scraper service
 func NewScraperService() *ScraperService {
 	return &ScraperService{
-		Client: &http.Client{},
+		Client: SharedHTTPClient(),
 	}
 }
 
@@ -28,14 +29,14 @@ func NewScraperService() *ScraperService {
 type ScrapedNpcData = parsers.ScrapedNpcData
 
 // ScrapeNpcData scrapes NPC data - tries both TurtleCraft and Wowhead and merges them
-func (s *ScraperService) ScrapeNpcData(npcID int) (*ScrapedNpcData, error) {
+func (s *ScraperService) ScrapeNpcData(ctx context.Context, npcID int) (*ScrapedNpcData, error) {
 	// Channels to receive results
 	tcChan := make(chan *ScrapedNpcData)
 	whChan := make(chan *ScrapedNpcData)
 
 	// Fetch concurrently
 	go func() {
-		data, err := s.scrapeFromTurtlecraft(npcID)
+		data, err := s.scrapeFromTurtlecraft(ctx, npcID)
 		if err != nil {
 			tcChan <- nil
 		} else {
@@ -44,7 +45,7 @@ func (s *ScraperService) ScrapeNpcData(npcID int) (*ScrapedNpcData, error) {
 	}()
 
 	go func() {
-		data, err := s.scrapeFromWowhead(npcID)
+		data, err := s.scrapeFromWowhead(ctx, npcID)
 		if err != nil {
 			whChan <- nil
 		} else {
@@ -106,10 +107,10 @@ func (s *ScraperService) ScrapeNpcData(npcID int) (*ScrapedNpcData, error) {
 }
 
 // scrapeFromTurtlecraft scrapes NPC data from database.turtlecraft.gg
-func (s *ScraperService) scrapeFromTurtlecraft(npcID int) (*ScrapedNpcData, error) {
+func (s *ScraperService) scrapeFromTurtlecraft(ctx context.Context, npcID int) (*ScrapedNpcData, error) {
 	url := fmt.Sprintf("https://database.turtlecraft.gg/?npc=%d", npcID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -129,10 +130,10 @@ func (s *ScraperService) scrapeFromTurtlecraft(npcID int) (*ScrapedNpcData, erro
 }
 
 // scrapeFromWowhead scrapes NPC data from Wowhead Classic
-func (s *ScraperService) scrapeFromWowhead(npcID int) (*ScrapedNpcData, error) {
+func (s *ScraperService) scrapeFromWowhead(ctx context.Context, npcID int) (*ScrapedNpcData, error) {
 	url := fmt.Sprintf("https://www.wowhead.com/classic/npc=%d", npcID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -153,10 +154,10 @@ func (s *ScraperService) scrapeFromWowhead(npcID int) (*ScrapedNpcData, error) {
 }
 
 // ScrapeQuestData scrapes Quest data from TurtleCraft
-func (s *ScraperService) ScrapeQuestData(entry int) (*parsers.ScrapedQuestData, error) {
+func (s *ScraperService) ScrapeQuestData(ctx context.Context, entry int) (*parsers.ScrapedQuestData, error) {
 	url := fmt.Sprintf("https://database.turtlecraft.gg/?quest=%d", entry)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,9 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"sync"
-	"time"
 )
 
 // GetMissingAtlasLootItemCount returns count of items in AtlasLoot that don't exist in item_template
@@ -66,7 +66,7 @@ type ImportResult struct {
 }
 
 // ImportMissingItems fetches and imports missing AtlasLoot items
-func (s *SyncService) ImportMissingItems(maxItems int, delayMs int) (*ImportResult, error) {
+func (s *SyncService) ImportMissingItems(ctx context.Context, maxItems int, delayMs int) (*ImportResult, error) {
 	if maxItems <= 0 {
 		maxItems = 2147483647 // Max Int32 (Unlimited)
 	}
@@ -83,7 +83,7 @@ func (s *SyncService) ImportMissingItems(maxItems int, delayMs int) (*ImportResu
 	}
 
 	// Worker Pool Setup
-	numWorkers := 10
+	numWorkers := s.workers()
 	jobs := make(chan MissingItem, len(missing))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -94,12 +94,12 @@ func (s *SyncService) ImportMissingItems(maxItems int, delayMs int) (*ImportResu
 		defer wg.Done()
 		for m := range jobs {
 			// Check for cancellation
-			if s.IsStopped() {
+			if ctx.Err() != nil {
 				return
 			}
 
 			// Reuse the main sync logic
-			syncRes := s.FetchAndImportItem(m.ItemID)
+			syncRes := s.FetchAndImportItem(ctx, m.ItemID)
 
 			mu.Lock()
 			if syncRes.Success {
@@ -111,9 +111,7 @@ func (s *SyncService) ImportMissingItems(maxItems int, delayMs int) (*ImportResu
 			}
 			mu.Unlock()
 
-			if delayMs > 0 {
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
-			}
+			s.throttle(ctx, delayMs)
 		}
 	}
 
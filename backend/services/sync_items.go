@@ -1,14 +1,16 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"path/filepath"
 	"shelllab/backend/database/models"
 	"shelllab/backend/parsers"
 	"strings"
 	"sync"
-	"time"
 )
 
 // GetLocalMaxItemID returns the maximum item entry in local database
@@ -36,18 +38,24 @@ func (s *SyncService) ItemExistsLocally(entry int) bool {
 }
 
 // CheckRemoteItem checks if an item exists on turtlecraft.gg and returns its name
-func (s *SyncService) CheckRemoteItem(entry int) (bool, string, error) {
+func (s *SyncService) CheckRemoteItem(ctx context.Context, entry int) (bool, string, error) {
 	url := fmt.Sprintf("%s/?item=%d", s.baseURL, entry)
 
-	resp, err := s.httpClient.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
+	if errors.Is(err, ErrNotFound) {
+		return false, "", nil
+	}
 	if err != nil {
 		return false, "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	if resp.StatusCode == 404 {
 		return false, "", nil
 	}
+	if resp.StatusCode != 200 {
+		return false, "", fmt.Errorf("unexpected HTTP status %d for item %d", resp.StatusCode, entry)
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -65,13 +73,15 @@ func (s *SyncService) CheckRemoteItem(entry int) (bool, string, error) {
 }
 
 // CheckNewItems checks for new items beyond local max ID
-func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan chan<- SyncProgress) ([]RemoteItem, error) {
+func (s *SyncService) CheckNewItems(ctx context.Context, maxChecks int, delayMs int, progressChan chan<- SyncProgress) ([]RemoteItem, error) {
 	localMax, _ := s.GetLocalMaxItemID()
 	startID := localMax + 1
 
 	var newItems []RemoteItem
 	consecutiveMisses := 0
 	maxConsecutiveMisses := 10000 // Stop after 10000 consecutive misses (handles large ID gaps)
+	consecutiveErrors := 0
+	maxConsecutiveErrors := 5 // Abort if the site keeps failing after retries
 
 	// If maxChecks <= 0, treat as practically unlimited (max int)
 	if maxChecks <= 0 {
@@ -81,7 +91,7 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 	checked := 0
 	for id := startID; checked < maxChecks && consecutiveMisses < maxConsecutiveMisses; id++ {
 		// Check for cancellation
-		if s.IsStopped() {
+		if ctx.Err() != nil {
 			return newItems, nil
 		}
 		checked++
@@ -100,17 +110,31 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 		}
 
 		// Use FetchItemDetails to get full info for import
-		item, itemSet, err := s.FetchItemDetails(id)
+		item, itemSet, err := s.FetchItemDetails(ctx, id)
 		if err != nil {
-			// If error is "not found", count as miss
-			if strings.Contains(err.Error(), "not found") {
-				consecutiveMisses++
-			} else {
-				// Network error or other, treat as miss to keep flow going
+			// A replay run ends where the recorded pages do
+			if errors.Is(err, ErrCacheMiss) {
+				return newItems, nil
+			}
+
+			// Only a real "not found" counts as a miss
+			if errors.Is(err, ErrNotFound) {
+				consecutiveErrors = 0
 				consecutiveMisses++
+				continue
+			}
+
+			// Network error: retry the same ID instead of skipping it
+			consecutiveErrors++
+			fmt.Printf("  ⚠ Error checking item %d: %v\n", id, err)
+			if consecutiveErrors >= maxConsecutiveErrors {
+				return newItems, fmt.Errorf("aborted at item %d after %d consecutive errors: %w", id, consecutiveErrors, err)
 			}
+			id--
+			checked--
 			continue
 		}
+		consecutiveErrors = 0
 
 		// If name is empty, it's a "shell" item (exists on web but no data).
 		// Treat as non-existent/miss to avoid polluting DB.
@@ -122,7 +146,8 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 		// Found valid item! Import it.
 		consecutiveMisses = 0
 
-		// Insert into item_template
+		// Insert into item_template, recording the new row in the change log
+		recordChanges := trackChanges(s.changes, models.ChangeEntityItem, models.ChangeSourceTurtlecraft, "item_template", "entry", id)
 		_, dbErr := s.db.Exec(`
 			INSERT OR IGNORE INTO item_template 
 			(entry, name, quality, item_level, required_level, class, subclass, inventory_type, display_id, armor, bonding, max_durability, description, sell_price, stat_type1, stat_value1, stat_type2, stat_value2, stat_type3, stat_value3, stat_type4, stat_value4, stat_type5, stat_value5, stat_type6, stat_value6, stat_type7, stat_value7, stat_type8, stat_value8, stat_type9, stat_value9, stat_type10, stat_value10, holy_res, fire_res, nature_res, frost_res, shadow_res, arcane_res, allowable_class, allowable_race, spellid_1, spelltrigger_1, spellid_2, spelltrigger_2, spellid_3, spelltrigger_3, spellid_4, spelltrigger_4, spellid_5, spelltrigger_5, delay, dmg_min1, dmg_max1, dmg_type1, set_id, bag_family, food_type, container_slots)
@@ -133,12 +158,15 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 			fmt.Printf("  Error importing item %d: %v\n", id, dbErr)
 		} else {
 			fmt.Printf("  ✓ Auto-imported: %d - %s\n", id, item.Name)
+			recordChanges(item.Name)
+			s.invalidate("item_template")
 
 			// Update Dropped By relations (Batch Sync)
 			if len(item.DroppedByNpcs) > 0 {
 				for _, npcID := range item.DroppedByNpcs {
 					_, _ = s.db.Exec(`INSERT OR IGNORE INTO creature_loot_template (entry, item, ChanceOrQuestChance, groupid, mincountOrRef, maxcount) VALUES (?, ?, 0, 0, 1, 1)`, npcID, item.Entry)
 				}
+				s.invalidate("creature_loot_template")
 			}
 
 			// Sync item set info
@@ -152,7 +180,7 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 				spells := []int{itemSet.Spell1, itemSet.Spell2, itemSet.Spell3, itemSet.Spell4, itemSet.Spell5, itemSet.Spell6, itemSet.Spell7, itemSet.Spell8}
 				for _, spellID := range spells {
 					if spellID > 0 {
-						s.SyncSpell(spellID, iconDir, "")
+						s.SyncSpell(ctx, spellID, iconDir, "")
 					}
 				}
 			}
@@ -165,24 +193,31 @@ func (s *SyncService) CheckNewItems(maxChecks int, delayMs int, progressChan cha
 		})
 
 		// Rate limiting
-		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+		s.throttle(ctx, delayMs)
 	}
 
 	return newItems, nil
 }
 
 // FetchItemDetails fetches detailed item info from turtlecraft.gg
-func (s *SyncService) FetchItemDetails(itemID int) (*models.ItemTemplateFull, *models.ItemSetEntry, error) {
+func (s *SyncService) FetchItemDetails(ctx context.Context, itemID int) (*models.ItemTemplateFull, *models.ItemSetEntry, error) {
 	url := fmt.Sprintf("%s/?item=%d", s.baseURL, itemID)
 
-	resp, err := s.httpClient.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 404 {
+		return nil, nil, fmt.Errorf("item %d: %w", itemID, ErrNotFound)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		// Only a client without retries returns these
+		return nil, nil, &RetryError{URL: url, Attempts: 1, StatusCode: resp.StatusCode}
+	}
 	if resp.StatusCode != 200 {
-		return nil, nil, fmt.Errorf("item not found: %d", itemID)
+		return nil, nil, fmt.Errorf("unexpected HTTP status %d for item %d", resp.StatusCode, itemID)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -193,7 +228,11 @@ func (s *SyncService) FetchItemDetails(itemID int) (*models.ItemTemplateFull, *m
 	content := string(body)
 
 	// Use the new parser
-	return parsers.ParseItem(content, itemID)
+	item, itemSet, err := parsers.ParseItem(content, itemID)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil, nil, fmt.Errorf("item %d: %w", itemID, ErrNotFound)
+	}
+	return item, itemSet, err
 }
 
 // SyncItemResult represents the result of syncing a single item
@@ -205,11 +244,11 @@ type SyncItemResult struct {
 }
 
 // FetchAndImportItem fetches a single item from turtlecraft.gg and imports it to local database
-func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
+func (s *SyncService) FetchAndImportItem(ctx context.Context, itemID int) *SyncItemResult {
 	fmt.Printf("[SyncService] FetchAndImportItem called for item %d\n", itemID)
 
 	// Fetch item details from remote
-	item, itemSet, err := s.FetchItemDetails(itemID)
+	item, itemSet, err := s.FetchItemDetails(ctx, itemID)
 	if err != nil {
 		return &SyncItemResult{
 			Success: false,
@@ -233,19 +272,19 @@ func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
 	// Pass fallback descriptions extracted from item page when spell pages don't have them
 	iconDir := filepath.Join("data", "icons")
 	if item.Spellid1 > 0 {
-		s.SyncSpell(item.Spellid1, iconDir, item.SpellDescriptions[item.Spellid1])
+		s.SyncSpell(ctx, item.Spellid1, iconDir, item.SpellDescriptions[item.Spellid1])
 	}
 	if item.Spellid2 > 0 {
-		s.SyncSpell(item.Spellid2, iconDir, item.SpellDescriptions[item.Spellid2])
+		s.SyncSpell(ctx, item.Spellid2, iconDir, item.SpellDescriptions[item.Spellid2])
 	}
 	if item.Spellid3 > 0 {
-		s.SyncSpell(item.Spellid3, iconDir, item.SpellDescriptions[item.Spellid3])
+		s.SyncSpell(ctx, item.Spellid3, iconDir, item.SpellDescriptions[item.Spellid3])
 	}
 	if item.Spellid4 > 0 {
-		s.SyncSpell(item.Spellid4, iconDir, item.SpellDescriptions[item.Spellid4])
+		s.SyncSpell(ctx, item.Spellid4, iconDir, item.SpellDescriptions[item.Spellid4])
 	}
 	if item.Spellid5 > 0 {
-		s.SyncSpell(item.Spellid5, iconDir, item.SpellDescriptions[item.Spellid5])
+		s.SyncSpell(ctx, item.Spellid5, iconDir, item.SpellDescriptions[item.Spellid5])
 	}
 
 	// Sync item set info
@@ -257,20 +296,18 @@ func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
 			spells := []int{itemSet.Spell1, itemSet.Spell2, itemSet.Spell3, itemSet.Spell4, itemSet.Spell5, itemSet.Spell6, itemSet.Spell7, itemSet.Spell8}
 			for _, spellID := range spells {
 				if spellID > 0 {
-					s.SyncSpell(spellID, iconDir, "")
+					s.SyncSpell(ctx, spellID, iconDir, "")
 				}
 			}
 		}
 	}
 
-	// Check if item already exists and fetch old data for diff
+	// Check if item already exists and snapshot it for the change log
 	var existingCount int
 	s.db.QueryRow("SELECT COUNT(*) FROM item_template WHERE entry = ?", itemID).Scan(&existingCount)
+	recordChanges := trackChanges(s.changes, models.ChangeEntityItem, models.ChangeSourceTurtlecraft, "item_template", "entry", itemID)
 
 	if existingCount > 0 {
-		// Fetch existing item for diff logging
-		oldItem := s.fetchExistingItemForDiff(itemID)
-
 		// UPDATE existing item - preserves columns not included here (buy_price, buy_count, flags, etc.)
 		_, err = s.db.Exec(`
 			UPDATE item_template SET
@@ -299,11 +336,6 @@ func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
 			item.Spelltrigger3, item.Spellid4, item.Spelltrigger4, item.Spellid5, item.Spelltrigger5,
 			item.Delay, item.DmgMin1, item.DmgMax1, item.DmgType1, item.SetId,
 			item.BagFamily, item.FoodType, itemID)
-
-		// Log diff after update
-		if oldItem != nil {
-			s.logItemDiff(itemID, oldItem, item)
-		}
 	} else {
 		// INSERT new item
 		fmt.Printf("[Sync] + NEW ITEM %d: %s\n", itemID, item.Name)
@@ -322,17 +354,24 @@ func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
 		}
 	}
 
+	s.invalidate("item_template")
+	changes := recordChanges(item.Name)
+	if existingCount > 0 {
+		s.logItemDiff(itemID, item.Name, changes)
+	}
+
 	// Update Dropped By relations (Single Sync)
 	if len(item.DroppedByNpcs) > 0 {
 		fmt.Printf("[SyncService] Updating %d Dropped By records for item %d\n", len(item.DroppedByNpcs), itemID)
 		for _, npcID := range item.DroppedByNpcs {
 			_, _ = s.db.Exec(`INSERT OR IGNORE INTO creature_loot_template (entry, item, ChanceOrQuestChance, groupid, mincountOrRef, maxcount) VALUES (?, ?, 0, 0, 1, 1)`, npcID, itemID)
 		}
+		s.invalidate("creature_loot_template")
 	}
 
 	// Auto-fix icon if needed
 	iconFixService := NewIconFixService(s.db, iconDir)
-	_, _, _ = iconFixService.FixSingleItem(s.db, itemID)
+	_, _, _ = iconFixService.FixSingleItem(ctx, s.db, itemID)
 
 	return &SyncItemResult{
 		Success: true,
@@ -341,149 +380,33 @@ func (s *SyncService) FetchAndImportItem(itemID int) *SyncItemResult {
 	}
 }
 
-// fetchExistingItemForDiff fetches an existing item from DB for diff comparison
-func (s *SyncService) fetchExistingItemForDiff(itemID int) *models.ItemTemplateFull {
-	row := s.db.QueryRow(`
-		SELECT name, quality, item_level, required_level, class, subclass, inventory_type, display_id,
-		       armor, bonding, max_durability, description, sell_price,
-		       stat_type1, stat_value1, stat_type2, stat_value2, stat_type3, stat_value3,
-		       stat_type4, stat_value4, stat_type5, stat_value5, stat_type6, stat_value6,
-		       stat_type7, stat_value7, stat_type8, stat_value8, stat_type9, stat_value9,
-		       stat_type10, stat_value10,
-		       holy_res, fire_res, nature_res, frost_res, shadow_res, arcane_res,
-		       allowable_class, allowable_race,
-		       spellid_1, spelltrigger_1, spellid_2, spelltrigger_2, spellid_3, spelltrigger_3,
-		       spellid_4, spelltrigger_4, spellid_5, spelltrigger_5,
-		       delay, dmg_min1, dmg_max1, dmg_type1, set_id, bag_family, food_type
-		FROM item_template WHERE entry = ?
-	`, itemID)
-
-	item := &models.ItemTemplateFull{Entry: itemID}
-	var desc, name string
-	err := row.Scan(
-		&name, &item.Quality, &item.ItemLevel, &item.RequiredLevel, &item.Class, &item.Subclass,
-		&item.InventoryType, &item.DisplayId, &item.Armor, &item.Bonding, &item.MaxDurability,
-		&desc, &item.SellPrice,
-		&item.StatType1, &item.StatValue1, &item.StatType2, &item.StatValue2,
-		&item.StatType3, &item.StatValue3, &item.StatType4, &item.StatValue4,
-		&item.StatType5, &item.StatValue5, &item.StatType6, &item.StatValue6,
-		&item.StatType7, &item.StatValue7, &item.StatType8, &item.StatValue8,
-		&item.StatType9, &item.StatValue9, &item.StatType10, &item.StatValue10,
-		&item.HolyRes, &item.FireRes, &item.NatureRes, &item.FrostRes, &item.ShadowRes, &item.ArcaneRes,
-		&item.AllowableClass, &item.AllowableRace,
-		&item.Spellid1, &item.Spelltrigger1, &item.Spellid2, &item.Spelltrigger2,
-		&item.Spellid3, &item.Spelltrigger3, &item.Spellid4, &item.Spelltrigger4,
-		&item.Spellid5, &item.Spelltrigger5,
-		&item.Delay, &item.DmgMin1, &item.DmgMax1, &item.DmgType1, &item.SetId, &item.BagFamily, &item.FoodType,
-	)
-	if err != nil {
-		return nil
+// logItemDiff logs a git-style diff of the changes recorded for an item
+func (s *SyncService) logItemDiff(itemID int, name string, changes []*models.ChangeLogEntry) {
+	if len(changes) == 0 {
+		fmt.Printf("[Sync] ○ item %d \"%s\" - no changes\n", itemID, name)
+		return
 	}
-	item.Name = name
-	item.Description = desc
-	return item
-}
 
-// logItemDiff logs a git-style diff of changes between old and new item
-func (s *SyncService) logItemDiff(itemID int, old, new *models.ItemTemplateFull) {
-	var diffs []string
-
-	// Helper to add diff line
-	addDiff := func(field string, oldVal, newVal interface{}) {
-		oldStr := fmt.Sprintf("%v", oldVal)
-		newStr := fmt.Sprintf("%v", newVal)
-		if oldStr != newStr {
-			diffs = append(diffs, fmt.Sprintf("  - %s: %v", field, oldVal))
-			diffs = append(diffs, fmt.Sprintf("  + %s: %v", field, newVal))
+	// Keep long text fields readable in the console
+	shorten := func(v string) string {
+		if len(v) > 50 {
+			return v[:50] + "..."
 		}
+		return v
 	}
 
-	// Compare fields
-	addDiff("name", old.Name, new.Name)
-	addDiff("quality", old.Quality, new.Quality)
-	addDiff("item_level", old.ItemLevel, new.ItemLevel)
-	addDiff("required_level", old.RequiredLevel, new.RequiredLevel)
-	addDiff("class", old.Class, new.Class)
-	addDiff("subclass", old.Subclass, new.Subclass)
-	addDiff("inventory_type", old.InventoryType, new.InventoryType)
-	addDiff("display_id", old.DisplayId, new.DisplayId)
-	addDiff("armor", old.Armor, new.Armor)
-	addDiff("bonding", old.Bonding, new.Bonding)
-	addDiff("max_durability", old.MaxDurability, new.MaxDurability)
-	addDiff("sell_price", old.SellPrice, new.SellPrice)
-	addDiff("stat_type1", old.StatType1, new.StatType1)
-	addDiff("stat_value1", old.StatValue1, new.StatValue1)
-	addDiff("stat_type2", old.StatType2, new.StatType2)
-	addDiff("stat_value2", old.StatValue2, new.StatValue2)
-	addDiff("stat_type3", old.StatType3, new.StatType3)
-	addDiff("stat_value3", old.StatValue3, new.StatValue3)
-	addDiff("stat_type4", old.StatType4, new.StatType4)
-	addDiff("stat_value4", old.StatValue4, new.StatValue4)
-	addDiff("stat_type5", old.StatType5, new.StatType5)
-	addDiff("stat_value5", old.StatValue5, new.StatValue5)
-	addDiff("stat_type6", old.StatType6, new.StatType6)
-	addDiff("stat_value6", old.StatValue6, new.StatValue6)
-	addDiff("stat_type7", old.StatType7, new.StatType7)
-	addDiff("stat_value7", old.StatValue7, new.StatValue7)
-	addDiff("stat_type8", old.StatType8, new.StatType8)
-	addDiff("stat_value8", old.StatValue8, new.StatValue8)
-	addDiff("stat_type9", old.StatType9, new.StatType9)
-	addDiff("stat_value9", old.StatValue9, new.StatValue9)
-	addDiff("stat_type10", old.StatType10, new.StatType10)
-	addDiff("stat_value10", old.StatValue10, new.StatValue10)
-	addDiff("holy_res", old.HolyRes, new.HolyRes)
-	addDiff("fire_res", old.FireRes, new.FireRes)
-	addDiff("nature_res", old.NatureRes, new.NatureRes)
-	addDiff("frost_res", old.FrostRes, new.FrostRes)
-	addDiff("shadow_res", old.ShadowRes, new.ShadowRes)
-	addDiff("arcane_res", old.ArcaneRes, new.ArcaneRes)
-	addDiff("allowable_class", old.AllowableClass, new.AllowableClass)
-	addDiff("allowable_race", old.AllowableRace, new.AllowableRace)
-	addDiff("spellid_1", old.Spellid1, new.Spellid1)
-	addDiff("spelltrigger_1", old.Spelltrigger1, new.Spelltrigger1)
-	addDiff("spellid_2", old.Spellid2, new.Spellid2)
-	addDiff("spelltrigger_2", old.Spelltrigger2, new.Spelltrigger2)
-	addDiff("spellid_3", old.Spellid3, new.Spellid3)
-	addDiff("spelltrigger_3", old.Spelltrigger3, new.Spelltrigger3)
-	addDiff("spellid_4", old.Spellid4, new.Spellid4)
-	addDiff("spelltrigger_4", old.Spelltrigger4, new.Spelltrigger4)
-	addDiff("spellid_5", old.Spellid5, new.Spellid5)
-	addDiff("spelltrigger_5", old.Spelltrigger5, new.Spelltrigger5)
-	addDiff("delay", old.Delay, new.Delay)
-	addDiff("dmg_min1", old.DmgMin1, new.DmgMin1)
-	addDiff("dmg_max1", old.DmgMax1, new.DmgMax1)
-	addDiff("dmg_type1", old.DmgType1, new.DmgType1)
-	addDiff("set_id", old.SetId, new.SetId)
-	addDiff("bag_family", old.BagFamily, new.BagFamily)
-	addDiff("food_type", old.FoodType, new.FoodType)
-	if old.Description != new.Description {
-		oldDesc := old.Description
-		newDesc := new.Description
-		if len(oldDesc) > 50 {
-			oldDesc = oldDesc[:50] + "..."
-		}
-		if len(newDesc) > 50 {
-			newDesc = newDesc[:50] + "..."
-		}
-		diffs = append(diffs, fmt.Sprintf("  - description: %s", oldDesc))
-		diffs = append(diffs, fmt.Sprintf("  + description: %s", newDesc))
-	}
-
-	// Print diff
-	if len(diffs) > 0 {
-		fmt.Printf("[Sync] diff item_template (entry=%d) \"%s\":\n", itemID, new.Name)
-		for _, line := range diffs {
-			fmt.Println(line)
-		}
-	} else {
-		fmt.Printf("[Sync] ○ item %d \"%s\" - no changes\n", itemID, new.Name)
+	fmt.Printf("[Sync] diff item_template (entry=%d) \"%s\":\n", itemID, name)
+	for _, c := range changes {
+		fmt.Printf("  - %s: %s\n", c.Field, shorten(c.OldValue))
+		fmt.Printf("  + %s: %s\n", c.Field, shorten(c.NewValue))
 	}
 }
 
 // FullSyncItems re-syncs all items from turtlecraft.gg
 // startFrom: if > 0, skip items with ID < startFrom (for resume)
 // progressCb: callback for progress updates (can be nil)
-func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string, startFrom int, progressCb ProgressCallback) *FullSyncResult {
+// checkpointCb: called when LastSyncedID advances (can be nil)
+func (s *SyncService) FullSyncItems(ctx context.Context, delayMs int, fixIcons bool, iconDir string, startFrom int, progressCb ProgressCallback, checkpointCb CheckpointCallback) *FullSyncResult {
 	// User requested faster sync ("unnecessary delay").
 	// We use a worker pool to parallelize requests.
 
@@ -525,7 +448,7 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 	}
 
 	// Worker Pool Setup
-	numWorkers := 10 // Concurrent workers
+	numWorkers := s.workers()
 	fmt.Printf("[FullSync] Starting parallel sync of %d items with %d workers...\n", len(filteredIDs), numWorkers)
 
 	jobs := make(chan int, len(filteredIDs))
@@ -536,6 +459,11 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 	processedCount := 0
 	totalFiltered := len(filteredIDs)
 
+	// Workers finish out of order, so only advance LastSyncedID over a
+	// contiguous run of finished IDs to keep it safe to resume from
+	finished := make(map[int]bool)
+	watermark := 0
+
 	iconFixService := NewIconFixService(s.db, iconDir)
 
 	// Worker function
@@ -543,14 +471,19 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 		defer wg.Done()
 		for itemID := range jobs {
 			// Check if stop requested
-			if s.IsStopped() {
+			if ctx.Err() != nil {
 				// Drain remaining jobs if necessary or just return
 				// For pool workers, we should check it frequently
 				return
 			}
 
 			// Fetch item
-			item, itemSet, err := s.FetchItemDetails(itemID)
+			item, itemSet, err := s.FetchItemDetails(ctx, itemID)
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				// Cut off by a pause, not a failure: leave the ID unfinished so
+				// the watermark stops before it and a resumed run fetches it again
+				return
+			}
 
 			var itemName string
 			if item != nil {
@@ -558,6 +491,9 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 			}
 
 			var success bool
+			// A network or server failure may pass when tried again, so like a
+			// pause it must not move the watermark past the ID
+			retryLater := err != nil && (IsTransient(err) || isRetryableNetError(err))
 
 			if err != nil {
 				mu.Lock()
@@ -571,7 +507,8 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 				result.Failed++
 				mu.Unlock()
 			} else {
-				// Update item (thread-safe via sql.DB)
+				// Snapshot the row for the change log, then update it (thread-safe via sql.DB)
+				recordChanges := trackChanges(s.changes, models.ChangeEntityItem, models.ChangeSourceTurtlecraft, "item_template", "entry", itemID)
 				_, err = s.db.Exec(`
 					UPDATE item_template SET
 						name = COALESCE(NULLIF(?, ''), name),
@@ -689,19 +626,13 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 					}
 					mu.Unlock()
 				} else {
+					s.invalidate("item_template")
+					recordChanges(item.Name)
 					success = true
 				}
 			}
 
 			if success {
-				mu.Lock()
-				result.Updated++
-				result.LastSyncedID = itemID // Roughly tracks progress
-				if fixIcons {
-					// Count fixed icons if we really want to, but it requires another call
-				}
-				mu.Unlock()
-
 				// Sync Spells (with duplication check)
 				spellIconDir := ""
 				if fixIcons {
@@ -711,7 +642,7 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 				syncSpellSafe := func(sid int, desc string) {
 					if sid > 0 {
 						if _, loaded := spellSeenCache.LoadOrStore(sid, true); !loaded {
-							s.SyncSpell(sid, spellIconDir, desc)
+							s.SyncSpell(ctx, sid, spellIconDir, desc)
 						}
 					}
 				}
@@ -738,7 +669,7 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 
 				// Fix icon for item
 				if fixIcons {
-					if success, _, _ := iconFixService.FixSingleItem(s.db, itemID); success {
+					if success, _, _ := iconFixService.FixSingleItem(ctx, s.db, itemID); success {
 						mu.Lock()
 						result.IconsFixed++
 						mu.Unlock()
@@ -746,9 +677,30 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 				}
 			}
 
+			// Spells and icons of a synced item may have been cut off too, so
+			// the item only counts once all of it is done
+			if ctx.Err() != nil {
+				return
+			}
+
 			// Reporting and Progress
 			mu.Lock()
+			if success {
+				result.Updated++
+			}
 			processedCount++
+			if !retryLater {
+				finished[itemID] = true
+			}
+			lastSynced := result.LastSyncedID
+			for watermark < totalFiltered && finished[filteredIDs[watermark]] {
+				delete(finished, filteredIDs[watermark])
+				result.LastSyncedID = filteredIDs[watermark]
+				watermark++
+			}
+			if checkpointCb != nil && result.LastSyncedID != lastSynced {
+				checkpointCb(result.LastSyncedID)
+			}
 			if progressCb != nil {
 				progressCb(processedCount, totalFiltered, itemID, itemName)
 			}
@@ -758,9 +710,7 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 			mu.Unlock()
 
 			// Delay (if requested)
-			if delayMs > 0 {
-				time.Sleep(time.Duration(delayMs) * time.Millisecond)
-			}
+			s.throttle(ctx, delayMs)
 		}
 	}
 
@@ -779,6 +729,11 @@ func (s *SyncService) FullSyncItems(delayMs int, fixIcons bool, iconDir string,
 	// Wait for completion
 	wg.Wait()
 
+	if ctx.Err() != nil {
+		result.Message = fmt.Sprintf("Sync stopped by user: %d updated, %d failed", result.Updated, result.Failed)
+		return result
+	}
+
 	result.Message = fmt.Sprintf("Full sync complete: %d updated, %d failed, %d icons fixed",
 		result.Updated, result.Failed, result.IconsFixed)
 	fmt.Printf("[FullSync] %s\n", result.Message)
@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+	"shelllab/backend/database/repositories"
+	"shelllab/backend/services"
+)
+
+func TestCheckNewItemsRecordsChanges(t *testing.T) {
+	db := openTestDB(t, `INSERT INTO item_template (entry, name) VALUES (1, 'Hearthstone')`).DB()
+	syncService := services.NewSyncService(db)
+	syncService.SetClient(itemPages(map[int]string{2: "Linen Cloth"}))
+
+	found, err := syncService.CheckNewItems(t.Context(), 3, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Entry != 2 {
+		t.Fatalf("found %v, want item 2", found)
+	}
+
+	entries, err := repositories.NewChangeLogRepository(db).GetRecentChanges(models.ChangeLogFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d change log entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.EntityType != models.ChangeEntityItem || e.EntityID != 2 || e.EntityName != "Linen Cloth" || e.Action != models.ChangeActionAdded {
+		t.Errorf("got %s %d %q %s, want item 2 \"Linen Cloth\" added", e.EntityType, e.EntityID, e.EntityName, e.Action)
+	}
+}
+
+func TestCheckNewItemsCountAsNewInSmartCollections(t *testing.T) {
+	db := openTestDB(t, `INSERT INTO item_template (entry, name) VALUES (1, 'Wool Cloth')`)
+	searches := database.NewSavedSearchRepository(db)
+	search, err := searches.SaveSearch(&models.SavedSearch{Name: "Cloth", Filter: models.SearchFilter{Query: "Cloth"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syncService := services.NewSyncService(db.DB())
+	syncService.SetClient(itemPages(map[int]string{2: "Linen Cloth"}))
+	if _, err := syncService.CheckNewItems(t.Context(), 3, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := searches.GetSmartCollection(search.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Collection.Count != 2 || view.Collection.NewCount != 1 {
+		t.Errorf("%d items, %d new, want 2 items, 1 new", view.Collection.Count, view.Collection.NewCount)
+	}
+	if want := []int{2}; !reflect.DeepEqual(view.NewItems, want) {
+		t.Errorf("new items %v, want %v", view.NewItems, want)
+	}
+}
+
+func TestNewContentScanEndsAtReplayCacheMiss(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded []int // item pages recorded before the replay run
+		scan     func(t *testing.T, s *services.SyncService) (int, error)
+		want     int
+	}{
+		{
+			name:     "items",
+			recorded: []int{2},
+			scan: func(t *testing.T, s *services.SyncService) (int, error) {
+				found, err := s.CheckNewItems(t.Context(), 0, 0, nil)
+				return len(found), err
+			},
+			want: 1,
+		},
+		{
+			name: "quests",
+			scan: func(t *testing.T, s *services.SyncService) (int, error) {
+				found, err := s.CheckNewQuests(t.Context(), 0, 0, nil)
+				return len(found), err
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, `INSERT INTO item_template (entry, name) VALUES (1, 'Hearthstone')`).DB()
+			dir := t.TempDir()
+			recorder := services.NewHTTPCache(itemPages(map[int]string{2: "Linen Cloth"}), dir, time.Hour)
+			for _, id := range tt.recorded {
+				resp, err := recorder.Get(fmt.Sprintf("https://database.turtlecraft.gg/?item=%d", id))
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+
+			replay := services.NewHTTPCache(itemPages(nil), dir, time.Hour)
+			replay.SetMode(services.CacheModeReplay)
+			syncService := services.NewSyncService(db)
+			syncService.SetCache(replay)
+
+			found, err := tt.scan(t, syncService)
+			if err != nil {
+				t.Fatalf("replay scan failed: %v", err)
+			}
+			if found != tt.want {
+				t.Errorf("found %d, want %d", found, tt.want)
+			}
+			if misses := replay.Stats().Misses; misses != 1 {
+				t.Errorf("%d cache misses, want the scan to stop at the first", misses)
+			}
+		})
+	}
+}
+
+func TestFullSyncItemsRefreshesCachedTooltip(t *testing.T) {
+	db := openTestDB(t, `INSERT INTO item_template (entry, name) VALUES (1, 'Linen Cloth')`).DB()
+	items := repositories.NewItemRepository(db)
+	before, err := items.GetTooltipData(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Name != "Linen Cloth" {
+		t.Fatalf("tooltip name %q", before.Name)
+	}
+
+	syncService := services.NewSyncService(db)
+	syncService.SetClient(itemPages(map[int]string{1: "Bolt of Linen Cloth"}))
+	syncService.SetWorkers(1)
+	if result := syncService.FullSyncItems(t.Context(), 0, false, "", 0, nil, nil); result.Updated != 1 {
+		t.Fatalf("updated %d items, want 1: %v", result.Updated, result.Errors)
+	}
+
+	after, err := items.GetTooltipData(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Name != "Bolt of Linen Cloth" {
+		t.Errorf("tooltip name %q after sync, want Bolt of Linen Cloth", after.Name)
+	}
+}
+
+func TestFullSyncItemsWatermarkStopsAtTransientFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		failItem func(req *http.Request) (*http.Response, error)
+		want     int // LastSyncedID after the run
+	}{
+		{
+			name:     "not found",
+			failItem: func(req *http.Request) (*http.Response, error) { return respond(req, http.StatusNotFound, "") },
+			want:     5,
+		},
+		{
+			name:     "server error",
+			failItem: func(req *http.Request) (*http.Response, error) { return respond(req, http.StatusServiceUnavailable, "") },
+			want:     2,
+		},
+		{
+			name: "network error",
+			failItem: func(req *http.Request) (*http.Response, error) {
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+			},
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, `INSERT INTO item_template (entry, name) VALUES (1, 'A'), (2, 'B'), (3, 'C'), (4, 'D'), (5, 'E')`).DB()
+			pages := itemPages(map[int]string{1: "A", 2: "B", 4: "D", 5: "E"})
+			syncService := services.NewSyncService(db)
+			syncService.SetClient(fakeClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("item") == "3" {
+					return tt.failItem(req)
+				}
+				return pages(req)
+			}))
+			syncService.SetWorkers(1)
+
+			checkpoint := 0
+			result := syncService.FullSyncItems(t.Context(), 0, false, "", 0, nil, func(id int) { checkpoint = id })
+			if result.Updated != 4 || result.Failed != 1 {
+				t.Errorf("updated %d, failed %d, want 4 and 1", result.Updated, result.Failed)
+			}
+			if result.LastSyncedID != tt.want || checkpoint != tt.want {
+				t.Errorf("last synced %d, checkpoint %d, want %d", result.LastSyncedID, checkpoint, tt.want)
+			}
+		})
+	}
+}
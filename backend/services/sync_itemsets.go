@@ -31,5 +31,6 @@ func (s *SyncService) UpsertItemSet(set *models.ItemSetEntry) error {
 	if err != nil {
 		return fmt.Errorf("failed to upsert item set %d: %w", set.ID, err)
 	}
+	s.invalidate("itemsets")
 	return nil
 }
@@ -1,11 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"shelllab/backend/database/models"
 	"shelllab/backend/parsers"
-	"time"
 )
 
 // GetLocalMaxQuestID returns the maximum quest entry in local database
@@ -33,18 +34,24 @@ func (s *SyncService) QuestExistsLocally(entry int) bool {
 }
 
 // CheckRemoteQuest checks if a quest exists on turtlecraft.gg and returns its title
-func (s *SyncService) CheckRemoteQuest(entry int) (bool, string, error) {
+func (s *SyncService) CheckRemoteQuest(ctx context.Context, entry int) (bool, string, error) {
 	url := fmt.Sprintf("%s/?quest=%d", s.baseURL, entry)
 
-	resp, err := s.httpClient.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
+	if errors.Is(err, ErrNotFound) {
+		return false, "", nil
+	}
 	if err != nil {
 		return false, "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
+	if resp.StatusCode == 404 {
 		return false, "", nil
 	}
+	if resp.StatusCode != 200 {
+		return false, "", fmt.Errorf("unexpected HTTP status %d for quest %d", resp.StatusCode, entry)
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -63,13 +70,15 @@ func (s *SyncService) CheckRemoteQuest(entry int) (bool, string, error) {
 }
 
 // CheckNewQuests checks for new quests beyond local max ID
-func (s *SyncService) CheckNewQuests(maxChecks int, delayMs int, progressChan chan<- SyncProgress) ([]RemoteQuest, error) {
+func (s *SyncService) CheckNewQuests(ctx context.Context, maxChecks int, delayMs int, progressChan chan<- SyncProgress) ([]RemoteQuest, error) {
 	localMax, _ := s.GetLocalMaxQuestID()
 	startID := localMax + 1
 
 	var newQuests []RemoteQuest
 	consecutiveMisses := 0
 	maxConsecutiveMisses := 20 // Stop after 20 consecutive misses
+	consecutiveErrors := 0
+	maxConsecutiveErrors := 5 // Abort if the site keeps failing after retries
 
 	// If maxChecks <= 0, treat as practically unlimited (max int)
 	if maxChecks <= 0 {
@@ -79,7 +88,7 @@ func (s *SyncService) CheckNewQuests(maxChecks int, delayMs int, progressChan ch
 	checked := 0
 	for id := startID; checked < maxChecks && consecutiveMisses < maxConsecutiveMisses; id++ {
 		// Check for cancellation
-		if s.IsStopped() {
+		if ctx.Err() != nil {
 			return newQuests, nil
 		}
 		checked++
@@ -97,11 +106,23 @@ func (s *SyncService) CheckNewQuests(maxChecks int, delayMs int, progressChan ch
 			}
 		}
 
-		exists, title, err := s.CheckRemoteQuest(id)
+		exists, title, err := s.CheckRemoteQuest(ctx, id)
+		if errors.Is(err, ErrCacheMiss) {
+			// A replay run ends where the recorded pages do
+			return newQuests, nil
+		}
 		if err != nil {
-			consecutiveMisses++
+			// Network error: retry the same ID instead of counting a miss
+			consecutiveErrors++
+			fmt.Printf("  ⚠ Error checking quest %d: %v\n", id, err)
+			if consecutiveErrors >= maxConsecutiveErrors {
+				return newQuests, fmt.Errorf("aborted at quest %d after %d consecutive errors: %w", id, consecutiveErrors, err)
+			}
+			id--
+			checked--
 			continue
 		}
+		consecutiveErrors = 0
 
 		if exists {
 			consecutiveMisses = 0
@@ -113,7 +134,7 @@ func (s *SyncService) CheckNewQuests(maxChecks int, delayMs int, progressChan ch
 			fmt.Printf("  Found new quest: %d - %s\n", id, title)
 
 			// Import it immediately
-			res := s.FetchAndImportQuest(id)
+			res := s.FetchAndImportQuest(ctx, id)
 			if res.Success {
 				fmt.Printf("  ✓ Imported quest: %s\n", title)
 				newQuests = append(newQuests, RemoteQuest{
@@ -129,24 +150,27 @@ func (s *SyncService) CheckNewQuests(maxChecks int, delayMs int, progressChan ch
 		}
 
 		// Rate limiting
-		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+		s.throttle(ctx, delayMs)
 	}
 
 	return newQuests, nil
 }
 
 // FetchQuestDetails fetches detailed quest info from turtlecraft.gg
-func (s *SyncService) FetchQuestDetails(questID int) (*models.QuestDetail, error) {
+func (s *SyncService) FetchQuestDetails(ctx context.Context, questID int) (*models.QuestDetail, error) {
 	url := fmt.Sprintf("%s/?quest=%d", s.baseURL, questID)
 
-	resp, err := s.httpClient.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("quest %d: %w", questID, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("quest not found: %d", questID)
+		return nil, fmt.Errorf("unexpected HTTP status %d for quest %d", resp.StatusCode, questID)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -168,11 +192,11 @@ type SyncQuestResult struct {
 }
 
 // FetchAndImportQuest fetches a single quest and imports it to local database
-func (s *SyncService) FetchAndImportQuest(questID int) *SyncQuestResult {
+func (s *SyncService) FetchAndImportQuest(ctx context.Context, questID int) *SyncQuestResult {
 	fmt.Printf("[SyncService] FetchAndImportQuest called for quest %d\n", questID)
 
 	// Fetch quest details from remote
-	quest, err := s.FetchQuestDetails(questID)
+	quest, err := s.FetchQuestDetails(ctx, questID)
 	if err != nil {
 		return &SyncQuestResult{
 			Success: false,
@@ -182,6 +206,8 @@ func (s *SyncService) FetchAndImportQuest(questID int) *SyncQuestResult {
 	}
 
 	// Insert or update in database
+	recordChanges := trackChanges(s.changes, models.ChangeEntityQuest, models.ChangeSourceTurtlecraft, "quest_template", "entry", questID)
+
 	// Start transaction
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -226,6 +252,8 @@ func (s *SyncService) FetchAndImportQuest(questID int) *SyncQuestResult {
 	if err := tx.Commit(); err != nil {
 		return &SyncQuestResult{Success: false, QuestID: questID, Error: err.Error()}
 	}
+	s.invalidate("quest_template")
+	recordChanges(quest.Title)
 
 	return &SyncQuestResult{
 		Success: true,
@@ -235,7 +263,7 @@ func (s *SyncService) FetchAndImportQuest(questID int) *SyncQuestResult {
 }
 
 // FullSyncQuests re-syncs all quests in the database
-func (s *SyncService) FullSyncQuests(delayMs int, startFrom int, progressCb ProgressCallback) *FullSyncResult {
+func (s *SyncService) FullSyncQuests(ctx context.Context, delayMs int, startFrom int, progressCb ProgressCallback) *FullSyncResult {
 	if delayMs <= 0 {
 		delayMs = 200
 	}
@@ -270,12 +298,17 @@ func (s *SyncService) FullSyncQuests(delayMs int, startFrom int, progressCb Prog
 
 	for i, questID := range questIDs {
 		// Check for stop request
-		if s.IsStopped() {
+		if ctx.Err() != nil {
 			result.Message = "Sync stopped by user"
 			return result
 		}
 
-		res := s.FetchAndImportQuest(questID)
+		res := s.FetchAndImportQuest(ctx, questID)
+		if ctx.Err() != nil {
+			// Cut off by a pause: don't count or checkpoint it, so a resumed run syncs it again
+			result.Message = "Sync stopped by user"
+			return result
+		}
 		if res.Success {
 			result.Updated++
 		} else {
@@ -290,9 +323,7 @@ func (s *SyncService) FullSyncQuests(delayMs int, startFrom int, progressCb Prog
 			progressCb(i+1, len(questIDs), questID, res.Title)
 		}
 
-		if delayMs > 0 {
-			time.Sleep(time.Duration(delayMs) * time.Millisecond)
-		}
+		s.throttle(ctx, delayMs)
 	}
 
 	result.Message = "Full quest sync complete"
@@ -1,20 +1,25 @@
 package services
 
 import (
+	"context"
 	"database/sql"
-	"net/http"
-	"sync/atomic"
+	"shelllab/backend/database/repositories"
 	"time"
 )
 
 // SyncService handles database synchronization with turtlecraft.gg
 type SyncService struct {
-	db            *sql.DB
-	httpClient    *http.Client
-	baseURL       string
-	stopRequested atomic.Bool
+	db         *sql.DB
+	httpClient HttpClient
+	cache      *HTTPCache
+	changes    *repositories.ChangeLogRepository
+	baseURL    string
+	numWorkers int
 }
 
+// defaultWorkers is the worker pool size for parallel item syncs
+const defaultWorkers = 10
+
 // SyncProgress represents the current sync progress
 type SyncProgress struct {
 	Type     string `json:"type"`     // "item" or "quest"
@@ -65,14 +70,60 @@ type RemoteQuest struct {
 // ProgressCallback is a function type for reporting sync progress
 type ProgressCallback func(current, total int, itemID int, itemName string)
 
+// CheckpointCallback receives the ID up to which every ID of a run is done,
+// the point a resumed run can safely start after
+type CheckpointCallback func(lastSyncedID int)
+
 // NewSyncService creates a new sync service
 func NewSyncService(db *sql.DB) *SyncService {
 	return &SyncService{
-		db: db,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		baseURL: "https://database.turtlecraft.gg",
+		db:         db,
+		httpClient: SharedHTTPClient(),
+		changes:    repositories.NewChangeLogRepository(db),
+		baseURL:    "https://database.turtlecraft.gg",
+	}
+}
+
+// SetClient sets the client pages are fetched with, bypassing any cache
+func (s *SyncService) SetClient(client HttpClient) {
+	s.cache = nil
+	s.httpClient = client
+}
+
+// SetCache routes all page fetches through a disk-backed HTTP cache
+func (s *SyncService) SetCache(cache *HTTPCache) {
+	s.cache = cache
+	if cache != nil {
+		s.httpClient = cache
+	}
+}
+
+// SetWorkers sets the worker pool size for item and AtlasLoot syncs (<= 0 restores the default)
+func (s *SyncService) SetWorkers(n int) {
+	s.numWorkers = n
+}
+
+// workers returns the configured worker pool size
+func (s *SyncService) workers() int {
+	if s.numWorkers <= 0 {
+		return defaultWorkers
+	}
+	return s.numWorkers
+}
+
+// invalidate purges the read caches of the tables a sync wrote rows to
+func (s *SyncService) invalidate(tables ...string) {
+	repositories.InvalidateCache(s.db, tables...)
+}
+
+// throttle sleeps between requests unless pages are replayed from disk or ctx is done
+func (s *SyncService) throttle(ctx context.Context, delayMs int) {
+	if delayMs <= 0 || (s.cache != nil && s.cache.IsReplay()) {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Duration(delayMs) * time.Millisecond):
 	}
 }
 
@@ -98,18 +149,3 @@ func (s *SyncService) GetSyncStats() map[string]interface{} {
 		"maxCreatureID":         maxCreatureID,
 	}
 }
-
-// RequestStop signals the sync process to stop
-func (s *SyncService) RequestStop() {
-	s.stopRequested.Store(true)
-}
-
-// IsStopped returns true if stop was requested
-func (s *SyncService) IsStopped() bool {
-	return s.stopRequested.Load()
-}
-
-// ResetStop resets the stop signal
-func (s *SyncService) ResetStop() {
-	s.stopRequested.Store(false)
-}
@@ -1,19 +1,20 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"net/http"
+	"shelllab/backend/database/models"
 	"shelllab/backend/parsers"
-	"time"
 )
 
 // SyncSpell fetches and imports a spell if it's missing from the database
 // Also fixes the spell icon if iconDir is provided
 // fallbackDesc is used when the spell page doesn't have a description (common for custom spells)
-func (s *SyncService) SyncSpell(spellID int, iconDir string, fallbackDesc string) {
+// Returns an error if the spell could not be fetched or stored
+func (s *SyncService) SyncSpell(ctx context.Context, spellID int, iconDir string, fallbackDesc string) error {
 	if spellID == 0 {
-		return
+		return nil
 	}
 
 	// Always fetch to check for description update if missing
@@ -21,21 +22,28 @@ func (s *SyncService) SyncSpell(spellID int, iconDir string, fallbackDesc string
 
 	// Fetch spell details
 	url := fmt.Sprintf("https://database.turtlecraft.gg/?spell=%d", spellID)
-	resp, err := http.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
 	if err != nil {
 		fmt.Printf("Error fetching spell %d: %v\n", spellID, err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unexpected HTTP status %d for spell %d", resp.StatusCode, spellID)
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return
+		return err
 	}
 	content := string(bodyBytes)
 
 	// Use parser
 	name, description := parsers.ParseSpell(content)
+	if name == "" {
+		return fmt.Errorf("spell %d: %w", spellID, ErrNotFound)
+	}
 
 	// Use fallback description if spell page doesn't have one
 	if description == "" && fallbackDesc != "" {
@@ -43,32 +51,35 @@ func (s *SyncService) SyncSpell(spellID int, iconDir string, fallbackDesc string
 		fmt.Printf("  Using fallback description from item page for spell %d\n", spellID)
 	}
 
-	if name != "" {
-		// Insert or Update logic to handle filling in missing descriptions
-		_, err = s.db.Exec(`
-			INSERT INTO spell_template (entry, name, description)
-			VALUES (?, ?, ?)
-			ON CONFLICT(entry) DO UPDATE SET
-				name = excluded.name,
-				description = excluded.description
-			WHERE description = '' OR description IS NULL OR length(description) < 5
-		`, spellID, name, description)
+	recordChanges := trackChanges(s.changes, models.ChangeEntitySpell, models.ChangeSourceTurtlecraft, "spell_template", "entry", spellID)
 
-		if err != nil {
-			fmt.Printf("Error inserting/updating spell %d: %v\n", spellID, err)
-		} else {
-			fmt.Printf("✓ Synced Spell %d: %s (Desc len: %d)\n", spellID, name, len(description))
-
-			// Fix spell icon if iconDir is provided
-			if iconDir != "" {
-				iconFixService := NewIconFixService(s.db, iconDir)
-				success, iconName, _ := iconFixService.FixSingleSpell(s.db, spellID)
-				if success {
-					fmt.Printf("  ✓ Fixed spell icon: %s\n", iconName)
-				}
-			}
+	// Insert or Update logic to handle filling in missing descriptions
+	_, err = s.db.Exec(`
+		INSERT INTO spell_template (entry, name, description)
+		VALUES (?, ?, ?)
+		ON CONFLICT(entry) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description
+		WHERE description = '' OR description IS NULL OR length(description) < 5
+	`, spellID, name, description)
+
+	if err != nil {
+		fmt.Printf("Error inserting/updating spell %d: %v\n", spellID, err)
+		return err
+	}
+	s.invalidate("spell_template")
+	recordChanges(name)
+	fmt.Printf("✓ Synced Spell %d: %s (Desc len: %d)\n", spellID, name, len(description))
+
+	// Fix spell icon if iconDir is provided
+	if iconDir != "" {
+		iconFixService := NewIconFixService(s.db, iconDir)
+		success, iconName, _ := iconFixService.FixSingleSpell(ctx, s.db, spellID)
+		if success {
+			fmt.Printf("  ✓ Fixed spell icon: %s\n", iconName)
 		}
 	}
+	return nil
 }
 
 // SyncSpellResult represents the result of syncing a single spell
@@ -81,7 +92,7 @@ type SyncSpellResult struct {
 }
 
 // FetchAndImportSpell fetches a single spell from turtlecraft.gg and imports it to local database
-func (s *SyncService) FetchAndImportSpell(spellID int, iconDir string) *SyncSpellResult {
+func (s *SyncService) FetchAndImportSpell(ctx context.Context, spellID int, iconDir string) *SyncSpellResult {
 	if spellID == 0 {
 		return &SyncSpellResult{
 			Success: false,
@@ -94,7 +105,7 @@ func (s *SyncService) FetchAndImportSpell(spellID int, iconDir string) *SyncSpel
 
 	// Fetch spell details from turtlecraft.gg
 	url := fmt.Sprintf("https://database.turtlecraft.gg/?spell=%d", spellID)
-	resp, err := http.Get(url)
+	resp, err := getWithContext(ctx, s.httpClient, url)
 	if err != nil {
 		return &SyncSpellResult{
 			Success: false,
@@ -134,6 +145,7 @@ func (s *SyncService) FetchAndImportSpell(spellID int, iconDir string) *SyncSpel
 	}
 
 	// Insert or Update spell
+	recordChanges := trackChanges(s.changes, models.ChangeEntitySpell, models.ChangeSourceTurtlecraft, "spell_template", "entry", spellID)
 	_, err = s.db.Exec(`
 		INSERT INTO spell_template (entry, name, description)
 		VALUES (?, ?, ?)
@@ -153,12 +165,14 @@ func (s *SyncService) FetchAndImportSpell(spellID int, iconDir string) *SyncSpel
 		}
 	}
 
+	s.invalidate("spell_template")
+	recordChanges(name)
 	fmt.Printf("✓ Synced Spell %d: %s (Desc len: %d)\n", spellID, name, len(description))
 
 	// Fix spell icon if iconDir is provided
 	if iconDir != "" {
 		iconFixService := NewIconFixService(s.db, iconDir)
-		success, iconName, _ := iconFixService.FixSingleSpell(s.db, spellID)
+		success, iconName, _ := iconFixService.FixSingleSpell(ctx, s.db, spellID)
 		if success {
 			fmt.Printf("  ✓ Fixed spell icon: %s\n", iconName)
 		}
@@ -173,7 +187,7 @@ func (s *SyncService) FetchAndImportSpell(spellID int, iconDir string) *SyncSpel
 }
 
 // FullSyncSpells re-syncs all spells referenced by items
-func (s *SyncService) FullSyncSpells(delayMs int, fixIcons bool, iconDir string, startFrom int, progressCb ProgressCallback) *FullSyncResult {
+func (s *SyncService) FullSyncSpells(ctx context.Context, delayMs int, fixIcons bool, iconDir string, startFrom int, progressCb ProgressCallback) *FullSyncResult {
 	if delayMs <= 0 {
 		delayMs = 200
 	}
@@ -216,22 +230,32 @@ func (s *SyncService) FullSyncSpells(delayMs int, fixIcons bool, iconDir string,
 
 	for i, spellID := range spellIDs {
 		// Check for stop request
-		if s.IsStopped() {
+		if ctx.Err() != nil {
 			result.Message = "Sync stopped by user"
 			return result
 		}
 
-		s.SyncSpell(spellID, iconDir, "")
-		result.Updated++
+		err := s.SyncSpell(ctx, spellID, iconDir, "")
+		if ctx.Err() != nil {
+			// Cut off by a pause: don't count or checkpoint it, so a resumed run syncs it again
+			result.Message = "Sync stopped by user"
+			return result
+		}
+		if err != nil {
+			result.Failed++
+			if len(result.Errors) < 10 {
+				result.Errors = append(result.Errors, fmt.Sprintf("Spell %d: %v", spellID, err))
+			}
+		} else {
+			result.Updated++
+		}
 		result.LastSyncedID = spellID
 
 		if progressCb != nil {
 			progressCb(i+1, len(spellIDs), spellID, fmt.Sprintf("Spell %d", spellID))
 		}
 
-		if delayMs > 0 {
-			time.Sleep(time.Duration(delayMs) * time.Millisecond)
-		}
+		s.throttle(ctx, delayMs)
 	}
 
 	result.Message = "Full spell sync complete"
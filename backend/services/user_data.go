@@ -0,0 +1,720 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"shelllab/backend/database"
+	"shelllab/backend/database/models"
+)
+
+const (
+	// UserDataFormat identifies user data archives
+	UserDataFormat = "shelllab-userdata"
+	// UserDataVersion is the archive version written by Export
+	UserDataVersion = 1
+
+	// ImportModeMerge adds new rows and keeps local rows on conflict
+	ImportModeMerge = "merge"
+	// ImportModeReplace wipes the imported tables first
+	ImportModeReplace = "replace"
+
+	// DefaultBackupInterval is how often automatic backups are taken
+	DefaultBackupInterval = 24 * time.Hour
+	// DefaultBackupKeep is how many backups are retained
+	DefaultBackupKeep = 14
+
+	backupPrefix     = "user-data-"
+	backupTimeFormat = "20060102-150405.000"
+	maxConflicts     = 200
+
+	// legacyBackupTimeFormat names backups taken before they had milliseconds
+	legacyBackupTimeFormat = "20060102-150405"
+)
+
+// UserDataArchive is the portable JSON form of every user table
+type UserDataArchive struct {
+	Format     string                              `json:"format"`
+	Version    int                                 `json:"version"`
+	ExportedAt string                              `json:"exportedAt"`
+	Tables     map[string][]map[string]interface{} `json:"tables"`
+}
+
+// UserDataConflict is an imported row whose key exists locally with different values
+type UserDataConflict struct {
+	Table  string   `json:"table"`
+	Key    string   `json:"key"`
+	Fields []string `json:"fields"`
+}
+
+// UserTableImport counts what happened to one table
+type UserTableImport struct {
+	Table     string `json:"table"`
+	Added     int    `json:"added"`
+	Unchanged int    `json:"unchanged"`
+	Conflicts int    `json:"conflicts"`
+	Replaced  int    `json:"replaced"`
+}
+
+// UserDataImportResult is returned by Import
+type UserDataImportResult struct {
+	Success   bool               `json:"success"`
+	Mode      string             `json:"mode"`
+	Tables    []UserTableImport  `json:"tables"`
+	Conflicts []UserDataConflict `json:"conflicts"`
+	Skipped   []string           `json:"skipped"`
+	Message   string             `json:"message"`
+}
+
+// UserDataBackup describes a backup file in the backups directory
+type UserDataBackup struct {
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// UserDataService exports, imports and backs up the tables in user.db
+type UserDataService struct {
+	db *sql.DB
+}
+
+// NewUserDataService creates a UserDataService for a database with user.db attached
+func NewUserDataService(db *sql.DB) *UserDataService {
+	return &UserDataService{db: db}
+}
+
+// userTable describes how rows of a user table are matched across machines
+type userTable struct {
+	name    string
+	columns map[string]bool
+	key     []string
+	localID string // rowid alias left out of archives when key is a natural key
+}
+
+// Tables lists the tables in user.db
+func (s *UserDataService) Tables() ([]string, error) {
+	rows, err := s.db.Query(fmt.Sprintf(
+		"SELECT name FROM %s.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%' ORDER BY name", database.UserSchema))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// Export reads every user table into an archive
+func (s *UserDataService) Export() (*UserDataArchive, error) {
+	tables, err := s.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &UserDataArchive{
+		Format:     UserDataFormat,
+		Version:    UserDataVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Tables:     make(map[string][]map[string]interface{}),
+	}
+	for _, name := range tables {
+		t, err := s.describe(s.db, name)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := s.readRows(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", name, err)
+		}
+		archive.Tables[name] = rows
+	}
+	return archive, nil
+}
+
+// ExportJSON returns the archive as indented JSON
+func (s *UserDataService) ExportJSON() ([]byte, error) {
+	archive, err := s.Export()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(archive, "", "  ")
+}
+
+// ParseArchive decodes and validates an archive
+func ParseArchive(data []byte) (*UserDataArchive, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var archive UserDataArchive
+	if err := dec.Decode(&archive); err != nil {
+		return nil, fmt.Errorf("invalid user data archive: %w", err)
+	}
+	if archive.Format != UserDataFormat {
+		return nil, fmt.Errorf("not a ShellLab user data archive")
+	}
+	if archive.Version < 1 || archive.Version > UserDataVersion {
+		return nil, fmt.Errorf("unsupported user data archive version %d", archive.Version)
+	}
+	for _, rows := range archive.Tables {
+		for _, row := range rows {
+			for col, v := range row {
+				if n, ok := v.(json.Number); ok {
+					row[col] = jsonNumber(n)
+				}
+			}
+		}
+	}
+	archive.upgradeFavorites()
+	return &archive, nil
+}
+
+// upgradeFavorites turns the favorites table of archives written before
+// wishlists into wishlists and wishlist_items rows, the way
+// WishlistRepository.MigrateFavorites moves a local favorites table, so a
+// replace import clears the wishlist tables. Each category becomes a list
+// created with its oldest favorite; the default list is left to
+// WishlistRepository.InitSchema.
+func (a *UserDataArchive) upgradeFavorites() {
+	favorites, ok := a.Tables["favorites"]
+	if !ok {
+		return
+	}
+	delete(a.Tables, "favorites")
+
+	lists := make(map[string]map[string]interface{})
+	var names []string
+	for _, row := range favorites {
+		list, _ := row["category"].(string)
+		list = strings.TrimSpace(list)
+		if list == "" {
+			list = models.DefaultWishlist
+		}
+		status := row["status"]
+		if status == nil {
+			status = int64(0)
+		}
+		addedAt, _ := row["added_at"].(string)
+
+		// The date an item was obtained wasn't recorded, so obtained_at stays empty
+		a.Tables["wishlist_items"] = append(a.Tables["wishlist_items"], map[string]interface{}{
+			"wishlist":       list,
+			"item_entry":     row["item_entry"],
+			"character_name": "",
+			"status":         status,
+			"added_at":       addedAt,
+		})
+
+		if list == models.DefaultWishlist {
+			continue
+		}
+		if l, ok := lists[list]; !ok {
+			lists[list] = map[string]interface{}{"name": list, "created_at": addedAt}
+			names = append(names, list)
+		} else if addedAt < l["created_at"].(string) {
+			l["created_at"] = addedAt
+		}
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		a.Tables["wishlists"] = append(a.Tables["wishlists"], lists[name])
+	}
+}
+
+// Import writes an archive in a single transaction. Merge adds rows whose key
+// is new and reports rows that differ from the local copy, keeping the local
+// one; replace clears each imported table first.
+func (s *UserDataService) Import(archive *UserDataArchive, mode string) (*UserDataImportResult, error) {
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return nil, fmt.Errorf("unknown import mode %q", mode)
+	}
+	result := &UserDataImportResult{Mode: mode, Tables: []UserTableImport{}, Conflicts: []UserDataConflict{}, Skipped: []string{}}
+
+	local, err := s.Tables()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(local))
+	for _, name := range local {
+		exists[name] = true
+	}
+
+	names := make([]string, 0, len(archive.Tables))
+	for name := range archive.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, name := range names {
+		if !exists[name] {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		t, err := s.describe(tx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		stats := UserTableImport{Table: name}
+		if mode == ImportModeReplace {
+			err = s.replaceRows(tx, t, archive.Tables[name], &stats)
+		} else {
+			err = s.mergeRows(tx, t, archive.Tables[name], &stats, result)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		result.Tables = append(result.Tables, stats)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.Success = true
+	result.Message = result.summary()
+	return result, nil
+}
+
+func (r *UserDataImportResult) summary() string {
+	var parts []string
+	for _, t := range r.Tables {
+		if r.Mode == ImportModeReplace {
+			parts = append(parts, fmt.Sprintf("%s: %d rows", t.Table, t.Added))
+			continue
+		}
+		part := fmt.Sprintf("%s: %d added, %d unchanged", t.Table, t.Added, t.Unchanged)
+		if t.Conflicts > 0 {
+			part += fmt.Sprintf(", %d conflicts kept local", t.Conflicts)
+		}
+		parts = append(parts, part)
+	}
+	if len(r.Skipped) > 0 {
+		parts = append(parts, "skipped unknown tables: "+strings.Join(r.Skipped, ", "))
+	}
+	if len(parts) == 0 {
+		return "Nothing to import"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (s *UserDataService) replaceRows(tx *sql.Tx, t *userTable, rows []map[string]interface{}, stats *UserTableImport) error {
+	res, err := tx.Exec(fmt.Sprintf("DELETE FROM %s.%s", database.UserSchema, t.name))
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	stats.Replaced = int(n)
+
+	for _, row := range rows {
+		if err := s.insertRow(tx, t, row, "INSERT OR REPLACE"); err != nil {
+			return err
+		}
+		stats.Added++
+	}
+	return nil
+}
+
+func (s *UserDataService) mergeRows(tx *sql.Tx, t *userTable, rows []map[string]interface{}, stats *UserTableImport, result *UserDataImportResult) error {
+	for _, row := range rows {
+		existing, err := s.findRow(tx, t, row)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := s.insertRow(tx, t, row, "INSERT"); err != nil {
+				return err
+			}
+			stats.Added++
+			continue
+		}
+
+		var fields []string
+		for col, v := range row {
+			if col == t.localID || !t.columns[col] {
+				continue
+			}
+			if fmt.Sprint(existing[col]) != fmt.Sprint(v) {
+				fields = append(fields, col)
+			}
+		}
+		if len(fields) == 0 {
+			stats.Unchanged++
+			continue
+		}
+
+		sort.Strings(fields)
+		stats.Conflicts++
+		if len(result.Conflicts) < maxConflicts {
+			result.Conflicts = append(result.Conflicts, UserDataConflict{Table: t.name, Key: keyString(t.key, row), Fields: fields})
+		}
+	}
+	return nil
+}
+
+// findRow returns the local row with the same key, or nil
+func (s *UserDataService) findRow(tx *sql.Tx, t *userTable, row map[string]interface{}) (map[string]interface{}, error) {
+	where := make([]string, len(t.key))
+	args := make([]interface{}, len(t.key))
+	for i, k := range t.key {
+		v, ok := row[k]
+		if !ok {
+			return nil, fmt.Errorf("row is missing key column %s", k)
+		}
+		where[i] = k + " = ?"
+		args[i] = v
+	}
+
+	rows, err := tx.Query(fmt.Sprintf("SELECT * FROM %s.%s WHERE %s LIMIT 1", database.UserSchema, t.name, strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	found, err := scanTypedRows(rows)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return found[0], nil
+}
+
+// insertRow writes the columns of row that exist locally
+func (s *UserDataService) insertRow(tx *sql.Tx, t *userTable, row map[string]interface{}, verb string) error {
+	var cols []string
+	for col := range row {
+		if t.columns[col] && col != t.localID {
+			cols = append(cols, col)
+		}
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	sort.Strings(cols)
+
+	args := make([]interface{}, len(cols))
+	for i, c := range cols {
+		args[i] = row[c]
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	_, err := tx.Exec(fmt.Sprintf("%s INTO %s.%s (%s) VALUES (%s)",
+		verb, database.UserSchema, t.name, strings.Join(cols, ", "), placeholders), args...)
+	return err
+}
+
+// readRows returns all rows of a table, without the local id when it isn't the key
+func (s *UserDataService) readRows(t *userTable) ([]map[string]interface{}, error) {
+	order := strings.Join(t.key, ", ")
+	rows, err := s.db.Query(fmt.Sprintf("SELECT * FROM %s.%s ORDER BY %s", database.UserSchema, t.name, order))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result, err := scanTypedRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if t.localID != "" {
+		for _, row := range result {
+			delete(row, t.localID)
+		}
+	}
+	return result, nil
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// describe reads a table's columns and picks its matching key: the first
+// UNIQUE constraint, otherwise the primary key
+func (s *UserDataService) describe(q queryer, table string) (*userTable, error) {
+	t := &userTable{name: table, columns: make(map[string]bool)}
+
+	rows, err := q.Query(fmt.Sprintf("PRAGMA %s.table_info(%s)", database.UserSchema, table))
+	if err != nil {
+		return nil, err
+	}
+	var pk []string
+	var pkTypes []string
+	for rows.Next() {
+		var cid, notNull, pos int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pos); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.columns[name] = true
+		if pos > 0 {
+			pk = append(pk, name)
+			pkTypes = append(pkTypes, strings.ToUpper(colType))
+		}
+	}
+	rows.Close()
+
+	unique, err := s.uniqueKey(q, table)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(unique) > 0:
+		t.key = unique
+		if len(pk) == 1 && pkTypes[0] == "INTEGER" {
+			t.localID = pk[0]
+		}
+	case len(pk) > 0:
+		t.key = pk
+	default:
+		t.key = []string{"rowid"}
+	}
+	return t, nil
+}
+
+// uniqueKey returns the columns of the first UNIQUE constraint declared on a table
+func (s *UserDataService) uniqueKey(q queryer, table string) ([]string, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA %s.index_list(%s)", database.UserSchema, table))
+	if err != nil {
+		return nil, err
+	}
+	var indexes []string
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if unique == 1 && origin == "u" {
+			indexes = append(indexes, name)
+		}
+	}
+	rows.Close()
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	sort.Strings(indexes)
+
+	rows, err = q.Query(fmt.Sprintf("PRAGMA %s.index_info(%s)", database.UserSchema, indexes[0]))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols []string
+	for rows.Next() {
+		var seqno, cid int
+		var name string
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		cols = append(cols, name)
+	}
+	return cols, rows.Err()
+}
+
+// ============================================================================
+// Backups
+// ============================================================================
+
+// Backup writes an archive to dir and prunes all but the newest keep backups.
+// A keep of 0 prunes nothing.
+func (s *UserDataService) Backup(dir string, keep int) (string, error) {
+	data, err := s.ExportJSON()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path, err := writeNewBackup(dir, data)
+	if err != nil {
+		return "", err
+	}
+
+	if keep > 0 {
+		backups, err := s.ListBackups(dir)
+		if err != nil {
+			return path, err
+		}
+		for _, b := range backups[min(keep, len(backups)):] {
+			if err := os.Remove(filepath.Join(dir, b.Name)); err != nil {
+				fmt.Printf("[UserData] ⚠ Failed to remove old backup %s: %v\n", b.Name, err)
+			}
+		}
+	}
+	return path, nil
+}
+
+// writeNewBackup writes data to a backup file named after the current time.
+// The file is created exclusively: when two backups are taken in the same
+// millisecond (e.g. the one before a restore and a manual one) the second
+// takes the next free millisecond instead of overwriting the first.
+func writeNewBackup(dir string, data []byte) (string, error) {
+	stamp := time.Now()
+	for {
+		path := filepath.Join(dir, backupPrefix+stamp.Format(backupTimeFormat)+".json")
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			stamp = stamp.Add(time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		return path, nil
+	}
+}
+
+// ListBackups returns the backups in dir, newest first
+func (s *UserDataService) ListBackups(dir string) ([]UserDataBackup, error) {
+	files, err := filepath.Glob(filepath.Join(dir, backupPrefix+"*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []UserDataBackup{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(file)
+		created := info.ModTime()
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), ".json")
+		for _, layout := range []string{backupTimeFormat, legacyBackupTimeFormat} {
+			if t, err := time.ParseInLocation(layout, stamp, time.Local); err == nil {
+				created = t
+				break
+			}
+		}
+		backups = append(backups, UserDataBackup{Name: name, Size: info.Size(), CreatedAt: created.Format(time.RFC3339)})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// StartAutoBackup takes a backup whenever the newest one is older than
+// interval, checking at startup and then every interval until ctx is done
+func (s *UserDataService) StartAutoBackup(ctx context.Context, dir string, interval time.Duration, keep int) {
+	check := func() {
+		backups, err := s.ListBackups(dir)
+		if err != nil {
+			fmt.Printf("[UserData] ⚠ Failed to list backups: %v\n", err)
+			return
+		}
+		if len(backups) > 0 {
+			if last, err := time.Parse(time.RFC3339, backups[0].CreatedAt); err == nil && time.Since(last) < interval {
+				return
+			}
+		}
+		path, err := s.Backup(dir, keep)
+		if err != nil {
+			fmt.Printf("[UserData] ⚠ Automatic backup failed: %v\n", err)
+			return
+		}
+		fmt.Printf("[UserData] ✓ Backed up user data to %s\n", path)
+	}
+
+	go func() {
+		check()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				check()
+			}
+		}
+	}()
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// scanTypedRows reads rows keeping int64/float64/string values
+func scanTypedRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// jsonNumber converts a decoded JSON number to int64 or float64
+func jsonNumber(n json.Number) interface{} {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+		return f
+	}
+	return string(n)
+}
+
+// keyString formats key columns as "col=value, ..."
+func keyString(key []string, row map[string]interface{}) string {
+	parts := make([]string, len(key))
+	for i, k := range key {
+		parts[i] = fmt.Sprintf("%s=%v", k, row[k])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,275 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"shelllab/backend/database"
+	"shelllab/backend/services"
+)
+
+// wishlistContents returns "list:item:status" for every wishlist item, and the list names
+func wishlistContents(t *testing.T, db *database.SQLiteDB) ([]string, []string) {
+	t.Helper()
+	rows, err := db.DB().Query(`SELECT wishlist || ':' || item_entry || ':' || status FROM user.wishlist_items ORDER BY wishlist, item_entry`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		items = append(items, s)
+	}
+
+	lists := []string{}
+	rows, err = db.DB().Query(`SELECT name FROM user.wishlists ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		lists = append(lists, s)
+	}
+	return items, lists
+}
+
+func TestImportLegacyFavorites(t *testing.T) {
+	legacy := []byte(`{
+		"format": "shelllab-userdata",
+		"version": 1,
+		"tables": {
+			"favorites": [
+				{"id": 1, "item_entry": 10, "category": "", "added_at": "2024-01-02T00:00:00Z", "status": 0},
+				{"id": 2, "item_entry": 20, "category": " Raid ", "added_at": "2024-01-03T00:00:00Z", "status": 1},
+				{"id": 3, "item_entry": 30, "category": "Raid", "added_at": "2024-01-01T00:00:00Z"}
+			]
+		}
+	}`)
+
+	tests := []struct {
+		name  string
+		mode  string
+		items []string
+		lists []string
+	}{
+		{
+			name:  "replace clears existing wishlists",
+			mode:  services.ImportModeReplace,
+			items: []string{"Favorites:10:0", "Raid:20:1", "Raid:30:0"},
+			lists: []string{"Favorites", "Raid"},
+		},
+		{
+			name:  "merge keeps existing wishlists",
+			mode:  services.ImportModeMerge,
+			items: []string{"Favorites:10:0", "Old:99:0", "Raid:20:1", "Raid:30:0"},
+			lists: []string{"Favorites", "Old", "Raid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			wishlists := database.NewWishlistRepository(db)
+			if err := wishlists.CreateWishlist("Old", ""); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := wishlists.AddItem(&database.WishlistItem{Wishlist: "Old", ItemEntry: 99}); err != nil {
+				t.Fatal(err)
+			}
+
+			archive, err := services.ParseArchive(legacy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := archive.Tables["favorites"]; ok {
+				t.Fatal("favorites table left in the parsed archive")
+			}
+			result, err := services.NewUserDataService(db.DB()).Import(archive, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Skipped) > 0 {
+				t.Errorf("skipped tables %v", result.Skipped)
+			}
+			// The app restores the default list after every import
+			if err := wishlists.InitSchema(); err != nil {
+				t.Fatal(err)
+			}
+
+			items, lists := wishlistContents(t, db)
+			if !reflect.DeepEqual(items, tt.items) {
+				t.Errorf("items = %v, want %v", items, tt.items)
+			}
+			if !reflect.DeepEqual(lists, tt.lists) {
+				t.Errorf("lists = %v, want %v", lists, tt.lists)
+			}
+		})
+	}
+}
+
+func TestImportModes(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		items     []string
+		notes     string
+		conflicts int
+	}{
+		{
+			name:      "merge keeps local rows on conflict",
+			mode:      services.ImportModeMerge,
+			items:     []string{"Favorites:10:0", "Favorites:11:0", "Old:99:0"},
+			notes:     "local",
+			conflicts: 1,
+		},
+		{
+			name:  "replace takes the archive as is",
+			mode:  services.ImportModeReplace,
+			items: []string{"Favorites:10:0", "Old:99:0"},
+			notes: "remote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := openTestDB(t)
+			remoteLists := database.NewWishlistRepository(remote)
+			if err := remoteLists.CreateWishlist("Old", ""); err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range []*database.WishlistItem{
+				{Wishlist: "Favorites", ItemEntry: 10},
+				{Wishlist: "Old", ItemEntry: 99, Notes: "remote"},
+			} {
+				if _, err := remoteLists.AddItem(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			local := openTestDB(t)
+			localLists := database.NewWishlistRepository(local)
+			if err := localLists.CreateWishlist("Old", ""); err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range []*database.WishlistItem{
+				{Wishlist: "Favorites", ItemEntry: 11},
+				{Wishlist: "Old", ItemEntry: 99, Notes: "local"},
+			} {
+				if _, err := localLists.AddItem(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Lists created in different seconds would conflict on created_at too
+			for _, db := range []*database.SQLiteDB{remote, local} {
+				if _, err := db.DB().Exec(`UPDATE user.wishlists SET created_at = '2024-01-01T00:00:00Z'`); err != nil {
+					t.Fatal(err)
+				}
+			}
+			archive, err := services.NewUserDataService(remote.DB()).Export()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := services.NewUserDataService(local.DB()).Import(archive, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Conflicts) != tt.conflicts {
+				t.Errorf("conflicts = %v, want %d", result.Conflicts, tt.conflicts)
+			}
+			items, _ := wishlistContents(t, local)
+			if !reflect.DeepEqual(items, tt.items) {
+				t.Errorf("items = %v, want %v", items, tt.items)
+			}
+			var notes string
+			if err := local.DB().QueryRow(`SELECT notes FROM user.wishlist_items WHERE item_entry = 99`).Scan(&notes); err != nil {
+				t.Fatal(err)
+			}
+			if notes != tt.notes {
+				t.Errorf("notes = %q, want %q", notes, tt.notes)
+			}
+		})
+	}
+}
+
+func TestBackupPruning(t *testing.T) {
+	tests := []struct {
+		name string
+		keep int
+		want int
+	}{
+		{name: "prunes to keep", keep: 2, want: 2},
+		{name: "keep 0 prunes nothing", keep: 0, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			dir := t.TempDir()
+			oldest := "user-data-20200101-000000.json"
+			for _, name := range []string{oldest, "user-data-20200102-000000.json", "user-data-20200103-000000.json"} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := services.NewUserDataService(db.DB()).Backup(dir, tt.keep); err != nil {
+				t.Fatal(err)
+			}
+			backups, err := services.NewUserDataService(db.DB()).ListBackups(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(backups) != tt.want {
+				t.Fatalf("%d backups left, want %d", len(backups), tt.want)
+			}
+			_, err = os.Stat(filepath.Join(dir, oldest))
+			if kept := err == nil; kept != (tt.keep == 0) {
+				t.Errorf("oldest backup kept = %v", kept)
+			}
+		})
+	}
+}
+
+func TestBackupsTakenTogetherAreAllKept(t *testing.T) {
+	db := openTestDB(t)
+	dir := t.TempDir()
+	userData := services.NewUserDataService(db.DB())
+
+	// Fast enough that several land in the same millisecond
+	paths := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		path, err := userData.Backup(dir, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[path] = true
+	}
+	if len(paths) != 5 {
+		t.Errorf("5 backups written to %d files", len(paths))
+	}
+
+	backups, err := userData.ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 5 {
+		t.Fatalf("%d backups listed, want 5", len(backups))
+	}
+	for i := 1; i < len(backups); i++ {
+		if backups[i-1].CreatedAt < backups[i].CreatedAt {
+			t.Errorf("backups not listed newest first: %s before %s", backups[i-1].Name, backups[i].Name)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package tooltip
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	_ "image/jpeg"
+
+	"shelllab/backend/database/models"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Image layout in pixels, matching ItemTooltip.jsx
+const (
+	imgPadding  = 10
+	imgIconSize = 56
+	imgIconGap  = 6
+	imgMinWidth = 240
+	imgMaxWidth = 320
+	imgColGap   = 16
+	imgBlockGap = 6
+	imgFontSize = 12
+	imgNameSize = 14
+)
+
+var (
+	imgBackground = color.RGBA{0x07, 0x07, 0x07, 0xff}
+	imgBorder     = hexColor(ColorBorder)
+)
+
+type faces struct {
+	regular, bold, italic font.Face
+}
+
+var (
+	fontsOnce                         sync.Once
+	regularFont, boldFont, italicFont *opentype.Font
+	fontErr                           error
+)
+
+// newFaces returns faces for one image. The embedded Go fonts are parsed
+// once, but faces keep per-glyph buffers and aren't safe for concurrent use,
+// so every image gets its own and closes them when drawn.
+func newFaces() (faces, error) {
+	fontsOnce.Do(func() {
+		parse := func(ttf []byte) *opentype.Font {
+			f, err := opentype.Parse(ttf)
+			if err != nil && fontErr == nil {
+				fontErr = err
+			}
+			return f
+		}
+		regularFont, boldFont, italicFont = parse(goregular.TTF), parse(gobold.TTF), parse(goitalic.TTF)
+	})
+	if fontErr != nil {
+		return faces{}, fontErr
+	}
+
+	var err error
+	newFace := func(f *opentype.Font, size float64) font.Face {
+		if err != nil {
+			return nil
+		}
+		var face font.Face
+		face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		return face
+	}
+	fs := faces{
+		regular: newFace(regularFont, imgFontSize),
+		bold:    newFace(boldFont, imgNameSize),
+		italic:  newFace(italicFont, imgFontSize),
+	}
+	if err != nil {
+		fs.close()
+		return faces{}, err
+	}
+	return fs, nil
+}
+
+// close releases the faces once the image is drawn
+func (f faces) close() {
+	for _, face := range []font.Face{f.regular, f.bold, f.italic} {
+		if face != nil {
+			face.Close()
+		}
+	}
+}
+
+func (f faces) forStyle(s Style) font.Face {
+	switch s {
+	case StyleName, StyleSetName:
+		return f.bold
+	case StyleDescription:
+		return f.italic
+	default:
+		return f.regular
+	}
+}
+
+// row is a laid out text row of the image
+type row struct {
+	left, right string
+	face        font.Face
+	color       color.Color
+	indent      int
+	gap         bool
+}
+
+// Image draws the tooltip in the game's style. The icon may be nil.
+func Image(t *models.TooltipData, icon *Icon) (image.Image, error) {
+	fs, err := newFaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fonts: %w", err)
+	}
+	defer fs.close()
+
+	textMax := imgMaxWidth - 2*imgPadding
+	var rows []row
+	for _, l := range Lines(t) {
+		face := fs.forStyle(l.Style)
+		c := hexColor(Color(l.Style, t.Quality))
+
+		indent := 0
+		text := l.Left
+		if l.Style == StyleSetItem {
+			indent = 8
+			text = strings.TrimSpace(text)
+		}
+
+		// The right column stays on the first row and the left side wraps
+		// beside it. A right column too wide to share a row gets its own rows.
+		leftMax := textMax - indent
+		right := l.Right
+		if right != "" {
+			leftMax -= imgColGap + font.MeasureString(face, right).Ceil()
+			if leftMax < textMax/2 {
+				leftMax, right = textMax-indent, ""
+			}
+		}
+		for i, part := range wrap(face, text, leftMax) {
+			r := row{left: part, face: face, color: c, indent: indent, gap: l.Gap && i == 0}
+			if i == 0 {
+				r.right = right
+			}
+			rows = append(rows, r)
+		}
+		if right == "" && l.Right != "" {
+			for _, part := range wrap(face, l.Right, textMax) {
+				rows = append(rows, row{right: part, face: face, color: c})
+			}
+		}
+	}
+
+	// Box width fits the widest row within the min/max bounds
+	textWidth := imgMinWidth - 2*imgPadding
+	for _, r := range rows {
+		w := r.indent + font.MeasureString(r.face, r.left).Ceil()
+		if r.right != "" {
+			w += imgColGap + font.MeasureString(r.face, r.right).Ceil()
+		}
+		textWidth = max(textWidth, min(w, textMax))
+	}
+	boxWidth := textWidth + 2*imgPadding
+
+	boxHeight := 2 * imgPadding
+	for _, r := range rows {
+		boxHeight += lineHeight(r.face)
+		if r.gap {
+			boxHeight += imgBlockGap
+		}
+	}
+
+	iconImg := decodeIcon(icon)
+	boxX := 0
+	if iconImg != nil {
+		boxX = imgIconSize + imgIconGap
+	}
+	img := image.NewRGBA(image.Rect(0, 0, boxX+boxWidth, max(boxHeight, imgIconSize)))
+
+	if iconImg != nil {
+		iconRect := image.Rect(0, 0, imgIconSize, imgIconSize)
+		draw.CatmullRom.Scale(img, iconRect, iconImg, iconImg.Bounds(), draw.Over, nil)
+		strokeRect(img, iconRect, imgBorder)
+	}
+
+	box := image.Rect(boxX, 0, boxX+boxWidth, boxHeight)
+	draw.Draw(img, box, image.NewUniform(imgBackground), image.Point{}, draw.Src)
+	strokeRect(img, box, imgBorder)
+
+	y := imgPadding
+	for _, r := range rows {
+		if r.gap {
+			y += imgBlockGap
+		}
+		ascent := r.face.Metrics().Ascent.Ceil()
+		d := &font.Drawer{Dst: img, Src: image.NewUniform(r.color), Face: r.face}
+		d.Dot = fixed.P(boxX+imgPadding+r.indent, y+ascent)
+		d.DrawString(r.left)
+		if r.right != "" {
+			d.Dot = fixed.P(box.Max.X-imgPadding-font.MeasureString(r.face, r.right).Ceil(), y+ascent)
+			d.DrawString(r.right)
+		}
+		y += lineHeight(r.face)
+	}
+	return img, nil
+}
+
+// PNG draws the tooltip and encodes it as PNG
+func PNG(t *models.TooltipData, icon *Icon) ([]byte, error) {
+	img, err := Image(t, icon)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadIcon reads an icon from iconDir by its icon_path name, or returns nil
+// when it has not been downloaded
+func LoadIcon(iconDir, iconPath string) *Icon {
+	if iconPath == "" || filepath.IsAbs(iconPath) || len(iconPath) >= 100 {
+		return nil
+	}
+	name := strings.ToLower(filepath.Base(iconPath))
+	for _, ext := range []string{".png", ".jpg", ".jpeg"} {
+		if data, err := os.ReadFile(filepath.Join(iconDir, name+ext)); err == nil {
+			mimeType := "image/jpeg"
+			if ext == ".png" {
+				mimeType = "image/png"
+			}
+			return &Icon{Data: data, MimeType: mimeType}
+		}
+	}
+	return nil
+}
+
+func decodeIcon(icon *Icon) image.Image {
+	if icon == nil || len(icon.Data) == 0 {
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(icon.Data))
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+func lineHeight(face font.Face) int {
+	return face.Metrics().Height.Ceil() + 2
+}
+
+// wrap splits text into lines no wider than maxWidth
+func wrap(face font.Face, text string, maxWidth int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{text}
+	}
+
+	var lines []string
+	current := words[0]
+	for _, w := range words[1:] {
+		if font.MeasureString(face, current+" "+w).Ceil() > maxWidth {
+			lines = append(lines, current)
+			current = w
+			continue
+		}
+		current += " " + w
+	}
+	return append(lines, current)
+}
+
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		img.Set(x, r.Min.Y, c)
+		img.Set(x, r.Max.Y-1, c)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.Set(r.Min.X, y, c)
+		img.Set(r.Max.X-1, y, c)
+	}
+}
+
+// hexColor parses "rrggbb"
+func hexColor(hex string) color.RGBA {
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{0xff, 0xff, 0xff, 0xff}
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
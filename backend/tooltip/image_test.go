@@ -0,0 +1,47 @@
+package tooltip_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"shelllab/backend/database/models"
+	"shelllab/backend/tooltip"
+)
+
+// TestPNGConcurrent renders from several goroutines at once, the way the app
+// bindings and API server call it; run with -race to catch shared font state
+func TestPNGConcurrent(t *testing.T) {
+	data := &models.TooltipData{
+		Entry:       19019,
+		Name:        "Thunderfury, Blessed Blade of the Windseeker",
+		Quality:     5,
+		Binding:     "Binds when picked up",
+		ItemType:    "Sword",
+		Slot:        "One-Hand",
+		DamageRange: "44 - 115 Damage",
+		AttackSpeed: "Speed 1.90",
+		Stats:       []string{"+5 Agility", "+8 Stamina"},
+	}
+	want, err := tooltip.PNG(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := tooltip.PNG(data, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !bytes.Equal(got, want) {
+				t.Error("concurrent render differs from the first one")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,159 @@
+package tooltip
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"shelllab/backend/database/models"
+)
+
+// columnSep joins left and right columns in formats without alignment
+const columnSep = " · "
+
+// textWidth is the minimum width of plain text tooltips
+const textWidth = 32
+
+func joinColumns(l Line) string {
+	if l.Right == "" {
+		return l.Left
+	}
+	return l.Left + columnSep + l.Right
+}
+
+var discordEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `_`, `\_`, "`", "\\`", `~`, `\~`, `|`, `\|`, `>`, `\>`, `[`, `\[`, `]`, `\]`,
+)
+
+// bbcodeEscaper breaks up brackets in text with a zero-width space, since
+// BBCode has no escape character and "[b]" in a name would otherwise parse as a tag
+var bbcodeEscaper = strings.NewReplacer(`[`, "[\u200b", `]`, "\u200b]")
+
+// Discord renders Discord-flavoured Markdown
+func Discord(t *models.TooltipData) string {
+	lines := Lines(t)
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l.Gap {
+			out = append(out, "")
+		}
+		text := discordEscaper.Replace(joinColumns(l))
+		switch l.Style {
+		case StyleName:
+			text = fmt.Sprintf("**[%s](<%s>)**", text, fmt.Sprintf(ItemURL, t.Entry))
+		case StyleSetName:
+			text = "**" + text + "**"
+		case StyleSetItem:
+			text = "• " + strings.TrimSpace(text)
+		case StyleEffect, StyleSetBonus:
+			text = "__" + text + "__"
+		case StyleDescription:
+			text = "*" + text + "*"
+		}
+		out = append(out, text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// BBCode renders forum BBCode with game colours
+func BBCode(t *models.TooltipData) string {
+	lines := Lines(t)
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l.Gap {
+			out = append(out, "")
+		}
+		text := bbcodeEscaper.Replace(joinColumns(l))
+		color := Color(l.Style, t.Quality)
+		switch l.Style {
+		case StyleName:
+			text = fmt.Sprintf("[b][url=%s][color=#%s]%s[/color][/url][/b]", fmt.Sprintf(ItemURL, t.Entry), color, text)
+		case StyleDescription:
+			text = fmt.Sprintf("[i][color=#%s]%s[/color][/i]", color, text)
+		default:
+			if color != ColorWhite {
+				text = fmt.Sprintf("[color=#%s]%s[/color]", color, text)
+			}
+		}
+		out = append(out, text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// Text renders plain text with right-aligned columns, for code blocks and chat
+func Text(t *models.TooltipData) string {
+	lines := Lines(t)
+
+	width := textWidth
+	for _, l := range lines {
+		if l.Right != "" {
+			width = max(width, utf8.RuneCountInString(l.Left)+2+utf8.RuneCountInString(l.Right))
+		}
+	}
+
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if l.Gap {
+			out = append(out, "")
+		}
+		if l.Right == "" {
+			out = append(out, l.Left)
+			continue
+		}
+		pad := width - utf8.RuneCountInString(l.Left) - utf8.RuneCountInString(l.Right)
+		out = append(out, l.Left+strings.Repeat(" ", pad)+l.Right)
+	}
+	return strings.Join(out, "\n")
+}
+
+const htmlStyle = `body{margin:0;padding:16px;background:#1a1a1a;font-family:Verdana,Arial,sans-serif}
+.tooltip{display:inline-flex;gap:6px;align-items:flex-start}
+.icon{width:56px;height:56px;border:1px solid #` + ColorBorder + `;border-radius:4px}
+.body{min-width:240px;max-width:320px;padding:8px 10px;background:#070707;border:1px solid #` + ColorBorder + `;border-radius:4px;font-size:12px;line-height:1.35}
+.line{display:flex;justify-content:space-between;gap:16px}
+.gap{margin-top:6px}
+.name{font-size:14px;font-weight:bold}
+.name a{color:inherit;text-decoration:none}
+.set-item{padding-left:8px}
+.description{font-style:italic}`
+
+// HTML renders a standalone HTML page. A non-nil icon is embedded as a data URI.
+func HTML(t *models.TooltipData, icon *Icon) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>\n%s\n</style>\n</head>\n<body>\n<div class=\"tooltip\">\n", html.EscapeString(t.Name), htmlStyle)
+
+	if icon != nil && len(icon.Data) > 0 {
+		fmt.Fprintf(&b, "<img class=\"icon\" alt=\"\" src=\"data:%s;base64,%s\">\n", icon.MimeType, base64.StdEncoding.EncodeToString(icon.Data))
+	}
+
+	b.WriteString("<div class=\"body\">\n")
+	for _, l := range Lines(t) {
+		classes := []string{"line"}
+		if l.Gap {
+			classes = append(classes, "gap")
+		}
+		switch l.Style {
+		case StyleName:
+			classes = append(classes, "name")
+		case StyleSetItem:
+			classes = append(classes, "set-item")
+		case StyleDescription:
+			classes = append(classes, "description")
+		}
+
+		left := html.EscapeString(strings.TrimSpace(l.Left))
+		if l.Style == StyleName {
+			left = fmt.Sprintf("<a href=\"%s\">%s</a>", fmt.Sprintf(ItemURL, t.Entry), left)
+		}
+		fmt.Fprintf(&b, "<div class=\"%s\" style=\"color:#%s\"><span>%s</span>", strings.Join(classes, " "), Color(l.Style, t.Quality), left)
+		if l.Right != "" {
+			fmt.Fprintf(&b, "<span>%s</span>", html.EscapeString(l.Right))
+		}
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</div>\n</div>\n</body>\n</html>\n")
+	return b.String()
+}
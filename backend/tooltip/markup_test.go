@@ -0,0 +1,176 @@
+package tooltip_test
+
+import (
+	"strings"
+	"testing"
+
+	"shelllab/backend/database/models"
+	"shelllab/backend/tooltip"
+)
+
+// markupItem has BBCode tags and Markdown in its text, which every format
+// has to show literally
+var markupItem = &models.TooltipData{
+	Entry:       12345,
+	Name:        "Blade of [b]Bold[/b] *Stars*",
+	Quality:     4,
+	Binding:     "Binds when picked up",
+	Slot:        "One-Hand",
+	ItemType:    "Sword",
+	DamageRange: "10 - 20 Damage",
+	AttackSpeed: "Speed 2.00",
+	Stats:       []string{"+5 Agility"},
+	Effects:     []string{"Equip: Improves your chance to hit by 1%."},
+	SetInfo: &models.ItemSetInfo{
+		Name:    "Battlegear of [Might]",
+		Items:   []string{"Belt of Might"},
+		Bonuses: []string{"(3) Set: +10 Armor."},
+	},
+	Description: "Forged in <fire> & ice_water",
+	SellPrice:   10203,
+}
+
+func TestRender(t *testing.T) {
+	thunderfury := &models.TooltipData{Entry: 19019, Name: "Thunderfury, Blessed Blade of the Windseeker", Quality: 5}
+
+	tests := []struct {
+		name   string
+		data   *models.TooltipData
+		format string
+		want   []string
+	}{
+		{
+			name:   "discord",
+			data:   markupItem,
+			format: tooltip.FormatDiscord,
+			want: []string{
+				`**[Blade of \[b\]Bold\[/b\] \*Stars\*](<https://database.turtlecraft.gg/?item=12345>)**`,
+				"Binds when picked up",
+				"One-Hand · Sword",
+				"10 - 20 Damage · Speed 2.00",
+				"+5 Agility",
+				"",
+				"__Equip: Improves your chance to hit by 1%.__",
+				"",
+				`**Battlegear of \[Might\]**`,
+				"• Belt of Might",
+				"",
+				"__(3) Set: +10 Armor.__",
+				"",
+				`*"Forged in <fire\> & ice\_water"*`,
+				"Sell Price: 1g 2s 3c",
+			},
+		},
+		{
+			name:   "bbcode",
+			data:   markupItem,
+			format: tooltip.FormatBBCode,
+			want: []string{
+				"[b][url=https://database.turtlecraft.gg/?item=12345][color=#a335ee]Blade of [\u200bb\u200b]Bold[\u200b/b\u200b] *Stars*[/color][/url][/b]",
+				"Binds when picked up",
+				"One-Hand · Sword",
+				"10 - 20 Damage · Speed 2.00",
+				"+5 Agility",
+				"",
+				"[color=#1eff00]Equip: Improves your chance to hit by 1%.[/color]",
+				"",
+				"[color=#ffd100]Battlegear of [\u200bMight\u200b][/color]",
+				"[color=#9d9d9d]  Belt of Might[/color]",
+				"",
+				"[color=#1eff00](3) Set: +10 Armor.[/color]",
+				"",
+				`[i][color=#ffd100]"Forged in <fire> & ice_water"[/color][/i]`,
+				"Sell Price: 1g 2s 3c",
+			},
+		},
+		{
+			name:   "text",
+			data:   markupItem,
+			format: tooltip.FormatText,
+			want: []string{
+				"Blade of [b]Bold[/b] *Stars*",
+				"Binds when picked up",
+				"One-Hand                   Sword",
+				"10 - 20 Damage        Speed 2.00",
+				"+5 Agility",
+				"",
+				"Equip: Improves your chance to hit by 1%.",
+				"",
+				"Battlegear of [Might]",
+				"  Belt of Might",
+				"",
+				"(3) Set: +10 Armor.",
+				"",
+				`"Forged in <fire> & ice_water"`,
+				"Sell Price: 1g 2s 3c",
+			},
+		},
+		{
+			name:   "link",
+			data:   thunderfury,
+			format: tooltip.FormatLink,
+			want:   []string{"|cffff8000|Hitem:19019:0:0:0|h[Thunderfury, Blessed Blade of the Windseeker]|h|r"},
+		},
+		{
+			name:   "link keeps brackets in the name",
+			data:   markupItem,
+			format: tooltip.FormatLink,
+			want:   []string{"|cffa335ee|Hitem:12345:0:0:0|h[Blade of [b]Bold[/b] *Stars*]|h|r"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tooltip.Render(tt.data, tt.format, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+// TestHTML checks everything after the fixed stylesheet
+func TestHTML(t *testing.T) {
+	icon := &tooltip.Icon{Data: []byte("png"), MimeType: "image/png"}
+	want := []string{
+		"<body>",
+		`<div class="tooltip">`,
+		`<img class="icon" alt="" src="data:image/png;base64,cG5n">`,
+		`<div class="body">`,
+		`<div class="line name" style="color:#a335ee"><span><a href="https://database.turtlecraft.gg/?item=12345">Blade of [b]Bold[/b] *Stars*</a></span></div>`,
+		`<div class="line" style="color:#ffffff"><span>Binds when picked up</span></div>`,
+		`<div class="line" style="color:#ffffff"><span>One-Hand</span><span>Sword</span></div>`,
+		`<div class="line" style="color:#ffffff"><span>10 - 20 Damage</span><span>Speed 2.00</span></div>`,
+		`<div class="line" style="color:#ffffff"><span>+5 Agility</span></div>`,
+		`<div class="line gap" style="color:#1eff00"><span>Equip: Improves your chance to hit by 1%.</span></div>`,
+		`<div class="line gap" style="color:#ffd100"><span>Battlegear of [Might]</span></div>`,
+		`<div class="line set-item" style="color:#9d9d9d"><span>Belt of Might</span></div>`,
+		`<div class="line gap" style="color:#1eff00"><span>(3) Set: +10 Armor.</span></div>`,
+		`<div class="line gap description" style="color:#ffd100"><span>&#34;Forged in &lt;fire&gt; &amp; ice_water&#34;</span></div>`,
+		`<div class="line" style="color:#ffffff"><span>Sell Price: 1g 2s 3c</span></div>`,
+		"</div>",
+		"</div>",
+		"</body>",
+		"</html>",
+		"",
+	}
+
+	got := tooltip.HTML(markupItem, icon)
+	head, body, ok := strings.Cut(got, "</head>\n")
+	if !ok {
+		t.Fatalf("no </head> in\n%s", got)
+	}
+	if !strings.Contains(head, "<title>Blade of [b]Bold[/b] *Stars*</title>") {
+		t.Errorf("head has no title:\n%s", head)
+	}
+	if want := strings.Join(want, "\n"); body != want {
+		t.Errorf("got\n%s\nwant\n%s", body, want)
+	}
+
+	if got := tooltip.HTML(markupItem, nil); strings.Contains(got, "<img") {
+		t.Error("rendered an icon without one")
+	}
+}
@@ -0,0 +1,201 @@
+// Package tooltip renders item tooltips outside the React UI: chat markup,
+// standalone HTML and in-game item links
+package tooltip
+
+import (
+	"fmt"
+	"strings"
+
+	"shelllab/backend/database/models"
+)
+
+// Output formats accepted by Render
+const (
+	FormatDiscord = "discord"
+	FormatBBCode  = "bbcode"
+	FormatText    = "text"
+	FormatHTML    = "html"
+	FormatLink    = "link"
+)
+
+// Formats lists the formats accepted by Render
+var Formats = []string{FormatDiscord, FormatBBCode, FormatText, FormatHTML, FormatLink}
+
+// ItemURL is the public database page linked from rendered names
+const ItemURL = "https://database.turtlecraft.gg/?item=%d"
+
+// Style says how a tooltip line is coloured
+type Style int
+
+const (
+	StyleName Style = iota
+	StyleNormal
+	StyleEffect
+	StyleSetName
+	StyleSetItem
+	StyleSetBonus
+	StyleDescription
+	StyleSellPrice
+)
+
+// Line is one tooltip row. Right is right-aligned (weapon speed, armor type).
+// Gap marks a row that starts a new block.
+type Line struct {
+	Left  string
+	Right string
+	Style Style
+	Gap   bool
+}
+
+// Icon is an item icon image
+type Icon struct {
+	Data     []byte
+	MimeType string
+}
+
+// Game text colours
+const (
+	ColorWhite  = "ffffff"
+	ColorGreen  = "1eff00"
+	ColorGold   = "ffd100"
+	ColorGrey   = "9d9d9d"
+	ColorBorder = "3a3a3a"
+)
+
+// qualityColors are the item quality colours (Poor..Artifact)
+var qualityColors = []string{"9d9d9d", "ffffff", "1eff00", "0070dd", "a335ee", "ff8000", "e6cc80"}
+
+// QualityColor returns the hex colour (without #) for an item quality
+func QualityColor(quality int) string {
+	if quality < 0 || quality >= len(qualityColors) {
+		return ColorWhite
+	}
+	return qualityColors[quality]
+}
+
+// Color returns the hex colour of a line style
+func Color(style Style, quality int) string {
+	switch style {
+	case StyleName:
+		return QualityColor(quality)
+	case StyleEffect, StyleSetBonus:
+		return ColorGreen
+	case StyleSetName, StyleDescription:
+		return ColorGold
+	case StyleSetItem:
+		return ColorGrey
+	default:
+		return ColorWhite
+	}
+}
+
+// hiddenTypes are item types the in-game tooltip leaves out
+var hiddenTypes = map[string]bool{"Consumable": true, "Junk": true, "Miscellaneous": true}
+
+// Lines lays out a tooltip in the order the game (and ItemTooltip.jsx) shows it
+func Lines(t *models.TooltipData) []Line {
+	lines := []Line{{Left: t.Name, Style: StyleName}}
+	add := func(text string) {
+		if text != "" {
+			lines = append(lines, Line{Left: text, Style: StyleNormal})
+		}
+	}
+
+	add(t.Binding)
+	if t.Unique {
+		add("Unique")
+	}
+
+	itemType := t.ItemType
+	if hiddenTypes[itemType] {
+		itemType = ""
+	}
+	if t.Slot != "" || itemType != "" {
+		if t.Slot == "" {
+			lines = append(lines, Line{Left: itemType, Style: StyleNormal})
+		} else {
+			lines = append(lines, Line{Left: t.Slot, Right: itemType, Style: StyleNormal})
+		}
+	}
+
+	add(t.Classes)
+	add(t.Races)
+	if t.DamageRange != "" {
+		lines = append(lines, Line{Left: t.DamageRange, Right: t.AttackSpeed, Style: StyleNormal})
+	}
+	add(t.DPS)
+	if t.Armor > 0 {
+		add(fmt.Sprintf("%d Armor", t.Armor))
+	}
+	for _, s := range t.Stats {
+		add(s)
+	}
+	for _, r := range t.Resistances {
+		add(r)
+	}
+	add(t.Durability)
+	if t.RequiredLevel > 1 {
+		add(fmt.Sprintf("Requires Level %d", t.RequiredLevel))
+	}
+
+	for i, e := range t.Effects {
+		lines = append(lines, Line{Left: e, Style: StyleEffect, Gap: i == 0})
+	}
+
+	if t.SetInfo != nil {
+		lines = append(lines, Line{Left: t.SetInfo.Name, Style: StyleSetName, Gap: true})
+		for _, item := range t.SetInfo.Items {
+			lines = append(lines, Line{Left: "  " + item, Style: StyleSetItem})
+		}
+		for i, bonus := range t.SetInfo.Bonuses {
+			lines = append(lines, Line{Left: bonus, Style: StyleSetBonus, Gap: i == 0})
+		}
+	}
+
+	if t.Description != "" {
+		lines = append(lines, Line{Left: `"` + t.Description + `"`, Style: StyleDescription, Gap: true})
+	}
+	if t.SellPrice > 0 {
+		lines = append(lines, Line{Left: "Sell Price: " + Money(t.SellPrice), Style: StyleSellPrice})
+	}
+	return lines
+}
+
+// Money formats copper as "1g 2s 3c", leaving out zero parts
+func Money(copper int) string {
+	var parts []string
+	if g := copper / 10000; g > 0 {
+		parts = append(parts, fmt.Sprintf("%dg", g))
+	}
+	if s := copper % 10000 / 100; s > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", s))
+	}
+	if c := copper % 100; c > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%dc", c))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ItemLink returns the chat link the game client uses for an item
+func ItemLink(t *models.TooltipData) string {
+	return fmt.Sprintf("|cff%s|Hitem:%d:0:0:0|h[%s]|h|r", QualityColor(t.Quality), t.Entry, t.Name)
+}
+
+// Render renders a tooltip in one of Formats. The icon is only used by
+// FormatHTML and may be nil.
+func Render(t *models.TooltipData, format string, icon *Icon) (string, error) {
+	switch format {
+	case FormatDiscord:
+		return Discord(t), nil
+	case FormatBBCode:
+		return BBCode(t), nil
+	case FormatText:
+		return Text(t), nil
+	case FormatHTML:
+		return HTML(t, icon), nil
+	case FormatLink:
+		return ItemLink(t), nil
+	default:
+		return "", fmt.Errorf("unknown tooltip format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+}
@@ -0,0 +1,205 @@
+// Command datapatch builds, signs and applies incremental game data patches.
+//
+//	go run ./cmd/datapatch keygen
+//	go run ./cmd/datapatch build -key <private> -from 1 -to 2 [-desc text] [-tables a,b] [-o out.slpatch] old.db new.db
+//	go run ./cmd/datapatch stamp -version 1 [-desc text] shelllab.db
+//	go run ./cmd/datapatch apply -key <public> shelllab.db patch.slpatch...
+//	go run ./cmd/datapatch version shelllab.db
+//
+// Keys may also come from SHELLLAB_PATCH_PRIVATE_KEY / SHELLLAB_PATCH_PUBLIC_KEY.
+package main
+
+import (
+	"crypto/ed25519"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"shelllab/backend/datapatch"
+	"shelllab/backend/dbdiff"
+
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+	switch cmd {
+	case "keygen":
+		keygen()
+	case "build":
+		build(args)
+	case "stamp":
+		stamp(args)
+	case "apply":
+		apply(args)
+	case "version":
+		version(args)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: datapatch <keygen|build|stamp|apply|version> [flags] args\n")
+	os.Exit(2)
+}
+
+func keygen() {
+	pub, priv, err := datapatch.GenerateKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Public key:  %s\n", pub)
+	fmt.Printf("Private key: %s\n", priv)
+	fmt.Println("\nBuild the app with -ldflags \"-X main.dataPatchPublicKey=<public key>\" and keep the private key secret.")
+}
+
+func build(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	key := fs.String("key", os.Getenv("SHELLLAB_PATCH_PRIVATE_KEY"), "Base64 private signing key")
+	from := fs.Int("from", 0, "Data version of old.db")
+	to := fs.Int("to", 0, "Data version after the patch")
+	desc := fs.String("desc", "", "Patch description")
+	tables := fs.String("tables", "", "Comma separated tables (default: all patchable tables)")
+	output := fs.String("o", "", "Output file (default: <from>-<to>.slpatch)")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatal("build needs old.db and new.db")
+	}
+	priv, err := datapatch.ParsePrivateKey(*key)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	oldDB, err := dbdiff.OpenReadOnly(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer oldDB.Close()
+	newDB, err := dbdiff.OpenReadOnly(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer newDB.Close()
+
+	patch, err := datapatch.Build(oldDB, newDB, splitList(*tables), *from, *to, *desc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := datapatch.Encode(patch, priv)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("%d-%d%s", *from, *to, datapatch.FileExt)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	upserts, deletes := patch.RowCount()
+	fmt.Printf("Wrote %s: %d tables, %d rows written, %d deleted (%d bytes)\n",
+		path, len(patch.Tables), upserts, deletes, len(data))
+}
+
+func stamp(args []string) {
+	fs := flag.NewFlagSet("stamp", flag.ExitOnError)
+	v := fs.Int("version", 0, "Data version to record")
+	desc := fs.String("desc", "", "Description")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 || *v <= 0 {
+		log.Fatal("stamp needs -version and a database")
+	}
+	db := openDB(fs.Arg(0))
+	defer db.Close()
+
+	if err := datapatch.Stamp(db, *v, *desc); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s is now at data version %d\n", fs.Arg(0), *v)
+}
+
+func apply(args []string) {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	key := fs.String("key", os.Getenv("SHELLLAB_PATCH_PUBLIC_KEY"), "Comma separated base64 public keys")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal("apply needs a database and at least one patch file")
+	}
+	var keys []ed25519.PublicKey
+	for _, s := range splitList(*key) {
+		k, err := datapatch.ParsePublicKey(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+
+	db := openDB(fs.Arg(0))
+	defer db.Close()
+
+	updater := datapatch.NewUpdater(db, keys)
+	for _, path := range fs.Args()[1:] {
+		result, err := updater.ApplyFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if result.Skipped {
+			fmt.Printf("%s: already applied\n", result.File)
+			continue
+		}
+		fmt.Printf("%s: v%d -> v%d, %d rows written, %d deleted\n",
+			result.File, result.FromVersion, result.ToVersion, result.Upserted, result.Deleted)
+	}
+}
+
+func version(args []string) {
+	if len(args) != 1 {
+		log.Fatal("version needs a database")
+	}
+	db := openDB(args[0])
+	defer db.Close()
+
+	v, err := datapatch.CurrentVersion(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(v)
+}
+
+// openDB opens a database for writing and makes sure data_version exists
+func openDB(path string) *sql.DB {
+	if _, err := os.Stat(path); err != nil {
+		log.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := datapatch.InitSchema(db); err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+// Command dbcheck runs the database integrity checks and optionally applies
+// the safe repairs.
+//
+//	go run ./cmd/dbcheck [-db data/shelllab.db] [-checks a,b] [-samples N] [-format text|json] [-fix] [-list]
+//
+// Exits with status 1 when an error-severity check still fails.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"shelllab/backend/services"
+
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	dbPath := flag.String("db", "data/shelllab.db", "Database to check")
+	checks := flag.String("checks", "", "Comma separated check IDs (default: all)")
+	samples := flag.Int("samples", 5, "Example rows listed per failing check")
+	format := flag.String("format", "text", "Output format: text or json")
+	fix := flag.Bool("fix", false, "Apply safe repairs")
+	list := flag.Bool("list", false, "List the available checks")
+	flag.Parse()
+
+	if *list {
+		all, _ := services.SelectIntegrityChecks(nil)
+		for _, c := range all {
+			fixable := ""
+			if c.Fixable() {
+				fixable = " [fixable]"
+			}
+			fmt.Printf("%-40s %-8s %s%s\n", c.ID, c.Severity, c.Description, fixable)
+		}
+		return
+	}
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		log.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	var ids []string
+	for _, id := range strings.Split(*checks, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	report, err := services.NewIntegrityService(db).Run(ids, *samples, *fix)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch *format {
+	case "json":
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+	case "text":
+		printReport(report, *fix)
+	default:
+		log.Fatalf("unknown format %q (use text or json)", *format)
+	}
+
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+func printReport(report *services.IntegrityReport, fixed bool) {
+	fixable := 0
+	for _, r := range report.Results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("✕ %-40s %s\n", r.ID, r.Error)
+			continue
+		case r.Count == 0 && r.Fixed > 0:
+			fmt.Printf("✓ %-40s repaired %d\n", r.ID, r.Fixed)
+			continue
+		case r.Count == 0:
+			fmt.Printf("✓ %s\n", r.ID)
+			continue
+		}
+
+		mark := "⚠"
+		if r.Severity == services.SeverityError {
+			mark = "✕"
+		}
+		fmt.Printf("%s %-40s %d  %s\n", mark, r.ID, r.Count, r.Description)
+		for _, s := range r.Samples {
+			fmt.Printf("      %s\n", s)
+		}
+		if r.Hint != "" {
+			fmt.Printf("      → %s\n", r.Hint)
+		}
+		if r.Fixable {
+			fixable++
+		}
+	}
+
+	fmt.Printf("\n%d errors, %d warnings\n", report.Errors, report.Warnings)
+	if fixable > 0 && !fixed {
+		fmt.Printf("%d of them can be repaired with -fix\n", fixable)
+	}
+}
@@ -0,0 +1,68 @@
+// Command dbdiff compares two shelllab.db files and reports added, removed
+// and changed rows per table.
+//
+//	go run ./cmd/dbdiff [-format markdown|json] [-tables a,b] [-max N] [-o out] old.db new.db
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"shelllab/backend/dbdiff"
+)
+
+func main() {
+	format := flag.String("format", "markdown", "Output format: markdown or json")
+	tables := flag.String("tables", "", "Comma separated tables to compare (default: templates, loot and atlasloot tables)")
+	maxRows := flag.Int("max", 0, "Max rows listed per table and kind, 0 = all")
+	output := flag.String("o", "", "Write the report to this file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dbdiff [flags] old.db new.db\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := dbdiff.Options{MaxRows: *maxRows}
+	if *tables != "" {
+		for _, t := range strings.Split(*tables, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				opts.Tables = append(opts.Tables, t)
+			}
+		}
+	}
+
+	result, err := dbdiff.CompareFiles(flag.Arg(0), flag.Arg(1), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var report []byte
+	switch *format {
+	case "json":
+		report, err = result.JSON()
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "markdown", "md":
+		report = []byte(result.Markdown())
+	default:
+		log.Fatalf("unknown format %q (use markdown or json)", *format)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(report)
+		return
+	}
+	if err := os.WriteFile(*output, report, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Wrote %s\n", *output)
+}
@@ -0,0 +1,76 @@
+// Command migrate shows and applies pending schema migrations.
+//
+//	go run ./cmd/migrate [-db data/shelllab.db] [-status] [-dry-run]
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"shelllab/backend/database/schema"
+
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	dbPath := flag.String("db", "data/shelllab.db", "Database to migrate")
+	status := flag.Bool("status", false, "List applied and pending migrations without changing anything")
+	dryRun := flag.Bool("dry-run", false, "Run pending migrations in a transaction and roll it back")
+	flag.Parse()
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		log.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", *dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator := schema.NewMigrator(db)
+
+	switch {
+	case *status:
+		applied, err := migrator.Applied()
+		if err != nil {
+			log.Fatal(err)
+		}
+		pending, err := migrator.Pending()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, a := range applied {
+			fmt.Printf("  applied  %3d  %s (%s)\n", a.Version, a.Description, a.AppliedAt)
+		}
+		for _, m := range pending {
+			fmt.Printf("  pending  %3d  %s\n", m.Version, m.Description)
+		}
+
+	case *dryRun:
+		ok, err := migrator.DryRun()
+		for _, m := range ok {
+			fmt.Printf("✓ Would apply %d: %s\n", m.Version, m.Description)
+		}
+		if err != nil {
+			log.Fatalf("✕ %v", err)
+		}
+		if len(ok) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+
+	default:
+		applied, err := migrator.Migrate()
+		for _, m := range applied {
+			fmt.Printf("✓ Applied %d: %s\n", m.Version, m.Description)
+		}
+		if err != nil {
+			log.Fatalf("✕ %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+	}
+}
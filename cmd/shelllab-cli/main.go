@@ -0,0 +1,499 @@
+// Command shelllab-cli runs the sync and import operations without the UI.
+//
+//	go run ./cmd/shelllab-cli <command> [flags]
+//
+//	sync items|spells|quests|npcs   re-sync from turtlecraft.gg / MySQL
+//	fix-icons                       download missing item or spell icons
+//	import mysql|atlasloot|json     run the importers
+//	stats                           print local data statistics
+//	check                           run the database integrity checks
+//	tooltip                         render an item tooltip (text formats or PNG)
+//	gc                              delete downloaded images no database row references
+//	atlas                           pack data/icons into sprite atlases for list views
+//
+// Flags go after the command, e.g. "shelllab-cli sync items -start-from 25000 -delay 200 -workers 4".
+// Ctrl-C stops a sync; the last synced ID is printed so it can be resumed with -start-from.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"shelllab/backend/database"
+	"shelllab/backend/media"
+	"shelllab/backend/services"
+	"shelllab/backend/tooltip"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	flags     = flag.NewFlagSet("shelllab-cli", flag.ExitOnError)
+	dataDir   = flags.String("data", "data", "Data directory containing shelllab.db")
+	startFrom = flags.Int("start-from", 0, "Sync: first ID to process")
+	delayMs   = flags.Int("delay", 0, "Sync: delay between requests in milliseconds")
+	workers   = flags.Int("workers", 0, "Sync items / import atlasloot: concurrent workers (default 10)")
+	fixIcons  = flags.Bool("fix-icons", false, "Sync items/spells: download missing icons while syncing")
+	iconType  = flags.String("type", "item", "fix-icons: item or spell")
+	maxItems  = flags.Int("max", 0, "fix-icons / import atlasloot: maximum entries to process (0 = all)")
+	checks    = flags.String("checks", "", "check: comma separated check IDs (default: all)")
+	fix       = flags.Bool("fix", false, "check: apply safe repairs")
+	itemID    = flags.Int("id", 0, "tooltip: item ID")
+	format    = flags.String("format", tooltip.FormatText, "tooltip: png, "+strings.Join(tooltip.Formats, ", "))
+	outPath   = flags.String("out", "", "tooltip: output file (default: stdout, <id>.png for png)")
+	dryRun    = flags.Bool("dry-run", false, "gc: list what would be deleted without deleting")
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: shelllab-cli <command> [flags]
+
+commands:
+  sync items|spells|quests|npcs
+  fix-icons
+  import mysql|atlasloot|json
+  stats
+  check
+  tooltip
+  gc
+  atlas
+
+flags:`)
+	flags.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	target := ""
+	if cmd == "sync" || cmd == "import" {
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			usage()
+		}
+		target, args = args[0], args[1:]
+	}
+	flags.Usage = usage
+	flags.Parse(args)
+
+	// Ctrl-C cancels the running operation
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// MySQL settings come from .env, as in the app
+	godotenv.Load()
+
+	var db *database.SQLiteDB
+	switch cmd {
+	case "atlas":
+		// Only reads data/icons
+	case "check":
+		// Reports on the database as it is; only -fix changes it
+		db = openExistingDB()
+	default:
+		db = openDB()
+	}
+	if db != nil {
+		defer db.Close()
+	}
+
+	switch cmd {
+	case "sync":
+		runSync(ctx, db, target)
+	case "fix-icons":
+		runFixIcons(ctx, db)
+	case "import":
+		runImport(ctx, db, target)
+	case "stats":
+		runStats(db)
+	case "check":
+		runCheck(db)
+	case "tooltip":
+		runTooltip(db)
+	case "gc":
+		runGC(db)
+	case "atlas":
+		runAtlas()
+	default:
+		usage()
+	}
+}
+
+// openDB opens the database and brings its schema up to date
+func openDB() *database.SQLiteDB {
+	db := openExistingDB()
+	if err := db.InitSchema(); err != nil {
+		log.Fatal(err)
+	}
+	// Syncs record what they change
+	if err := database.NewChangeLogRepository(db).InitSchema(); err != nil {
+		log.Fatal(err)
+	}
+	// Downloads are indexed in media_files
+	if err := media.InitSchema(db.DB()); err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
+
+// openExistingDB opens the database without creating tables or applying migrations
+func openExistingDB() *database.SQLiteDB {
+	dbPath := filepath.Join(*dataDir, "shelllab.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		log.Fatal(err)
+	}
+	db, err := database.NewSQLiteDBWithUserData(dbPath, filepath.Join(*dataDir, "user.db"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
+
+func newHTTPCache() *services.HTTPCache {
+	cache := services.NewHTTPCache(services.SharedHTTPClient(), filepath.Join(*dataDir, "http_cache"), 7*24*time.Hour)
+	if os.Getenv("SHELLLAB_HTTP_REPLAY") == "1" {
+		cache.SetMode(services.CacheModeReplay)
+	}
+	return cache
+}
+
+func newSyncService(db *database.SQLiteDB) *services.SyncService {
+	svc := services.NewSyncService(db.DB())
+	svc.SetCache(newHTTPCache())
+	svc.SetWorkers(*workers)
+	return svc
+}
+
+func connectMySQL() *database.MySQLConnection {
+	dsn := database.MySQLDSNFromEnv()
+	if dsn == "" {
+		return nil
+	}
+	conn, err := database.NewMySQLConnection(dsn)
+	if err != nil {
+		fmt.Printf("⚠ MySQL connection failed: %v\n", err)
+		return nil
+	}
+	fmt.Println("✓ MySQL Connected")
+	return conn
+}
+
+// progress prints at most one line every two seconds, plus the final one
+func progress(label string) func(current, total, id int) {
+	var last time.Time
+	return func(current, total, id int) {
+		if current < total && time.Since(last) < 2*time.Second {
+			return
+		}
+		last = time.Now()
+		pct := 0.0
+		if total > 0 {
+			pct = float64(current) * 100 / float64(total)
+		}
+		fmt.Printf("  %s %d/%d (%.1f%%) last ID %d\n", label, current, total, pct, id)
+	}
+}
+
+// ============================================================================
+// Commands
+// ============================================================================
+
+func runSync(ctx context.Context, db *database.SQLiteDB, target string) {
+	iconDir := filepath.Join(*dataDir, "icons")
+
+	var result *services.FullSyncResult
+	switch target {
+	case "items", "spells", "quests":
+		svc := newSyncService(db)
+		report := progress(target)
+		cb := func(current, total, id int, name string) { report(current, total, id) }
+
+		fmt.Printf("Syncing %s from ID %d (delay %dms)...\n", target, *startFrom, *delayMs)
+		switch target {
+		case "items":
+			result = svc.FullSyncItems(ctx, *delayMs, *fixIcons, iconDir, *startFrom, cb, nil)
+		case "spells":
+			result = svc.FullSyncSpells(ctx, *delayMs, *fixIcons, iconDir, *startFrom, cb)
+		case "quests":
+			result = svc.FullSyncQuests(ctx, *delayMs, *startFrom, cb)
+		}
+
+	case "npcs":
+		mysqlConn := connectMySQL()
+		if mysqlConn != nil {
+			defer mysqlConn.Close()
+		}
+		scraper := services.NewScraperService()
+		scraper.Client = newHTTPCache()
+		creatureRepo := database.NewCreatureRepository(db)
+		if mysqlConn != nil {
+			creatureRepo.SetMySQL(mysqlConn.DB())
+		}
+		npcService := services.NewNpcService(db.DB(), mysqlConn, scraper, database.NewItemRepository(db), creatureRepo, *dataDir)
+
+		report := progress(target)
+		cb := func(current, total, id int, name string) { report(current, total, id) }
+
+		fmt.Printf("Syncing npcs from ID %d (delay %dms)...\n", *startFrom, *delayMs)
+		result = npcService.FullSyncNpcs(ctx, *startFrom, *delayMs, cb)
+
+	default:
+		usage()
+	}
+
+	for _, e := range result.Errors {
+		fmt.Printf("  ✕ %s\n", e)
+	}
+	if ctx.Err() != nil {
+		// Nothing finished yet: resume where this run started
+		next := *startFrom
+		if result.LastSyncedID > 0 {
+			next = result.LastSyncedID + 1
+		}
+		fmt.Printf("⚠ Stopped. Resume with -start-from %d\n", next)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ %s\n", result.Message)
+}
+
+func runFixIcons(ctx context.Context, db *database.SQLiteDB) {
+	iconFixService := services.NewIconFixService(db.DB(), filepath.Join(*dataDir, "icons"))
+
+	var missing []services.MissingIconItem
+	var err error
+	switch *iconType {
+	case "item":
+		missing, err = iconFixService.GetMissingIcons()
+	case "spell":
+		missing, err = iconFixService.GetMissingSpellIcons()
+	default:
+		log.Fatalf("unknown -type %q (use item or spell)", *iconType)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	total := len(missing)
+	if *maxItems > 0 && *maxItems < len(missing) {
+		missing = missing[:*maxItems]
+	}
+	fmt.Printf("Fixing %d of %d missing %s icons...\n", len(missing), total, *iconType)
+
+	fixed, failed := 0, 0
+	for _, item := range missing {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var success bool
+		var iconName string
+		if *iconType == "spell" {
+			success, iconName, err = iconFixService.FixSingleSpell(ctx, db.DB(), item.Entry)
+		} else {
+			success, iconName, err = iconFixService.FixSingleItem(ctx, db.DB(), item.Entry)
+		}
+
+		if err != nil || !success {
+			failed++
+			fmt.Printf("  ✕ %s %d: %v\n", *iconType, item.Entry, err)
+		} else {
+			fixed++
+			fmt.Printf("  ✓ %s %d: %s\n", *iconType, item.Entry, iconName)
+		}
+	}
+
+	fmt.Printf("Fixed %d %s icons, %d failed, %d remaining\n", fixed, *iconType, failed, total-fixed)
+}
+
+func runImport(ctx context.Context, db *database.SQLiteDB, target string) {
+	switch target {
+	case "mysql":
+		mysqlConn := connectMySQL()
+		if mysqlConn == nil {
+			log.Fatal("MySQL is not configured (set MYSQL_USER, MYSQL_PASSWORD, MYSQL_HOST, MYSQL_PORT, MYSQL_DATABASE)")
+		}
+		defer mysqlConn.Close()
+		if err := database.NewMySQLImporter(db.DB(), mysqlConn.DB()).ImportAllFromMySQL(ctx); err != nil {
+			log.Fatalf("✕ %v", err)
+		}
+		fmt.Println("✓ MySQL import complete")
+
+	case "atlasloot":
+		if err := database.NewAtlasLootImporter(db).CheckAndImport(*dataDir); err != nil {
+			log.Fatalf("✕ %v", err)
+		}
+		fmt.Println("✓ AtlasLoot tables up to date")
+
+		// Then fetch the items AtlasLoot references but the database lacks
+		svc := newSyncService(db)
+		count, _ := svc.GetMissingAtlasLootItemCount()
+		if count == 0 {
+			return
+		}
+		fmt.Printf("Importing %d missing AtlasLoot items...\n", count)
+		result, err := svc.ImportMissingItems(ctx, *maxItems, *delayMs)
+		if err != nil {
+			log.Fatalf("✕ %v", err)
+		}
+		for _, e := range result.Errors {
+			fmt.Printf("  ✕ %s\n", e)
+		}
+		fmt.Printf("✓ Checked %d, imported %d, failed %d\n", result.Checked, result.Imported, result.Failed)
+
+	case "json":
+		jsonImports := []struct {
+			file string
+			run  func(path string) error
+		}{
+			{"item_sets.json", database.NewItemSetImporter(db).ImportFromJSON},
+			{"factions.json", database.NewFactionImporter(db).ImportFromJSON},
+			{"item_icons.json", database.NewGeneratedImporter(db.DB()).ImportItemIcons},
+		}
+		for _, imp := range jsonImports {
+			path := filepath.Join(*dataDir, imp.file)
+			if _, err := os.Stat(path); err != nil {
+				fmt.Printf("⚠ %s not found, skipped\n", imp.file)
+				continue
+			}
+			if err := imp.run(path); err != nil {
+				log.Fatalf("✕ %s: %v", imp.file, err)
+			}
+			fmt.Printf("✓ Imported %s\n", imp.file)
+		}
+		// Zones and skills
+		if err := database.NewMetadataImporter(db).ImportAll(*dataDir); err != nil {
+			log.Fatalf("✕ metadata: %v", err)
+		}
+		fmt.Println("✓ Imported metadata")
+
+	default:
+		usage()
+	}
+}
+
+func runStats(db *database.SQLiteDB) {
+	stats := newSyncService(db).GetSyncStats()
+
+	keys := make([]string, 0, len(stats))
+	for k := range stats {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("  %-24s %v\n", k, stats[k])
+	}
+}
+
+func runCheck(db *database.SQLiteDB) {
+	var ids []string
+	for _, id := range strings.Split(*checks, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	report, err := services.NewIntegrityService(db.DB()).Run(ids, 5, *fix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, r := range report.Results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("✕ %-40s %s\n", r.ID, r.Error)
+		case r.Count == 0:
+			fmt.Printf("✓ %s\n", r.ID)
+		case r.Severity == services.SeverityError:
+			fmt.Printf("✕ %-40s %d  %s\n", r.ID, r.Count, r.Description)
+		default:
+			fmt.Printf("⚠ %-40s %d  %s\n", r.ID, r.Count, r.Description)
+		}
+	}
+	fmt.Printf("\n%d errors, %d warnings (see cmd/dbcheck for details)\n", report.Errors, report.Warnings)
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+func runGC(db *database.SQLiteDB) {
+	caches := []*media.Cache{
+		media.New(db.DB(), media.KindIcon, filepath.Join(*dataDir, "icons"), services.SharedHTTPClient()),
+		media.New(db.DB(), media.KindImage, filepath.Join(*dataDir, "npc_images"), services.SharedHTTPClient()),
+	}
+
+	verb := "Removed"
+	if *dryRun {
+		verb = "Would remove"
+	}
+	for _, cache := range caches {
+		result, err := cache.GC(*dryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range result.Removed {
+			fmt.Printf("  %s\n", filepath.Join(cache.Dir(), name))
+		}
+		fmt.Printf("✓ %s: %s %d of %d files (%.1f MB), %d stale index rows\n",
+			cache.Dir(), verb, len(result.Removed), result.Scanned, float64(result.Bytes)/(1<<20), result.StaleRows)
+	}
+}
+
+func runAtlas() {
+	start := time.Now()
+	manifest, err := media.BuildAtlases(filepath.Join(*dataDir, "icons"), media.AtlasSizes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("✓ Packed %d icons into %d sheet(s) per size %v in %s\n",
+		manifest.Count, manifest.Sheets(), manifest.Sizes, time.Since(start).Round(time.Millisecond))
+}
+
+func runTooltip(db *database.SQLiteDB) {
+	if *itemID <= 0 {
+		log.Fatal("-id is required")
+	}
+	items := database.NewItemRepository(db)
+	data, err := items.GetTooltipData(*itemID)
+	if err != nil {
+		log.Fatalf("item %d: %v", *itemID, err)
+	}
+
+	var icon *tooltip.Icon
+	if item, err := items.GetItemByID(*itemID); err == nil {
+		icon = tooltip.LoadIcon(filepath.Join(*dataDir, "icons"), item.IconPath)
+	}
+
+	var out []byte
+	if *format == "png" {
+		if out, err = tooltip.PNG(data, icon); err != nil {
+			log.Fatal(err)
+		}
+		if *outPath == "" {
+			*outPath = fmt.Sprintf("%d.png", *itemID)
+		}
+	} else {
+		text, err := tooltip.Render(data, *format, icon)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out = []byte(text + "\n")
+	}
+
+	if *outPath == "" {
+		os.Stdout.Write(out)
+		return
+	}
+	if err := os.WriteFile(*outPath, out, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("✓ Wrote %s\n", *outPath)
+}
@@ -0,0 +1,45 @@
+// Command shelllab-server serves the game database as a read-only JSON API.
+//
+//	go run ./cmd/shelllab-server [-addr 127.0.0.1:8787] [-data data] [-cors "*"]
+//
+// GET /api lists the endpoints. Icons are served from <data>/icons under /icons/<name>.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"shelllab/backend/api"
+	"shelllab/backend/database"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8787", "Listen address")
+	dataDir := flag.String("data", "data", "Data directory containing shelllab.db and icons")
+	cors := flag.String("cors", "*", "Access-Control-Allow-Origin value (empty to disable)")
+	flag.Parse()
+
+	dbPath := filepath.Join(*dataDir, "shelllab.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		log.Fatal(err)
+	}
+	db, err := database.NewReadOnlySQLiteDB(dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           api.NewServer(db, filepath.Join(*dataDir, "icons"), *cors).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Printf("✓ ShellLab API listening on http://%s/api\n", *addr)
+	log.Fatal(server.ListenAndServe())
+}
@@ -1,5 +1,5 @@
 import React, { useState, useEffect } from "react";
-import { GetNpcFullDetails, SyncNpcData } from "../../../services/api";
+import { GetNpcFullDetails, SyncNpcData, GetLootTree } from "../../../services/api";
 import { useNpcModel, useNpcMap } from "../../../services/useImage";
 import { getQualityColor, formatMoney } from "../../../utils/wow";
 import {
@@ -12,6 +12,7 @@ import {
   DetailLoading,
   DetailError,
   LootItem,
+  LootTree,
 } from "../../ui";
 
 const NPCDetailView = ({ entry, onBack, onNavigate, tooltipHook }) => {
@@ -19,6 +20,9 @@ const NPCDetailView = ({ entry, onBack, onNavigate, tooltipHook }) => {
   const [detail, setDetail] = useState(null);
   const [loading, setLoading] = useState(true);
   const [showMapModal, setShowMapModal] = useState(false);
+  // Loot tab: flat item list, or the loot table with its reference tables
+  const [lootView, setLootView] = useState("list");
+  const [lootTree, setLootTree] = useState(null);
 
   // Use unified image hooks for model and map
   const modelImage = useNpcModel(entry, detail?.modelImageUrl);
@@ -32,6 +36,15 @@ const NPCDetailView = ({ entry, onBack, onNavigate, tooltipHook }) => {
     });
   }, [entry]);
 
+  useEffect(() => {
+    setLootTree(null);
+  }, [entry]);
+
+  useEffect(() => {
+    if (lootView !== "tree" || lootTree || !detail?.lootId) return;
+    GetLootTree(detail.lootId).then((res) => setLootTree(res));
+  }, [lootView, lootTree, detail?.lootId]);
+
   const handleSync = () => {
     setLoading(true);
     SyncNpcData(entry).then((res) => {
@@ -391,7 +404,39 @@ const NPCDetailView = ({ entry, onBack, onNavigate, tooltipHook }) => {
 
               {activeTab === "loot" && (
                 <div className="animate-fade-in">
-                  {loot.length > 0 ? (
+                  {loot.length > 0 && (
+                    <div className="flex justify-end gap-1 mb-2">
+                      {[
+                        { id: "list", label: "All Items" },
+                        { id: "tree", label: "By Table" },
+                      ].map((view) => (
+                        <button
+                          key={view.id}
+                          onClick={() => setLootView(view.id)}
+                          className={`px-2 py-0.5 text-xs rounded border transition-colors ${
+                            lootView === view.id
+                              ? "bg-wow-gold/20 text-wow-gold border-wow-gold/40"
+                              : "bg-black/30 text-gray-400 border-gray-700 hover:text-white"
+                          }`}
+                        >
+                          {view.label}
+                        </button>
+                      ))}
+                    </div>
+                  )}
+                  {loot.length > 0 && lootView === "tree" ? (
+                    lootTree ? (
+                      <LootTree
+                        nodes={lootTree.nodes || []}
+                        onItemClick={(itemId) => onNavigate("item", itemId)}
+                        getItemHandlers={tooltipHook?.getItemHandlers}
+                      />
+                    ) : (
+                      <div className="p-8 text-center text-gray-500 italic">
+                        Loading loot table...
+                      </div>
+                    )
+                  ) : loot.length > 0 ? (
                     <LootGrid>
                       {loot
                         .sort((a, b) => b.chance - a.chance)
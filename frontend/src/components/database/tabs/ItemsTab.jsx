@@ -1,7 +1,8 @@
 import { useState, useEffect, useMemo } from 'react'
-import { GetItemClasses, BrowseItemsByClass } from '../../../../wailsjs/go/main/App'
-import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, LootItem, ContentGrid } from '../../ui'
-import { BrowseItemsByClassAndSlot, filterItems } from '../../../utils/databaseApi'
+import { GetItemClasses } from '../../../../wailsjs/go/main/App'
+import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, LootItem, ContentGrid, SortSelect, PagedFooter } from '../../ui'
+import { BrowseItemsByClass, BrowseItemsByClassAndSlot, filterItems } from '../../../utils/databaseApi'
+import { usePagedList, useDebouncedValue, PAGE_SIZE } from '../../../hooks/usePagedList'
 import { getCategoryIcon } from '../../../utils/categoryIcons'
 import { 
     GRID_LAYOUT, ITEMS_LAYOUT, 
@@ -9,6 +10,15 @@ import {
 } from '../../common/layout'
 import ItemFilters from './ItemFilters'
 
+const SORT_OPTIONS = [
+    { value: '', label: 'Default' },
+    { value: 'name', label: 'Name' },
+    { value: 'level', label: 'Req Level' },
+    { value: 'quality', label: 'Quality' },
+    { value: 'itemLevel', label: 'Item Level' },
+    { value: 'dps', label: 'DPS' },
+]
+
 // Stat ID mappings
 const STAT_IDS = {
     'stamina': 7,
@@ -40,7 +50,6 @@ function ItemsTab({ tooltipHook, onNavigate }) {
     const [selectedClass, setSelectedClass] = useState(null)
     const [selectedSubClass, setSelectedSubClass] = useState(null)
     const [selectedSlot, setSelectedSlot] = useState(null)
-    const [items, setItems] = useState([])
     const [loading, setLoading] = useState(false)
 
     // Independent filter states for each column
@@ -48,6 +57,8 @@ function ItemsTab({ tooltipHook, onNavigate }) {
     const [subClassFilter, setSubClassFilter] = useState('')
     const [slotFilter, setSlotFilter] = useState('')
     const [itemFilter, setItemFilter] = useState('')
+    const [sort, setSort] = useState({ sort: '', desc: false })
+    const nameFilter = useDebouncedValue(itemFilter)
 
     // Advanced Filters
     const [advancedFilters, setAdvancedFilters] = useState({})
@@ -128,43 +139,22 @@ function ItemsTab({ tooltipHook, onNavigate }) {
     // Special marker for "All Slots" selection
     const ALL_SLOTS = { inventoryType: -1, name: 'All Slots' }
     
-    // Browse items when class/subclass/slot selected
-    useEffect(() => {
-        if (selectedClass !== null && selectedSubClass !== null) {
-            // For classes with inventory slots, wait for slot selection (null means not yet selected)
-            if (needsSlotFilter && selectedSlot === null) {
-                setItems([])
-                return
-            }
-            
-            setLoading(true)
-            setItems([])
-            
+    // For classes with inventory slots, wait for slot selection (null means not yet selected)
+    const browseReady = selectedClass !== null && selectedSubClass !== null && !(needsSlotFilter && selectedSlot === null)
+
+    // Browse items page by page when class/subclass/slot selected; name filter and sort are applied server-side
+    const { items, total, hasMore, loading: loadingItems, loadingMore, loadMore, handleScroll } = usePagedList(
+        browseReady ? (page) => {
+            const query = { ...page, ...sort }
             // Check if "All Slots" is selected (inventoryType === -1) or a specific slot
             if (selectedSlot !== null && selectedSlot.inventoryType !== -1) {
-                BrowseItemsByClassAndSlot(selectedClass.class, selectedSubClass.subClass, selectedSlot.inventoryType, '')
-                    .then(res => {
-                        setItems(res || [])
-                        setLoading(false)
-                    })
-                    .catch(err => {
-                        console.error("Failed to browse items by slot:", err)
-                        setLoading(false)
-                    })
-            } else {
-                // "All Slots" selected or non-slot-filtered class - load all items in subclass
-                BrowseItemsByClass(selectedClass.class, selectedSubClass.subClass, '')
-                    .then(res => {
-                        setItems(res || [])
-                        setLoading(false)
-                    })
-                    .catch(err => {
-                        console.error("Failed to browse items:", err)
-                        setLoading(false)
-                    })
+                return BrowseItemsByClassAndSlot(selectedClass.class, selectedSubClass.subClass, selectedSlot.inventoryType, nameFilter, query)
             }
-        }
-    }, [selectedSubClass, selectedSlot, needsSlotFilter])
+            // "All Slots" selected or non-slot-filtered class - browse the whole subclass
+            return BrowseItemsByClass(selectedClass.class, selectedSubClass.subClass, nameFilter, query)
+        } : null,
+        [selectedClass, selectedSubClass, selectedSlot, browseReady, nameFilter, sort]
+    )
 
     // Preload tooltips when items change
     useEffect(() => {
@@ -188,9 +178,9 @@ function ItemsTab({ tooltipHook, onNavigate }) {
         return filterItems(selectedSubClass.inventorySlots, slotFilter)
     }, [selectedSubClass, slotFilter])
     
-    // Advanced Item Filtering
+    // Advanced Item Filtering (applies to the loaded pages)
     const filteredItems = useMemo(() => {
-        let result = filterItems(items, itemFilter)
+        let result = items
 
         // Min Item Level
         if (advancedFilters.minIlvl) {
@@ -284,7 +274,7 @@ function ItemsTab({ tooltipHook, onNavigate }) {
         }
 
         return result
-    }, [items, itemFilter, advancedFilters])
+    }, [items, advancedFilters])
     
     // Debug: Log filtering results
     useEffect(() => {
@@ -364,6 +354,13 @@ function ItemsTab({ tooltipHook, onNavigate }) {
         return parts.length > 0 ? parts.join(' • ') : null
     }, [advancedFilters])
 
+    // Advanced filters only see loaded pages; keep loading while they leave the list short
+    useEffect(() => {
+        if (filterSummary && hasMore && filteredItems.length < PAGE_SIZE / 2) {
+            loadMore()
+        }
+    }, [filterSummary, hasMore, filteredItems.length, loadMore])
+
     // Layout and Filter visibility
     const [showFilters, setShowFilters] = useState(false)
     
@@ -423,7 +420,6 @@ function ItemsTab({ tooltipHook, onNavigate }) {
                                     setSelectedClass(cls)
                                     setSelectedSubClass(null)
                                     setSelectedSlot(null)
-                                    setItems([])
                                     setSubClassFilter('')
                                     setSlotFilter('')
                                     setItemFilter('')
@@ -518,22 +514,27 @@ function ItemsTab({ tooltipHook, onNavigate }) {
                 <SectionHeader 
                     title={
                         selectedSubClass 
-                            ? `${selectedSlot ? selectedSlot.name : selectedSubClass.name} (${filteredItems.length})${filterSummary ? ` • ${filterSummary}` : ''}` 
+                            ? `${selectedSlot ? selectedSlot.name : selectedSubClass.name} (${filteredItems.length}${total > items.length ? ` of ${total}` : ''})${filterSummary ? ` • ${filterSummary}` : ''}` 
                             : 'Select SubClass'
                     }
                     placeholder="Filter by name..."
                     onFilterChange={setItemFilter}
-                    actions={filterToggleButton}
+                    actions={
+                        <div className="flex items-center gap-2">
+                            {browseReady && <SortSelect options={SORT_OPTIONS} value={sort} onChange={setSort} />}
+                            {filterToggleButton}
+                        </div>
+                    }
                 />
                 
-                {loading && (
+                {loadingItems && (
                     <div className="flex-1 flex items-center justify-center text-wow-gold italic animate-pulse">
                         Loading items...
                     </div>
                 )}
                 
-                {!loading && items.length > 0 && (
-                    <ScrollList className="grid grid-cols-1 xl:grid-cols-2 gap-1 p-2 auto-rows-min">
+                {!loadingItems && items.length > 0 && (
+                    <ScrollList className="grid grid-cols-1 xl:grid-cols-2 gap-1 p-2 auto-rows-min" onScroll={handleScroll}>
                         {filteredItems.map((item, idx) => {
                             const itemId = item.entry || item.id || item.itemId
                             const handlers = tooltipHook.getItemHandlers?.(itemId) || {
@@ -551,10 +552,13 @@ function ItemsTab({ tooltipHook, onNavigate }) {
                                 />
                             )
                         })}
+                        <div className="xl:col-span-2">
+                            <PagedFooter loadingMore={loadingMore} hasMore={hasMore} loaded={items.length} total={total} />
+                        </div>
                     </ScrollList>
                 )}
                 
-                {!loading && items.length === 0 && selectedSubClass && (
+                {!loadingItems && items.length === 0 && browseReady && (
                     <div className="flex-1 flex items-center justify-center text-gray-600 italic">
                         No items found
                     </div>
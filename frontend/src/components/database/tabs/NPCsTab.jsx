@@ -1,6 +1,7 @@
-import { useState, useEffect, useMemo, useRef, useCallback } from 'react'
-import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon } from '../../ui'
-import { GetCreatureTypes, BrowseCreaturesByTypePaged, filterItems } from '../../../utils/databaseApi'
+import { useState, useEffect, useMemo } from 'react'
+import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon, SortSelect, PagedFooter } from '../../ui'
+import { GetCreatureTypes, BrowseCreaturesByType, filterItems } from '../../../utils/databaseApi'
+import { usePagedList, useDebouncedValue } from '../../../hooks/usePagedList'
 
 // NPC rank colors
 const getRankColor = (rank) => {
@@ -9,22 +10,21 @@ const getRankColor = (rank) => {
     return '#1eff00' // Normal - Uncommon green
 }
 
-const PAGE_SIZE = 100
+const SORT_OPTIONS = [
+    { value: '', label: 'Default' },
+    { value: 'name', label: 'Name' },
+    { value: 'level', label: 'Level' },
+]
 
 function NPCsTab({ onNavigate, tooltipHook }) {
     const [creatureTypes, setCreatureTypes] = useState([])
     const [selectedCreatureType, setSelectedCreatureType] = useState(null)
-    const [creatures, setCreatures] = useState([])
     const [loading, setLoading] = useState(false)
-    const [loadingMore, setLoadingMore] = useState(false)
-    const [total, setTotal] = useState(0)
-    const [hasMore, setHasMore] = useState(false)
-    const [offset, setOffset] = useState(0)
 
     const [typeFilter, setTypeFilter] = useState('')
     const [creatureFilter, setCreatureFilter] = useState('')
-
-    const scrollRef = useRef(null)
+    const [sort, setSort] = useState({ sort: '', desc: false })
+    const nameFilter = useDebouncedValue(creatureFilter)
 
     // Load creature types on mount
     useEffect(() => {
@@ -40,58 +40,13 @@ function NPCsTab({ onNavigate, tooltipHook }) {
             })
     }, [])
 
-    // Load initial creatures when a type is selected
-    useEffect(() => {
-        if (selectedCreatureType !== null) {
-            setLoading(true)
-            setCreatures([])
-            setOffset(0)
-            setHasMore(false)
-            
-            BrowseCreaturesByTypePaged(selectedCreatureType.type, '', PAGE_SIZE, 0)
-                .then(res => {
-                    setCreatures(res.creatures || [])
-                    setTotal(res.total || 0)
-                    setHasMore(res.hasMore || false)
-                    setOffset(PAGE_SIZE)
-                    setLoading(false)
-                })
-                .catch(err => {
-                    console.error("Failed to load creatures:", err)
-                    setLoading(false)
-                })
-        }
-    }, [selectedCreatureType])
-
-    // Load more creatures function
-    const loadMore = useCallback(() => {
-        if (!hasMore || loadingMore || !selectedCreatureType) return
-        
-        setLoadingMore(true)
-        BrowseCreaturesByTypePaged(selectedCreatureType.type, '', PAGE_SIZE, offset)
-            .then(res => {
-                setCreatures(prev => [...prev, ...(res.creatures || [])])
-                setHasMore(res.hasMore || false)
-                setOffset(prev => prev + PAGE_SIZE)
-                setLoadingMore(false)
-            })
-            .catch(err => {
-                console.error("Failed to load more creatures:", err)
-                setLoadingMore(false)
-            })
-    }, [hasMore, loadingMore, selectedCreatureType, offset])
-
-    // Infinite scroll handler
-    const handleScroll = useCallback((e) => {
-        const { scrollTop, scrollHeight, clientHeight } = e.target
-        // Load more when user scrolls to bottom (with 200px threshold)
-        if (scrollHeight - scrollTop - clientHeight < 200) {
-            loadMore()
-        }
-    }, [loadMore])
+    // Load creatures page by page when a type is selected; name filter and sort are applied server-side
+    const { items: creatures, total, hasMore, loading: loadingCreatures, loadingMore, handleScroll } = usePagedList(
+        selectedCreatureType ? (page) => BrowseCreaturesByType(selectedCreatureType.type, nameFilter, { ...page, ...sort }) : null,
+        [selectedCreatureType, nameFilter, sort]
+    )
 
     const filteredTypes = useMemo(() => filterItems(creatureTypes, typeFilter), [creatureTypes, typeFilter])
-    const filteredCreatures = useMemo(() => filterItems(creatures, creatureFilter), [creatures, creatureFilter])
 
     return (
         <>
@@ -128,26 +83,26 @@ function NPCsTab({ onNavigate, tooltipHook }) {
             <ContentPanel className="col-span-3">
                 <SectionHeader 
                     title={selectedCreatureType 
-                        ? `${selectedCreatureType.name} (${filteredCreatures.length}${total > creatures.length ? ` of ${total}` : ''})` 
+                        ? `${selectedCreatureType.name} (${creatures.length}${total > creatures.length ? ` of ${total}` : ''})` 
                         : 'Select a Type'
                     }
                     placeholder="Filter NPCs..."
                     onFilterChange={setCreatureFilter}
+                    actions={selectedCreatureType && <SortSelect options={SORT_OPTIONS} value={sort} onChange={setSort} />}
                 />
                 
-                {loading && selectedCreatureType && (
+                {loadingCreatures && (
                     <div className="flex-1 flex items-center justify-center text-wow-gold italic animate-pulse">
                         Loading creatures...
                     </div>
                 )}
                 
-                {!loading && creatures.length > 0 && (
+                {!loadingCreatures && creatures.length > 0 && (
                     <ScrollList 
-                        ref={scrollRef}
                         className="p-2 space-y-1"
                         onScroll={handleScroll}
                     >
-                        {filteredCreatures.map(creature => {
+                        {creatures.map(creature => {
                             const rankColor = getRankColor(creature.rank)
                             const levelText = creature.levelMin === creature.levelMax 
                                 ? `${creature.levelMin}` 
@@ -205,19 +160,7 @@ function NPCsTab({ onNavigate, tooltipHook }) {
                             )
                         })}
                         
-                        {/* Loading more indicator */}
-                        {loadingMore && (
-                            <div className="p-4 text-center text-wow-gold italic animate-pulse">
-                                Loading more...
-                            </div>
-                        )}
-                        
-                        {/* Has more indicator */}
-                        {hasMore && !loadingMore && (
-                            <div className="p-2 text-center text-gray-600 text-sm">
-                                Scroll for more ({creatures.length} of {total})
-                            </div>
-                        )}
+                        <PagedFooter loadingMore={loadingMore} hasMore={hasMore} loaded={creatures.length} total={total} />
                     </ScrollList>
                 )}
                 
@@ -1,17 +1,24 @@
 import { useState, useEffect, useMemo } from 'react'
-import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon } from '../../ui'
+import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon, SortSelect, PagedFooter } from '../../ui'
 import { GetObjectTypes, GetObjectsByType, filterItems } from '../../../utils/databaseApi'
+import { usePagedList, useDebouncedValue } from '../../../hooks/usePagedList'
 
 const OBJECT_COLOR = '#00B4FF'
 
+const SORT_OPTIONS = [
+    { value: '', label: 'Default' },
+    { value: 'name', label: 'Name' },
+]
+
 function ObjectsTab({ onNavigate }) {
     const [objectTypes, setObjectTypes] = useState([])
     const [selectedObjectType, setSelectedObjectType] = useState(null)
-    const [objects, setObjects] = useState([])
     const [loading, setLoading] = useState(false)
 
     const [typeFilter, setTypeFilter] = useState('')
     const [objectFilter, setObjectFilter] = useState('')
+    const [sort, setSort] = useState({ sort: '', desc: false })
+    const nameFilter = useDebouncedValue(objectFilter)
 
     // Load object types on mount
     useEffect(() => {
@@ -27,25 +34,13 @@ function ObjectsTab({ onNavigate }) {
             })
     }, [])
 
-    // Load objects when a type is selected
-    useEffect(() => {
-        if (selectedObjectType !== null) {
-            setLoading(true)
-            setObjects([])
-            GetObjectsByType(selectedObjectType.id, '')
-                .then(res => {
-                    setObjects(res || [])
-                    setLoading(false)
-                })
-                .catch(err => {
-                    console.error("Failed to load objects:", err)
-                    setLoading(false)
-                })
-        }
-    }, [selectedObjectType])
+    // Load objects page by page when a type is selected; name filter and sort are applied server-side
+    const { items: objects, total, hasMore, loading: loadingObjects, loadingMore, handleScroll } = usePagedList(
+        selectedObjectType ? (page) => GetObjectsByType(selectedObjectType.id, nameFilter, { ...page, ...sort }) : null,
+        [selectedObjectType, nameFilter, sort]
+    )
 
     const filteredTypes = useMemo(() => filterItems(objectTypes, typeFilter), [objectTypes, typeFilter])
-    const filteredObjects = useMemo(() => filterItems(objects, objectFilter), [objects, objectFilter])
 
     return (
         <>
@@ -81,20 +76,24 @@ function ObjectsTab({ onNavigate }) {
             {/* Objects List */}
             <ContentPanel className="col-span-3">
                 <SectionHeader 
-                    title={selectedObjectType ? `${selectedObjectType.name} (${filteredObjects.length})` : 'Select a Type'}
+                    title={selectedObjectType
+                        ? `${selectedObjectType.name} (${objects.length}${total > objects.length ? ` of ${total}` : ''})`
+                        : 'Select a Type'
+                    }
                     placeholder="Filter objects..."
                     onFilterChange={setObjectFilter}
+                    actions={selectedObjectType && <SortSelect options={SORT_OPTIONS} value={sort} onChange={setSort} />}
                 />
                 
-                {loading && selectedObjectType && (
+                {loadingObjects && (
                     <div className="flex-1 flex items-center justify-center text-wow-gold italic animate-pulse">
                         Loading objects...
                     </div>
                 )}
                 
-                {!loading && objects.length > 0 && (
-                    <ScrollList className="p-2 space-y-1">
-                        {filteredObjects.map(obj => (
+                {!loadingObjects && objects.length > 0 && (
+                    <ScrollList className="p-2 space-y-1" onScroll={handleScroll}>
+                        {objects.map(obj => (
                             <div 
                                 key={obj.entry}
                                 className="flex items-center gap-3 p-2 bg-white/[0.02] hover:bg-white/5 border-l-[3px] cursor-pointer transition-colors rounded-r"
@@ -123,6 +122,7 @@ function ObjectsTab({ onNavigate }) {
                                 </span>
                             </div>
                         ))}
+                        <PagedFooter loadingMore={loadingMore} hasMore={hasMore} loaded={objects.length} total={total} />
                     </ScrollList>
                 )}
                 
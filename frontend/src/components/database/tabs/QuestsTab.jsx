@@ -1,6 +1,13 @@
 import { useState, useEffect, useMemo } from 'react'
-import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon } from '../../ui'
+import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon, SortSelect, PagedFooter } from '../../ui'
 import { GetQuestCategoryGroups, GetQuestCategoriesByGroup, GetQuestsByEnhancedCategory, filterItems } from '../../../utils/databaseApi'
+import { usePagedList, useDebouncedValue } from '../../../hooks/usePagedList'
+
+const SORT_OPTIONS = [
+    { value: '', label: 'Default' },
+    { value: 'name', label: 'Name' },
+    { value: 'level', label: 'Level' },
+]
 
 // Quest type badge colors
 const getQuestTypeInfo = (type) => {
@@ -16,7 +23,6 @@ const getQuestTypeInfo = (type) => {
 function QuestsTab({ onNavigate }) {
     const [groups, setGroups] = useState([])
     const [categories, setCategories] = useState([])
-    const [quests, setQuests] = useState([])
     const [selectedGroup, setSelectedGroup] = useState(null)
     const [selectedCategory, setSelectedCategory] = useState(null)
     const [loading, setLoading] = useState(false)
@@ -24,6 +30,8 @@ function QuestsTab({ onNavigate }) {
     const [groupFilter, setGroupFilter] = useState('')
     const [categoryFilter, setCategoryFilter] = useState('')
     const [questFilter, setQuestFilter] = useState('')
+    const [sort, setSort] = useState({ sort: '', desc: false })
+    const nameFilter = useDebouncedValue(questFilter)
 
     // Load groups on mount
     useEffect(() => {
@@ -44,7 +52,6 @@ function QuestsTab({ onNavigate }) {
         if (selectedGroup) {
             setLoading(true)
             setCategories([])
-            setQuests([])
             setSelectedCategory(null)
             GetQuestCategoriesByGroup(selectedGroup.id)
                 .then(res => {
@@ -58,26 +65,14 @@ function QuestsTab({ onNavigate }) {
         }
     }, [selectedGroup])
 
-    // Load quests when category is selected
-    useEffect(() => {
-        if (selectedCategory) {
-            setLoading(true)
-            setQuests([])
-            GetQuestsByEnhancedCategory(selectedCategory.id, '')
-                .then(res => {
-                    setQuests(res || [])
-                    setLoading(false)
-                })
-                .catch(err => {
-                    console.error("Failed to load quests:", err)
-                    setLoading(false)
-                })
-        }
-    }, [selectedCategory])
+    // Load quests page by page when a category is selected; name filter and sort are applied server-side
+    const { items: quests, total, hasMore, loading: loadingQuests, loadingMore, handleScroll } = usePagedList(
+        selectedCategory ? (page) => GetQuestsByEnhancedCategory(selectedCategory.id, nameFilter, { ...page, ...sort }) : null,
+        [selectedCategory, nameFilter, sort]
+    )
 
     const filteredGroups = useMemo(() => filterItems(groups, groupFilter), [groups, groupFilter])
     const filteredCategories = useMemo(() => filterItems(categories, categoryFilter), [categories, categoryFilter])
-    const filteredQuests = useMemo(() => filterItems(quests, questFilter), [quests, questFilter])
 
     return (
         <>
@@ -134,13 +129,17 @@ function QuestsTab({ onNavigate }) {
             {/* 3. Quests List (spans 2 columns) */}
             <ContentPanel className="col-span-2">
                 <SectionHeader 
-                    title={selectedCategory ? `${selectedCategory.name} (${filteredQuests.length})` : 'Select Category'}
+                    title={selectedCategory
+                        ? `${selectedCategory.name} (${quests.length}${total > quests.length ? ` of ${total}` : ''})`
+                        : 'Select Category'
+                    }
                     placeholder="Filter quests..."
                     onFilterChange={setQuestFilter}
                     titleColor="#FFD100"
+                    actions={selectedCategory && <SortSelect options={SORT_OPTIONS} value={sort} onChange={setSort} />}
                 />
 
-                {loading && selectedCategory && (
+                {loadingQuests && (
                     <div className="flex-1 flex items-center justify-center text-wow-gold italic animate-pulse">
                         Loading quests...
                     </div>
@@ -152,9 +151,9 @@ function QuestsTab({ onNavigate }) {
                     </div>
                 )}
 
-                {!loading && quests.length > 0 && (
-                    <ScrollList className="p-2 space-y-1">
-                        {filteredQuests.map(quest => {
+                {!loadingQuests && quests.length > 0 && (
+                    <ScrollList className="p-2 space-y-1" onScroll={handleScroll}>
+                        {quests.map(quest => {
                             const typeInfo = getQuestTypeInfo(quest.type)
                             
                             return (
@@ -207,6 +206,7 @@ function QuestsTab({ onNavigate }) {
                                 </div>
                             )
                         })}
+                        <PagedFooter loadingMore={loadingMore} hasMore={hasMore} loaded={quests.length} total={total} />
                     </ScrollList>
                 )}
             </ContentPanel>
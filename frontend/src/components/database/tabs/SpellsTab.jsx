@@ -1,7 +1,8 @@
 import { useState, useEffect, useMemo } from 'react'
-import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon } from '../../ui'
+import { SidebarPanel, ContentPanel, ScrollList, SectionHeader, ListItem, EntityIcon, SortSelect, PagedFooter } from '../../ui'
 import { GetSpellSkillCategories, GetSpellSkillsByCategory, GetSpellsBySkill, filterItems } from '../../../utils/databaseApi'
 import { useIcon } from '../../../services/useImage'
+import { usePagedList, useDebouncedValue } from '../../../hooks/usePagedList'
 
 const SpellListItemIcon = ({ iconName, spellColor }) => {
     const icon = useIcon(iconName)
@@ -33,10 +34,15 @@ const SpellListItemIcon = ({ iconName, spellColor }) => {
 
 const SPELL_COLOR = '#772ce8'
 
+const SORT_OPTIONS = [
+    { value: '', label: 'Default' },
+    { value: 'name', label: 'Name' },
+    { value: 'level', label: 'Level' },
+]
+
 function SpellsTab({ onNavigate }) {
     const [categories, setCategories] = useState([])
     const [skills, setSkills] = useState([])
-    const [spells, setSpells] = useState([])
     const [selectedCategory, setSelectedCategory] = useState(null)
     const [selectedSkill, setSelectedSkill] = useState(null)
     const [loading, setLoading] = useState(false)
@@ -44,6 +50,8 @@ function SpellsTab({ onNavigate }) {
     const [categoryFilter, setCategoryFilter] = useState('')
     const [skillFilter, setSkillFilter] = useState('')
     const [spellFilter, setSpellFilter] = useState('')
+    const [sort, setSort] = useState({ sort: '', desc: false })
+    const nameFilter = useDebouncedValue(spellFilter)
 
     // Load categories on mount
     useEffect(() => {
@@ -64,7 +72,6 @@ function SpellsTab({ onNavigate }) {
         if (selectedCategory) {
             setLoading(true)
             setSkills([])
-            setSpells([])
             setSelectedSkill(null)
             GetSpellSkillsByCategory(selectedCategory.id)
                 .then(res => {
@@ -78,26 +85,14 @@ function SpellsTab({ onNavigate }) {
         }
     }, [selectedCategory])
 
-    // Load spells when skill is selected
-    useEffect(() => {
-        if (selectedSkill) {
-            setLoading(true)
-            setSpells([])
-            GetSpellsBySkill(selectedSkill.id, '')
-                .then(res => {
-                    setSpells(res || [])
-                    setLoading(false)
-                })
-                .catch(err => {
-                    console.error("Failed to load spells:", err)
-                    setLoading(false)
-                })
-        }
-    }, [selectedSkill])
+    // Load spells page by page when a skill is selected; name filter and sort are applied server-side
+    const { items: spells, total, hasMore, loading: loadingSpells, loadingMore, handleScroll } = usePagedList(
+        selectedSkill ? (page) => GetSpellsBySkill(selectedSkill.id, nameFilter, { ...page, ...sort }) : null,
+        [selectedSkill, nameFilter, sort]
+    )
 
     const filteredCategories = useMemo(() => filterItems(categories, categoryFilter), [categories, categoryFilter])
     const filteredSkills = useMemo(() => filterItems(skills, skillFilter), [skills, skillFilter])
-    const filteredSpells = useMemo(() => filterItems(spells, spellFilter), [spells, spellFilter])
 
     return (
         <>
@@ -154,13 +149,17 @@ function SpellsTab({ onNavigate }) {
             {/* 3. Spells List */}
             <ContentPanel className="col-span-2">
                 <SectionHeader 
-                    title={selectedSkill ? `${selectedSkill.name} (${filteredSpells.length})` : 'Select Skill'}
+                    title={selectedSkill
+                        ? `${selectedSkill.name} (${spells.length}${total > spells.length ? ` of ${total}` : ''})`
+                        : 'Select Skill'
+                    }
                     placeholder="Filter spells..."
                     onFilterChange={setSpellFilter}
                     titleColor={SPELL_COLOR}
+                    actions={selectedSkill && <SortSelect options={SORT_OPTIONS} value={sort} onChange={setSort} />}
                 />
 
-                {loading && selectedSkill && (
+                {loadingSpells && (
                     <div className="flex-1 flex items-center justify-center italic animate-pulse" style={{ color: SPELL_COLOR }}>
                         Loading spells...
                     </div>
@@ -172,9 +171,9 @@ function SpellsTab({ onNavigate }) {
                     </div>
                 )}
 
-                {!loading && spells.length > 0 && (
-                    <ScrollList className="p-2 space-y-1">
-                        {filteredSpells.map(spell => (
+                {!loadingSpells && spells.length > 0 && (
+                    <ScrollList className="p-2 space-y-1" onScroll={handleScroll}>
+                        {spells.map(spell => (
                             <div 
                                 key={spell.entry}
                                 className="flex items-center gap-3 p-2 bg-white/[0.02] hover:bg-white/5 border-l-[3px] transition-colors rounded-r cursor-pointer"
@@ -212,6 +211,7 @@ function SpellsTab({ onNavigate }) {
                                 </div>
                             </div>
                         ))}
+                        <PagedFooter loadingMore={loadingMore} hasMore={hasMore} loaded={spells.length} total={total} />
                     </ScrollList>
                 )}
             </ContentPanel>
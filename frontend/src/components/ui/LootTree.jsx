@@ -0,0 +1,75 @@
+import React, { useState } from 'react'
+import { LootItem } from './LootItem'
+
+const formatChance = (chance) => `${chance < 0.1 ? chance.toFixed(2) : chance.toFixed(1)}%`
+
+/**
+ * A loot table as stored: items plus reference tables that expand to their own rows
+ * @param {Array} nodes - LootTree.nodes from GetLootTree
+ * @param {Function} onItemClick - Called with the item id
+ * @param {Function} getItemHandlers - Optional tooltip handlers per item id
+ */
+export const LootTree = ({ nodes, onItemClick, getItemHandlers, depth = 0 }) => (
+    <div className={depth > 0 ? 'ml-4 pl-3 border-l border-white/10 space-y-1' : 'space-y-1'}>
+        {nodes.map((node, i) => node.referenceId ? (
+            <LootReference
+                key={`ref-${node.referenceId}-${i}`}
+                node={node}
+                onItemClick={onItemClick}
+                getItemHandlers={getItemHandlers}
+                depth={depth}
+            />
+        ) : (
+            <LootItem
+                key={`item-${node.itemId}-${i}`}
+                item={{
+                    entry: node.itemId,
+                    name: node.name,
+                    quality: node.quality,
+                    iconPath: node.iconPath,
+                    dropChance: formatChance(node.effectiveChance),
+                }}
+                onClick={() => onItemClick?.(node.itemId)}
+                showDropChance
+                {...(getItemHandlers?.(node.itemId) || {})}
+            />
+        ))}
+    </div>
+)
+
+/**
+ * Collapsible reference table row; the top level starts expanded
+ */
+const LootReference = ({ node, onItemClick, getItemHandlers, depth }) => {
+    const [open, setOpen] = useState(depth === 0)
+    const children = node.children || []
+
+    return (
+        <div>
+            <div
+                className="flex items-center gap-2 px-2 py-1 text-[12px] text-gray-400 hover:text-white cursor-pointer select-none"
+                onClick={() => setOpen(!open)}
+            >
+                <span className="w-3 text-gray-600">{node.cycle ? '↻' : open ? '▾' : '▸'}</span>
+                <span className="font-bold">Reference table #{node.referenceId}</span>
+                {node.groupId > 0 && <span className="text-gray-600">group {node.groupId}</span>}
+                {node.maxCount > 1 && <span className="text-gray-600">×{node.maxCount}</span>}
+                <span className="ml-auto text-gray-500 text-[10px] uppercase tracking-tight">
+                    {formatChance(node.effectiveChance)}
+                </span>
+            </div>
+            {node.cycle ? (
+                <div className="ml-9 text-[11px] text-gray-600 italic">
+                    Loops back to a table above, not expanded.
+                </div>
+            ) : open && children.length > 0 && (
+                <LootTree
+                    nodes={children}
+                    onItemClick={onItemClick}
+                    getItemHandlers={getItemHandlers}
+                    depth={depth + 1}
+                />
+            )}
+        </div>
+    )
+}
@@ -0,0 +1,47 @@
+import React from 'react'
+
+/**
+ * Sort control for browse lists: a column select and a direction toggle
+ * @param {Array<{value: string, label: string}>} options - Sort keys the list supports ('' = default order)
+ * @param {{sort: string, desc: boolean}} value
+ */
+export const SortSelect = ({ options, value, onChange }) => (
+    <div className="flex items-center gap-1">
+        <select
+            value={value.sort}
+            onChange={(e) => onChange({ ...value, sort: e.target.value })}
+            className="bg-black/30 border border-gray-700 rounded text-xs px-1 py-1 text-gray-300 outline-none focus:border-wow-gold"
+            title="Sort by"
+        >
+            {options.map(opt => (
+                <option key={opt.value} value={opt.value}>{opt.label}</option>
+            ))}
+        </select>
+        <button
+            onClick={() => onChange({ ...value, desc: !value.desc })}
+            disabled={!value.sort}
+            className="px-2 py-1 text-xs rounded border bg-black/30 text-gray-400 border-gray-700 hover:text-white hover:border-gray-500 disabled:opacity-40 transition-colors"
+            title={value.desc ? 'Descending' : 'Ascending'}
+        >
+            {value.desc ? '▼' : '▲'}
+        </button>
+    </div>
+)
+
+/**
+ * Footer for infinitely scrolled lists
+ */
+export const PagedFooter = ({ loadingMore, hasMore, loaded, total }) => (
+    <>
+        {loadingMore && (
+            <div className="p-4 text-center text-wow-gold italic animate-pulse">
+                Loading more...
+            </div>
+        )}
+        {hasMore && !loadingMore && (
+            <div className="p-2 text-center text-gray-600 text-sm">
+                Scroll for more ({loaded} of {total})
+            </div>
+        )}
+    </>
+)
@@ -2,7 +2,9 @@
 export { PageLayout, ContentGrid, SidebarPanel, ContentPanel, ScrollList } from './Layout'
 export { WowButton, ListItem, TabButton, TabBar } from './Button'
 export { LootItem, EntityIcon } from './LootItem'
+export { LootTree } from './LootTree'
 export { SectionHeader } from './SectionHeader'
+export { SortSelect, PagedFooter } from './PagedList'
 export { default as ItemTooltip } from './ItemTooltip'
 export { 
     DetailPageLayout, 
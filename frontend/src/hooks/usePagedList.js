@@ -0,0 +1,89 @@
+import { useState, useEffect, useCallback, useRef } from 'react'
+
+export const PAGE_SIZE = 100
+
+/**
+ * Hook for browse lists that load page by page as the user scrolls.
+ * @param {Function|null} fetchPage - Called with a PageQuery ({limit, cursor}), resolves to
+ *   a page ({items, total, hasMore, nextCursor}). Null leaves the list empty.
+ * @param {Array} deps - The list reloads from the first page when these change
+ * @returns {Object} List state, loadMore and an onScroll handler for ScrollList
+ */
+export function usePagedList(fetchPage, deps) {
+    const [items, setItems] = useState([])
+    const [total, setTotal] = useState(0)
+    const [hasMore, setHasMore] = useState(false)
+    const [loading, setLoading] = useState(false)
+    const [loadingMore, setLoadingMore] = useState(false)
+
+    const cursorRef = useRef('')
+    // Responses of a list that was reloaded in the meantime are dropped
+    const generationRef = useRef(0)
+
+    useEffect(() => {
+        const generation = ++generationRef.current
+        setItems([])
+        setTotal(0)
+        setHasMore(false)
+        setLoadingMore(false)
+        cursorRef.current = ''
+        if (!fetchPage) {
+            setLoading(false)
+            return
+        }
+
+        setLoading(true)
+        fetchPage({ limit: PAGE_SIZE, offset: 0 })
+            .then(res => {
+                if (generation !== generationRef.current) return
+                setItems(res?.items || [])
+                setTotal(res?.total || 0)
+                setHasMore(res?.hasMore || false)
+                cursorRef.current = res?.nextCursor || ''
+            })
+            .catch(err => console.error("Failed to load page:", err))
+            .finally(() => {
+                if (generation === generationRef.current) setLoading(false)
+            })
+    }, deps)
+
+    const loadMore = useCallback(() => {
+        if (!fetchPage || !hasMore || loading || loadingMore) return
+
+        const generation = generationRef.current
+        setLoadingMore(true)
+        fetchPage({ limit: PAGE_SIZE, offset: 0, cursor: cursorRef.current })
+            .then(res => {
+                if (generation !== generationRef.current) return
+                setItems(prev => [...prev, ...(res?.items || [])])
+                setHasMore(res?.hasMore || false)
+                cursorRef.current = res?.nextCursor || ''
+            })
+            .catch(err => console.error("Failed to load more:", err))
+            .finally(() => {
+                if (generation === generationRef.current) setLoadingMore(false)
+            })
+    }, [fetchPage, hasMore, loading, loadingMore])
+
+    // Infinite scroll: load more when the user nears the bottom (200px threshold)
+    const handleScroll = useCallback((e) => {
+        const { scrollTop, scrollHeight, clientHeight } = e.target
+        if (scrollHeight - scrollTop - clientHeight < 200) {
+            loadMore()
+        }
+    }, [loadMore])
+
+    return { items, total, hasMore, loading, loadingMore, loadMore, handleScroll }
+}
+
+/**
+ * Debounce a value, so typing in a filter box doesn't query on every key
+ */
+export function useDebouncedValue(value, delay = 250) {
+    const [debounced, setDebounced] = useState(value)
+    useEffect(() => {
+        const timer = setTimeout(() => setDebounced(value), delay)
+        return () => clearTimeout(timer)
+    }, [value, delay])
+    return debounced
+}
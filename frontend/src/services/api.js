@@ -39,6 +39,20 @@ export const SyncNpcData = (entry) => {
     return Promise.resolve(null)
 }
 
+export const GetLootTree = (lootId) => {
+    if (window?.go?.main?.App?.GetLootTree) {
+        return window.go.main.App.GetLootTree(lootId);
+    }
+    return Promise.resolve(null)
+}
+
+export const GetQuestGraph = (entry) => {
+    if (window?.go?.main?.App?.GetQuestGraph) {
+        return window.go.main.App.GetQuestGraph(entry);
+    }
+    return Promise.resolve(null)
+}
+
 export const GetItemDetail = (entry) => {
     console.log(`[API] Fetching Item Detail for: ${entry}`);
     if (window?.go?.main?.App?.GetItemDetail) {
@@ -139,6 +153,117 @@ export const ToggleFavorite = (itemEntry, category = '') => {
     return Promise.resolve({ success: false, message: 'API not available' });
 }
 
+// === Wishlists API ===
+
+export const GetWishlists = () => {
+    if (window?.go?.main?.App?.GetWishlists) {
+        return window.go.main.App.GetWishlists();
+    }
+    return Promise.resolve([]);
+}
+
+export const CreateWishlist = (name, description = '') => {
+    if (window?.go?.main?.App?.CreateWishlist) {
+        return window.go.main.App.CreateWishlist(name, description);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const UpdateWishlist = (id, name, description = '') => {
+    if (window?.go?.main?.App?.UpdateWishlist) {
+        return window.go.main.App.UpdateWishlist(id, name, description);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const DeleteWishlist = (id) => {
+    if (window?.go?.main?.App?.DeleteWishlist) {
+        return window.go.main.App.DeleteWishlist(id);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const GetWishlistItems = (filter = {}) => {
+    if (window?.go?.main?.App?.GetWishlistItems) {
+        return window.go.main.App.GetWishlistItems(filter);
+    }
+    return Promise.resolve([]);
+}
+
+export const AddWishlistItem = (item) => {
+    console.log(`[API] Adding Wishlist Item: ${item.itemEntry} to '${item.wishlist || ''}'`);
+    if (window?.go?.main?.App?.AddWishlistItem) {
+        return window.go.main.App.AddWishlistItem(item);
+    }
+    return Promise.reject(new Error('API not available'));
+}
+
+export const UpdateWishlistItem = (item) => {
+    if (window?.go?.main?.App?.UpdateWishlistItem) {
+        return window.go.main.App.UpdateWishlistItem(item);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const SetWishlistItemStatus = (id, status) => {
+    if (window?.go?.main?.App?.SetWishlistItemStatus) {
+        return window.go.main.App.SetWishlistItemStatus(id, status);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const RemoveWishlistItem = (id) => {
+    if (window?.go?.main?.App?.RemoveWishlistItem) {
+        return window.go.main.App.RemoveWishlistItem(id);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const GetWishlistCharacters = () => {
+    if (window?.go?.main?.App?.GetWishlistCharacters) {
+        return window.go.main.App.GetWishlistCharacters();
+    }
+    return Promise.resolve([]);
+}
+
+// === Saved Searches API ===
+
+export const SaveSearch = (search) => {
+    console.log(`[API] Saving Search: ${search.name}`);
+    if (window?.go?.main?.App?.SaveSearch) {
+        return window.go.main.App.SaveSearch(search);
+    }
+    return Promise.reject(new Error('API not available'));
+}
+
+export const DeleteSavedSearch = (id) => {
+    if (window?.go?.main?.App?.DeleteSavedSearch) {
+        return window.go.main.App.DeleteSavedSearch(id);
+    }
+    return Promise.resolve('API not available');
+}
+
+export const GetSmartCollections = () => {
+    if (window?.go?.main?.App?.GetSmartCollections) {
+        return window.go.main.App.GetSmartCollections();
+    }
+    return Promise.resolve([]);
+}
+
+export const GetSmartCollection = (id, limit = 50, offset = 0) => {
+    if (window?.go?.main?.App?.GetSmartCollection) {
+        return window.go.main.App.GetSmartCollection(id, limit, offset);
+    }
+    return Promise.resolve(null);
+}
+
+export const MarkSmartCollectionViewed = (id) => {
+    if (window?.go?.main?.App?.MarkSmartCollectionViewed) {
+        return window.go.main.App.MarkSmartCollectionViewed(id);
+    }
+    return Promise.resolve('API not available');
+}
+
 export const SyncQuestData = (entry) => {
     console.log(`[API] Syncing Quest: ${entry}`);
     if (window?.go?.main?.App?.SyncQuestData) {
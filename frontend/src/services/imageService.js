@@ -1,62 +1,49 @@
+import { GetIconSprites } from '../../wailsjs/go/main/App';
+
 /**
  * Unified Image Service
- * Handles loading images from local storage with remote fallback
- * Works consistently in both dev and production modes
+ * Images are plain URLs served by the Go asset handler (backend/services/asset_handler.go),
+ * which serves from the data directory, falls back to embedded assets and
+ * fetches and caches misses. The webview caches them like any other image.
  */
 
-// Image cache to avoid repeated API calls
+// URL cache to avoid rebuilding the same URLs
 const imageCache = new Map();
 
+// Asset handler routes per image type
+const imageRoutes = {
+    icon: '/icons/',
+    npc_model: '/npc/',
+    npc_map: '/npc/',
+};
+
 /**
- * Load an image with local-first strategy
+ * Resolve an image URL with local-first strategy
  * @param {string} imageType - 'icon' | 'npc_model' | 'npc_map'
  * @param {string} name - Image name without extension (e.g., 'inv_sword_01', 'model_15114')
- * @param {string} remoteUrl - Fallback remote URL
- * @returns {Promise<string>} - Data URL that can be used as img src
+ * @param {string} remoteUrl - Remote URL the handler fetches on a local miss
+ * @returns {Promise<string>} - URL that can be used as img src
  */
 export const loadImage = async (imageType, name, remoteUrl = null) => {
     const cacheKey = `${imageType}:${name}`;
-    
-    // Check cache first
+
     if (imageCache.has(cacheKey)) {
         return imageCache.get(cacheKey);
     }
 
-    // Try local first
-    if (window?.go?.main?.App?.GetLocalImage) {
-        try {
-            const result = await window.go.main.App.GetLocalImage(imageType, name);
-            if (result && result.data && !result.error) {
-                const dataUrl = `data:${result.mimeType};base64,${result.data}`;
-                imageCache.set(cacheKey, dataUrl);
-                return dataUrl;
-            }
-        } catch (e) {
-            console.log(`[ImageService] Local not found: ${name}`);
-        }
-    }
-
-    // Fallback to remote
-    if (remoteUrl) {
-        if (window?.go?.main?.App?.FetchRemoteImage) {
-            try {
-                const result = await window.go.main.App.FetchRemoteImage(remoteUrl, imageType, name);
-                if (result && result.data && !result.error) {
-                    const dataUrl = `data:${result.mimeType};base64,${result.data}`;
-                    imageCache.set(cacheKey, dataUrl);
-                    return dataUrl;
-                }
-            } catch (e) {
-                console.log(`[ImageService] Remote fetch failed: ${remoteUrl}`);
-            }
-        }
-        
-        // If API not available, return the remote URL directly
-        // This allows the browser to load it (works for external URLs)
+    const route = imageRoutes[imageType];
+    // Outside the app (plain browser) there is no asset handler
+    if (!route || !name || !window?.go) {
         return remoteUrl;
     }
 
-    return null;
+    let url = route + encodeURIComponent(name);
+    // Icons are fetched from the known icon sources; NPC images need their source URL
+    if (imageType !== 'icon' && remoteUrl) {
+        url += `?src=${encodeURIComponent(remoteUrl)}`;
+    }
+    imageCache.set(cacheKey, url);
+    return url;
 };
 
 /**
@@ -109,3 +96,48 @@ export const preloadImages = async (images) => {
         images.map(img => loadImage(img.type, img.name, img.url))
     );
 };
+
+// Sprite lookups per size, keyed by lowercase icon name (null = not in the atlases)
+const spriteCache = new Map();
+
+/**
+ * Look up where icons sit in the sprite atlases, for list views
+ * @param {string[]} iconNames - iconPath names
+ * @param {number} size - 18 | 36
+ * @returns {Promise<Object<string, {sheet: string, x: number, y: number, size: number, width: number, height: number}>>}
+ *   Sprites keyed by lowercase icon name; icons not in the atlases are left out (use loadIcon)
+ */
+export const loadIconSprites = async (iconNames, size = 36) => {
+    if (!spriteCache.has(size)) spriteCache.set(size, new Map());
+    const cache = spriteCache.get(size);
+
+    const names = [...new Set(iconNames.filter(Boolean).map(n => n.toLowerCase()))];
+    const missing = names.filter(n => !cache.has(n));
+    if (missing.length > 0 && window?.go) {
+        try {
+            const sprites = await GetIconSprites(missing, size) || {};
+            missing.forEach(n => cache.set(n, sprites[n] || null));
+        } catch (err) {
+            console.error('[Images] Failed to load icon sprites:', err);
+        }
+    }
+
+    const result = {};
+    names.forEach(n => {
+        if (cache.get(n)) result[n] = cache.get(n);
+    });
+    return result;
+};
+
+/**
+ * CSS for drawing a sprite at its atlas size
+ * @param {{sheet: string, x: number, y: number, size: number, width: number, height: number}} sprite
+ * @returns {Object} - Inline style object
+ */
+export const spriteStyle = (sprite) => ({
+    width: sprite.size,
+    height: sprite.size,
+    backgroundImage: `url(${sprite.sheet})`,
+    backgroundPosition: `-${sprite.x}px -${sprite.y}px`,
+    backgroundSize: `${sprite.width}px ${sprite.height}px`,
+});
@@ -1,6 +1,10 @@
 // Shared API utilities for database components
 // These wrap Wails Go bindings with fallbacks
 
+// Browse APIs take a PageQuery ({limit, offset, cursor, sort, desc}) and
+// resolve to a page: {items, total, hasMore, nextCursor}
+const EMPTY_PAGE = { items: [], total: 0, hasMore: false }
+
 // Detail APIs (consolidated from services/api.js)
 export const GetQuestDetail = (entry) => {
     console.log(`[API] Fetching Quest Detail for: ${entry}`);
@@ -44,13 +48,18 @@ export const GetSpellDetail = (entry) => {
 }
 
 // Items APIs
+export const BrowseItemsByClass = (classId, subClass, nameFilter = '', page = {}) => {
+    if (window?.go?.main?.App?.BrowseItemsByClass) {
+        return window.go.main.App.BrowseItemsByClass(classId, subClass, nameFilter, page)
+    }
+    return Promise.resolve(EMPTY_PAGE)
+}
 
-// Items APIs
-export const BrowseItemsByClassAndSlot = (classId, subClass, inventoryType, nameFilter = '') => {
+export const BrowseItemsByClassAndSlot = (classId, subClass, inventoryType, nameFilter = '', page = {}) => {
     if (window?.go?.main?.App?.BrowseItemsByClassAndSlot) {
-        return window.go.main.App.BrowseItemsByClassAndSlot(classId, subClass, inventoryType, nameFilter)
+        return window.go.main.App.BrowseItemsByClassAndSlot(classId, subClass, inventoryType, nameFilter, page)
     }
-    return Promise.resolve([])
+    return Promise.resolve(EMPTY_PAGE)
 }
 
 export const GetItemSets = () => {
@@ -75,19 +84,11 @@ export const GetCreatureTypes = () => {
     return Promise.resolve([])
 }
 
-export const BrowseCreaturesByType = (creatureType, nameFilter = '') => {
+export const BrowseCreaturesByType = (creatureType, nameFilter = '', page = {}) => {
     if (window?.go?.main?.App?.BrowseCreaturesByType) {
-        return window.go.main.App.BrowseCreaturesByType(creatureType, nameFilter)
-    }
-    return Promise.resolve([])
-}
-
-// Paginated version of BrowseCreaturesByType
-export const BrowseCreaturesByTypePaged = (creatureType, nameFilter = '', limit = 100, offset = 0) => {
-    if (window?.go?.main?.App?.BrowseCreaturesByTypePaged) {
-        return window.go.main.App.BrowseCreaturesByTypePaged(creatureType, nameFilter, limit, offset)
+        return window.go.main.App.BrowseCreaturesByType(creatureType, nameFilter, page)
     }
-    return Promise.resolve({ creatures: [], total: 0, hasMore: false })
+    return Promise.resolve(EMPTY_PAGE)
 }
 
 export const GetCreatureLoot = (entry) => {
@@ -127,11 +128,11 @@ export const GetObjectTypes = () => {
     return Promise.resolve([])
 }
 
-export const GetObjectsByType = (typeId, nameFilter = '') => {
+export const GetObjectsByType = (typeId, nameFilter = '', page = {}) => {
     if (window?.go?.main?.App?.GetObjectsByType) {
-        return window.go.main.App.GetObjectsByType(typeId, nameFilter)
+        return window.go.main.App.GetObjectsByType(typeId, nameFilter, page)
     }
-    return Promise.resolve([])
+    return Promise.resolve(EMPTY_PAGE)
 }
 
 export const SearchObjects = (query) => {
@@ -172,11 +173,11 @@ export const GetSpellSkillsByCategory = (categoryId) => {
     return Promise.resolve([])
 }
 
-export const GetSpellsBySkill = (skillId, nameFilter = '') => {
+export const GetSpellsBySkill = (skillId, nameFilter = '', page = {}) => {
     if (window?.go?.main?.App?.GetSpellsBySkill) {
-        return window.go.main.App.GetSpellsBySkill(skillId, nameFilter)
+        return window.go.main.App.GetSpellsBySkill(skillId, nameFilter, page)
     }
-    return Promise.resolve([])
+    return Promise.resolve(EMPTY_PAGE)
 }
 
 // Enhanced Quest Categories APIs (3-level navigation)
@@ -194,11 +195,11 @@ export const GetQuestCategoriesByGroup = (groupId) => {
     return Promise.resolve([])
 }
 
-export const GetQuestsByEnhancedCategory = (categoryId, nameFilter = '') => {
+export const GetQuestsByEnhancedCategory = (categoryId, nameFilter = '', page = {}) => {
     if (window?.go?.main?.App?.GetQuestsByEnhancedCategory) {
-        return window.go.main.App.GetQuestsByEnhancedCategory(categoryId, nameFilter)
+        return window.go.main.App.GetQuestsByEnhancedCategory(categoryId, nameFilter, page)
     }
-    return Promise.resolve([])
+    return Promise.resolve(EMPTY_PAGE)
 }
 
 // Filter helper function
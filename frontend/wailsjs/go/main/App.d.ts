@@ -1,25 +1,46 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
-import {main} from '../models';
 import {services} from '../models';
+import {media} from '../models';
+import {main} from '../models';
 
 export function AddFavorite(arg1:number,arg2:string):Promise<models.FavoriteResult>;
 
+export function AddWishlistItem(arg1:models.WishlistItem):Promise<models.WishlistItem>;
+
 export function AdvancedSearch(arg1:models.SearchFilter):Promise<models.SearchResult>;
 
-export function BrowseCreaturesByType(arg1:number,arg2:string):Promise<Array<models.Creature>>;
+export function ApplyDataPatch(arg1:string):Promise<string>;
 
-export function BrowseCreaturesByTypePaged(arg1:number,arg2:string,arg3:number,arg4:number):Promise<main.CreaturePageResult>;
+export function BackupUserData():Promise<string>;
 
-export function BrowseItemsByClass(arg1:number,arg2:number,arg3:string):Promise<Array<models.Item>>;
+export function BrowseCreaturesByType(arg1:number,arg2:string,arg3:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_Creature_>;
 
-export function BrowseItemsByClassAndSlot(arg1:number,arg2:number,arg3:number,arg4:string):Promise<Array<models.Item>>;
+export function BrowseItemsByClass(arg1:number,arg2:number,arg3:string,arg4:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_Item_>;
+
+export function BrowseItemsByClassAndSlot(arg1:number,arg2:number,arg3:number,arg4:string,arg5:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_Item_>;
+
+export function CancelJob(arg1:number):Promise<string>;
 
 export function CheckNewItems(arg1:number,arg2:number):Promise<Array<services.RemoteItem>>;
 
 export function CheckNewQuests(arg1:number,arg2:number):Promise<Array<services.RemoteQuest>>;
 
+export function CleanupImages(arg1:boolean):Promise<Array<media.GCResult>>;
+
+export function ClearHTTPCache():Promise<string>;
+
+export function ClearReadCache():Promise<string>;
+
+export function CreateWishlist(arg1:string,arg2:string):Promise<string>;
+
+export function DeleteSavedSearch(arg1:number):Promise<string>;
+
+export function DeleteWishlist(arg1:number):Promise<string>;
+
+export function ExportUserData(arg1:string):Promise<string>;
+
 export function FetchRemoteImage(arg1:string,arg2:string,arg3:string):Promise<main.ImageResult>;
 
 export function FixMissingIcons(arg1:string,arg2:number):Promise<main.FixMissingIconsResult>;
@@ -34,6 +55,10 @@ export function FullSyncQuests(arg1:number,arg2:number):Promise<string>;
 
 export function FullSyncSpells(arg1:number,arg2:boolean,arg3:number):Promise<string>;
 
+export function GeneratePatchNotes(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function GeneratePatchNotesFromSnapshot(arg1:string,arg2:string):Promise<string>;
+
 export function GetAllFavorites():Promise<Array<models.FavoriteItem>>;
 
 export function GetCategories():Promise<Array<string>>;
@@ -48,6 +73,8 @@ export function GetCreatureLoot(arg1:number):Promise<Array<models.LootItem>>;
 
 export function GetCreatureTypes():Promise<Array<models.CreatureType>>;
 
+export function GetDataVersion():Promise<number>;
+
 export function GetFactionDetail(arg1:number):Promise<models.FactionDetail>;
 
 export function GetFactions():Promise<Array<models.Faction>>;
@@ -56,6 +83,10 @@ export function GetFavoriteCategories():Promise<Array<models.FavoriteCategory>>;
 
 export function GetFavoritesByCategory(arg1:string):Promise<Array<models.FavoriteItem>>;
 
+export function GetHTTPCacheStats():Promise<services.HTTPCacheStats>;
+
+export function GetIconSprites(arg1:Array<string>,arg2:number):Promise<Record<string, media.Sprite>>;
+
 export function GetInstances(arg1:string):Promise<Array<string>>;
 
 export function GetItemClasses():Promise<Array<models.ItemClass>>;
@@ -70,6 +101,8 @@ export function GetLocalImage(arg1:string,arg2:string):Promise<main.ImageResult>
 
 export function GetLoot(arg1:string,arg2:string,arg3:string):Promise<main.LegacyBossLoot>;
 
+export function GetLootTree(arg1:number):Promise<models.LootTree>;
+
 export function GetMissingAtlasLootItems(arg1:number):Promise<Array<services.MissingItem>>;
 
 export function GetNpcDetails(arg1:number):Promise<services.NpcFullDetails>;
@@ -78,7 +111,7 @@ export function GetObjectDetail(arg1:number):Promise<models.GameObjectDetail>;
 
 export function GetObjectTypes():Promise<Array<models.ObjectType>>;
 
-export function GetObjectsByType(arg1:number,arg2:string):Promise<Array<models.GameObject>>;
+export function GetObjectsByType(arg1:number,arg2:string,arg3:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_GameObject_>;
 
 export function GetQuestCategories():Promise<Array<models.QuestCategory>>;
 
@@ -88,19 +121,31 @@ export function GetQuestCategoryGroups():Promise<Array<models.QuestCategoryGroup
 
 export function GetQuestDetail(arg1:number):Promise<models.QuestDetail>;
 
+export function GetQuestGraph(arg1:number):Promise<models.QuestGraph>;
+
 export function GetQuestsByCategory(arg1:number):Promise<Array<models.Quest>>;
 
-export function GetQuestsByEnhancedCategory(arg1:number,arg2:string):Promise<Array<models.Quest>>;
+export function GetQuestsByEnhancedCategory(arg1:number,arg2:string,arg3:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_Quest_>;
+
+export function GetReadCacheStats():Promise<Array<models.CacheStats>>;
+
+export function GetRecentChanges(arg1:models.ChangeLogFilter):Promise<Array<models.ChangeLogEntry>>;
 
 export function GetRootCategories():Promise<Array<models.Category>>;
 
+export function GetSavedSearches():Promise<Array<models.SavedSearch>>;
+
+export function GetSmartCollection(arg1:number,arg2:number,arg3:number):Promise<models.SmartCollectionView>;
+
+export function GetSmartCollections():Promise<Array<models.SmartCollection>>;
+
 export function GetSpellDetail(arg1:number):Promise<models.SpellDetail>;
 
 export function GetSpellSkillCategories():Promise<Array<models.SpellSkillCategory>>;
 
 export function GetSpellSkillsByCategory(arg1:number):Promise<Array<models.SpellSkill>>;
 
-export function GetSpellsBySkill(arg1:number,arg2:string):Promise<Array<models.Spell>>;
+export function GetSpellsBySkill(arg1:number,arg2:string,arg3:models.PageQuery):Promise<models.Page_shelllab_backend_database_models_Spell_>;
 
 export function GetSyncStats():Promise<Record<string, any>>;
 
@@ -108,10 +153,38 @@ export function GetTables(arg1:string,arg2:string):Promise<Array<models.AtlasTab
 
 export function GetTooltipData(arg1:number):Promise<models.TooltipData>;
 
+export function GetUserDataBackups():Promise<Array<services.UserDataBackup>>;
+
+export function GetWishlistCharacters():Promise<Array<string>>;
+
+export function GetWishlistItems(arg1:models.WishlistItemFilter):Promise<Array<models.WishlistItem>>;
+
+export function GetWishlists():Promise<Array<models.Wishlist>>;
+
+export function ImportUserData(arg1:string,arg2:string):Promise<services.UserDataImportResult>;
+
 export function IsFavorite(arg1:number):Promise<boolean>;
 
+export function ListJobs(arg1:number):Promise<Array<services.SyncJob>>;
+
+export function MarkSmartCollectionViewed(arg1:number):Promise<string>;
+
+export function PauseJob(arg1:number):Promise<string>;
+
 export function RemoveFavorite(arg1:number):Promise<models.FavoriteResult>;
 
+export function RemoveWishlistItem(arg1:number):Promise<string>;
+
+export function RenderTooltip(arg1:number,arg2:string):Promise<string>;
+
+export function RenderTooltipImage(arg1:number):Promise<main.ImageResult>;
+
+export function RestoreUserDataBackup(arg1:string):Promise<services.UserDataImportResult>;
+
+export function ResumeJob(arg1:number):Promise<string>;
+
+export function SaveSearch(arg1:models.SavedSearch):Promise<models.SavedSearch>;
+
 export function SearchCreatures(arg1:string):Promise<Array<models.Creature>>;
 
 export function SearchItems(arg1:string):Promise<Array<models.Item>>;
@@ -122,8 +195,14 @@ export function SearchQuests(arg1:string):Promise<Array<models.Quest>>;
 
 export function SearchSpells(arg1:string):Promise<Array<models.Spell>>;
 
+export function SetHTTPCacheMode(arg1:string):Promise<string>;
+
+export function SetWishlistItemStatus(arg1:number,arg2:number):Promise<string>;
+
 export function StopSync():Promise<string>;
 
+export function StopSyncType(arg1:string):Promise<string>;
+
 export function SyncMissingAtlasLoot(arg1:number,arg2:number):Promise<services.ImportResult>;
 
 export function SyncNpcData(arg1:number):Promise<services.NpcFullDetails>;
@@ -140,4 +219,8 @@ export function UpdateFavoriteCategory(arg1:number,arg2:string):Promise<models.F
 
 export function UpdateFavoriteStatus(arg1:number,arg2:number):Promise<models.FavoriteResult>;
 
+export function UpdateWishlist(arg1:number,arg2:string,arg3:string):Promise<string>;
+
+export function UpdateWishlistItem(arg1:models.WishlistItem):Promise<string>;
+
 export function WaitForReady():Promise<boolean>;
@@ -6,24 +6,36 @@ export function AddFavorite(arg1, arg2) {
   return window['go']['main']['App']['AddFavorite'](arg1, arg2);
 }
 
+export function AddWishlistItem(arg1) {
+  return window['go']['main']['App']['AddWishlistItem'](arg1);
+}
+
 export function AdvancedSearch(arg1) {
   return window['go']['main']['App']['AdvancedSearch'](arg1);
 }
 
-export function BrowseCreaturesByType(arg1, arg2) {
-  return window['go']['main']['App']['BrowseCreaturesByType'](arg1, arg2);
+export function ApplyDataPatch(arg1) {
+  return window['go']['main']['App']['ApplyDataPatch'](arg1);
+}
+
+export function BackupUserData() {
+  return window['go']['main']['App']['BackupUserData']();
+}
+
+export function BrowseCreaturesByType(arg1, arg2, arg3) {
+  return window['go']['main']['App']['BrowseCreaturesByType'](arg1, arg2, arg3);
 }
 
-export function BrowseCreaturesByTypePaged(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['BrowseCreaturesByTypePaged'](arg1, arg2, arg3, arg4);
+export function BrowseItemsByClass(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BrowseItemsByClass'](arg1, arg2, arg3, arg4);
 }
 
-export function BrowseItemsByClass(arg1, arg2, arg3) {
-  return window['go']['main']['App']['BrowseItemsByClass'](arg1, arg2, arg3);
+export function BrowseItemsByClassAndSlot(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['BrowseItemsByClassAndSlot'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function BrowseItemsByClassAndSlot(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['BrowseItemsByClassAndSlot'](arg1, arg2, arg3, arg4);
+export function CancelJob(arg1) {
+  return window['go']['main']['App']['CancelJob'](arg1);
 }
 
 export function CheckNewItems(arg1, arg2) {
@@ -34,6 +46,34 @@ export function CheckNewQuests(arg1, arg2) {
   return window['go']['main']['App']['CheckNewQuests'](arg1, arg2);
 }
 
+export function CleanupImages(arg1) {
+  return window['go']['main']['App']['CleanupImages'](arg1);
+}
+
+export function ClearHTTPCache() {
+  return window['go']['main']['App']['ClearHTTPCache']();
+}
+
+export function ClearReadCache() {
+  return window['go']['main']['App']['ClearReadCache']();
+}
+
+export function CreateWishlist(arg1, arg2) {
+  return window['go']['main']['App']['CreateWishlist'](arg1, arg2);
+}
+
+export function DeleteSavedSearch(arg1) {
+  return window['go']['main']['App']['DeleteSavedSearch'](arg1);
+}
+
+export function DeleteWishlist(arg1) {
+  return window['go']['main']['App']['DeleteWishlist'](arg1);
+}
+
+export function ExportUserData(arg1) {
+  return window['go']['main']['App']['ExportUserData'](arg1);
+}
+
 export function FetchRemoteImage(arg1, arg2, arg3) {
   return window['go']['main']['App']['FetchRemoteImage'](arg1, arg2, arg3);
 }
@@ -62,6 +102,14 @@ export function FullSyncSpells(arg1, arg2, arg3) {
   return window['go']['main']['App']['FullSyncSpells'](arg1, arg2, arg3);
 }
 
+export function GeneratePatchNotes(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GeneratePatchNotes'](arg1, arg2, arg3);
+}
+
+export function GeneratePatchNotesFromSnapshot(arg1, arg2) {
+  return window['go']['main']['App']['GeneratePatchNotesFromSnapshot'](arg1, arg2);
+}
+
 export function GetAllFavorites() {
   return window['go']['main']['App']['GetAllFavorites']();
 }
@@ -90,6 +138,10 @@ export function GetCreatureTypes() {
   return window['go']['main']['App']['GetCreatureTypes']();
 }
 
+export function GetDataVersion() {
+  return window['go']['main']['App']['GetDataVersion']();
+}
+
 export function GetFactionDetail(arg1) {
   return window['go']['main']['App']['GetFactionDetail'](arg1);
 }
@@ -106,6 +158,14 @@ export function GetFavoritesByCategory(arg1) {
   return window['go']['main']['App']['GetFavoritesByCategory'](arg1);
 }
 
+export function GetHTTPCacheStats() {
+  return window['go']['main']['App']['GetHTTPCacheStats']();
+}
+
+export function GetIconSprites(arg1, arg2) {
+  return window['go']['main']['App']['GetIconSprites'](arg1, arg2);
+}
+
 export function GetInstances(arg1) {
   return window['go']['main']['App']['GetInstances'](arg1);
 }
@@ -134,6 +194,10 @@ export function GetLoot(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetLoot'](arg1, arg2, arg3);
 }
 
+export function GetLootTree(arg1) {
+  return window['go']['main']['App']['GetLootTree'](arg1);
+}
+
 export function GetMissingAtlasLootItems(arg1) {
   return window['go']['main']['App']['GetMissingAtlasLootItems'](arg1);
 }
@@ -150,8 +214,8 @@ export function GetObjectTypes() {
   return window['go']['main']['App']['GetObjectTypes']();
 }
 
-export function GetObjectsByType(arg1, arg2) {
-  return window['go']['main']['App']['GetObjectsByType'](arg1, arg2);
+export function GetObjectsByType(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetObjectsByType'](arg1, arg2, arg3);
 }
 
 export function GetQuestCategories() {
@@ -170,18 +234,42 @@ export function GetQuestDetail(arg1) {
   return window['go']['main']['App']['GetQuestDetail'](arg1);
 }
 
+export function GetQuestGraph(arg1) {
+  return window['go']['main']['App']['GetQuestGraph'](arg1);
+}
+
 export function GetQuestsByCategory(arg1) {
   return window['go']['main']['App']['GetQuestsByCategory'](arg1);
 }
 
-export function GetQuestsByEnhancedCategory(arg1, arg2) {
-  return window['go']['main']['App']['GetQuestsByEnhancedCategory'](arg1, arg2);
+export function GetQuestsByEnhancedCategory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetQuestsByEnhancedCategory'](arg1, arg2, arg3);
+}
+
+export function GetReadCacheStats() {
+  return window['go']['main']['App']['GetReadCacheStats']();
+}
+
+export function GetRecentChanges(arg1) {
+  return window['go']['main']['App']['GetRecentChanges'](arg1);
 }
 
 export function GetRootCategories() {
   return window['go']['main']['App']['GetRootCategories']();
 }
 
+export function GetSavedSearches() {
+  return window['go']['main']['App']['GetSavedSearches']();
+}
+
+export function GetSmartCollection(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetSmartCollection'](arg1, arg2, arg3);
+}
+
+export function GetSmartCollections() {
+  return window['go']['main']['App']['GetSmartCollections']();
+}
+
 export function GetSpellDetail(arg1) {
   return window['go']['main']['App']['GetSpellDetail'](arg1);
 }
@@ -194,8 +282,8 @@ export function GetSpellSkillsByCategory(arg1) {
   return window['go']['main']['App']['GetSpellSkillsByCategory'](arg1);
 }
 
-export function GetSpellsBySkill(arg1, arg2) {
-  return window['go']['main']['App']['GetSpellsBySkill'](arg1, arg2);
+export function GetSpellsBySkill(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetSpellsBySkill'](arg1, arg2, arg3);
 }
 
 export function GetSyncStats() {
@@ -210,14 +298,70 @@ export function GetTooltipData(arg1) {
   return window['go']['main']['App']['GetTooltipData'](arg1);
 }
 
+export function GetUserDataBackups() {
+  return window['go']['main']['App']['GetUserDataBackups']();
+}
+
+export function GetWishlistCharacters() {
+  return window['go']['main']['App']['GetWishlistCharacters']();
+}
+
+export function GetWishlistItems(arg1) {
+  return window['go']['main']['App']['GetWishlistItems'](arg1);
+}
+
+export function GetWishlists() {
+  return window['go']['main']['App']['GetWishlists']();
+}
+
+export function ImportUserData(arg1, arg2) {
+  return window['go']['main']['App']['ImportUserData'](arg1, arg2);
+}
+
 export function IsFavorite(arg1) {
   return window['go']['main']['App']['IsFavorite'](arg1);
 }
 
+export function ListJobs(arg1) {
+  return window['go']['main']['App']['ListJobs'](arg1);
+}
+
+export function MarkSmartCollectionViewed(arg1) {
+  return window['go']['main']['App']['MarkSmartCollectionViewed'](arg1);
+}
+
+export function PauseJob(arg1) {
+  return window['go']['main']['App']['PauseJob'](arg1);
+}
+
 export function RemoveFavorite(arg1) {
   return window['go']['main']['App']['RemoveFavorite'](arg1);
 }
 
+export function RemoveWishlistItem(arg1) {
+  return window['go']['main']['App']['RemoveWishlistItem'](arg1);
+}
+
+export function RenderTooltip(arg1, arg2) {
+  return window['go']['main']['App']['RenderTooltip'](arg1, arg2);
+}
+
+export function RenderTooltipImage(arg1) {
+  return window['go']['main']['App']['RenderTooltipImage'](arg1);
+}
+
+export function RestoreUserDataBackup(arg1) {
+  return window['go']['main']['App']['RestoreUserDataBackup'](arg1);
+}
+
+export function ResumeJob(arg1) {
+  return window['go']['main']['App']['ResumeJob'](arg1);
+}
+
+export function SaveSearch(arg1) {
+  return window['go']['main']['App']['SaveSearch'](arg1);
+}
+
 export function SearchCreatures(arg1) {
   return window['go']['main']['App']['SearchCreatures'](arg1);
 }
@@ -238,10 +382,22 @@ export function SearchSpells(arg1) {
   return window['go']['main']['App']['SearchSpells'](arg1);
 }
 
+export function SetHTTPCacheMode(arg1) {
+  return window['go']['main']['App']['SetHTTPCacheMode'](arg1);
+}
+
+export function SetWishlistItemStatus(arg1, arg2) {
+  return window['go']['main']['App']['SetWishlistItemStatus'](arg1, arg2);
+}
+
 export function StopSync() {
   return window['go']['main']['App']['StopSync']();
 }
 
+export function StopSyncType(arg1) {
+  return window['go']['main']['App']['StopSyncType'](arg1);
+}
+
 export function SyncMissingAtlasLoot(arg1, arg2) {
   return window['go']['main']['App']['SyncMissingAtlasLoot'](arg1, arg2);
 }
@@ -274,6 +430,14 @@ export function UpdateFavoriteStatus(arg1, arg2) {
   return window['go']['main']['App']['UpdateFavoriteStatus'](arg1, arg2);
 }
 
+export function UpdateWishlist(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateWishlist'](arg1, arg2, arg3);
+}
+
+export function UpdateWishlistItem(arg1) {
+  return window['go']['main']['App']['UpdateWishlistItem'](arg1);
+}
+
 export function WaitForReady() {
   return window['go']['main']['App']['WaitForReady']();
 }
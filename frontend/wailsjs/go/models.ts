@@ -1,39 +1,5 @@
 export namespace main {
 	
-	export class CreaturePageResult {
-	    creatures: models.Creature[];
-	    total: number;
-	    hasMore: boolean;
-	
-	    static createFrom(source: any = {}) {
-	        return new CreaturePageResult(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.creatures = this.convertValues(source["creatures"], models.Creature);
-	        this.total = source["total"];
-	        this.hasMore = source["hasMore"];
-	    }
-	
-		convertValues(a: any, classs: any, asMap: boolean = false): any {
-		    if (!a) {
-		        return a;
-		    }
-		    if (a.slice && a.map) {
-		        return (a as any[]).map(elem => this.convertValues(elem, classs));
-		    } else if ("object" === typeof a) {
-		        if (asMap) {
-		            for (const key of Object.keys(a)) {
-		                a[key] = new classs(a[key]);
-		            }
-		            return a;
-		        }
-		        return new classs(a);
-		    }
-		    return a;
-		}
-	}
 	export class FixMissingIconsResult {
 	    totalMissing: number;
 	    fixed: number;
@@ -129,6 +95,33 @@ export namespace main {
 
 }
 
+export namespace media {
+	
+	export class GCResult {
+	    kind: string;
+	    scanned: number;
+	    removed: string[];
+	    bytes: number;
+	    staleRows: number;
+	    dryRun: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GCResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.scanned = source["scanned"];
+	        this.removed = source["removed"];
+	        this.bytes = source["bytes"];
+	        this.staleRows = source["staleRows"];
+	        this.dryRun = source["dryRun"];
+	    }
+	}
+
+}
+
 export namespace models {
 	
 	export class AtlasTable {
@@ -145,6 +138,30 @@ export namespace models {
 	        this.displayName = source["displayName"];
 	    }
 	}
+	export class CacheStats {
+	    name: string;
+	    entries: number;
+	    capacity: number;
+	    hits: number;
+	    misses: number;
+	    evictions: number;
+	    purges: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.entries = source["entries"];
+	        this.capacity = source["capacity"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.evictions = source["evictions"];
+	        this.purges = source["purges"];
+	    }
+	}
 	export class Category {
 	    id: number;
 	    key: string;
@@ -167,6 +184,66 @@ export namespace models {
 	        this.sortOrder = source["sortOrder"];
 	    }
 	}
+	export class ChangeLogEntry {
+	    id: number;
+	    entityType: string;
+	    entityId: number;
+	    entityName: string;
+	    action: string;
+	    field: string;
+	    oldValue: string;
+	    newValue: string;
+	    source: string;
+	    changedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangeLogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.entityType = source["entityType"];
+	        this.entityId = source["entityId"];
+	        this.entityName = source["entityName"];
+	        this.action = source["action"];
+	        this.field = source["field"];
+	        this.oldValue = source["oldValue"];
+	        this.newValue = source["newValue"];
+	        this.source = source["source"];
+	        this.changedAt = source["changedAt"];
+	    }
+	}
+	export class ChangeLogFilter {
+	    entityType?: string;
+	    entityId?: number;
+	    action?: string;
+	    field?: string;
+	    source?: string;
+	    search?: string;
+	    since?: string;
+	    until?: string;
+	    limit?: number;
+	    offset?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ChangeLogFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entityType = source["entityType"];
+	        this.entityId = source["entityId"];
+	        this.action = source["action"];
+	        this.field = source["field"];
+	        this.source = source["source"];
+	        this.search = source["search"];
+	        this.since = source["since"];
+	        this.until = source["until"];
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	    }
+	}
 	export class Creature {
 	    entry: number;
 	    name: string;
@@ -1161,6 +1238,90 @@ export namespace models {
 	}
 	
 	
+	export class LootNode {
+	    itemId?: number;
+	    name?: string;
+	    iconPath?: string;
+	    quality: number;
+	    referenceId?: number;
+	    chance: number;
+	    effectiveChance: number;
+	    groupId: number;
+	    minCount: number;
+	    maxCount: number;
+	    cycle?: boolean;
+	    children?: LootNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LootNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.name = source["name"];
+	        this.iconPath = source["iconPath"];
+	        this.quality = source["quality"];
+	        this.referenceId = source["referenceId"];
+	        this.chance = source["chance"];
+	        this.effectiveChance = source["effectiveChance"];
+	        this.groupId = source["groupId"];
+	        this.minCount = source["minCount"];
+	        this.maxCount = source["maxCount"];
+	        this.cycle = source["cycle"];
+	        this.children = this.convertValues(source["children"], LootNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LootTree {
+	    lootId: number;
+	    nodes: LootNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LootTree(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lootId = source["lootId"];
+	        this.nodes = this.convertValues(source["nodes"], LootNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class ObjectType {
 	    id: number;
 	    name: string;
@@ -1177,6 +1338,134 @@ export namespace models {
 	        this.count = source["count"];
 	    }
 	}
+	export class PageQuery {
+	    limit: number;
+	    offset: number;
+	    cursor?: string;
+	    sort?: string;
+	    desc?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PageQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.limit = source["limit"];
+	        this.offset = source["offset"];
+	        this.cursor = source["cursor"];
+	        this.sort = source["sort"];
+	        this.desc = source["desc"];
+	    }
+	}
+	export class Page__shelllab_backend_database_models_Creature_ {
+	    items: Creature[];
+	    total: number;
+	    hasMore: boolean;
+	    nextCursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Page__shelllab_backend_database_models_Creature_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], Creature);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	        this.nextCursor = source["nextCursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Page__shelllab_backend_database_models_GameObject_ {
+	    items: GameObject[];
+	    total: number;
+	    hasMore: boolean;
+	    nextCursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Page__shelllab_backend_database_models_GameObject_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], GameObject);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	        this.nextCursor = source["nextCursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Page__shelllab_backend_database_models_Item_ {
+	    items: Item[];
+	    total: number;
+	    hasMore: boolean;
+	    nextCursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Page__shelllab_backend_database_models_Item_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], Item);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	        this.nextCursor = source["nextCursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class Quest {
 	    entry: number;
 	    title: string;
@@ -1219,20 +1508,113 @@ export namespace models {
 	        this.nextQuestInChain = source["nextQuestInChain"];
 	    }
 	}
-	export class QuestCategory {
-	    id: number;
-	    name: string;
-	    count: number;
+	export class Page__shelllab_backend_database_models_Quest_ {
+	    items: Quest[];
+	    total: number;
+	    hasMore: boolean;
+	    nextCursor?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new QuestCategory(source);
+	        return new Page__shelllab_backend_database_models_Quest_(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.count = source["count"];
+	        this.items = this.convertValues(source["items"], Quest);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	        this.nextCursor = source["nextCursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Spell {
+	    entry: number;
+	    name: string;
+	    subname: string;
+	    description: string;
+	    icon: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Spell(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = source["entry"];
+	        this.name = source["name"];
+	        this.subname = source["subname"];
+	        this.description = source["description"];
+	        this.icon = source["icon"];
+	    }
+	}
+	export class Page__shelllab_backend_database_models_Spell_ {
+	    items: Spell[];
+	    total: number;
+	    hasMore: boolean;
+	    nextCursor?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Page__shelllab_backend_database_models_Spell_(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], Spell);
+	        this.total = source["total"];
+	        this.hasMore = source["hasMore"];
+	        this.nextCursor = source["nextCursor"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class QuestCategory {
+	    id: number;
+	    name: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestCategory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.count = source["count"];
 	    }
 	}
 	export class QuestCategoryEnhanced {
@@ -1399,6 +1781,74 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class QuestGraphEdge {
+	    from: number;
+	    to: number;
+	    type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestGraphEdge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.type = source["type"];
+	    }
+	}
+	export class QuestGraphNode {
+	    entry: number;
+	    title: string;
+	    level: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestGraphNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entry = source["entry"];
+	        this.title = source["title"];
+	        this.level = source["level"];
+	    }
+	}
+	export class QuestGraph {
+	    root: number;
+	    nodes: QuestGraphNode[];
+	    edges: QuestGraphEdge[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuestGraph(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.root = source["root"];
+	        this.nodes = this.convertValues(source["nodes"], QuestGraphNode);
+	        this.edges = this.convertValues(source["edges"], QuestGraphEdge);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
 	
 	
 	
@@ -1414,6 +1864,10 @@ export namespace models {
 	    maxLevel?: number;
 	    minReqLevel?: number;
 	    maxReqLevel?: number;
+	    stats?: number[];
+	    effect?: string;
+	    sort?: string;
+	    desc?: boolean;
 	    limit: number;
 	    offset: number;
 	
@@ -1432,30 +1886,57 @@ export namespace models {
 	        this.maxLevel = source["maxLevel"];
 	        this.minReqLevel = source["minReqLevel"];
 	        this.maxReqLevel = source["maxReqLevel"];
+	        this.stats = source["stats"];
+	        this.effect = source["effect"];
+	        this.sort = source["sort"];
+	        this.desc = source["desc"];
 	        this.limit = source["limit"];
 	        this.offset = source["offset"];
 	    }
 	}
-	export class Spell {
-	    entry: number;
+	export class SavedSearch {
+	    id: number;
 	    name: string;
-	    subname: string;
-	    description: string;
-	    icon: string;
+	    filter: SearchFilter;
+	    sort?: string;
+	    desc?: boolean;
+	    createdAt: string;
+	    lastViewedAt: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Spell(source);
+	        return new SavedSearch(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.entry = source["entry"];
+	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.subname = source["subname"];
-	        this.description = source["description"];
-	        this.icon = source["icon"];
+	        this.filter = this.convertValues(source["filter"], SearchFilter);
+	        this.sort = source["sort"];
+	        this.desc = source["desc"];
+	        this.createdAt = source["createdAt"];
+	        this.lastViewedAt = source["lastViewedAt"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class SearchResult {
 	    items: Item[];
 	    creatures?: Creature[];
@@ -1495,6 +1976,74 @@ export namespace models {
 		}
 	}
 	
+	export class SmartCollection {
+	    search?: SavedSearch;
+	    count: number;
+	    newCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.search = this.convertValues(source["search"], SavedSearch);
+	        this.count = source["count"];
+	        this.newCount = source["newCount"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SmartCollectionView {
+	    collection?: SmartCollection;
+	    items: Item[];
+	    newItems: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SmartCollectionView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.collection = this.convertValues(source["collection"], SmartCollection);
+	        this.items = this.convertValues(source["items"], Item);
+	        this.newItems = source["newItems"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	
 	export class SpellUsedByItem {
 	    entry: number;
@@ -1961,11 +2510,117 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class Wishlist {
+	    id: number;
+	    name: string;
+	    description: string;
+	    createdAt: string;
+	    itemCount: number;
+	    obtainedCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Wishlist(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.createdAt = source["createdAt"];
+	        this.itemCount = source["itemCount"];
+	        this.obtainedCount = source["obtainedCount"];
+	    }
+	}
+	export class WishlistItem {
+	    id: number;
+	    wishlist: string;
+	    itemEntry: number;
+	    character: string;
+	    priority: number;
+	    notes: string;
+	    sourceType: string;
+	    sourceEntry: number;
+	    status: number;
+	    obtainedAt: string;
+	    addedAt: string;
+	    sourceName?: string;
+	    itemName?: string;
+	    itemQuality?: number;
+	    iconPath?: string;
+	    itemLevel?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WishlistItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.wishlist = source["wishlist"];
+	        this.itemEntry = source["itemEntry"];
+	        this.character = source["character"];
+	        this.priority = source["priority"];
+	        this.notes = source["notes"];
+	        this.sourceType = source["sourceType"];
+	        this.sourceEntry = source["sourceEntry"];
+	        this.status = source["status"];
+	        this.obtainedAt = source["obtainedAt"];
+	        this.addedAt = source["addedAt"];
+	        this.sourceName = source["sourceName"];
+	        this.itemName = source["itemName"];
+	        this.itemQuality = source["itemQuality"];
+	        this.iconPath = source["iconPath"];
+	        this.itemLevel = source["itemLevel"];
+	    }
+	}
+	export class WishlistItemFilter {
+	    wishlist?: string;
+	    character?: string;
+	    itemEntry?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WishlistItemFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.wishlist = source["wishlist"];
+	        this.character = source["character"];
+	        this.itemEntry = source["itemEntry"];
+	    }
+	}
 
 }
 
 export namespace services {
 	
+	export class HTTPCacheStats {
+	    mode: string;
+	    dir: string;
+	    ttlHours: number;
+	    entries: number;
+	    sizeBytes: number;
+	    hits: number;
+	    misses: number;
+	    revalidated: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HTTPCacheStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.dir = source["dir"];
+	        this.ttlHours = source["ttlHours"];
+	        this.entries = source["entries"];
+	        this.sizeBytes = source["sizeBytes"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.revalidated = source["revalidated"];
+	    }
+	}
 	export class ImportResult {
 	    checked: number;
 	    imported: number;
@@ -1986,6 +2641,22 @@ export namespace services {
 	        this.errors = source["errors"];
 	    }
 	}
+	export class JobParams {
+	    delayMs: number;
+	    fixIcons: boolean;
+	    startFrom: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobParams(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.delayMs = source["delayMs"];
+	        this.fixIcons = source["fixIcons"];
+	        this.startFrom = source["startFrom"];
+	    }
+	}
 	export class MissingItem {
 	    itemId: number;
 	    tableKey: string;
@@ -2114,6 +2785,7 @@ export namespace services {
 	    zoneName: string;
 	    x: number;
 	    y: number;
+	    lootId: number;
 	    loot: NpcLoot[];
 	    quests: NpcQuest[];
 	    abilities: NpcAbility[];
@@ -2158,6 +2830,7 @@ export namespace services {
 	        this.zoneName = source["zoneName"];
 	        this.x = source["x"];
 	        this.y = source["y"];
+	        this.lootId = source["lootId"];
 	        this.loot = this.convertValues(source["loot"], NpcLoot);
 	        this.quests = this.convertValues(source["quests"], NpcQuest);
 	        this.abilities = this.convertValues(source["abilities"], NpcAbility);
@@ -2235,6 +2908,64 @@ export namespace services {
 	        this.error = source["error"];
 	    }
 	}
+	export class SyncJob {
+	    id: number;
+	    type: string;
+	    params: JobParams;
+	    state: string;
+	    cursor: number;
+	    total: number;
+	    processed: number;
+	    updated: number;
+	    failed: number;
+	    errors: string[];
+	    message: string;
+	    createdAt: string;
+	    startedAt?: string;
+	    updatedAt: string;
+	    finishedAt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SyncJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.type = source["type"];
+	        this.params = this.convertValues(source["params"], JobParams);
+	        this.state = source["state"];
+	        this.cursor = source["cursor"];
+	        this.total = source["total"];
+	        this.processed = source["processed"];
+	        this.updated = source["updated"];
+	        this.failed = source["failed"];
+	        this.errors = source["errors"];
+	        this.message = source["message"];
+	        this.createdAt = source["createdAt"];
+	        this.startedAt = source["startedAt"];
+	        this.updatedAt = source["updatedAt"];
+	        this.finishedAt = source["finishedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SyncSpellResult {
 	    success: boolean;
 	    spellId: number;
@@ -2255,6 +2986,98 @@ export namespace services {
 	        this.error = source["error"];
 	    }
 	}
+	export class UserDataBackup {
+	    name: string;
+	    size: number;
+	    createdAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserDataBackup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class UserDataConflict {
+	    table: string;
+	    key: string;
+	    fields: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UserDataConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.key = source["key"];
+	        this.fields = source["fields"];
+	    }
+	}
+	export class UserTableImport {
+	    table: string;
+	    added: number;
+	    unchanged: number;
+	    conflicts: number;
+	    replaced: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserTableImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.table = source["table"];
+	        this.added = source["added"];
+	        this.unchanged = source["unchanged"];
+	        this.conflicts = source["conflicts"];
+	        this.replaced = source["replaced"];
+	    }
+	}
+	export class UserDataImportResult {
+	    success: boolean;
+	    mode: string;
+	    tables: UserTableImport[];
+	    conflicts: UserDataConflict[];
+	    skipped: string[];
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserDataImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.mode = source["mode"];
+	        this.tables = this.convertValues(source["tables"], UserTableImport);
+	        this.conflicts = this.convertValues(source["conflicts"], UserDataConflict);
+	        this.skipped = source["skipped"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -10,6 +10,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/wailsapp/wails/v2 v2.11.0
+	golang.org/x/image v0.12.0
 	modernc.org/sqlite v1.40.1
 )
 